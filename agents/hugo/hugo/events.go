@@ -0,0 +1,86 @@
+package hugo
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// RebuildSubject is the NATS subject Serve publishes RebuildEvents on.
+const RebuildSubject = "site.rebuilt"
+
+// RebuildEvent describes one hugo server rebuild.
+type RebuildEvent struct {
+	Source       string        `json:"source"`
+	Duration     time.Duration `json:"duration_ns"`
+	ChangedFiles int           `json:"changed_files"`
+	Timestamp    time.Time     `json:"timestamp"`
+}
+
+var totalRe = regexp.MustCompile(`^Total in (\d+(?:\.\d+)?) ?(ms|s)$`)
+
+// rebuildParser follows hugo server output. A rebuild starts with "Change
+// detected, rebuilding site", lists one "... changed <path>" line per file
+// and ends with "Total in N ms".
+type rebuildParser struct {
+	rebuilding bool
+	changed    int
+}
+
+// line consumes one line of output and reports a finished rebuild.
+func (p *rebuildParser) line(line string) (RebuildEvent, bool) {
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, "Change detected") || strings.HasPrefix(line, "Change of config file detected"):
+		p.rebuilding, p.changed = true, 0
+	case !p.rebuilding:
+	case strings.Contains(line, " changed "):
+		p.changed++
+	default:
+		m := totalRe.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		p.rebuilding = false
+		n, _ := strconv.ParseFloat(m[1], 64)
+		unit := time.Millisecond
+		if m[2] == "s" {
+			unit = time.Second
+		}
+		return RebuildEvent{Duration: time.Duration(n * float64(unit)), ChangedFiles: p.changed}, true
+	}
+	return RebuildEvent{}, false
+}
+
+// publishRebuilds reads hugo output from r until EOF and publishes a
+// RebuildEvent per rebuild. Publish failures are logged, never fatal.
+func publishRebuilds(nc *nats.Conn, source string, r io.Reader) {
+	var p rebuildParser
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		event, ok := p.line(scanner.Text())
+		if !ok {
+			continue
+		}
+		event.Source = source
+		event.Timestamp = time.Now()
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			slog.Error("Failed to marshal rebuild event", "error", err)
+			continue
+		}
+		if err := nc.Publish(RebuildSubject, data); err != nil {
+			slog.Error("Failed to publish rebuild event to NATS", "subject", RebuildSubject, "error", err)
+		}
+	}
+	// Keep draining so hugo never blocks on a full pipe.
+	_, _ = io.Copy(io.Discard, r)
+}
@@ -0,0 +1,41 @@
+package hugo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRebuildParser(t *testing.T) {
+	output := `Web Server is available at http://localhost:1414/ (bind address 127.0.0.1)
+Press Ctrl+C to stop
+
+Change detected, rebuilding site (#1).
+2025-09-30 10:00:00.000 +0200
+Source changed /content/tasks/a.md
+Source changed /content/tasks/b.md
+Total in 34 ms
+
+Change of config file detected, rebuilding site (#2).
+Total in 1.5 s
+`
+	var (
+		p      rebuildParser
+		events []RebuildEvent
+	)
+	for _, line := range strings.Split(output, "\n") {
+		if e, ok := p.line(line); ok {
+			events = append(events, e)
+		}
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+	if events[0].ChangedFiles != 2 || events[0].Duration != 34*time.Millisecond {
+		t.Errorf("first rebuild = %+v", events[0])
+	}
+	if events[1].ChangedFiles != 0 || events[1].Duration != 1500*time.Millisecond {
+		t.Errorf("second rebuild = %+v", events[1])
+	}
+}
@@ -0,0 +1,175 @@
+// Package hugo renders the agents docs site with the hugo binary managed by
+// pkg/dep, so callers don't need hugo on PATH.
+package hugo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/joeblew999/infra/pkg/dep"
+	"github.com/nats-io/nats.go"
+)
+
+// Options configures a Serve or Build run.
+type Options struct {
+	// SourceDir is the Hugo site root; it must contain a content directory.
+	SourceDir string
+	// Destination is where Build writes the site (default SourceDir/public).
+	Destination string
+	// Port is the hugo server port (Serve only, default 1414).
+	Port int
+	// Drafts includes draft and future content.
+	Drafts bool
+	// Watch enables file watching (Serve only).
+	Watch bool
+	// DryRun prints the hugo command instead of running it.
+	DryRun bool
+	// HugoBin overrides the hugo binary resolved through pkg/dep.
+	HugoBin string
+	// Stdout and Stderr receive hugo's output (default os.Stdout/os.Stderr).
+	Stdout io.Writer
+	Stderr io.Writer
+	// NATS, if set, receives a RebuildEvent on RebuildSubject after each
+	// successful rebuild while serving with Watch. Nil disables publishing.
+	NATS *nats.Conn
+}
+
+// DefaultPort is the port Serve uses when Options.Port is zero.
+const DefaultPort = 1414
+
+// Serve runs hugo server until ctx is cancelled.
+func Serve(ctx context.Context, opts Options) error {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"server", "--source", opts.SourceDir,
+		"--bind", "127.0.0.1", "--port", fmt.Sprint(opts.Port)}
+	if !opts.Watch {
+		args = append(args, "--watch=false")
+	}
+	args = append(args, "--disableFastRender", "--renderToMemory")
+
+	if opts.NATS == nil || !opts.Watch || opts.DryRun {
+		return opts.run(ctx, args)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		publishRebuilds(opts.NATS, opts.SourceDir, pr)
+	}()
+
+	out := opts.Stdout
+	opts.Stdout = io.MultiWriter(out, pw)
+	err = opts.run(ctx, args)
+	pw.Close()
+	<-done
+	return err
+}
+
+// Build renders the site and returns the output directory.
+func Build(ctx context.Context, opts Options) (string, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"--source", opts.SourceDir, "--minify", "--destination", opts.Destination}
+	if err := opts.run(ctx, args); err != nil {
+		return "", err
+	}
+	return opts.Destination, nil
+}
+
+func (o Options) withDefaults() (Options, error) {
+	if o.SourceDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return o, fmt.Errorf("resolve working directory: %w", err)
+		}
+		o.SourceDir = cwd
+	}
+	abs, err := filepath.Abs(o.SourceDir)
+	if err != nil {
+		return o, fmt.Errorf("resolve source directory: %w", err)
+	}
+	o.SourceDir = abs
+
+	if _, err := os.Stat(filepath.Join(o.SourceDir, "content")); err != nil {
+		return o, fmt.Errorf("expected content directory inside %s: %w", o.SourceDir, err)
+	}
+	if o.Destination == "" {
+		o.Destination = filepath.Join(o.SourceDir, "public")
+	}
+	if o.Port == 0 {
+		o.Port = DefaultPort
+	}
+	if o.Stdout == nil {
+		o.Stdout = os.Stdout
+	}
+	if o.Stderr == nil {
+		o.Stderr = os.Stderr
+	}
+	return o, nil
+}
+
+func (o Options) run(ctx context.Context, args []string) error {
+	if o.Drafts {
+		args = append(args, "--buildDrafts", "--buildFuture")
+	}
+
+	hugoPath := o.HugoBin
+	if hugoPath == "" {
+		if o.DryRun {
+			hugoPath = "hugo"
+		} else {
+			path, err := resolveHugo()
+			if err != nil {
+				return err
+			}
+			hugoPath = path
+		}
+	}
+
+	if o.DryRun {
+		fmt.Fprintf(o.Stdout, "[dry-run] %s %s\n", hugoPath, strings.Join(args, " "))
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, hugoPath, args...)
+	cmd.Stdout = o.Stdout
+	cmd.Stderr = o.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil
+		}
+		return fmt.Errorf("hugo finished with error: %w", err)
+	}
+	return nil
+}
+
+// resolveHugo returns the pkg/dep managed hugo binary, installing it on
+// first use.
+func resolveHugo() (string, error) {
+	path, err := dep.Get("hugo")
+	if err != nil {
+		return "", fmt.Errorf("resolve hugo: %w", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := dep.InstallBinary("hugo", false); err != nil {
+		return "", fmt.Errorf("install hugo: %w", err)
+	}
+	return path, nil
+}
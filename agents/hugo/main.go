@@ -2,123 +2,80 @@ package main
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"os/signal"
-	"path/filepath"
 	"strings"
 	"syscall"
-	"time"
+
+	"github.com/joeblew999/infra/agents/hugo"
+	"github.com/nats-io/nats.go"
 )
 
 func main() {
-	cfg := parseFlags()
-
-	if err := cfg.run(); err != nil {
-		fmt.Fprintf(os.Stderr, "agents hugo runner: %v\n", err)
-		os.Exit(1)
-	}
+	os.Exit(realMain())
 }
 
-type options struct {
-	mode    string
-	port    int
-	drafts  bool
-	watch   bool
-	dryRun  bool
-	hugoBin string
-}
-
-func parseFlags() options {
-	var cfg options
-	flag.StringVar(&cfg.mode, "mode", "serve", "Operation to run: serve or build")
-	flag.IntVar(&cfg.port, "port", 1414, "Port for hugo server (serve mode only)")
-	flag.BoolVar(&cfg.drafts, "drafts", true, "Include draft content")
-	flag.BoolVar(&cfg.watch, "watch", true, "Enable file watching in serve mode")
-	flag.BoolVar(&cfg.dryRun, "dry-run", false, "Print commands without executing them")
-	flag.StringVar(&cfg.hugoBin, "hugo", "", "Path to the hugo binary (defaults to PATH lookup)")
-	flag.Parse()
-
-	cfg.mode = strings.ToLower(cfg.mode)
-	return cfg
-}
+// realMain returns the exit code so deferred cleanup (draining the NATS
+// connection) runs before main calls os.Exit.
+func realMain() int {
+	mode, natsURL, opts := parseFlags()
 
-func (o options) run() error {
-	if o.mode != "serve" && o.mode != "build" {
-		return fmt.Errorf("unknown mode %q", o.mode)
-	}
-
-	hugoPath := o.hugoBin
-	if hugoPath == "" {
-		path, err := exec.LookPath("hugo")
+	// Rebuild events are optional: without -nats the runner needs no broker.
+	if natsURL != "" {
+		nc, err := nats.Connect(natsURL)
 		if err != nil {
-			return errors.New("hugo binary not found; install with `brew install hugo` or provide --hugo path")
+			fmt.Fprintf(os.Stderr, "agents hugo runner: connect to NATS: %v\n", err)
+			return 1
 		}
-		hugoPath = path
-	}
-
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("resolve working directory: %w", err)
+		defer nc.Drain()
+		opts.NATS = nc
 	}
 
-	agentsDir := cwd
-	// ensure we are inside agents directory (no parent operations)
-	if _, err := os.Stat(filepath.Join(agentsDir, "content")); err != nil {
-		return fmt.Errorf("expected content directory inside %s: %w", agentsDir, err)
-	}
-
-	args := []string{"--source", agentsDir}
-
-	if o.mode == "serve" {
-		args = append([]string{"server"}, args...)
-		args = append(args, "--bind", "127.0.0.1", "--port", fmt.Sprint(o.port))
-		if !o.watch {
-			args = append(args, "--watch", "false")
-		}
-		args = append(args, "--disableFastRender", "--renderToMemory")
-	} else {
-		args = append(args, "--minify", "--destination", filepath.Join(agentsDir, "public"))
+	if err := run(mode, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "agents hugo runner: %v\n", err)
+		return 1
 	}
+	return 0
+}
 
-	if o.drafts {
-		args = append(args, "--buildDrafts", "--buildFuture")
-	}
+func parseFlags() (string, string, hugo.Options) {
+	var (
+		mode    string
+		natsURL string
+		opts    hugo.Options
+	)
+	flag.StringVar(&mode, "mode", "serve", "Operation to run: serve or build")
+	flag.IntVar(&opts.Port, "port", hugo.DefaultPort, "Port for hugo server (serve mode only)")
+	flag.BoolVar(&opts.Drafts, "drafts", true, "Include draft content")
+	flag.BoolVar(&opts.Watch, "watch", true, "Enable file watching in serve mode")
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "Print commands without executing them")
+	flag.StringVar(&opts.HugoBin, "hugo", "", "Path to the hugo binary (defaults to the pkg/dep managed hugo)")
+	flag.StringVar(&natsURL, "nats", "", "NATS URL to publish "+hugo.RebuildSubject+" events to (serve mode with -watch)")
+	flag.Parse()
 
-	if o.dryRun {
-		fmt.Printf("[dry-run] %s %s\n", hugoPath, strings.Join(args, " "))
-		return nil
-	}
+	return strings.ToLower(mode), natsURL, opts
+}
 
+func run(mode string, opts hugo.Options) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	cmd := exec.CommandContext(ctx, hugoPath, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("start hugo: %w", err)
-	}
-
-	err = cmd.Wait()
-	if ctx.Err() == context.Canceled {
+	switch mode {
+	case "serve":
+		fmt.Printf("Hugo server on http://127.0.0.1:%d (Ctrl+C to stop)\n", opts.Port)
+		return hugo.Serve(ctx, opts)
+	case "build":
+		outputDir, err := hugo.Build(ctx, opts)
+		if err != nil {
+			return err
+		}
+		if !opts.DryRun {
+			fmt.Printf("Build complete at %s\n", outputDir)
+		}
 		return nil
+	default:
+		return fmt.Errorf("unknown mode %q", mode)
 	}
-	if err != nil {
-		return fmt.Errorf("hugo finished with error: %w", err)
-	}
-
-	if o.mode == "build" {
-		fmt.Printf("Build complete at %s\n", filepath.Join(agentsDir, "public"))
-	} else {
-		fmt.Printf("Hugo server running on http://127.0.0.1:%d (Ctrl+C to stop)\n", o.port)
-		// give server a moment to stay alive if command returns unexpectedly
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	return nil
 }
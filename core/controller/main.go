@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -14,15 +15,20 @@ import (
 
 	"github.com/joeblew999/infra/core/controller/pkg/apiserver"
 	cloudflareprovider "github.com/joeblew999/infra/core/controller/pkg/providers/cloudflare"
+	flyprovider "github.com/joeblew999/infra/core/controller/pkg/providers/fly"
 	"github.com/joeblew999/infra/core/controller/pkg/reconcile"
 )
 
 func main() {
 	var (
-		specPath    = flag.String("spec", "controller/spec.yaml", "path to desired state spec")
-		addr        = flag.String("addr", "127.0.0.1:4400", "address to bind the controller API")
-		cfToken     = flag.String("cloudflare-token", "", "Cloudflare API token (overrides CLOUDFLARE_API_TOKEN)")
-		cfTokenFile = flag.String("cloudflare-token-file", "", "Path to Cloudflare API token file (overrides CLOUDFLARE_API_TOKEN_FILE)")
+		specPath     = flag.String("spec", "controller/spec.yaml", "path to desired state spec")
+		addr         = flag.String("addr", "127.0.0.1:4400", "address to bind the controller API")
+		cfToken      = flag.String("cloudflare-token", "", "Cloudflare API token (overrides CLOUDFLARE_API_TOKEN)")
+		cfTokenFile  = flag.String("cloudflare-token-file", "", "Path to Cloudflare API token file (overrides CLOUDFLARE_API_TOKEN_FILE)")
+		flyToken     = flag.String("fly-token", "", "Fly.io API token (overrides FLY_API_TOKEN)")
+		flyTokenFile = flag.String("fly-token-file", "", "Path to Fly.io API token file (overrides FLY_API_TOKEN_FILE)")
+		flyctlPath   = flag.String("flyctl", "", "Path to the flyctl binary (overrides FLYCTL_PATH, defaults to PATH lookup)")
+		once         = flag.Bool("once", false, "Run a single reconcile pass, print a summary and exit non-zero if any service failed")
 	)
 	flag.Parse()
 
@@ -31,20 +37,36 @@ func main() {
 		log.Fatalf("load desired state: %v", err)
 	}
 
+	options := reconcile.Options{Tick: 30 * time.Second, Metrics: reconcile.NewMetrics()}
+	var routing reconcile.MultiRouting
+	if provider, err := loadCloudflareProvider(*cfToken, *cfTokenFile); err != nil {
+		log.Fatalf("cloudflare provider: %v", err)
+	} else if provider != nil {
+		routing = append(routing, provider)
+	}
+	if provider, err := loadFlyProvider(*flyToken, *flyTokenFile, *flyctlPath); err != nil {
+		log.Fatalf("fly provider: %v", err)
+	} else if provider != nil {
+		routing = append(routing, provider)
+	}
+	if len(routing) > 0 {
+		options.Routing = routing
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", server.Router())
+	mux.Handle("/metrics", options.Metrics.Handler())
 	srv := &http.Server{
 		Addr:    *addr,
-		Handler: server.Router(),
+		Handler: mux,
+	}
+
+	if *once {
+		os.Exit(runOnce(reconcile.New(server, options), server, srv, flagSet("addr")))
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-
-	options := reconcile.Options{Tick: 30 * time.Second}
-	if routing, err := loadCloudflareProvider(*cfToken, *cfTokenFile); err != nil {
-		log.Fatalf("cloudflare provider: %v", err)
-	} else if routing != nil {
-		options.Routing = routing
-	}
 	go reconcile.New(server, options).Run(ctx)
 
 	errCh := make(chan error, 1)
@@ -58,6 +80,19 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGHUP reloads the spec file after an on-disk edit.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			if err := server.Reload(); err != nil {
+				log.Printf("reload %s: %v (keeping current state)", *specPath, err)
+				continue
+			}
+			log.Printf("reloaded %s", *specPath)
+		}
+	}()
+
 	select {
 	case <-sigCh:
 		log.Println("shutting down controller...")
@@ -80,28 +115,107 @@ func main() {
 	fmt.Println("controller stopped")
 }
 
+// runOnce reconciles every service once and returns the process exit code.
+// The API is only served while the pass runs when --addr was given
+// explicitly, e.g. so a CI job can watch /v1/events.
+func runOnce(reconciler *reconcile.Reconciler, server *apiserver.Server, srv *http.Server, serve bool) int {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if serve {
+		go func() {
+			log.Printf("controller listening on http://%s", srv.Addr)
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("controller error: %v", err)
+			}
+		}()
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				log.Printf("server shutdown error: %v", err)
+			}
+			if err := server.Close(); err != nil {
+				log.Printf("persist state error: %v", err)
+			}
+		}()
+	}
+
+	summary := reconciler.ReconcileOnce(ctx)
+	printSummary(os.Stdout, summary)
+	if summary.Failed() > 0 {
+		return 1
+	}
+	return 0
+}
+
+func printSummary(w io.Writer, summary reconcile.Summary) {
+	fmt.Fprintf(w, "reconciled %d services, %d failed\n", len(summary.Services), summary.Failed())
+	for _, svc := range summary.Services {
+		if svc.Err != nil {
+			fmt.Fprintf(w, "  FAIL %s: %v\n", svc.ID, svc.Err)
+			continue
+		}
+		fmt.Fprintf(w, "  ok   %s regions=%v\n", svc.ID, svc.Regions)
+	}
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
 func loadCloudflareProvider(flagToken, flagFile string) (reconcile.RoutingProvider, error) {
+	token, err := resolveToken("cloudflare", flagToken, flagFile, "CLOUDFLARE_API_TOKEN", "CLOUDFLARE_API_TOKEN_FILE")
+	if err != nil || token == "" {
+		return nil, err
+	}
+	provider, err := cloudflareprovider.New(token)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func loadFlyProvider(flagToken, flagFile, flagFlyctl string) (reconcile.RoutingProvider, error) {
+	token, err := resolveToken("fly", flagToken, flagFile, "FLY_API_TOKEN", "FLY_API_TOKEN_FILE")
+	if err != nil || token == "" {
+		return nil, err
+	}
+	flyctl := strings.TrimSpace(flagFlyctl)
+	if flyctl == "" {
+		flyctl = strings.TrimSpace(os.Getenv("FLYCTL_PATH"))
+	}
+	provider, err := flyprovider.New(token, flyctl)
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// resolveToken picks a provider token from the flag, the environment, or a
+// token file (flag first, then env), returning "" when none is configured.
+func resolveToken(name, flagToken, flagFile, tokenEnv, fileEnv string) (string, error) {
 	token := strings.TrimSpace(flagToken)
 	if token == "" {
-		token = strings.TrimSpace(os.Getenv("CLOUDFLARE_API_TOKEN"))
+		token = strings.TrimSpace(os.Getenv(tokenEnv))
 	}
 	filePath := strings.TrimSpace(flagFile)
 	if filePath == "" {
-		filePath = strings.TrimSpace(os.Getenv("CLOUDFLARE_API_TOKEN_FILE"))
+		filePath = strings.TrimSpace(os.Getenv(fileEnv))
 	}
 	if token == "" && filePath != "" {
 		data, err := os.ReadFile(filePath)
 		if err != nil {
-			return nil, fmt.Errorf("read cloudflare token file %s: %w", filePath, err)
+			return "", fmt.Errorf("read %s token file %s: %w", name, filePath, err)
 		}
 		token = strings.TrimSpace(string(data))
 	}
-	if token == "" {
-		return nil, nil
-	}
-	provider, err := cloudflareprovider.New(token)
-	if err != nil {
-		return nil, err
-	}
-	return provider, nil
+	return token, nil
 }
@@ -29,6 +29,22 @@ func New(specPath string) (*Server, error) {
 	return &Server{specPath: specPath, state: state, watchers: make(map[chan struct{}]struct{})}, nil
 }
 
+// Reload re-reads the spec file and, if it is valid, replaces the desired
+// state and notifies watchers. An invalid file leaves the current state in
+// place and returns the same *spec.ValidationError that POST
+// /v1/spec/validate would report for it.
+func (s *Server) Reload() error {
+	state, err := controllerspec.LoadFile(s.specPath)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.state = state
+	s.mu.Unlock()
+	s.notifyWatchers()
+	return nil
+}
+
 // Close persists the state back to disk.
 func (s *Server) Close() error {
 	s.mu.RLock()
@@ -46,6 +62,8 @@ func (s *Server) Router() http.Handler {
 	mux.HandleFunc("/v1/services", s.handleListServices)
 	mux.HandleFunc("/v1/services/update", s.handleUpdateService)
 	mux.HandleFunc("/v1/events", s.handleEvents)
+	mux.HandleFunc("/v1/spec/validate", s.handleValidateSpec)
+	mux.HandleFunc("/v1/spec/diff", s.handleDiffSpec)
 	return mux
 }
 
@@ -0,0 +1,53 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	controllerspec "github.com/joeblew999/infra/core/controller/pkg/spec"
+)
+
+// maxSpecBytes bounds candidate specs submitted for validation or diffing.
+const maxSpecBytes = 1 << 20
+
+// ValidateResponse is returned by POST /v1/spec/validate, and by
+// POST /v1/spec/diff when the candidate is invalid.
+type ValidateResponse struct {
+	Valid  bool                   `json:"valid"`
+	Issues []controllerspec.Issue `json:"issues,omitempty"`
+}
+
+// handleValidateSpec checks a candidate spec (YAML or JSON) with the same
+// rules used when loading the spec file.
+func (s *Server) handleValidateSpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := controllerspec.Load(http.MaxBytesReader(w, r.Body, maxSpecBytes)); err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, ValidateResponse{Issues: controllerspec.Issues(err)})
+		return
+	}
+	writeJSON(w, http.StatusOK, ValidateResponse{Valid: true})
+}
+
+// handleDiffSpec reports what applying a candidate spec would add, remove
+// and change relative to the live desired state.
+func (s *Server) handleDiffSpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	candidate, err := controllerspec.Load(http.MaxBytesReader(w, r.Body, maxSpecBytes))
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, ValidateResponse{Issues: controllerspec.Issues(err)})
+		return
+	}
+	writeJSON(w, http.StatusOK, controllerspec.Compare(s.State(), candidate))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
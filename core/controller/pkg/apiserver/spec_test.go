@@ -0,0 +1,84 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	controllerspec "github.com/joeblew999/infra/core/controller/pkg/spec"
+)
+
+const liveSpec = `services:
+  - id: api
+    scale:
+      strategy: infra
+      regions:
+        - {name: iad, min: 1, desired: 1, max: 2}
+`
+
+func newTestServer(t *testing.T) (*Server, string) {
+	t.Helper()
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(liveSpec), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	server, err := New(specPath)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return server, specPath
+}
+
+func TestValidateSpecMatchesReload(t *testing.T) {
+	server, specPath := newTestServer(t)
+	invalid := `services:
+  - id: api
+    scale:
+      regions:
+        - {name: iad, min: 3, desired: 1, max: 2}
+`
+	req := httptest.NewRequest(http.MethodPost, "/v1/spec/validate", strings.NewReader(invalid))
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	var resp ValidateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if err := os.WriteFile(specPath, []byte(invalid), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	reloadErr := server.Reload()
+	if !reflect.DeepEqual(resp.Issues, controllerspec.Issues(reloadErr)) {
+		t.Fatalf("API issues %+v differ from reload issues %+v", resp.Issues, controllerspec.Issues(reloadErr))
+	}
+	if server.State().Services[0].Scale.Regions[0].Min != 1 {
+		t.Fatal("invalid reload replaced the live state")
+	}
+}
+
+func TestDiffSpec(t *testing.T) {
+	server, _ := newTestServer(t)
+	candidate := `{"services":[{"id":"web","scale":{"strategy":"infra","regions":[{"name":"syd","min":1,"desired":1,"max":1}]}}]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/spec/diff", strings.NewReader(candidate))
+	w := httptest.NewRecorder()
+	server.Router().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", w.Code, w.Body)
+	}
+	var diff controllerspec.Diff
+	if err := json.NewDecoder(w.Body).Decode(&diff); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(diff.Added, []string{"web"}) || !reflect.DeepEqual(diff.Removed, []string{"api"}) {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+}
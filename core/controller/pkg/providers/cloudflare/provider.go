@@ -31,6 +31,9 @@ func New(token string) (*Provider, error) {
 	return &Provider{api: api}, nil
 }
 
+// Name satisfies reconcile.Named.
+func (p *Provider) Name() string { return "cloudflare" }
+
 // EnsureRouting satisfies reconcile.RoutingProvider.
 func (p *Provider) EnsureRouting(ctx context.Context, svc controllerspec.Service, runtime reconcile.ServiceRuntimeState) error {
 	if !strings.EqualFold(svc.Routing.Provider, "cloudflare") {
@@ -82,6 +85,7 @@ func (p *Provider) EnsureRouting(ctx context.Context, svc controllerspec.Service
 			if err != nil {
 				return fmt.Errorf("cloudflare: create %s %s: %w", record.Type, fqdn, err)
 			}
+			reconcile.RecordChange(ctx)
 			log.Printf("[cloudflare] created %s %s -> %s", record.Type, fqdn, desiredContent)
 			continue
 		}
@@ -102,6 +106,7 @@ func (p *Provider) EnsureRouting(ctx context.Context, svc controllerspec.Service
 		if err != nil {
 			return fmt.Errorf("cloudflare: update %s %s: %w", record.Type, fqdn, err)
 		}
+		reconcile.RecordChange(ctx)
 		log.Printf("[cloudflare] updated %s %s -> %s", record.Type, fqdn, desiredContent)
 	}
 	return nil
@@ -0,0 +1,178 @@
+package fly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/joeblew999/infra/core/controller/pkg/reconcile"
+	controllerspec "github.com/joeblew999/infra/core/controller/pkg/spec"
+)
+
+// runner executes flyctl and returns its stdout. Swapped out in tests.
+type runner func(ctx context.Context, env []string, bin string, args ...string) ([]byte, error)
+
+// Provider reconciles Fly.io edge routing for services configured with the fly
+// routing provider. Desired DNS records map to Fly TLS certificates on the
+// service's app, and the app is given public IPs when it has none. Like
+// pkg/fly, it drives flyctl rather than linking the Fly API client.
+type Provider struct {
+	flyctl string
+	token  string
+	run    runner
+}
+
+// New constructs a Fly routing provider. The token is passed to flyctl via
+// FLY_API_TOKEN; flyctl defaults to the binary found on PATH.
+func New(token, flyctl string) (*Provider, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("fly: token is required")
+	}
+	if strings.TrimSpace(flyctl) == "" {
+		flyctl = "flyctl"
+	}
+	return &Provider{flyctl: flyctl, token: token, run: execFlyctl}, nil
+}
+
+// Name satisfies reconcile.Named.
+func (p *Provider) Name() string { return "fly" }
+
+// EnsureRouting satisfies reconcile.RoutingProvider.
+func (p *Provider) EnsureRouting(ctx context.Context, svc controllerspec.Service, runtime reconcile.ServiceRuntimeState) error {
+	if !strings.EqualFold(svc.Routing.Provider, "fly") {
+		return nil
+	}
+	app := AppName(svc)
+
+	if err := p.ensureIPs(ctx, app); err != nil {
+		return err
+	}
+
+	hostnames := Hostnames(svc)
+	if len(hostnames) == 0 {
+		return nil
+	}
+	existing, err := p.listCertificates(ctx, app)
+	if err != nil {
+		return err
+	}
+	for _, host := range hostnames {
+		if _, ok := existing[strings.ToLower(host)]; ok {
+			continue
+		}
+		if _, err := p.call(ctx, "certs", "add", host, "--app", app); err != nil {
+			return fmt.Errorf("fly: add certificate %s to %s: %w", host, app, err)
+		}
+		reconcile.RecordChange(ctx)
+		log.Printf("[fly] app=%s added certificate %s regions=%v", app, host, runtime.Regions)
+	}
+	return nil
+}
+
+// AppName returns the Fly app backing a service. The app can be overridden via
+// routing.load_balancing.options.app; otherwise the service ID is used.
+func AppName(svc controllerspec.Service) string {
+	if app := strings.TrimSpace(svc.Routing.LoadBalancing.Options["app"]); app != "" {
+		return app
+	}
+	return svc.ID
+}
+
+// Hostnames expands the service DNS records into fully-qualified hostnames
+// relative to routing.zone, skipping incomplete records.
+func Hostnames(svc controllerspec.Service) []string {
+	zone := strings.TrimSuffix(strings.TrimSpace(svc.Routing.Zone), ".")
+	var hosts []string
+	seen := make(map[string]struct{})
+	for _, record := range svc.Routing.DNSRecords {
+		name := strings.TrimSuffix(strings.TrimSpace(record.Name), ".")
+		if name == "" {
+			log.Printf("[fly] service=%s skipping incomplete record: %+v", svc.ID, record)
+			continue
+		}
+		fqdn := name
+		if zone != "" && !strings.HasSuffix(strings.ToLower(fqdn), strings.ToLower(zone)) {
+			fqdn = fmt.Sprintf("%s.%s", name, zone)
+		}
+		key := strings.ToLower(fqdn)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		hosts = append(hosts, fqdn)
+	}
+	return hosts
+}
+
+func (p *Provider) ensureIPs(ctx context.Context, app string) error {
+	out, err := p.call(ctx, "ips", "list", "--app", app, "--json")
+	if err != nil {
+		return fmt.Errorf("fly: list ips for %s: %w", app, err)
+	}
+	var ips []struct {
+		Address string `json:"Address"`
+		Type    string `json:"Type"`
+	}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &ips); err != nil {
+			return fmt.Errorf("fly: decode ips for %s: %w", app, err)
+		}
+	}
+	if len(ips) > 0 {
+		return nil
+	}
+	if _, err := p.call(ctx, "ips", "allocate-v6", "--app", app); err != nil {
+		return fmt.Errorf("fly: allocate ipv6 for %s: %w", app, err)
+	}
+	if _, err := p.call(ctx, "ips", "allocate-v4", "--shared", "--app", app); err != nil {
+		return fmt.Errorf("fly: allocate shared ipv4 for %s: %w", app, err)
+	}
+	reconcile.RecordChange(ctx)
+	log.Printf("[fly] app=%s allocated public ips", app)
+	return nil
+}
+
+func (p *Provider) listCertificates(ctx context.Context, app string) (map[string]struct{}, error) {
+	out, err := p.call(ctx, "certs", "list", "--app", app, "--json")
+	if err != nil {
+		return nil, fmt.Errorf("fly: list certificates for %s: %w", app, err)
+	}
+	var certs []struct {
+		Hostname string `json:"Hostname"`
+	}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &certs); err != nil {
+			return nil, fmt.Errorf("fly: decode certificates for %s: %w", app, err)
+		}
+	}
+	existing := make(map[string]struct{}, len(certs))
+	for _, cert := range certs {
+		existing[strings.ToLower(cert.Hostname)] = struct{}{}
+	}
+	return existing, nil
+}
+
+func (p *Provider) call(ctx context.Context, args ...string) ([]byte, error) {
+	env := append(os.Environ(), "FLY_API_TOKEN="+p.token)
+	return p.run(ctx, env, p.flyctl, args...)
+}
+
+func execFlyctl(ctx context.Context, env []string, bin string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Env = env
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,81 @@
+package fly
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/joeblew999/infra/core/controller/pkg/reconcile"
+	controllerspec "github.com/joeblew999/infra/core/controller/pkg/spec"
+)
+
+func TestHostnames(t *testing.T) {
+	svc := controllerspec.Service{
+		ID: "pocketbase",
+		Routing: controllerspec.RoutingSpec{
+			Provider: "fly",
+			Zone:     "example.com",
+			DNSRecords: []controllerspec.DNSRecordSpec{
+				{Name: "pocketbase", Type: "CNAME"},
+				{Name: "api.example.com.", Type: "CNAME"},
+				{Name: "POCKETBASE", Type: "A"},
+				{Name: "", Type: "A"},
+			},
+		},
+	}
+	got := Hostnames(svc)
+	want := []string{"pocketbase.example.com", "api.example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Hostnames() = %v, want %v", got, want)
+	}
+}
+
+func TestEnsureRoutingAddsMissingCertificates(t *testing.T) {
+	var calls []string
+	p := &Provider{flyctl: "flyctl", token: "tok", run: func(ctx context.Context, env []string, bin string, args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch {
+		case args[0] == "ips" && args[1] == "list":
+			return []byte(`[{"Address":"2a09::1","Type":"v6"}]`), nil
+		case args[0] == "certs" && args[1] == "list":
+			return []byte(`[{"Hostname":"api.example.com"}]`), nil
+		}
+		return nil, nil
+	}}
+
+	svc := controllerspec.Service{
+		ID: "pocketbase",
+		Routing: controllerspec.RoutingSpec{
+			Provider: "fly",
+			Zone:     "example.com",
+			DNSRecords: []controllerspec.DNSRecordSpec{
+				{Name: "pocketbase", Type: "CNAME"},
+				{Name: "api", Type: "CNAME"},
+			},
+			LoadBalancing: controllerspec.LoadBalancing{Options: map[string]string{"app": "pb-prod"}},
+		},
+	}
+	if err := p.EnsureRouting(context.Background(), svc, reconcile.ServiceRuntimeState{}); err != nil {
+		t.Fatalf("EnsureRouting: %v", err)
+	}
+	want := []string{
+		"ips list --app pb-prod --json",
+		"certs list --app pb-prod --json",
+		"certs add pocketbase.example.com --app pb-prod",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestEnsureRoutingSkipsOtherProviders(t *testing.T) {
+	p := &Provider{run: func(ctx context.Context, env []string, bin string, args ...string) ([]byte, error) {
+		t.Fatalf("unexpected flyctl call: %v", args)
+		return nil, nil
+	}}
+	svc := controllerspec.Service{ID: "worker", Routing: controllerspec.RoutingSpec{Provider: "cloudflare"}}
+	if err := p.EnsureRouting(context.Background(), svc, reconcile.ServiceRuntimeState{}); err != nil {
+		t.Fatalf("EnsureRouting: %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package reconcile
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -24,6 +26,9 @@ type Options struct {
 	Tick     time.Duration
 	Machines MachinesProvider
 	Routing  RoutingProvider
+	// Metrics receives run, latency, error and change counts; New creates
+	// one when nil.
+	Metrics *Metrics
 }
 
 // Reconciler drives desired state towards infrastructure reality.
@@ -32,6 +37,7 @@ type Reconciler struct {
 	tick     time.Duration
 	machines MachinesProvider
 	routing  RoutingProvider
+	metrics  *Metrics
 }
 
 // New constructs a reconciler with the provided options.
@@ -48,11 +54,16 @@ func New(server *apiserver.Server, opts Options) *Reconciler {
 	if routing == nil {
 		routing = NullRouting{}
 	}
+	metrics := opts.Metrics
+	if metrics == nil {
+		metrics = NewMetrics()
+	}
 	return &Reconciler{
 		server:   server,
 		tick:     tick,
 		machines: machines,
 		routing:  routing,
+		metrics:  metrics,
 	}
 }
 
@@ -77,27 +88,97 @@ func (r *Reconciler) Run(ctx context.Context) {
 	}
 }
 
-func (r *Reconciler) reconcileOnce(ctx context.Context, reason string) {
+// ReconcileOnce runs a single pass over every service in the desired state
+// and reports the outcome per service. Run calls it on every tick; the
+// controller's --once mode calls it directly.
+func (r *Reconciler) ReconcileOnce(ctx context.Context) Summary {
+	return r.reconcileOnce(ctx, "once")
+}
+
+func (r *Reconciler) reconcileOnce(ctx context.Context, reason string) Summary {
 	desired := r.server.State()
 	log.Printf("reconcile (%s): services=%d", reason, len(desired.Services))
+	r.metrics.runs.WithLabelValues(reason).Inc()
 
+	summary := Summary{Services: make([]ServiceResult, 0, len(desired.Services))}
 	for _, svc := range desired.Services {
-		if err := r.reconcileService(ctx, svc); err != nil {
+		runtime, err := r.reconcileService(ctx, svc)
+		if err != nil {
 			log.Printf("  service %s error: %v", svc.ID, err)
 		}
+		summary.Services = append(summary.Services, ServiceResult{ID: svc.ID, Regions: runtime.Regions, Err: err})
 	}
+	return summary
 }
 
-func (r *Reconciler) reconcileService(ctx context.Context, svc controllerspec.Service) error {
-	runtime, err := r.machines.EnsureMachines(ctx, svc)
-	if err != nil {
+func (r *Reconciler) reconcileService(ctx context.Context, svc controllerspec.Service) (ServiceRuntimeState, error) {
+	var runtime ServiceRuntimeState
+	err := r.metrics.observe(ctx, providerName(r.machines), func(ctx context.Context) error {
+		var err error
+		runtime, err = r.machines.EnsureMachines(ctx, svc)
 		return err
+	})
+	if err != nil {
+		return runtime, err
 	}
-	if err := r.routing.EnsureRouting(ctx, svc, runtime); err != nil {
-		return err
+
+	// Observe MultiRouting members one by one so each gets its own metrics.
+	routers, ok := r.routing.(MultiRouting)
+	if !ok {
+		routers = MultiRouting{r.routing}
+	}
+	err = routers.each(func(provider RoutingProvider) error {
+		return r.metrics.observe(ctx, providerName(provider), func(ctx context.Context) error {
+			return provider.EnsureRouting(ctx, svc, runtime)
+		})
+	})
+	if err != nil {
+		return runtime, err
 	}
 	log.Printf("  service %s reconciled regions=%v", svc.ID, runtime.Regions)
-	return nil
+	return runtime, nil
+}
+
+func providerName(provider any) string {
+	if named, ok := provider.(Named); ok {
+		return named.Name()
+	}
+	return fmt.Sprintf("%T", provider)
+}
+
+// Summary reports the outcome of one reconcile pass.
+type Summary struct {
+	Services []ServiceResult
+}
+
+// ServiceResult is the outcome for one service; Err is nil when every
+// provider succeeded.
+type ServiceResult struct {
+	ID      string
+	Regions map[string]int
+	Err     error
+}
+
+// Failed counts the services that reported an error.
+func (s Summary) Failed() int {
+	failed := 0
+	for _, svc := range s.Services {
+		if svc.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Err joins the errors of every failed service, or returns nil.
+func (s Summary) Err() error {
+	var errs []error
+	for _, svc := range s.Services {
+		if svc.Err != nil {
+			errs = append(errs, fmt.Errorf("service %s: %w", svc.ID, svc.Err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // ServiceRuntimeState reflects current infrastructure for a service.
@@ -0,0 +1,98 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/joeblew999/infra/core/controller/pkg/apiserver"
+	controllerspec "github.com/joeblew999/infra/core/controller/pkg/spec"
+)
+
+const twoServices = `services:
+  - id: api
+    scale:
+      strategy: infra
+      regions:
+        - {name: iad, min: 1, desired: 1, max: 2}
+  - id: web
+    scale:
+      strategy: infra
+      regions:
+        - {name: syd, min: 1, desired: 2, max: 3}
+`
+
+type failingRouting struct{ service string }
+
+func (f failingRouting) EnsureRouting(ctx context.Context, svc controllerspec.Service, runtime ServiceRuntimeState) error {
+	if svc.ID == f.service {
+		return errors.New("dns update rejected")
+	}
+	return nil
+}
+
+func TestReconcileOnceReportsEachService(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(twoServices), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	server, err := apiserver.New(specPath)
+	if err != nil {
+		t.Fatalf("apiserver.New: %v", err)
+	}
+
+	summary := New(server, Options{Routing: failingRouting{service: "web"}}).ReconcileOnce(context.Background())
+	if len(summary.Services) != 2 || summary.Failed() != 1 {
+		t.Fatalf("expected 2 services with 1 failure, got %+v", summary)
+	}
+	if summary.Services[0].Err != nil || summary.Services[0].Regions["iad"] != 1 {
+		t.Fatalf("unexpected result for api: %+v", summary.Services[0])
+	}
+	if err := summary.Err(); err == nil || summary.Services[1].Err == nil {
+		t.Fatalf("expected web to fail, got %v", err)
+	}
+}
+
+type changingRouting struct{}
+
+func (changingRouting) Name() string { return "dns" }
+
+func (changingRouting) EnsureRouting(ctx context.Context, svc controllerspec.Service, runtime ServiceRuntimeState) error {
+	RecordChange(ctx)
+	return nil
+}
+
+func TestReconcileRecordsProviderMetrics(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specPath, []byte(twoServices), 0o644); err != nil {
+		t.Fatalf("write spec: %v", err)
+	}
+	server, err := apiserver.New(specPath)
+	if err != nil {
+		t.Fatalf("apiserver.New: %v", err)
+	}
+
+	metrics := NewMetrics()
+	New(server, Options{
+		Routing: MultiRouting{changingRouting{}, failingRouting{service: "web"}},
+		Metrics: metrics,
+	}).ReconcileOnce(context.Background())
+
+	if got := testutil.ToFloat64(metrics.runs.WithLabelValues("once")); got != 1 {
+		t.Fatalf("reconcile_runs_total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.changes.WithLabelValues("dns")); got != 2 {
+		t.Fatalf("reconcile_changes_total{provider=dns} = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(metrics.errors.WithLabelValues("reconcile.failingRouting")); got != 1 {
+		t.Fatalf("reconcile_errors_total{provider=reconcile.failingRouting} = %v, want 1", got)
+	}
+	// machines, dns and failingRouting each observed once per service
+	if got := testutil.CollectAndCount(metrics.duration); got != 3 {
+		t.Fatalf("reconcile_duration_seconds series = %d, want 3", got)
+	}
+}
@@ -0,0 +1,34 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+
+	controllerspec "github.com/joeblew999/infra/core/controller/pkg/spec"
+)
+
+// MultiRouting fans a routing request out to every configured provider. Each
+// provider decides whether a service applies to it (via routing.provider), so
+// Cloudflare and Fly can be loaded side by side.
+type MultiRouting []RoutingProvider
+
+// EnsureRouting calls every provider and aggregates their errors.
+func (m MultiRouting) EnsureRouting(ctx context.Context, svc controllerspec.Service, runtime ServiceRuntimeState) error {
+	return m.each(func(provider RoutingProvider) error {
+		return provider.EnsureRouting(ctx, svc, runtime)
+	})
+}
+
+// each calls fn for every non-nil provider and joins the errors.
+func (m MultiRouting) each(fn func(RoutingProvider) error) error {
+	var errs []error
+	for _, provider := range m {
+		if provider == nil {
+			continue
+		}
+		if err := fn(provider); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,35 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	controllerspec "github.com/joeblew999/infra/core/controller/pkg/spec"
+)
+
+type recordingRouting struct {
+	calls int
+	err   error
+}
+
+func (r *recordingRouting) EnsureRouting(ctx context.Context, svc controllerspec.Service, runtime ServiceRuntimeState) error {
+	r.calls++
+	return r.err
+}
+
+func TestMultiRoutingAggregatesErrors(t *testing.T) {
+	errA := errors.New("cloudflare down")
+	errB := errors.New("fly down")
+	a := &recordingRouting{err: errA}
+	ok := &recordingRouting{}
+	b := &recordingRouting{err: errB}
+
+	err := MultiRouting{a, nil, ok, b}.EnsureRouting(context.Background(), controllerspec.Service{ID: "svc"}, ServiceRuntimeState{})
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("expected aggregated errors, got %v", err)
+	}
+	if a.calls != 1 || ok.calls != 1 || b.calls != 1 {
+		t.Fatalf("expected every provider to be called once, got %d/%d/%d", a.calls, ok.calls, b.calls)
+	}
+}
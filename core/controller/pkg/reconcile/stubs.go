@@ -10,6 +10,9 @@ import (
 // NullMachines is a no-op MachinesProvider used until real integration is wired.
 type NullMachines struct{}
 
+// Name implements Named.
+func (NullMachines) Name() string { return "null-machines" }
+
 // EnsureMachines simply echoes desired counts for observability.
 func (NullMachines) EnsureMachines(ctx context.Context, svc controllerspec.Service) (ServiceRuntimeState, error) {
 	runtime := ServiceRuntimeState{Regions: make(map[string]int, len(svc.Scale.Regions))}
@@ -28,6 +31,9 @@ func (NullMachines) EnsureMachines(ctx context.Context, svc controllerspec.Servi
 // NullRouting is a no-op RoutingProvider used until Cloudflare integration is ready.
 type NullRouting struct{}
 
+// Name implements Named.
+func (NullRouting) Name() string { return "null-routing" }
+
 // EnsureRouting logs routing intent without performing changes.
 func (NullRouting) EnsureRouting(ctx context.Context, svc controllerspec.Service, runtime ServiceRuntimeState) error {
 	select {
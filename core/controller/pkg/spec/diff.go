@@ -0,0 +1,70 @@
+package spec
+
+import "reflect"
+
+// Diff is the change set between two desired states, keyed by service ID.
+type Diff struct {
+	Added   []string        `json:"added"`
+	Removed []string        `json:"removed"`
+	Changed []ServiceChange `json:"changed"`
+}
+
+// ServiceChange names the top-level fields of a service that differ.
+type ServiceChange struct {
+	Service string   `json:"service"`
+	Fields  []string `json:"fields"`
+}
+
+// Empty reports whether the two states are identical.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns what applying next would add, remove and change relative
+// to current. Services keep the order they have in the respective state.
+func Compare(current, next DesiredState) Diff {
+	diff := Diff{Added: []string{}, Removed: []string{}, Changed: []ServiceChange{}}
+
+	before := make(map[string]Service, len(current.Services))
+	for _, svc := range current.Services {
+		before[svc.ID] = svc
+	}
+	after := make(map[string]struct{}, len(next.Services))
+	for _, svc := range next.Services {
+		after[svc.ID] = struct{}{}
+		old, ok := before[svc.ID]
+		if !ok {
+			diff.Added = append(diff.Added, svc.ID)
+			continue
+		}
+		if fields := changedFields(old, svc); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ServiceChange{Service: svc.ID, Fields: fields})
+		}
+	}
+	for _, svc := range current.Services {
+		if _, ok := after[svc.ID]; !ok {
+			diff.Removed = append(diff.Removed, svc.ID)
+		}
+	}
+	return diff
+}
+
+func changedFields(a, b Service) []string {
+	var fields []string
+	if a.DisplayName != b.DisplayName {
+		fields = append(fields, "displayName")
+	}
+	if a.Description != b.Description {
+		fields = append(fields, "description")
+	}
+	if !reflect.DeepEqual(a.Scale, b.Scale) {
+		fields = append(fields, "scale")
+	}
+	if !reflect.DeepEqual(a.Storage, b.Storage) {
+		fields = append(fields, "storage")
+	}
+	if !reflect.DeepEqual(a.Routing, b.Routing) {
+		fields = append(fields, "routing")
+	}
+	return fields
+}
@@ -152,34 +152,44 @@ func LoadServiceFile(path string) (Service, error) {
 	return Service{}, fmt.Errorf("could not parse service definition in %s", path)
 }
 
-// Validate ensures the desired state is well-formed.
+// Validate ensures the desired state is well-formed. It reports every problem
+// at once as a *ValidationError, so an invalid spec file and an invalid API
+// submission produce the same issues.
 func (d DesiredState) Validate() error {
+	var issues []Issue
+	add := func(path, service, format string, args ...any) {
+		issues = append(issues, Issue{Path: path, Service: service, Message: fmt.Sprintf(format, args...)})
+	}
+
 	ids := make(map[string]struct{}, len(d.Services))
-	for _, svc := range d.Services {
+	for i, svc := range d.Services {
+		path := fmt.Sprintf("services[%d]", i)
 		if svc.ID == "" {
-			return fmt.Errorf("service id is required")
-		}
-		if _, exists := ids[svc.ID]; exists {
-			return fmt.Errorf("service id %s defined multiple times", svc.ID)
+			add(path+".id", "", "service id is required")
+		} else if _, exists := ids[svc.ID]; exists {
+			add(path+".id", svc.ID, "service id %s defined multiple times", svc.ID)
 		}
 		ids[svc.ID] = struct{}{}
 		if len(svc.Scale.Regions) == 0 {
-			return fmt.Errorf("service %s must define at least one region", svc.ID)
+			add(path+".scale.regions", svc.ID, "service %s must define at least one region", svc.ID)
 		}
-		for _, region := range svc.Scale.Regions {
+		for j, region := range svc.Scale.Regions {
+			regionPath := fmt.Sprintf("%s.scale.regions[%d]", path, j)
 			if region.Name == "" {
-				return fmt.Errorf("service %s has a region with empty name", svc.ID)
+				add(regionPath+".name", svc.ID, "service %s has a region with empty name", svc.ID)
 			}
-			if region.Min < 0 || region.Desired < 0 || region.Max < 0 {
-				return fmt.Errorf("service %s region %s has negative replica counts", svc.ID, region.Name)
-			}
-			if region.Min > region.Desired {
-				return fmt.Errorf("service %s region %s has min > desired", svc.ID, region.Name)
-			}
-			if region.Desired > region.Max {
-				return fmt.Errorf("service %s region %s has desired > max", svc.ID, region.Name)
+			switch {
+			case region.Min < 0 || region.Desired < 0 || region.Max < 0:
+				add(regionPath, svc.ID, "service %s region %s has negative replica counts", svc.ID, region.Name)
+			case region.Min > region.Desired:
+				add(regionPath+".min", svc.ID, "service %s region %s has min > desired", svc.ID, region.Name)
+			case region.Desired > region.Max:
+				add(regionPath+".desired", svc.ID, "service %s region %s has desired > max", svc.ID, region.Name)
 			}
 		}
 	}
+	if len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
 	return nil
 }
@@ -171,3 +171,44 @@ func TestDesiredStateValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateReportsEveryIssue(t *testing.T) {
+	state := DesiredState{Services: []Service{
+		{ID: "", Scale: ScaleSpec{Regions: []RegionScaleSpec{{Name: "iad", Min: 1, Desired: 1, Max: 1}}}},
+		{ID: "svc", Scale: ScaleSpec{Regions: []RegionScaleSpec{{Name: "iad", Min: 1, Desired: 3, Max: 2}}}},
+	}}
+	issues := Issues(state.Validate())
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %+v", issues)
+	}
+	if issues[0].Path != "services[0].id" || issues[1].Path != "services[1].scale.regions[0].desired" {
+		t.Fatalf("unexpected issue paths: %+v", issues)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	region := func(desired int) ScaleSpec {
+		return ScaleSpec{Regions: []RegionScaleSpec{{Name: "iad", Min: 1, Desired: desired, Max: 5}}}
+	}
+	current := DesiredState{Services: []Service{
+		{ID: "api", Scale: region(1)},
+		{ID: "old", Scale: region(1)},
+		{ID: "web", Scale: region(1)},
+	}}
+	next := DesiredState{Services: []Service{
+		{ID: "api", Scale: region(2), Description: "scaled"},
+		{ID: "web", Scale: region(1)},
+		{ID: "new", Scale: region(1)},
+	}}
+
+	diff := Compare(current, next)
+	if strings.Join(diff.Added, ",") != "new" || strings.Join(diff.Removed, ",") != "old" {
+		t.Fatalf("unexpected added/removed: %+v", diff)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Service != "api" || strings.Join(diff.Changed[0].Fields, ",") != "description,scale" {
+		t.Fatalf("unexpected changes: %+v", diff.Changed)
+	}
+	if !Compare(next, next).Empty() {
+		t.Fatal("expected no diff between identical states")
+	}
+}
@@ -0,0 +1,40 @@
+package spec
+
+import (
+	"errors"
+	"strings"
+)
+
+// Issue is one problem found while validating a desired state. Path locates
+// the offending field, e.g. "services[1].scale.regions[0].min".
+type Issue struct {
+	Path    string `json:"path"`
+	Service string `json:"service,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every issue found in a desired state.
+type ValidationError struct {
+	Issues []Issue `json:"issues"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Issues returns the issues behind err: those of a *ValidationError, or a
+// single issue without a path for anything else, such as a YAML syntax error.
+func Issues(err error) []Issue {
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr.Issues
+	}
+	return []Issue{{Message: err.Error()}}
+}
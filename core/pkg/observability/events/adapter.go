@@ -9,6 +9,7 @@ import (
 
 	"github.com/joeblew999/infra/core/pkg/runtime/process"
 	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
 )
 
@@ -17,6 +18,10 @@ type Adapter struct {
 	composePort  int
 	natsURL      string
 	pollInterval time.Duration
+	retention    time.Duration
+	debounce     *debouncer
+	retryCfg     Retry
+	retry        *retryQueue
 	nc           *nats.Conn
 	js           nats.JetStreamContext
 	lastStates   map[string]process.ComposeProcessState
@@ -30,8 +35,14 @@ type Config struct {
 	ComposePort  int           // Port for process-compose API (default: 28081)
 	NATSURL      string        // NATS server URL (default: nats://127.0.0.1:4222)
 	PollInterval time.Duration // How often to poll for state changes (default: 2s)
+	Retention    time.Duration // How long the stream retains events for replay (default: 24h)
+	Debounce     Debounce      // Collapse flapping processes into one unstable event (disabled when Window is 0)
+	Retry        Retry         // Buffer and retry events that fail to publish, e.g. across NATS restarts
 }
 
+// StreamName is the JetStream stream process events are published into.
+const StreamName = "PROCESS_EVENTS"
+
 // NewAdapter creates a new event adapter.
 func NewAdapter(cfg Config) (*Adapter, error) {
 	if cfg.ComposePort == 0 {
@@ -43,17 +54,25 @@ func NewAdapter(cfg Config) (*Adapter, error) {
 	if cfg.PollInterval == 0 {
 		cfg.PollInterval = 2 * time.Second
 	}
+	if cfg.Retention == 0 {
+		cfg.Retention = 24 * time.Hour
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Adapter{
+	a := &Adapter{
 		composePort:  cfg.ComposePort,
 		natsURL:      cfg.NATSURL,
 		pollInterval: cfg.PollInterval,
+		retention:    cfg.Retention,
+		debounce:     newDebouncer(cfg.Debounce),
+		retryCfg:     cfg.Retry.withDefaults(),
 		lastStates:   make(map[string]process.ComposeProcessState),
 		ctx:          ctx,
 		cancel:       cancel,
-	}, nil
+	}
+	a.retry = newRetryQueue(a.retryCfg, a.sendEvent, a.deadLetterEvent)
+	return a, nil
 }
 
 // Start connects to NATS and begins polling process-compose.
@@ -87,17 +106,25 @@ func (a *Adapter) Start() error {
 		Str("nats_url", a.natsURL).
 		Int("compose_port", a.composePort).
 		Dur("poll_interval", a.pollInterval).
+		Dur("retention", a.retention).
+		Int("retry_buffer", a.retryCfg.BufferSize).
 		Msg("Event adapter started")
 
-	// Start polling in background
+	// Start polling and retrying in background
+	go a.retry.run(a.ctx)
 	go a.pollLoop()
 
 	return nil
 }
 
-// Stop gracefully stops the adapter.
+// Stop gracefully stops the adapter. Events still waiting for a retry are
+// dead-lettered rather than lost.
 func (a *Adapter) Stop() error {
 	a.cancel()
+	if n := a.retry.len(); n > 0 {
+		log.Warn().Int("events", n).Msg("Dead-lettering buffered events on shutdown")
+		a.retry.drain()
+	}
 	if a.nc != nil {
 		a.nc.Close()
 	}
@@ -105,31 +132,45 @@ func (a *Adapter) Stop() error {
 	return nil
 }
 
-// ensureStream creates the NATS JetStream stream for process events if it doesn't exist.
+// Collectors returns the adapter's Prometheus metrics: events buffered for
+// retry, dropped from a full buffer and dead-lettered. Register them with
+// e.g. MetricsExporter.Registry().
+func (a *Adapter) Collectors() []prometheus.Collector {
+	return a.retry.collectors()
+}
+
+// ensureStream creates the NATS JetStream stream for process events if it
+// doesn't exist, and realigns its retention window when it does.
 func (a *Adapter) ensureStream() error {
-	streamName := "PROCESS_EVENTS"
+	cfg := &nats.StreamConfig{
+		Name:        StreamName,
+		Description: "Process lifecycle and health events from process-compose",
+		Subjects:    []string{"core.process.>"},
+		Retention:   nats.LimitsPolicy,
+		MaxAge:      a.retention, // Retained events can be replayed by late watchers
+		Storage:     nats.FileStorage,
+		Replicas:    1,
+	}
 
 	// Check if stream exists
-	_, err := a.js.StreamInfo(streamName)
+	info, err := a.js.StreamInfo(StreamName)
 	if err == nil {
-		return nil // Stream already exists
+		if info.Config.MaxAge == a.retention {
+			return nil // Stream already exists
+		}
+		if _, err := a.js.UpdateStream(cfg); err != nil {
+			return fmt.Errorf("update stream retention: %w", err)
+		}
+		log.Info().Str("stream", StreamName).Dur("retention", a.retention).Msg("Updated JetStream stream retention")
+		return nil
 	}
 
 	// Create stream
-	_, err = a.js.AddStream(&nats.StreamConfig{
-		Name:        streamName,
-		Description: "Process lifecycle and health events from process-compose",
-		Subjects:    []string{"core.process.>"},
-		Retention:   nats.LimitsPolicy,
-		MaxAge:      24 * time.Hour, // Retain events for 24 hours
-		Storage:     nats.FileStorage,
-		Replicas:    1,
-	})
-	if err != nil {
+	if _, err := a.js.AddStream(cfg); err != nil {
 		return fmt.Errorf("create stream: %w", err)
 	}
 
-	log.Info().Str("stream", streamName).Msg("Created JetStream stream")
+	log.Info().Str("stream", StreamName).Msg("Created JetStream stream")
 	return nil
 }
 
@@ -163,6 +204,14 @@ func (a *Adapter) poll() {
 		return
 	}
 
+	for _, evt := range a.observe(states, time.Now()) {
+		a.publishEvent(evt)
+	}
+}
+
+// observe diffs a poll snapshot against the last seen states and returns the
+// events to publish, after deduplication and debouncing.
+func (a *Adapter) observe(states []process.ComposeProcessState, now time.Time) []Event {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -173,49 +222,58 @@ func (a *Adapter) poll() {
 		currentStates[key] = state
 	}
 
+	var events []Event
+
 	// Detect changes
 	for key, current := range currentStates {
 		last, existed := a.lastStates[key]
 
 		if !existed {
 			// New process detected
-			a.publishEvent(Event{
+			events = append(events, Event{
 				Type:      EventTypeStarted,
 				Process:   current.Name,
 				Namespace: current.Namespace,
-				Timestamp: time.Now(),
+				Timestamp: now,
 				State:     current,
 			})
 		} else {
 			// Check for state transitions
-			a.detectTransitions(last, current)
+			events = append(events, a.detectTransitions(last, current, now)...)
 		}
 	}
 
 	// Detect removed processes
 	for key, last := range a.lastStates {
 		if _, exists := currentStates[key]; !exists {
-			a.publishEvent(Event{
+			events = append(events, Event{
 				Type:      EventTypeStopped,
 				Process:   last.Name,
 				Namespace: last.Namespace,
-				Timestamp: time.Now(),
+				Timestamp: now,
 				State:     last,
+				Removed:   true,
 			})
 		}
 	}
 
 	// Update last states
 	a.lastStates = currentStates
+
+	events = dedupEvents(events)
+	if a.debounce != nil {
+		events = a.debounce.filter(events, currentStates, now)
+	}
+	return events
 }
 
-// detectTransitions identifies state changes and publishes events.
-func (a *Adapter) detectTransitions(last, current process.ComposeProcessState) {
-	now := time.Now()
+// detectTransitions identifies state changes between two snapshots.
+func (a *Adapter) detectTransitions(last, current process.ComposeProcessState, now time.Time) []Event {
+	var events []Event
 
 	// Running state changed
 	if !last.IsRunning && current.IsRunning {
-		a.publishEvent(Event{
+		events = append(events, Event{
 			Type:      EventTypeStarted,
 			Process:   current.Name,
 			Namespace: current.Namespace,
@@ -228,19 +286,20 @@ func (a *Adapter) detectTransitions(last, current process.ComposeProcessState) {
 		if current.ExitCode != 0 {
 			eventType = EventTypeCrashed
 		}
-		a.publishEvent(Event{
+		exitCode := current.ExitCode
+		events = append(events, Event{
 			Type:      eventType,
 			Process:   current.Name,
 			Namespace: current.Namespace,
 			Timestamp: now,
 			State:     current,
-			ExitCode:  &current.ExitCode,
+			ExitCode:  &exitCode,
 		})
 	}
 
 	// Restart count changed
 	if current.Restarts > last.Restarts {
-		a.publishEvent(Event{
+		events = append(events, Event{
 			Type:      EventTypeRestarted,
 			Process:   current.Name,
 			Namespace: current.Namespace,
@@ -256,7 +315,7 @@ func (a *Adapter) detectTransitions(last, current process.ComposeProcessState) {
 		if current.Health != "Ready" {
 			eventType = EventTypeUnhealthy
 		}
-		a.publishEvent(Event{
+		events = append(events, Event{
 			Type:      eventType,
 			Process:   current.Name,
 			Namespace: current.Namespace,
@@ -268,7 +327,7 @@ func (a *Adapter) detectTransitions(last, current process.ComposeProcessState) {
 
 	// Status changed
 	if last.Status != current.Status {
-		a.publishEvent(Event{
+		events = append(events, Event{
 			Type:      EventTypeStatusChanged,
 			Process:   current.Name,
 			Namespace: current.Namespace,
@@ -278,21 +337,28 @@ func (a *Adapter) detectTransitions(last, current process.ComposeProcessState) {
 			NewStatus: current.Status,
 		})
 	}
+
+	return events
 }
 
-// publishEvent publishes an event to NATS JetStream.
+// publishEvent publishes an event to NATS JetStream, buffering it for retry
+// when the publish fails.
 func (a *Adapter) publishEvent(evt Event) {
+	a.retry.publish(evt)
+}
+
+// sendEvent makes a single publish attempt for an event.
+func (a *Adapter) sendEvent(evt Event) error {
 	subject := evt.Subject()
 	data, err := json.Marshal(evt)
 	if err != nil {
+		// Retrying can't fix an encoding error; log it and move on.
 		log.Error().Err(err).Str("event_type", string(evt.Type)).Msg("Failed to marshal event")
-		return
+		return nil
 	}
 
-	_, err = a.js.Publish(subject, data)
-	if err != nil {
-		log.Error().Err(err).Str("subject", subject).Msg("Failed to publish event")
-		return
+	if _, err := a.js.Publish(subject, data); err != nil {
+		return fmt.Errorf("publish %s: %w", subject, err)
 	}
 
 	log.Debug().
@@ -300,6 +366,7 @@ func (a *Adapter) publishEvent(evt Event) {
 		Str("event_type", string(evt.Type)).
 		Str("process", evt.Process).
 		Msg("Published event")
+	return nil
 }
 
 // processKey generates a unique key for a process state.
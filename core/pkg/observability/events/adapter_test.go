@@ -0,0 +1,114 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/infra/core/pkg/runtime/process"
+)
+
+func running(name string, restarts int) process.ComposeProcessState {
+	return process.ComposeProcessState{Name: name, Status: "Running", IsRunning: true, Restarts: restarts}
+}
+
+func crashed(name string, restarts int) process.ComposeProcessState {
+	return process.ComposeProcessState{Name: name, Status: "Completed", ExitCode: 1, Restarts: restarts}
+}
+
+func eventTypes(events []Event) []EventType {
+	types := make([]EventType, 0, len(events))
+	for _, evt := range events {
+		types = append(types, evt.Type)
+	}
+	return types
+}
+
+func newTestAdapter(t *testing.T, cfg Config) *Adapter {
+	t.Helper()
+	a, err := NewAdapter(cfg)
+	if err != nil {
+		t.Fatalf("NewAdapter: %v", err)
+	}
+	t.Cleanup(func() { a.cancel() })
+	return a
+}
+
+func TestObserveEmitsOnlyOnTransitions(t *testing.T) {
+	a := newTestAdapter(t, Config{})
+	start := time.Unix(0, 0)
+
+	steps := []struct {
+		states []process.ComposeProcessState
+		want   []EventType
+	}{
+		{states: []process.ComposeProcessState{running("nats", 0)}, want: []EventType{EventTypeStarted}},
+		{states: []process.ComposeProcessState{running("nats", 0)}, want: []EventType{}},
+		{states: []process.ComposeProcessState{crashed("nats", 0)}, want: []EventType{EventTypeCrashed, EventTypeStatusChanged}},
+		{states: []process.ComposeProcessState{crashed("nats", 0)}, want: []EventType{}},
+		{states: []process.ComposeProcessState{running("nats", 1)}, want: []EventType{EventTypeStarted, EventTypeRestarted, EventTypeStatusChanged}},
+		{states: nil, want: []EventType{EventTypeStopped}},
+	}
+
+	for i, step := range steps {
+		got := eventTypes(a.observe(step.states, start.Add(time.Duration(i)*2*time.Second)))
+		if !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("step %d: events = %v, want %v", i, got, step.want)
+		}
+	}
+}
+
+func TestObserveDebouncesFlappingProcess(t *testing.T) {
+	a := newTestAdapter(t, Config{Debounce: Debounce{Window: 10 * time.Second, Threshold: 3}})
+	start := time.Unix(0, 0)
+	at := func(sec int) time.Time { return start.Add(time.Duration(sec) * time.Second) }
+
+	steps := []struct {
+		at     time.Time
+		states []process.ComposeProcessState
+		want   []EventType
+	}{
+		// Initial start counts as the first transition.
+		{at: at(0), states: []process.ComposeProcessState{running("pb", 0)}, want: []EventType{EventTypeStarted}},
+		// Crash is the second transition and still passes through.
+		{at: at(2), states: []process.ComposeProcessState{crashed("pb", 0)}, want: []EventType{EventTypeCrashed, EventTypeStatusChanged}},
+		// Restart pushes it over the threshold: a single unstable event.
+		{at: at(4), states: []process.ComposeProcessState{running("pb", 1)}, want: []EventType{EventTypeUnstable}},
+		// Further flapping is suppressed entirely.
+		{at: at(6), states: []process.ComposeProcessState{crashed("pb", 1)}, want: []EventType{}},
+		{at: at(8), states: []process.ComposeProcessState{running("pb", 2)}, want: []EventType{}},
+		// Still inside the window since the last transition.
+		{at: at(14), states: []process.ComposeProcessState{running("pb", 2)}, want: []EventType{}},
+		// Quiet for a full window: the settled state is reported once.
+		{at: at(20), states: []process.ComposeProcessState{running("pb", 2)}, want: []EventType{EventTypeStable}},
+		// Back to normal transition reporting.
+		{at: at(22), states: []process.ComposeProcessState{crashed("pb", 2)}, want: []EventType{EventTypeCrashed, EventTypeStatusChanged}},
+	}
+
+	for i, step := range steps {
+		events := a.observe(step.states, step.at)
+		got := eventTypes(events)
+		if !reflect.DeepEqual(got, step.want) {
+			t.Fatalf("step %d: events = %v, want %v", i, got, step.want)
+		}
+		for _, evt := range events {
+			if evt.Type == EventTypeUnstable && evt.Transitions != 3 {
+				t.Fatalf("step %d: unstable transitions = %d, want 3", i, evt.Transitions)
+			}
+		}
+	}
+}
+
+func TestDedupEventsKeepsFirstPerProcessAndType(t *testing.T) {
+	events := []Event{
+		{Type: EventTypeStarted, Process: "nats"},
+		{Type: EventTypeStarted, Process: "nats"},
+		{Type: EventTypeStarted, Process: "nats", Namespace: "edge"},
+		{Type: EventTypeRestarted, Process: "nats"},
+	}
+	got := eventTypes(dedupEvents(events))
+	want := []EventType{EventTypeStarted, EventTypeStarted, EventTypeRestarted}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("dedupEvents = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ExecAlerterConfig configures an ExecAlerter.
+type ExecAlerterConfig struct {
+	Command     string        // Shell command to run for each event (event JSON on stdin)
+	MinInterval time.Duration // Minimum spacing between invocations (default: 1s)
+	Timeout     time.Duration // Per-invocation timeout (default: 30s)
+	QueueSize   int           // Pending events before new ones are dropped (default: 16)
+}
+
+// ExecAlerter runs a shell command for events, passing the event JSON on stdin.
+// Invocations happen on a single background worker, spaced at least
+// MinInterval apart. Notify never blocks: when the queue is full the event is
+// dropped, so a slow alert handler cannot stall the event stream.
+type ExecAlerter struct {
+	command     string
+	minInterval time.Duration
+	timeout     time.Duration
+	queue       chan Event
+	dropped     atomic.Int64
+	closeOnce   sync.Once
+	done        chan struct{}
+	run         func(ctx context.Context, command string, stdin []byte) error
+}
+
+// NewExecAlerter starts an alerter for the configured command.
+func NewExecAlerter(cfg ExecAlerterConfig) (*ExecAlerter, error) {
+	if strings.TrimSpace(cfg.Command) == "" {
+		return nil, fmt.Errorf("exec alerter: command is required")
+	}
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 16
+	}
+	a := &ExecAlerter{
+		command:     cfg.Command,
+		minInterval: cfg.MinInterval,
+		timeout:     cfg.Timeout,
+		queue:       make(chan Event, cfg.QueueSize),
+		done:        make(chan struct{}),
+		run:         runShell,
+	}
+	go a.loop()
+	return a, nil
+}
+
+// Notify queues an event for the command. It returns false when the event was
+// dropped because the queue is full or the alerter is closed.
+func (a *ExecAlerter) Notify(evt Event) (queued bool) {
+	defer func() {
+		// Sending on a closed queue panics; treat it as a drop.
+		if recover() != nil {
+			queued = false
+		}
+		if !queued {
+			a.dropped.Add(1)
+		}
+	}()
+	select {
+	case a.queue <- evt:
+		return true
+	default:
+		return false
+	}
+}
+
+// Dropped returns how many events were not delivered to the command.
+func (a *ExecAlerter) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+// Close stops accepting events and waits for queued invocations to finish.
+func (a *ExecAlerter) Close() {
+	a.closeOnce.Do(func() { close(a.queue) })
+	<-a.done
+}
+
+func (a *ExecAlerter) loop() {
+	defer close(a.done)
+	var last time.Time
+	for evt := range a.queue {
+		if wait := a.minInterval - time.Since(last); !last.IsZero() && wait > 0 {
+			time.Sleep(wait)
+		}
+		last = time.Now()
+
+		payload, err := json.Marshal(evt)
+		if err != nil {
+			log.Error().Err(err).Str("process", evt.Process).Msg("Failed to marshal event for alert")
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+		if err := a.run(ctx, a.command, payload); err != nil {
+			log.Warn().Err(err).Str("process", evt.Process).Str("type", string(evt.Type)).Msg("Alert command failed")
+		}
+		cancel()
+	}
+}
+
+func runShell(ctx context.Context, command string, stdin []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(stdin)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
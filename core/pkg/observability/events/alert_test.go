@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSeverityAtLeast(t *testing.T) {
+	min, err := ParseSeverity("warn")
+	if err != nil {
+		t.Fatalf("ParseSeverity: %v", err)
+	}
+	if !(Event{Type: EventTypeCrashed}).Severity().AtLeast(min) {
+		t.Fatal("crashed should pass a warning threshold")
+	}
+	if (Event{Type: EventTypeStarted}).Severity().AtLeast(min) {
+		t.Fatal("started should not pass a warning threshold")
+	}
+	if _, err := ParseSeverity("loud"); err == nil {
+		t.Fatal("expected error for unknown severity")
+	}
+}
+
+func TestExecAlerterDropsWhenBusy(t *testing.T) {
+	a, err := NewExecAlerter(ExecAlerterConfig{Command: "true", MinInterval: time.Millisecond, QueueSize: 1})
+	if err != nil {
+		t.Fatalf("NewExecAlerter: %v", err)
+	}
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	var mu sync.Mutex
+	var got []Event
+	a.run = func(ctx context.Context, command string, stdin []byte) error {
+		started <- struct{}{}
+		<-release
+		var evt Event
+		if err := json.Unmarshal(stdin, &evt); err != nil {
+			t.Errorf("unmarshal stdin: %v", err)
+		}
+		mu.Lock()
+		got = append(got, evt)
+		mu.Unlock()
+		return nil
+	}
+
+	if !a.Notify(Event{Type: EventTypeCrashed, Process: "one"}) {
+		t.Fatal("first event should be queued")
+	}
+	<-started // worker is now blocked inside the handler
+	if !a.Notify(Event{Type: EventTypeCrashed, Process: "two"}) {
+		t.Fatal("second event should fit in the queue")
+	}
+	if a.Notify(Event{Type: EventTypeCrashed, Process: "three"}) {
+		t.Fatal("third event should be dropped while the handler is busy")
+	}
+
+	close(release)
+	a.Close()
+
+	if a.Dropped() != 1 {
+		t.Fatalf("Dropped() = %d, want 1", a.Dropped())
+	}
+	if len(got) != 2 || got[0].Process != "one" || got[1].Process != "two" {
+		t.Fatalf("handled events = %+v", got)
+	}
+	if a.Notify(Event{Process: "late"}) {
+		t.Fatal("Notify after Close should drop")
+	}
+}
@@ -73,56 +73,218 @@ func (c *Consumer) Close() error {
 	return nil
 }
 
-// Subscribe subscribes to events matching the pattern and calls handler for each event.
+// DefaultDurable is the durable consumer name used by Subscribe.
+const DefaultDurable = "core-event-consumer"
+
+// SubscribeOptions controls how a JetStream subscription is bound to the
+// process event stream and where delivery starts.
+type SubscribeOptions struct {
+	// Durable names a durable consumer that survives restarts. Leave empty for
+	// an ephemeral consumer, which is what replaying watchers should use.
+	Durable string
+	// Since replays events published within this window before now.
+	Since time.Duration
+	// StartTime replays events published at or after this time. Takes
+	// precedence over Since.
+	StartTime time.Time
+	// DeliverAll replays every event still retained by the stream.
+	DeliverAll bool
+
+	// AckWait is how long JetStream waits for the handler to finish before
+	// redelivering the event (server default of 30s when zero).
+	AckWait time.Duration
+	// MaxDeliver caps how often one event is delivered, so a handler that
+	// keeps failing eventually moves on (unlimited when zero).
+	MaxDeliver int
+	// RedeliveryDelay holds back an event the handler failed on before it is
+	// delivered again (immediately when zero).
+	RedeliveryDelay time.Duration
+
+	// Live subscribes over core NATS instead of JetStream: lowest latency,
+	// but no acknowledgement, redelivery or replay, so events are lost while
+	// the handler is slow or disconnected. Meant for live tailing; it cannot
+	// be combined with Durable or a start position.
+	Live bool
+}
+
+// validate rejects option combinations that core NATS cannot honour.
+func (o SubscribeOptions) validate() error {
+	if !o.Live {
+		return nil
+	}
+	if o.Durable != "" || o.Since > 0 || !o.StartTime.IsZero() || o.DeliverAll {
+		return fmt.Errorf("live subscriptions cannot replay events or use a durable consumer")
+	}
+	return nil
+}
+
+// startPosition resolves where delivery should begin. A zero time with
+// all=false means only new events are delivered.
+func (o SubscribeOptions) startPosition(now time.Time) (start time.Time, all bool) {
+	switch {
+	case !o.StartTime.IsZero():
+		return o.StartTime, false
+	case o.Since > 0:
+		return now.Add(-o.Since), false
+	default:
+		return time.Time{}, o.DeliverAll
+	}
+}
+
+func (o SubscribeOptions) subOpts(now time.Time) []nats.SubOpt {
+	// Acks are sent by the handler wrapper once the handler returns, so a
+	// crash mid-handler leaves the event unacknowledged and it is redelivered.
+	opts := []nats.SubOpt{nats.BindStream(StreamName), nats.ManualAck(), nats.AckExplicit()}
+	if o.AckWait > 0 {
+		opts = append(opts, nats.AckWait(o.AckWait))
+	}
+	if o.MaxDeliver > 0 {
+		opts = append(opts, nats.MaxDeliver(o.MaxDeliver))
+	}
+	if o.Durable != "" {
+		opts = append(opts, nats.Durable(o.Durable))
+	}
+	start, all := o.startPosition(now)
+	switch {
+	case !start.IsZero():
+		opts = append(opts, nats.StartTime(start))
+	case all:
+		opts = append(opts, nats.DeliverAll())
+	default:
+		opts = append(opts, nats.DeliverNew())
+	}
+	return opts
+}
+
+// Subscribe subscribes to new events matching the pattern through the default
+// durable consumer and calls handler for each event.
 func (c *Consumer) Subscribe(pattern string, handler func(Event) error) error {
+	return c.SubscribeWith(pattern, SubscribeOptions{Durable: DefaultDurable}, handler)
+}
+
+// SubscribeWith subscribes to events matching the pattern using the supplied
+// options, replaying retained events from the stream when a start position is
+// set.
+//
+// Unless opts.Live is set delivery is at least once: an event is acknowledged
+// only after handler returns nil. A handler error or panic naks the event so
+// JetStream redelivers it, up to opts.MaxDeliver times.
+func (c *Consumer) SubscribeWith(pattern string, opts SubscribeOptions, handler func(Event) error) error {
+	if err := opts.validate(); err != nil {
+		return fmt.Errorf("subscribe to %s: %w", pattern, err)
+	}
+	if opts.Live {
+		return c.subscribeLive(pattern, handler)
+	}
+
 	sub, err := c.js.Subscribe(pattern, func(msg *nats.Msg) {
 		var evt Event
 		if err := json.Unmarshal(msg.Data, &evt); err != nil {
-			log.Error().Err(err).Msg("Failed to unmarshal event")
-			msg.Nak() // Negative acknowledge
+			log.Error().Err(err).Str("subject", msg.Subject).Msg("Failed to unmarshal event")
+			msg.Term() // Redelivering can't fix a malformed event
 			return
 		}
 
-		if err := handler(evt); err != nil {
-			log.Error().
+		if err := callHandler(handler, evt); err != nil {
+			logEvt := log.Error().
 				Err(err).
 				Str("process", evt.Process).
-				Str("type", string(evt.Type)).
-				Msg("Event handler failed")
-			msg.Nak()
+				Str("type", string(evt.Type))
+			if meta, metaErr := msg.Metadata(); metaErr == nil {
+				logEvt = logEvt.Uint64("delivery", meta.NumDelivered)
+			}
+			logEvt.Msg("Event handler failed, requesting redelivery")
+			if opts.RedeliveryDelay > 0 {
+				msg.NakWithDelay(opts.RedeliveryDelay)
+			} else {
+				msg.Nak()
+			}
 			return
 		}
 
 		msg.Ack() // Acknowledge successful processing
-	}, nats.Durable("core-event-consumer"), nats.DeliverNew())
+	}, opts.subOpts(time.Now())...)
 
 	if err != nil {
 		return fmt.Errorf("subscribe to %s: %w", pattern, err)
 	}
 
 	c.subs = append(c.subs, sub)
-	log.Info().Str("pattern", pattern).Msg("Subscribed to events")
+	start, all := opts.startPosition(time.Now())
+	logEvt := log.Info().Str("pattern", pattern).Str("durable", opts.Durable)
+	if !start.IsZero() {
+		logEvt = logEvt.Time("replay_from", start)
+	} else if all {
+		logEvt = logEvt.Bool("replay_all", true)
+	}
+	logEvt.Msg("Subscribed to events")
 	return nil
 }
 
+// subscribeLive is the fire-and-forget path for SubscribeOptions.Live.
+func (c *Consumer) subscribeLive(pattern string, handler func(Event) error) error {
+	sub, err := c.nc.Subscribe(pattern, func(msg *nats.Msg) {
+		var evt Event
+		if err := json.Unmarshal(msg.Data, &evt); err != nil {
+			log.Error().Err(err).Str("subject", msg.Subject).Msg("Failed to unmarshal event")
+			return
+		}
+		if err := callHandler(handler, evt); err != nil {
+			log.Error().
+				Err(err).
+				Str("process", evt.Process).
+				Str("type", string(evt.Type)).
+				Msg("Event handler failed")
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe to %s: %w", pattern, err)
+	}
+
+	c.subs = append(c.subs, sub)
+	log.Info().Str("pattern", pattern).Bool("live", true).Msg("Subscribed to events")
+	return nil
+}
+
+// callHandler runs handler, turning a panic into an error so one bad event
+// can't take the consumer down or skip its redelivery.
+func callHandler(handler func(Event) error, evt Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(evt)
+}
+
 // SubscribeAll subscribes to all process events.
 func (c *Consumer) SubscribeAll(handler func(Event) error) error {
-	return c.Subscribe(SubjectPattern(AllEvents()), handler)
+	return c.subscribePattern(handler, AllEvents())
 }
 
 // SubscribeProcess subscribes to all events for a specific process.
 func (c *Consumer) SubscribeProcess(processName string, handler func(Event) error) error {
-	return c.Subscribe(SubjectPattern(ForProcess(processName)), handler)
+	return c.subscribePattern(handler, ForProcess(processName))
 }
 
 // SubscribeEventType subscribes to a specific event type across all processes.
 func (c *Consumer) SubscribeEventType(eventType EventType, handler func(Event) error) error {
-	return c.Subscribe(SubjectPattern(ForEventType(eventType)), handler)
+	return c.subscribePattern(handler, ForEventType(eventType))
 }
 
 // SubscribeProcessEvent subscribes to a specific event type for a specific process.
 func (c *Consumer) SubscribeProcessEvent(processName string, eventType EventType, handler func(Event) error) error {
-	return c.Subscribe(SubjectPattern(ForProcessAndType(processName, eventType)), handler)
+	return c.subscribePattern(handler, ForProcessAndType(processName, eventType))
+}
+
+// subscribePattern validates the pattern options before subscribing, so an
+// unknown event type fails instead of silently matching nothing.
+func (c *Consumer) subscribePattern(handler func(Event) error, opts ...SubjectOption) error {
+	pattern, err := BuildSubjectPattern(opts...)
+	if err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	return c.Subscribe(pattern, handler)
 }
 
 // Wait blocks until the consumer is closed or context is cancelled.
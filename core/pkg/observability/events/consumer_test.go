@@ -0,0 +1,190 @@
+package events
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func TestSubscribeOptionsStartPosition(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	explicit := now.Add(-time.Hour)
+
+	cases := []struct {
+		name      string
+		opts      SubscribeOptions
+		wantStart time.Time
+		wantAll   bool
+	}{
+		{name: "new only", opts: SubscribeOptions{}},
+		{name: "deliver all", opts: SubscribeOptions{DeliverAll: true}, wantAll: true},
+		{name: "since", opts: SubscribeOptions{Since: 10 * time.Minute}, wantStart: now.Add(-10 * time.Minute)},
+		{name: "start time wins", opts: SubscribeOptions{Since: time.Minute, StartTime: explicit, DeliverAll: true}, wantStart: explicit},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, all := tc.opts.startPosition(now)
+			if !start.Equal(tc.wantStart) || all != tc.wantAll {
+				t.Fatalf("startPosition() = (%v, %v), want (%v, %v)", start, all, tc.wantStart, tc.wantAll)
+			}
+		})
+	}
+}
+
+// startJetStream runs an embedded NATS server with the process event stream
+// and returns an adapter wired to publish into it.
+func startJetStream(t *testing.T) (*Adapter, string) {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	a := newTestAdapter(t, Config{NATSURL: ns.ClientURL()})
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+	if a.js, err = nc.JetStream(); err != nil {
+		t.Fatal(err)
+	}
+	a.nc = nc
+	if err := a.ensureStream(); err != nil {
+		t.Fatalf("ensureStream: %v", err)
+	}
+	return a, ns.ClientURL()
+}
+
+func newTestConsumer(t *testing.T, url string) *Consumer {
+	t.Helper()
+	c, err := NewConsumer(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestSubscribeWithRedeliversUntilHandled(t *testing.T) {
+	a, url := startJetStream(t)
+	c := newTestConsumer(t, url)
+
+	var mu sync.Mutex
+	calls := 0
+	done := make(chan Event, 1)
+	handler := func(evt Event) error {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		switch n {
+		case 1:
+			return errors.New("alert sink unavailable")
+		case 2:
+			panic("alert handler crashed")
+		}
+		done <- evt
+		return nil
+	}
+	opts := SubscribeOptions{AckWait: time.Second, MaxDeliver: 5}
+	if err := c.SubscribeWith(SubjectPattern(ForEventType(EventTypeCrashed)), opts, handler); err != nil {
+		t.Fatalf("SubscribeWith: %v", err)
+	}
+
+	a.publishEvent(Event{Type: EventTypeCrashed, Process: "pocketbase"})
+
+	select {
+	case evt := <-done:
+		if evt.Process != "pocketbase" {
+			t.Fatalf("handled %q, want pocketbase", evt.Process)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event was not redelivered after handler failures")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 3 {
+		t.Fatalf("handler calls = %d, want 3", calls)
+	}
+}
+
+func TestSubscribeWithDelaysRedelivery(t *testing.T) {
+	a, url := startJetStream(t)
+	c := newTestConsumer(t, url)
+
+	deliveries := make(chan time.Time, 2)
+	calls := 0 // handler calls for one subscription are serialised
+	opts := SubscribeOptions{RedeliveryDelay: 300 * time.Millisecond}
+	if err := c.SubscribeWith(SubjectPattern(AllEvents()), opts, func(Event) error {
+		calls++
+		deliveries <- time.Now()
+		if calls == 1 {
+			return errors.New("alert queue full")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("SubscribeWith: %v", err)
+	}
+
+	a.publishEvent(Event{Type: EventTypeCrashed, Process: "nats"})
+
+	var times []time.Time
+	for len(times) < 2 {
+		select {
+		case ts := <-deliveries:
+			times = append(times, ts)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d deliveries, want 2", len(times))
+		}
+	}
+	if gap := times[1].Sub(times[0]); gap < opts.RedeliveryDelay {
+		t.Fatalf("redelivered after %s, want at least %s", gap, opts.RedeliveryDelay)
+	}
+}
+
+func TestSubscribeLiveDeliversWithoutReplay(t *testing.T) {
+	a, url := startJetStream(t)
+	c := newTestConsumer(t, url)
+
+	// Published before the subscription: a live subscriber never sees it.
+	a.publishEvent(Event{Type: EventTypeStarted, Process: "old"})
+
+	got := make(chan string, 2)
+	if err := c.SubscribeWith(SubjectPattern(AllEvents()), SubscribeOptions{Live: true}, func(evt Event) error {
+		got <- evt.Process
+		return nil
+	}); err != nil {
+		t.Fatalf("SubscribeWith: %v", err)
+	}
+	if err := c.nc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	a.publishEvent(Event{Type: EventTypeStarted, Process: "new"})
+	select {
+	case name := <-got:
+		if name != "new" {
+			t.Fatalf("live subscriber got %q, want new", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("live subscriber received nothing")
+	}
+
+	if err := c.SubscribeWith(SubjectPattern(AllEvents()), SubscribeOptions{Live: true, Since: time.Minute}, func(Event) error { return nil }); err == nil {
+		t.Fatal("live subscription with Since succeeded, want error")
+	}
+}
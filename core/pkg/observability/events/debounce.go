@@ -0,0 +1,151 @@
+package events
+
+import (
+	"time"
+
+	"github.com/joeblew999/infra/core/pkg/runtime/process"
+)
+
+// Debounce configures flap detection for the adapter. When a process makes
+// Threshold or more lifecycle transitions within Window, its individual events
+// are replaced by a single unstable event until it has been quiet for a full
+// Window, at which point a stable event reports the settled state.
+type Debounce struct {
+	Window    time.Duration // Sliding window for counting transitions (0 disables debouncing)
+	Threshold int           // Transitions within Window that mark a process unstable (default: 3)
+}
+
+// debouncer tracks recent transitions per process.
+type debouncer struct {
+	window    time.Duration
+	threshold int
+	history   map[string][]time.Time
+	unstable  map[string]bool
+}
+
+func newDebouncer(cfg Debounce) *debouncer {
+	if cfg.Window <= 0 {
+		return nil
+	}
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = 3
+	}
+	return &debouncer{
+		window:    cfg.Window,
+		threshold: threshold,
+		history:   make(map[string][]time.Time),
+		unstable:  make(map[string]bool),
+	}
+}
+
+// filter applies flap detection to the events from a single poll. states holds
+// the snapshot the events were derived from, used to report settled processes.
+func (d *debouncer) filter(events []Event, states map[string]process.ComposeProcessState, now time.Time) []Event {
+	var out []Event
+
+	// Release processes that have been quiet for a full window.
+	for key := range d.unstable {
+		history := d.prune(key, now)
+		if len(history) > 0 {
+			continue
+		}
+		delete(d.unstable, key)
+		if state, ok := states[key]; ok {
+			out = append(out, Event{
+				Type:      EventTypeStable,
+				Process:   state.Name,
+				Namespace: state.Namespace,
+				Timestamp: now,
+				State:     state,
+			})
+		}
+	}
+
+	for _, evt := range events {
+		key := eventKey(evt)
+		if !isTransition(evt.Type) {
+			if !d.unstable[key] {
+				out = append(out, evt)
+			}
+			continue
+		}
+
+		history := append(d.prune(key, now), now)
+		d.history[key] = history
+
+		if d.unstable[key] {
+			continue
+		}
+		if len(history) >= d.threshold {
+			d.unstable[key] = true
+			out = append(out, Event{
+				Type:        EventTypeUnstable,
+				Process:     evt.Process,
+				Namespace:   evt.Namespace,
+				Timestamp:   now,
+				State:       evt.State,
+				Transitions: len(history),
+			})
+			continue
+		}
+		out = append(out, evt)
+	}
+	return out
+}
+
+// prune drops transitions older than the window and returns what remains.
+func (d *debouncer) prune(key string, now time.Time) []time.Time {
+	history := d.history[key]
+	cutoff := now.Add(-d.window)
+	kept := history[:0]
+	for _, ts := range history {
+		if ts.After(cutoff) {
+			kept = append(kept, ts)
+		}
+	}
+	if len(kept) == 0 {
+		delete(d.history, key)
+		return nil
+	}
+	d.history[key] = kept
+	return kept
+}
+
+// isTransition reports whether an event type counts towards flap detection.
+func isTransition(t EventType) bool {
+	switch t {
+	case EventTypeStarted, EventTypeStopped, EventTypeCrashed, EventTypeRestarted,
+		EventTypeHealthy, EventTypeUnhealthy:
+		return true
+	default:
+		return false
+	}
+}
+
+// dedupEvents drops repeated events of the same type for the same process
+// within a single poll, keeping the first occurrence.
+func dedupEvents(events []Event) []Event {
+	if len(events) < 2 {
+		return events
+	}
+	seen := make(map[string]struct{}, len(events))
+	out := events[:0]
+	for _, evt := range events {
+		id := eventKey(evt) + "|" + string(evt.Type)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, evt)
+	}
+	return out
+}
+
+// eventKey mirrors Adapter.processKey for an event.
+func eventKey(evt Event) string {
+	if evt.Namespace != "" {
+		return evt.Namespace + "/" + evt.Process
+	}
+	return evt.Process
+}
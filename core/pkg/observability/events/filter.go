@@ -0,0 +1,104 @@
+package events
+
+import (
+	"regexp"
+	"slices"
+)
+
+// Handler processes a single event. Returning an error naks the message so
+// JetStream redelivers it.
+type Handler func(Event) error
+
+// Filter is an in-process predicate applied after the NATS subject match.
+// Subject patterns stay the coarse, broker-side filter; a Filter refines what
+// reaches the handler. A nil Filter matches every event.
+type Filter func(Event) bool
+
+// Match reports whether evt passes the filter.
+func (f Filter) Match(evt Event) bool {
+	return f == nil || f(evt)
+}
+
+// And returns a filter matching events that pass f and every other filter.
+func (f Filter) And(others ...Filter) Filter {
+	return All(append([]Filter{f}, others...)...)
+}
+
+// Or returns a filter matching events that pass f or any other filter.
+func (f Filter) Or(others ...Filter) Filter {
+	return Any(append([]Filter{f}, others...)...)
+}
+
+// All matches events that pass every filter. With no filters it matches
+// everything.
+func All(filters ...Filter) Filter {
+	return func(evt Event) bool {
+		for _, f := range filters {
+			if !f.Match(evt) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Any matches events that pass at least one filter. With no filters it
+// matches nothing.
+func Any(filters ...Filter) Filter {
+	return func(evt Event) bool {
+		for _, f := range filters {
+			if f.Match(evt) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not inverts a filter.
+func Not(f Filter) Filter {
+	return func(evt Event) bool { return !f.Match(evt) }
+}
+
+// ByProcessRegex matches events whose process name matches re. Namespaced
+// processes are matched as "namespace/process".
+func ByProcessRegex(re *regexp.Regexp) Filter {
+	return func(evt Event) bool {
+		name := evt.Process
+		if evt.Namespace != "" {
+			name = evt.Namespace + "/" + evt.Process
+		}
+		return re.MatchString(name)
+	}
+}
+
+// BySeverityAtLeast matches events at min severity or above.
+func BySeverityAtLeast(min Severity) Filter {
+	return func(evt Event) bool { return evt.Severity().AtLeast(min) }
+}
+
+// ByTypeIn matches events of any of the given types.
+func ByTypeIn(types ...EventType) Filter {
+	return func(evt Event) bool { return slices.Contains(types, evt.Type) }
+}
+
+// SubscribeFiltered subscribes to pattern through the default durable
+// consumer and calls handler only for events that pass filter. Events that
+// fail the filter are acknowledged without reaching the handler.
+func (c *Consumer) SubscribeFiltered(pattern string, filter Filter, handler Handler) error {
+	return c.Subscribe(pattern, filtered(filter, handler))
+}
+
+// SubscribeFilteredWith is SubscribeFiltered with explicit subscribe options.
+func (c *Consumer) SubscribeFilteredWith(pattern string, opts SubscribeOptions, filter Filter, handler Handler) error {
+	return c.SubscribeWith(pattern, opts, filtered(filter, handler))
+}
+
+func filtered(filter Filter, handler Handler) func(Event) error {
+	return func(evt Event) error {
+		if !filter.Match(evt) {
+			return nil
+		}
+		return handler(evt)
+	}
+}
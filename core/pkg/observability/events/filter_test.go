@@ -0,0 +1,61 @@
+package events
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestFilterPredicates(t *testing.T) {
+	crashedAPI := Event{Type: EventTypeCrashed, Process: "api-server"}
+	unhealthyDB := Event{Type: EventTypeUnhealthy, Process: "postgres", Namespace: "data"}
+	startedAPI := Event{Type: EventTypeStarted, Process: "api-worker"}
+
+	apiWarnings := ByProcessRegex(regexp.MustCompile(`^api-`)).And(BySeverityAtLeast(SeverityWarning))
+	nsData := ByProcessRegex(regexp.MustCompile(`^data/`))
+	lifecycle := ByTypeIn(EventTypeStarted, EventTypeStopped)
+
+	cases := []struct {
+		name   string
+		filter Filter
+		evt    Event
+		want   bool
+	}{
+		{"regex and severity", apiWarnings, crashedAPI, true},
+		{"severity too low", apiWarnings, startedAPI, false},
+		{"regex misses", apiWarnings, unhealthyDB, false},
+		{"namespace qualified", nsData, unhealthyDB, true},
+		{"type in", lifecycle, startedAPI, true},
+		{"type not in", lifecycle, crashedAPI, false},
+		{"or", lifecycle.Or(nsData), unhealthyDB, true},
+		{"not", Not(lifecycle), crashedAPI, true},
+		{"nil matches", nil, crashedAPI, true},
+		{"empty any", Any(), crashedAPI, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.Match(tc.evt); got != tc.want {
+				t.Fatalf("Match(%s) = %v, want %v", tc.evt, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilteredHandler(t *testing.T) {
+	boom := errors.New("boom")
+	var seen []EventType
+	h := filtered(ByTypeIn(EventTypeCrashed), func(evt Event) error {
+		seen = append(seen, evt.Type)
+		return boom
+	})
+
+	if err := h(Event{Type: EventTypeStarted}); err != nil {
+		t.Fatalf("filtered-out event should be acked, got %v", err)
+	}
+	if err := h(Event{Type: EventTypeCrashed}); !errors.Is(err, boom) {
+		t.Fatalf("handler error should propagate, got %v", err)
+	}
+	if len(seen) != 1 || seen[0] != EventTypeCrashed {
+		t.Fatalf("handler saw %v, want [crashed]", seen)
+	}
+}
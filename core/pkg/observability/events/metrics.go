@@ -0,0 +1,120 @@
+package events
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsExporter turns process events into Prometheus metrics:
+//   - process_up (gauge, 0/1)
+//   - process_restarts_total (counter)
+//   - process_crashes_total (counter)
+//
+// Series are labeled by process and namespace. They are created when a
+// process is first seen and deleted when the adapter reports it removed.
+type MetricsExporter struct {
+	registry *prometheus.Registry
+	up       *prometheus.GaugeVec
+	restarts *prometheus.CounterVec
+	crashes  *prometheus.CounterVec
+
+	mu           sync.Mutex
+	lastRestarts map[string]int
+}
+
+// NewMetricsExporter creates an exporter backed by its own registry.
+func NewMetricsExporter() *MetricsExporter {
+	labels := []string{"process", "namespace"}
+	m := &MetricsExporter{
+		registry: prometheus.NewRegistry(),
+		up: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "process_up",
+			Help: "Whether the process is running (1) or not (0).",
+		}, labels),
+		restarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "process_restarts_total",
+			Help: "Number of process restarts observed.",
+		}, labels),
+		crashes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "process_crashes_total",
+			Help: "Number of process crashes (non-zero exits) observed.",
+		}, labels),
+		lastRestarts: make(map[string]int),
+	}
+	m.registry.MustRegister(m.up, m.restarts, m.crashes)
+	return m
+}
+
+// Subscribe feeds the exporter from all process events on the consumer. It
+// uses an ephemeral consumer so each exporter starts from live events.
+func (m *MetricsExporter) Subscribe(c *Consumer) error {
+	if err := c.SubscribeWith(SubjectPattern(AllEvents()), SubscribeOptions{}, m.Handle); err != nil {
+		return fmt.Errorf("metrics exporter: %w", err)
+	}
+	return nil
+}
+
+// Handle updates the metrics for a single event.
+func (m *MetricsExporter) Handle(evt Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := eventKey(evt)
+	labels := prometheus.Labels{"process": evt.Process, "namespace": evt.Namespace}
+
+	if evt.Removed {
+		m.up.Delete(labels)
+		m.restarts.Delete(labels)
+		m.crashes.Delete(labels)
+		delete(m.lastRestarts, key)
+		return nil
+	}
+
+	// Touch every series so a newly seen process exports zeros rather than gaps.
+	up := m.up.With(labels)
+	restarts := m.restarts.With(labels)
+	crashes := m.crashes.With(labels)
+	if _, seen := m.lastRestarts[key]; !seen {
+		m.lastRestarts[key] = evt.State.Restarts
+		up.Set(boolGauge(evt.State.IsRunning))
+	}
+
+	switch evt.Type {
+	case EventTypeStarted, EventTypeHealthy:
+		up.Set(1)
+	case EventTypeStopped:
+		up.Set(0)
+	case EventTypeCrashed:
+		up.Set(0)
+		crashes.Inc()
+	case EventTypeRestarted:
+		delta := evt.Restarts - m.lastRestarts[key]
+		if delta <= 0 {
+			delta = 1
+		}
+		restarts.Add(float64(delta))
+		m.lastRestarts[key] = evt.Restarts
+	}
+	return nil
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *MetricsExporter) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Registry exposes the underlying registry, e.g. to add collectors.
+func (m *MetricsExporter) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+func boolGauge(v bool) float64 {
+	if v {
+		return 1
+	}
+	return 0
+}
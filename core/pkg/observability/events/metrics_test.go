@@ -0,0 +1,48 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/joeblew999/infra/core/pkg/runtime/process"
+)
+
+func TestMetricsExporterTracksLifecycle(t *testing.T) {
+	m := NewMetricsExporter()
+
+	handle := func(evt Event) {
+		t.Helper()
+		if err := m.Handle(evt); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+
+	handle(Event{Type: EventTypeStarted, Process: "nats", State: process.ComposeProcessState{Name: "nats", IsRunning: true}})
+	handle(Event{Type: EventTypeCrashed, Process: "nats", State: process.ComposeProcessState{Name: "nats", ExitCode: 1}})
+	handle(Event{Type: EventTypeRestarted, Process: "nats", Restarts: 2, State: process.ComposeProcessState{Name: "nats", Restarts: 2}})
+	handle(Event{Type: EventTypeStarted, Process: "nats", State: process.ComposeProcessState{Name: "nats", IsRunning: true, Restarts: 2}})
+
+	if got := testutil.ToFloat64(m.up.WithLabelValues("nats", "")); got != 1 {
+		t.Fatalf("process_up = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.crashes.WithLabelValues("nats", "")); got != 1 {
+		t.Fatalf("process_crashes_total = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.restarts.WithLabelValues("nats", "")); got != 2 {
+		t.Fatalf("process_restarts_total = %v, want 2", got)
+	}
+
+	handle(Event{Type: EventTypeStarted, Process: "pocketbase", State: process.ComposeProcessState{Name: "pocketbase", IsRunning: true}})
+	if got := testutil.CollectAndCount(m.up); got != 2 {
+		t.Fatalf("process_up series = %d, want 2", got)
+	}
+
+	handle(Event{Type: EventTypeStopped, Process: "nats", Removed: true})
+	if got := testutil.CollectAndCount(m.up); got != 1 {
+		t.Fatalf("process_up series after removal = %d, want 1", got)
+	}
+	if got := testutil.CollectAndCount(m.restarts); got != 1 {
+		t.Fatalf("process_restarts_total series after removal = %d, want 1", got)
+	}
+}
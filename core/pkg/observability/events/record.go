@@ -0,0 +1,150 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// RecordToFile appends every new process event to path as newline-delimited
+// JSON until ctx is cancelled. The file can be replayed with ReplayFromFile.
+func RecordToFile(ctx context.Context, consumer *Consumer, path string) error {
+	return RecordToFileWith(ctx, consumer, path, SubjectPattern(AllEvents()), SubscribeOptions{})
+}
+
+// RecordToFileWith is RecordToFile for a subject pattern and start position,
+// e.g. SubscribeOptions{Since: 10 * time.Minute} to capture a window that
+// JetStream still retains.
+func RecordToFileWith(ctx context.Context, consumer *Consumer, path, pattern string, opts SubscribeOptions) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+
+	rec := newRecorder(f)
+	if err := consumer.SubscribeWith(pattern, opts, rec.handle); err != nil {
+		f.Close()
+		return err
+	}
+
+	<-ctx.Done()
+	if err := rec.close(); err != nil {
+		f.Close()
+		return fmt.Errorf("flush %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// recorder serialises events from concurrent deliveries onto one writer and
+// ignores deliveries that arrive after close.
+type recorder struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closed bool
+}
+
+func newRecorder(w io.Writer) *recorder {
+	return &recorder{w: bufio.NewWriter(w)}
+}
+
+func (r *recorder) handle(evt Event) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	if _, err := r.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	// Flush per event so a crash of the recorder itself loses nothing.
+	return r.w.Flush()
+}
+
+func (r *recorder) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return r.w.Flush()
+}
+
+// ReplayFromFile re-emits events recorded by RecordToFile in file order,
+// sleeping between them so their relative timing is preserved. speed scales
+// the playback rate: 1 is real time, 10 is ten times faster, and 0 replays
+// without any delay.
+func ReplayFromFile(path string, handler Handler, speed float64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	return Replay(context.Background(), f, handler, speed)
+}
+
+// Replay is ReplayFromFile for an arbitrary reader, stopping early when ctx
+// is cancelled.
+func Replay(ctx context.Context, r io.Reader, handler Handler, speed float64) error {
+	return replay(ctx, r, handler, speed, sleepContext)
+}
+
+func replay(ctx context.Context, r io.Reader, handler Handler, speed float64, wait func(context.Context, time.Duration) error) error {
+	if speed < 0 {
+		return fmt.Errorf("replay speed must not be negative, got %v", speed)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var prev time.Time
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var evt Event
+		if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil {
+			return fmt.Errorf("line %d: decode event: %w", line, err)
+		}
+
+		// Out-of-order timestamps (clock skew between publishers) replay
+		// immediately rather than reordering the file.
+		if speed > 0 && !prev.IsZero() && evt.Timestamp.After(prev) {
+			gap := time.Duration(float64(evt.Timestamp.Sub(prev)) / speed)
+			if err := wait(ctx, gap); err != nil {
+				return err
+			}
+		}
+		if !evt.Timestamp.IsZero() {
+			prev = evt.Timestamp
+		}
+
+		if err := handler(evt); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read events: %w", err)
+	}
+	return ctx.Err()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	exit := 1
+	recorded := []Event{
+		{Type: EventTypeStarted, Process: "api", Timestamp: start},
+		{Type: EventTypeCrashed, Process: "api", Timestamp: start.Add(2 * time.Second), ExitCode: &exit},
+		{Type: EventTypeRestarted, Process: "api", Timestamp: start.Add(3 * time.Second), Restarts: 1},
+		// Skewed clock: earlier than its predecessor but must stay in place.
+		{Type: EventTypeHealthy, Process: "db", Namespace: "data", Timestamp: start.Add(time.Second)},
+	}
+
+	var buf bytes.Buffer
+	rec := newRecorder(&buf)
+	for _, evt := range recorded {
+		if err := rec.handle(evt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rec.close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.handle(recorded[0]); err != nil || bytes.Count(buf.Bytes(), []byte("\n")) != len(recorded) {
+		t.Fatalf("events after close should be dropped, got %q", buf.String())
+	}
+
+	var waits []time.Duration
+	wait := func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	var got []Event
+	handler := func(evt Event) error {
+		got = append(got, evt)
+		return nil
+	}
+	if err := replay(context.Background(), bytes.NewReader(buf.Bytes()), handler, 2, wait); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != len(recorded) {
+		t.Fatalf("replayed %d events, want %d", len(got), len(recorded))
+	}
+	for i := range recorded {
+		if got[i].Type != recorded[i].Type || got[i].Process != recorded[i].Process || !got[i].Timestamp.Equal(recorded[i].Timestamp) {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], recorded[i])
+		}
+	}
+	if got[1].ExitCode == nil || *got[1].ExitCode != 1 || got[3].Namespace != "data" {
+		t.Errorf("event fields not preserved: %+v", got)
+	}
+
+	wantWaits := []time.Duration{time.Second, 500 * time.Millisecond}
+	if len(waits) != len(wantWaits) || waits[0] != wantWaits[0] || waits[1] != wantWaits[1] {
+		t.Errorf("waits at 2x = %v, want %v", waits, wantWaits)
+	}
+}
+
+func TestReplayWithoutDelay(t *testing.T) {
+	data := `{"type":"started","process":"a","timestamp":"2025-01-01T12:00:00Z"}
+
+{"type":"stopped","process":"a","timestamp":"2025-01-01T13:00:00Z"}
+`
+	waited := false
+	wait := func(context.Context, time.Duration) error { waited = true; return nil }
+	n := 0
+	if err := replay(context.Background(), bytes.NewBufferString(data), func(Event) error { n++; return nil }, 0, wait); err != nil {
+		t.Fatal(err)
+	}
+	if waited || n != 2 {
+		t.Fatalf("speed 0 should replay %d events without waiting (waited=%v, n=%d)", 2, waited, n)
+	}
+
+	if err := replay(context.Background(), bytes.NewBufferString("not json\n"), func(Event) error { return nil }, 0, wait); err == nil {
+		t.Error("expected decode error")
+	}
+}
@@ -0,0 +1,292 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// Retry configures how the adapter holds on to events it could not publish,
+// e.g. while NATS restarts. Failed events are buffered in order and retried
+// with exponential backoff; an event that still fails after MaxAttempts is
+// dead-lettered to DeadLetterFile and/or DeadLetterSubject.
+type Retry struct {
+	BufferSize        int           // Events held for retry before the oldest is dropped (default: 1000)
+	MaxAttempts       int           // Publish attempts before an event is dead-lettered (default: 10)
+	InitialBackoff    time.Duration // Delay after the first failure (default: 500ms)
+	MaxBackoff        time.Duration // Upper bound for the doubling delay (default: 30s)
+	DeadLetterFile    string        // Append dead-lettered events here as NDJSON, replayable with ReplayFromFile
+	DeadLetterSubject string        // Also publish dead-lettered events to this core NATS subject
+}
+
+func (r Retry) withDefaults() Retry {
+	if r.BufferSize <= 0 {
+		r.BufferSize = 1000
+	}
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = 10
+	}
+	if r.InitialBackoff <= 0 {
+		r.InitialBackoff = 500 * time.Millisecond
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = 30 * time.Second
+	}
+	if r.MaxBackoff < r.InitialBackoff {
+		r.MaxBackoff = r.InitialBackoff
+	}
+	return r
+}
+
+// pendingEvent is a buffered event and the publish attempts made so far.
+type pendingEvent struct {
+	seq      uint64
+	evt      Event
+	attempts int
+	err      error
+}
+
+// retryQueue buffers events that failed to publish and retries them in
+// order. Only the head is retried, so an outage costs one attempt per
+// backoff period rather than one per buffered event.
+type retryQueue struct {
+	cfg        Retry
+	send       func(Event) error
+	deadLetter func(Event, error)
+
+	mu      sync.Mutex
+	pending []pendingEvent
+	nextSeq uint64
+	backoff time.Duration
+	wake    chan struct{}
+
+	buffered     prometheus.Gauge
+	dropped      prometheus.Counter
+	deadLettered prometheus.Counter
+}
+
+func newRetryQueue(cfg Retry, send func(Event) error, deadLetter func(Event, error)) *retryQueue {
+	return &retryQueue{
+		cfg:        cfg.withDefaults(),
+		send:       send,
+		deadLetter: deadLetter,
+		wake:       make(chan struct{}, 1),
+		buffered: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "process_events_buffered",
+			Help: "Process events waiting to be retried after a failed publish.",
+		}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "process_events_dropped_total",
+			Help: "Process events discarded because the retry buffer was full.",
+		}),
+		deadLettered: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "process_events_dead_lettered_total",
+			Help: "Process events dead-lettered after exhausting publish retries.",
+		}),
+	}
+}
+
+// publish sends evt, buffering it for retry if that fails. While events are
+// already buffered new ones queue behind them so ordering is preserved.
+func (q *retryQueue) publish(evt Event) {
+	q.mu.Lock()
+	if len(q.pending) > 0 {
+		q.pushLocked(evt, 0, nil)
+		q.mu.Unlock()
+		return
+	}
+	q.mu.Unlock()
+
+	err := q.send(evt)
+	if err == nil {
+		return
+	}
+	log.Warn().Err(err).Str("subject", evt.Subject()).Msg("Failed to publish event, buffering for retry")
+
+	q.mu.Lock()
+	q.pushLocked(evt, 1, err)
+	q.mu.Unlock()
+	q.signal()
+}
+
+// pushLocked appends an event, dropping the oldest when the buffer is full.
+func (q *retryQueue) pushLocked(evt Event, attempts int, err error) {
+	if len(q.pending) >= q.cfg.BufferSize {
+		oldest := q.pending[0]
+		q.pending = q.pending[1:]
+		q.dropped.Inc()
+		log.Error().Str("subject", oldest.evt.Subject()).Int("buffer_size", q.cfg.BufferSize).Msg("Retry buffer full, dropping oldest event")
+	}
+	q.nextSeq++
+	q.pending = append(q.pending, pendingEvent{seq: q.nextSeq, evt: evt, attempts: attempts, err: err})
+	q.buffered.Set(float64(len(q.pending)))
+}
+
+func (q *retryQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// len returns the number of buffered events.
+func (q *retryQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// flush publishes buffered events until the buffer is empty or a publish
+// fails, and returns how long to wait before the next attempt (0 when empty).
+func (q *retryQueue) flush() time.Duration {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.backoff = 0
+			q.mu.Unlock()
+			return 0
+		}
+		head := q.pending[0]
+		q.mu.Unlock()
+
+		err := q.send(head.evt)
+
+		q.mu.Lock()
+		// The head may have been dropped by a full buffer while it was in flight.
+		stillHead := len(q.pending) > 0 && q.pending[0].seq == head.seq
+		if err == nil {
+			if stillHead {
+				q.pending = q.pending[1:]
+			}
+			q.backoff = 0
+			q.buffered.Set(float64(len(q.pending)))
+			q.mu.Unlock()
+			continue
+		}
+
+		var expired *pendingEvent
+		if stillHead {
+			q.pending[0].attempts++
+			q.pending[0].err = err
+			if q.pending[0].attempts >= q.cfg.MaxAttempts {
+				p := q.pending[0]
+				expired = &p
+				q.pending = q.pending[1:]
+				q.buffered.Set(float64(len(q.pending)))
+			}
+		}
+		q.backoff = q.nextBackoff()
+		delay := q.backoff
+		q.mu.Unlock()
+
+		if expired != nil {
+			q.deadLettered.Inc()
+			q.deadLetter(expired.evt, expired.err)
+		}
+		return delay
+	}
+}
+
+// nextBackoff doubles the current delay, starting at InitialBackoff and
+// capped at MaxBackoff.
+func (q *retryQueue) nextBackoff() time.Duration {
+	if q.backoff == 0 {
+		return q.cfg.InitialBackoff
+	}
+	next := q.backoff * 2
+	if next > q.cfg.MaxBackoff {
+		return q.cfg.MaxBackoff
+	}
+	return next
+}
+
+// run retries buffered events until ctx is cancelled.
+func (q *retryQueue) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+		if delay := q.flush(); delay > 0 {
+			timer.Reset(delay)
+		}
+	}
+}
+
+// drain dead-letters everything still buffered, so stopping the adapter
+// during an outage doesn't lose events silently.
+func (q *retryQueue) drain() {
+	q.mu.Lock()
+	pending := q.pending
+	q.pending = nil
+	q.buffered.Set(0)
+	q.mu.Unlock()
+
+	for _, p := range pending {
+		err := p.err
+		if err == nil {
+			err = fmt.Errorf("adapter stopped before publish")
+		}
+		q.deadLettered.Inc()
+		q.deadLetter(p.evt, err)
+	}
+}
+
+func (q *retryQueue) collectors() []prometheus.Collector {
+	return []prometheus.Collector{q.buffered, q.dropped, q.deadLettered}
+}
+
+// deadLetterEvent records an event that could not be published. Without a
+// dead-letter file or subject the event only appears in the log.
+func (a *Adapter) deadLetterEvent(evt Event, cause error) {
+	logEvt := log.Error().Err(cause).Str("subject", evt.Subject()).Str("event_type", string(evt.Type)).Str("process", evt.Process)
+
+	data, err := json.Marshal(evt)
+	if err != nil {
+		logEvt.Msg("Dead-lettered event (unencodable)")
+		return
+	}
+
+	if path := a.retryCfg.DeadLetterFile; path != "" {
+		if err := appendLine(path, data); err != nil {
+			log.Error().Err(err).Str("path", path).Msg("Failed to write dead-letter file")
+		}
+		logEvt = logEvt.Str("dead_letter_file", path)
+	}
+	if subject := a.retryCfg.DeadLetterSubject; subject != "" && a.nc != nil {
+		// Core NATS buffers publishes while reconnecting, unlike JetStream
+		// which needs the server to acknowledge.
+		if err := a.nc.Publish(subject, data); err != nil {
+			log.Error().Err(err).Str("subject", subject).Msg("Failed to publish dead-lettered event")
+		}
+		logEvt = logEvt.Str("dead_letter_subject", subject)
+	}
+	logEvt.Msg("Dead-lettered event")
+}
+
+// appendLine appends data and a newline to path, creating it if needed.
+func appendLine(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// flakyPublisher fails every publish while down and records what got through.
+type flakyPublisher struct {
+	down      bool
+	published []string
+}
+
+func (p *flakyPublisher) send(evt Event) error {
+	if p.down {
+		return errors.New("nats: no responders available for request")
+	}
+	p.published = append(p.published, evt.Process)
+	return nil
+}
+
+func TestRetryQueueReplaysInOrderAfterOutage(t *testing.T) {
+	pub := &flakyPublisher{down: true}
+	var dead []string
+	q := newRetryQueue(Retry{InitialBackoff: time.Second, MaxBackoff: 4 * time.Second},
+		pub.send, func(evt Event, err error) { dead = append(dead, evt.Process) })
+
+	q.publish(Event{Type: EventTypeStarted, Process: "a"})
+	q.publish(Event{Type: EventTypeStarted, Process: "b"})
+	if got := testutil.ToFloat64(q.buffered); got != 2 {
+		t.Fatalf("buffered = %v, want 2", got)
+	}
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		delays = append(delays, q.flush())
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}
+	if !reflect.DeepEqual(delays, want) {
+		t.Fatalf("backoff = %v, want %v", delays, want)
+	}
+
+	// New events queue behind the buffered ones rather than jumping ahead.
+	pub.down = false
+	q.publish(Event{Type: EventTypeStarted, Process: "c"})
+	if len(pub.published) != 0 {
+		t.Fatalf("published %v while events were buffered", pub.published)
+	}
+	if delay := q.flush(); delay != 0 {
+		t.Fatalf("flush delay = %v, want 0 once drained", delay)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(pub.published, want) {
+		t.Fatalf("published = %v, want %v", pub.published, want)
+	}
+	if len(dead) != 0 || testutil.ToFloat64(q.buffered) != 0 {
+		t.Fatalf("dead = %v, buffered = %v, want none", dead, testutil.ToFloat64(q.buffered))
+	}
+}
+
+func TestRetryQueueDeadLettersAndDrops(t *testing.T) {
+	pub := &flakyPublisher{down: true}
+	var dead []string
+	q := newRetryQueue(Retry{BufferSize: 2, MaxAttempts: 2},
+		pub.send, func(evt Event, err error) { dead = append(dead, evt.Process) })
+
+	q.publish(Event{Type: EventTypeCrashed, Process: "a"})
+	q.publish(Event{Type: EventTypeCrashed, Process: "b"})
+	q.publish(Event{Type: EventTypeCrashed, Process: "c"}) // buffer full: a is dropped
+	if got := testutil.ToFloat64(q.dropped); got != 1 {
+		t.Fatalf("dropped = %v, want 1", got)
+	}
+
+	// b was buffered behind a with no attempts yet, so it takes two retries.
+	q.flush()
+	q.flush()
+	if want := []string{"b"}; !reflect.DeepEqual(dead, want) {
+		t.Fatalf("dead-lettered = %v, want %v", dead, want)
+	}
+
+	q.drain()
+	if want := []string{"b", "c"}; !reflect.DeepEqual(dead, want) {
+		t.Fatalf("dead-lettered after drain = %v, want %v", dead, want)
+	}
+	if got := testutil.ToFloat64(q.deadLettered); got != 2 {
+		t.Fatalf("dead_lettered_total = %v, want 2", got)
+	}
+	if q.len() != 0 {
+		t.Fatalf("buffer holds %d events after drain", q.len())
+	}
+}
+
+func TestDeadLetterFileIsReplayable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.ndjson")
+	a := newTestAdapter(t, Config{Retry: Retry{DeadLetterFile: path}})
+
+	a.deadLetterEvent(Event{Type: EventTypeCrashed, Process: "nats"}, errors.New("timeout"))
+	a.deadLetterEvent(Event{Type: EventTypeStarted, Process: "nats"}, errors.New("timeout"))
+
+	var got []EventType
+	if err := ReplayFromFile(path, func(evt Event) error {
+		got = append(got, evt.Type)
+		return nil
+	}, 0); err != nil {
+		t.Fatalf("ReplayFromFile: %v", err)
+	}
+	if want := []EventType{EventTypeCrashed, EventTypeStarted}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("replayed = %v, want %v", got, want)
+	}
+}
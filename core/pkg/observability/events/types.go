@@ -6,6 +6,7 @@ import (
 
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/joeblew999/infra/core/pkg/runtime/process"
 )
@@ -27,10 +28,44 @@ const (
 	// Status events
 	EventTypeStatusChanged EventType = "status_changed"
 
+	// Flap detection events (emitted when the adapter debounces a process)
+	EventTypeUnstable EventType = "unstable"
+	EventTypeStable   EventType = "stable"
+
 	// Log events (for future WebSocket integration)
 	EventTypeLog EventType = "log"
 )
 
+// knownEventTypes lists every EventType, in declaration order.
+var knownEventTypes = []EventType{
+	EventTypeStarted, EventTypeStopped, EventTypeCrashed, EventTypeRestarted,
+	EventTypeHealthy, EventTypeUnhealthy, EventTypeStatusChanged,
+	EventTypeUnstable, EventTypeStable, EventTypeLog,
+}
+
+// Valid reports whether t is one of the event types the adapter publishes.
+func (t EventType) Valid() bool {
+	for _, known := range knownEventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseEventType parses an event type name such as "crashed".
+func ParseEventType(value string) (EventType, error) {
+	t := EventType(strings.ToLower(strings.TrimSpace(value)))
+	if !t.Valid() {
+		names := make([]string, len(knownEventTypes))
+		for i, known := range knownEventTypes {
+			names[i] = string(known)
+		}
+		return "", fmt.Errorf("unknown event type %q (want one of %s)", value, strings.Join(names, ", "))
+	}
+	return t, nil
+}
+
 // Event represents a process lifecycle or health event.
 type Event struct {
 	// Event metadata
@@ -49,6 +84,9 @@ type Event struct {
 	OldStatus string `json:"old_status,omitempty"` // For status_changed events
 	NewStatus string `json:"new_status,omitempty"` // For status_changed events
 	LogLine   string `json:"log_line,omitempty"`   // For log events
+
+	Transitions int  `json:"transitions,omitempty"` // For unstable events
+	Removed     bool `json:"removed,omitempty"`     // For stopped events when the process left the project
 }
 
 // Subject returns the NATS subject for this event.
@@ -57,17 +95,15 @@ type Event struct {
 //   - core.process.nats.started
 //   - core.process.pocketbase.crashed
 //   - core.process.datastar.healthy
+//
+// A namespace adds a token before the name. Characters NATS treats specially
+// are escaped (see ForProcess), so any name yields a valid subject.
 func (e Event) Subject() string {
 	processName := e.Process
 	if e.Namespace != "" {
-		// Replace slashes with dots for NATS subject hierarchy
-		processName = e.Namespace + "." + e.Process
+		processName = e.Namespace + "/" + e.Process
 	}
-	// Sanitize process name for NATS subject
-	processName = strings.ReplaceAll(processName, "/", ".")
-	processName = strings.ReplaceAll(processName, " ", "_")
-
-	return fmt.Sprintf("core.process.%s.%s", processName, e.Type)
+	return fmt.Sprintf("core.process.%s.%s", processTokens(processName), subjectToken(string(e.Type)))
 }
 
 // String returns a human-readable description of the event.
@@ -100,6 +136,10 @@ func (e Event) String() string {
 		return fmt.Sprintf("%s status: %s → %s", prefix, e.OldStatus, e.NewStatus)
 	case EventTypeLog:
 		return fmt.Sprintf("%s: %s", prefix, e.LogLine)
+	case EventTypeUnstable:
+		return fmt.Sprintf("%s unstable (%d transitions)", prefix, e.Transitions)
+	case EventTypeStable:
+		return fmt.Sprintf("%s stable (status=%s)", prefix, e.State.Status)
 	default:
 		return fmt.Sprintf("%s %s", prefix, e.Type)
 	}
@@ -110,11 +150,11 @@ func (e Event) Severity() Severity {
 	switch e.Type {
 	case EventTypeCrashed:
 		return SeverityError
-	case EventTypeUnhealthy:
+	case EventTypeUnhealthy, EventTypeUnstable:
 		return SeverityWarning
 	case EventTypeStopped:
 		return SeverityInfo
-	case EventTypeStarted, EventTypeHealthy, EventTypeRestarted:
+	case EventTypeStarted, EventTypeHealthy, EventTypeRestarted, EventTypeStable:
 		return SeverityInfo
 	case EventTypeStatusChanged, EventTypeLog:
 		return SeverityDebug
@@ -133,6 +173,43 @@ const (
 	SeverityError   Severity = "error"
 )
 
+// rank orders severities from least to most severe.
+func (s Severity) rank() int {
+	switch s {
+	case SeverityDebug:
+		return 0
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityError:
+		return 3
+	default:
+		return -1
+	}
+}
+
+// AtLeast reports whether s is as severe as min or more.
+func (s Severity) AtLeast(min Severity) bool {
+	return s.rank() >= min.rank()
+}
+
+// ParseSeverity parses a severity name (debug, info, warning/warn, error).
+func ParseSeverity(value string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return SeverityDebug, nil
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	default:
+		return "", fmt.Errorf("unknown severity %q (want debug, info, warning, or error)", value)
+	}
+}
+
 // MarshalJSON implements json.Marshaler.
 func (e Event) MarshalJSON() ([]byte, error) {
 	type Alias Event
@@ -152,7 +229,18 @@ func (e Event) MarshalJSON() ([]byte, error) {
 //   - AllEvents() -> "core.process.>"
 //   - ForProcess("nats") -> "core.process.nats.*"
 //   - ForEventType(EventTypeCrashed) -> "core.process.*.crashed"
+//
+// Invalid options, such as an unknown event type, still produce a pattern
+// but one that matches no event; use BuildSubjectPattern to reject them.
 func SubjectPattern(opts ...SubjectOption) string {
+	pattern, _ := BuildSubjectPattern(opts...)
+	return pattern
+}
+
+// BuildSubjectPattern is SubjectPattern that reports invalid options: an
+// empty process name, an unknown event type, or an event type combined with
+// AllEvents.
+func BuildSubjectPattern(opts ...SubjectOption) (string, error) {
 	pattern := &subjectPattern{
 		process: "*",
 		event:   "*",
@@ -160,12 +248,13 @@ func SubjectPattern(opts ...SubjectOption) string {
 	for _, opt := range opts {
 		opt(pattern)
 	}
-	return pattern.build()
+	return pattern.build(), pattern.err
 }
 
 type subjectPattern struct {
 	process string
 	event   string
+	err     error
 }
 
 func (p *subjectPattern) build() string {
@@ -175,6 +264,29 @@ func (p *subjectPattern) build() string {
 	return fmt.Sprintf("core.process.%s.%s", p.process, p.event)
 }
 
+// setProcess matches a single process, given as "name" or "namespace/name".
+func (p *subjectPattern) setProcess(name string) {
+	if strings.TrimSpace(name) == "" && p.err == nil {
+		p.err = fmt.Errorf("process name is empty")
+	}
+	p.process = processTokens(name)
+	if p.event == "" {
+		// Narrowing AllEvents to one process keeps every event type.
+		p.event = "*"
+	}
+}
+
+// setEvent matches a single event type.
+func (p *subjectPattern) setEvent(eventType EventType) {
+	if !eventType.Valid() && p.err == nil {
+		_, p.err = ParseEventType(string(eventType))
+	}
+	if p.process == ">" && p.err == nil {
+		p.err = fmt.Errorf("an event type cannot be combined with AllEvents")
+	}
+	p.event = subjectToken(string(eventType))
+}
+
 // SubjectOption configures a subject pattern.
 type SubjectOption func(*subjectPattern)
 
@@ -187,23 +299,53 @@ func AllEvents() SubjectOption {
 }
 
 // ForProcess returns a pattern matching all events for a specific process.
+// A "/" separates a namespace from the process name. Dots, spaces and the
+// NATS wildcards * and > are escaped to "_" so the name matches exactly that
+// process, the same way Event.Subject escapes it.
 func ForProcess(name string) SubjectOption {
 	return func(p *subjectPattern) {
-		p.process = strings.ReplaceAll(name, "/", ".")
+		p.setProcess(name)
 	}
 }
 
 // ForEventType returns a pattern matching a specific event type across all processes.
 func ForEventType(eventType EventType) SubjectOption {
 	return func(p *subjectPattern) {
-		p.event = string(eventType)
+		p.setEvent(eventType)
 	}
 }
 
 // ForProcessAndType returns a pattern matching a specific event type for a specific process.
 func ForProcessAndType(name string, eventType EventType) SubjectOption {
 	return func(p *subjectPattern) {
-		p.process = strings.ReplaceAll(name, "/", ".")
-		p.event = string(eventType)
+		p.setProcess(name)
+		p.setEvent(eventType)
+	}
+}
+
+// processTokens turns "namespace/name" into subject tokens, escaping each.
+func processTokens(name string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		parts[i] = subjectToken(part)
 	}
+	return strings.Join(parts, ".")
+}
+
+// subjectToken escapes s into a single literal subject token: the token
+// separator ".", the wildcards "*" and ">", whitespace and control characters
+// all become "_". An empty string becomes "_" as well.
+func subjectToken(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '.' || r == '*' || r == '>':
+			return '_'
+		case unicode.IsSpace(r) || unicode.IsControl(r):
+			return '_'
+		}
+		return r
+	}, s)
 }
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+func TestForProcessEscapesWildcards(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{name: "nats", want: "core.process.nats.*"},
+		{name: "edge/nats", want: "core.process.edge.nats.*"},
+		{name: "my app", want: "core.process.my_app.*"},
+		{name: "api.v2", want: "core.process.api_v2.*"},
+		{name: "*", want: "core.process._.*"},
+		{name: ">", want: "core.process._.*"},
+		{name: "a.*.>", want: "core.process.a____.*"},
+	}
+	for _, tc := range cases {
+		got, err := BuildSubjectPattern(ForProcess(tc.name))
+		if err != nil {
+			t.Fatalf("ForProcess(%q): %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("ForProcess(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+		if !server.IsValidSubject(got) {
+			t.Fatalf("ForProcess(%q) = %q is not a valid subject", tc.name, got)
+		}
+	}
+}
+
+func TestWildcardProcessNameMatchesOnlyItself(t *testing.T) {
+	pattern := SubjectPattern(ForProcessAndType("a.*", EventTypeCrashed))
+
+	own := Event{Type: EventTypeCrashed, Process: "a.*"}.Subject()
+	if !server.IsValidLiteralSubject(own) {
+		t.Fatalf("Subject() = %q is not a literal subject", own)
+	}
+	if !server.SubjectMatchesFilter(own, pattern) {
+		t.Fatalf("pattern %q does not match the process's own subject %q", pattern, own)
+	}
+	other := Event{Type: EventTypeCrashed, Process: "a.b"}.Subject()
+	if server.SubjectMatchesFilter(other, pattern) {
+		t.Fatalf("pattern %q for process a.* also matches %q", pattern, other)
+	}
+}
+
+func TestBuildSubjectPatternRejectsInvalidInput(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []SubjectOption
+		want string
+	}{
+		{name: "unknown type", opts: []SubjectOption{ForEventType("bogus")}, want: `unknown event type "bogus"`},
+		{name: "wildcard type", opts: []SubjectOption{ForProcessAndType("nats", ">")}, want: "unknown event type"},
+		{name: "empty process", opts: []SubjectOption{ForProcess(" ")}, want: "process name is empty"},
+		{name: "all events with type", opts: []SubjectOption{AllEvents(), ForEventType(EventTypeCrashed)}, want: "AllEvents"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pattern, err := BuildSubjectPattern(tc.opts...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want it to mention %q", err, tc.want)
+			}
+			// Even unchecked, the pattern must not widen into a wildcard.
+			if strings.HasSuffix(pattern, ">") {
+				t.Fatalf("invalid options produced wildcard pattern %q", pattern)
+			}
+		})
+	}
+}
+
+func TestParseEventType(t *testing.T) {
+	if got, err := ParseEventType(" Crashed "); err != nil || got != EventTypeCrashed {
+		t.Fatalf("ParseEventType(Crashed) = %q, %v", got, err)
+	}
+	if _, err := ParseEventType("bogus"); err == nil || !strings.Contains(err.Error(), "status_changed") {
+		t.Fatalf("ParseEventType(bogus) error = %v, want the list of valid types", err)
+	}
+}
@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/joeblew999/infra/core/pkg/runtime/process"
+	caddyservice "github.com/joeblew999/infra/core/services/caddy"
+	natssvc "github.com/joeblew999/infra/core/services/nats"
+	pocketbasesvc "github.com/joeblew999/infra/core/services/pocketbase"
+)
+
+// Doctor check statuses.
+const (
+	doctorOK      = "ok"
+	doctorWarning = "warning"
+	doctorError   = "error"
+)
+
+// doctorCheck is a single diagnostic result.
+type doctorCheck struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail"`
+	Hint     string `json:"hint,omitempty"`
+
+	// note marks informational results that are hidden without --verbose.
+	note bool
+}
+
+// doctorReport is the structured result of stack doctor. Both the JSON and
+// the human-readable output are rendered from it.
+type doctorReport struct {
+	Checks   []doctorCheck `json:"checks"`
+	Issues   int           `json:"issues"`
+	Warnings int           `json:"warnings"`
+
+	composeDown bool
+}
+
+func (r *doctorReport) add(check doctorCheck) {
+	switch check.Status {
+	case doctorError:
+		r.Issues++
+	case doctorWarning:
+		r.Warnings++
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+func stackDoctorRun(cmd *cobra.Command, args []string) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	out := cmd.OutOrStdout()
+
+	report := runStackDoctor(cmd, args)
+
+	if jsonOut {
+		if err := writeJSON(out, report); err != nil {
+			return err
+		}
+	} else {
+		printDoctorReport(out, report, verbose)
+	}
+	if report.Issues > 0 {
+		return fmt.Errorf("stack doctor found %d issue(s)", report.Issues)
+	}
+	return nil
+}
+
+// runStackDoctor executes every diagnostic and collects the results.
+func runStackDoctor(cmd *cobra.Command, args []string) *doctorReport {
+	report := &doctorReport{}
+
+	// 1. Check port availability
+	ports, err := getStackPorts()
+	if err != nil {
+		report.add(doctorCheck{Category: "ports", Name: "stack ports", Status: doctorWarning,
+			Detail: fmt.Sprintf("Could not determine stack ports: %v", err)})
+	} else {
+		for _, port := range ports {
+			detail := fmt.Sprintf("Port %d: available", port)
+			if isPortBusy(port) {
+				detail = fmt.Sprintf("Port %d: in use (OK if stack is running)", port)
+			}
+			report.add(doctorCheck{Category: "ports", Name: fmt.Sprintf("port %d", port), Status: doctorOK, Detail: detail, note: true})
+		}
+	}
+
+	// 2. Check process-compose connectivity
+	port := process.ComposePort(args)
+	states, pcErr := process.FetchComposeProcesses(cmd.Context(), port)
+	if pcErr != nil {
+		if errors.Is(pcErr, process.ErrComposeUnavailable) {
+			report.composeDown = true
+			report.add(doctorCheck{Category: "process-compose", Name: "process-compose", Status: doctorOK,
+				Detail: fmt.Sprintf("Process-compose not running (port %d)", port),
+				Hint:   "Run: go run ./cmd/core stack up"})
+		} else {
+			report.add(doctorCheck{Category: "process-compose", Name: "process-compose", Status: doctorError,
+				Detail: fmt.Sprintf("Process-compose error: %v", pcErr)})
+		}
+	} else {
+		report.add(doctorCheck{Category: "process-compose", Name: "process-compose", Status: doctorOK,
+			Detail: fmt.Sprintf("Process-compose running (%d processes)", len(states))})
+
+		// Check individual process health
+		for _, state := range states {
+			status := doctorOK
+			if !state.IsRunning {
+				status = doctorError
+			}
+			report.add(doctorCheck{Category: "process-compose", Name: state.Name, Status: status,
+				Detail: fmt.Sprintf("%s: %s (restarts: %d)", state.Name, state.Status, state.Restarts), note: true})
+		}
+	}
+
+	// 3. Check health endpoints
+	if targets, err := stackHealthTargets(); err != nil {
+		report.add(doctorCheck{Category: "health", Name: "service specs", Status: doctorWarning,
+			Detail: fmt.Sprintf("Could not load service specs: %v", err)})
+	} else {
+		checkHealthTargets(report, targets)
+	}
+
+	// 4. Check .data directory
+	dataDir := ".data/core"
+	if stat, err := os.Stat(dataDir); err == nil {
+		if !stat.IsDir() {
+			report.add(doctorCheck{Category: "data", Name: dataDir, Status: doctorError,
+				Detail: fmt.Sprintf("%s exists but is not a directory", dataDir)})
+		} else {
+			report.add(doctorCheck{Category: "data", Name: dataDir, Status: doctorOK,
+				Detail: fmt.Sprintf("%s exists", dataDir)})
+
+			// Check for tokens
+			tokens := []struct{ name, path string }{
+				{"Fly.io", ".data/core/fly/settings.json"},
+				{"Cloudflare", ".data/core/cloudflare/settings.json"},
+			}
+			for _, token := range tokens {
+				check := doctorCheck{Category: "data", Name: token.name + " token", Status: doctorOK}
+				if _, err := os.Stat(token.path); err == nil {
+					check.Detail = token.name + " token found"
+				} else {
+					check.Detail = token.name + " token not found (optional)"
+					check.note = true
+				}
+				report.add(check)
+			}
+		}
+	} else {
+		report.add(doctorCheck{Category: "data", Name: dataDir, Status: doctorWarning,
+			Detail: fmt.Sprintf("%s not found (deployment tokens unavailable)", dataDir)})
+	}
+
+	// 5. Check for zombie processes
+	if ports != nil && report.composeDown {
+		// process-compose not running, check if any ports are in use
+		for _, port := range ports {
+			if isPortBusy(port) {
+				report.add(doctorCheck{Category: "zombies", Name: fmt.Sprintf("port %d", port), Status: doctorWarning,
+					Detail: fmt.Sprintf("Port %d in use but process-compose not running", port),
+					Hint:   "Run: go run ./cmd/core stack clean --processes"})
+			}
+		}
+	}
+
+	return report
+}
+
+// healthTarget is a service health endpoint probed by doctor.
+type healthTarget struct {
+	name string
+	url  string
+	port int
+}
+
+// stackHealthTargets derives the health endpoints from each service's spec so
+// port overrides are honoured.
+func stackHealthTargets() ([]healthTarget, error) {
+	natsSpec, err := natssvc.LoadSpec()
+	if err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	pbSpec, err := pocketbasesvc.LoadSpec()
+	if err != nil {
+		return nil, fmt.Errorf("pocketbase: %w", err)
+	}
+	caddyCfg, err := caddyservice.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("caddy: %w", err)
+	}
+	return healthTargetsFromSpecs(natsSpec, pbSpec, caddyCfg), nil
+}
+
+func healthTargetsFromSpecs(natsSpec *natssvc.Spec, pbSpec *pocketbasesvc.Spec, caddyCfg *caddyservice.Config) []healthTarget {
+	return []healthTarget{
+		{name: "NATS", url: natsSpec.HealthURL(), port: natsSpec.HealthPort()},
+		{name: "PocketBase", url: pbSpec.HealthURL(), port: pbSpec.HealthPort()},
+		{name: "Caddy", url: caddyCfg.HealthURL(), port: caddyCfg.HealthPort()},
+	}
+}
+
+// checkHealthTargets probes each target whose port is in use.
+func checkHealthTargets(report *doctorReport, targets []healthTarget) {
+	client := &http.Client{Timeout: 3 * time.Second}
+	for _, hc := range targets {
+		check := doctorCheck{Category: "health", Name: hc.name}
+		if !isPortBusy(hc.port) {
+			check.Status = doctorOK
+			check.Detail = fmt.Sprintf("%s: not running (port %d not in use)", hc.name, hc.port)
+			check.note = true
+			report.add(check)
+			continue
+		}
+
+		resp, err := client.Get(hc.url)
+		if err != nil {
+			check.Status = doctorError
+			check.Detail = fmt.Sprintf("%s: health check failed (%v)", hc.name, err)
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				check.Status = doctorOK
+				check.Detail = fmt.Sprintf("%s: healthy (%s)", hc.name, hc.url)
+			} else {
+				check.Status = doctorWarning
+				check.Detail = fmt.Sprintf("%s: returned status %d (%s)", hc.name, resp.StatusCode, hc.url)
+			}
+		}
+		report.add(check)
+	}
+}
+
+var doctorSections = []struct{ category, title string }{
+	{"ports", "Checking port availability..."},
+	{"process-compose", "Checking process-compose..."},
+	{"health", "Checking health endpoints..."},
+	{"data", "Checking .data directory..."},
+	{"zombies", "Checking for zombie processes..."},
+}
+
+// printDoctorReport renders the report for humans. Passing informational
+// notes are only shown with --verbose.
+func printDoctorReport(out io.Writer, report *doctorReport, verbose bool) {
+	fmt.Fprintln(out, "🔍 Running stack diagnostics...")
+
+	for _, section := range doctorSections {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "→ %s\n", section.title)
+
+		shown := 0
+		for _, check := range report.Checks {
+			if check.Category != section.category {
+				continue
+			}
+			if check.note && check.Status == doctorOK && !verbose {
+				continue
+			}
+			fmt.Fprintf(out, "  %s %s\n", doctorIcon(check), check.Detail)
+			if check.Hint != "" {
+				fmt.Fprintf(out, "    %s\n", check.Hint)
+			}
+			shown++
+		}
+		if shown == 0 {
+			switch section.category {
+			case "ports":
+				fmt.Fprintln(out, "  ✓ Port check complete")
+			case "zombies":
+				fmt.Fprintln(out, "  ✓ No zombie processes detected")
+			}
+		}
+	}
+
+	// Summary
+	fmt.Fprintln(out, "\n"+strings.Repeat("━", 60))
+	if report.Issues == 0 && report.Warnings == 0 {
+		fmt.Fprintln(out, "✅ All checks passed! Stack is healthy.")
+		return
+	}
+	if report.Issues > 0 {
+		fmt.Fprintf(out, "❌ Found %d issue(s)\n", report.Issues)
+	}
+	if report.Warnings > 0 {
+		fmt.Fprintf(out, "⚠  Found %d warning(s)\n", report.Warnings)
+	}
+	fmt.Fprintln(out, "\nSuggested actions:")
+	if report.composeDown {
+		fmt.Fprintln(out, "  • Start stack: go run ./cmd/core stack up")
+	}
+	if report.Issues > 0 {
+		fmt.Fprintln(out, "  • Check logs: go run ./cmd/core stack processes")
+		fmt.Fprintln(out, "  • Clean and restart: go run ./cmd/core stack clean && go run ./cmd/core stack up")
+	}
+}
+
+func doctorIcon(check doctorCheck) string {
+	switch check.Status {
+	case doctorError:
+		return "❌"
+	case doctorWarning:
+		return "⚠"
+	}
+	if check.Hint != "" {
+		return "ℹ"
+	}
+	if check.note {
+		return "•"
+	}
+	return "✓"
+}
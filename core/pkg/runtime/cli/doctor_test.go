@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	caddyservice "github.com/joeblew999/infra/core/services/caddy"
+	natssvc "github.com/joeblew999/infra/core/services/nats"
+	pocketbasesvc "github.com/joeblew999/infra/core/services/pocketbase"
+)
+
+func TestDoctorProbesOverriddenSpecPort(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == pocketbasesvc.HealthPath {
+			hits.Add(1)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	natsSpec, err := natssvc.LoadSpec()
+	if err != nil {
+		t.Fatalf("load nats spec: %v", err)
+	}
+	pbSpec, err := pocketbasesvc.LoadSpec()
+	if err != nil {
+		t.Fatalf("load pocketbase spec: %v", err)
+	}
+	caddyCfg, err := caddyservice.LoadConfig()
+	if err != nil {
+		t.Fatalf("load caddy config: %v", err)
+	}
+
+	// Override the PocketBase port; doctor must follow the spec rather than 8090.
+	pbSpec.Ports.Primary.Port = port
+
+	targets := healthTargetsFromSpecs(natsSpec, pbSpec, caddyCfg)
+	var pbTarget *healthTarget
+	for i := range targets {
+		if targets[i].name == "PocketBase" {
+			pbTarget = &targets[i]
+		}
+	}
+	if pbTarget == nil {
+		t.Fatal("PocketBase health target missing")
+	}
+	if pbTarget.port != port || !strings.Contains(pbTarget.url, ":"+portStr+pocketbasesvc.HealthPath) {
+		t.Fatalf("PocketBase target = %+v, want port %d", *pbTarget, port)
+	}
+
+	report := &doctorReport{}
+	checkHealthTargets(report, []healthTarget{*pbTarget})
+	if hits.Load() != 1 {
+		t.Fatalf("expected doctor to probe the overridden port once, got %d hits", hits.Load())
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Status != doctorOK || report.Issues != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	psnet "github.com/shirou/gopsutil/v4/net"
+)
+
+// listenerPIDs returns the PIDs of the processes listening on TCP port, read
+// through gopsutil so no lsof/netstat shell-out is needed on any platform.
+// Clients connected to the port are left alone.
+func listenerPIDs(port int) ([]int32, error) {
+	conns, err := psnet.Connections("tcp")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int32
+	seen := map[int32]bool{}
+	for _, c := range conns {
+		if c.Status != "LISTEN" || c.Laddr.Port != uint32(port) || c.Pid <= 0 || seen[c.Pid] {
+			continue
+		}
+		seen[c.Pid] = true
+		pids = append(pids, c.Pid)
+	}
+	return pids, nil
+}
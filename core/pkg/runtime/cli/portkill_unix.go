@@ -0,0 +1,26 @@
+//go:build !windows
+
+package cli
+
+import (
+	"os"
+	"syscall"
+)
+
+// terminatePID asks pid to shut down with SIGTERM.
+func terminatePID(pid int32) error {
+	p, err := os.FindProcess(int(pid))
+	if err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGTERM)
+}
+
+// killPID force-kills pid with SIGKILL.
+func killPID(pid int32) error {
+	p, err := os.FindProcess(int(pid))
+	if err != nil {
+		return err
+	}
+	return p.Signal(syscall.SIGKILL)
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package cli
+
+import (
+	"errors"
+	"os"
+)
+
+// terminatePID reports that Windows has no graceful signal to send, so the
+// caller goes straight to killPID.
+func terminatePID(int32) error {
+	return errors.ErrUnsupported
+}
+
+// killPID force-kills pid; Windows has no signals, so this is
+// TerminateProcess.
+func killPID(pid int32) error {
+	p, err := os.FindProcess(int(pid))
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -10,7 +10,6 @@ import (
 	"net"
 	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
 	"sort"
 	"strconv"
@@ -18,6 +17,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 
 	"github.com/joeblew999/infra/core/pkg/runtime/observability"
@@ -63,6 +64,7 @@ func newStackCommand() *cobra.Command {
 	cmd.AddCommand(newStackProcessCommand())
 	cmd.AddCommand(newStackProjectCommand())
 	cmd.AddCommand(newStackReloadCommand())
+	cmd.AddCommand(newStackRestartCommand())
 	cmd.AddCommand(newStackObserveCommand())
 	return cmd
 }
@@ -111,12 +113,24 @@ func newStackProjectCommand() *cobra.Command {
 		Short: "Update the Process Compose project using a JSON payload",
 		RunE:  stackProjectUpdate,
 	}
-	update.Flags().StringP("file", "f", "", "Path to JSON file describing project overrides")
+	update.Flags().StringP("file", "f", "", "Path to JSON project file (e.g. from 'project export'), or - for stdin")
 	update.Flags().Bool("json", false, "Output update results as JSON")
+	update.Flags().Bool("dry-run", false, "Show what the update would change without applying it")
+
+	export := &cobra.Command{
+		Use:   "export",
+		Short: "Export the running project definition to a versionable JSON file",
+		Long: `Write the running Process Compose project as canonical JSON: derived runtime
+fields are dropped and keys are sorted, so the file diffs cleanly under version
+control. Apply it again with 'core stack project update --file <path>'.`,
+		Args: cobra.NoArgs,
+		RunE: stackProjectExport,
+	}
+	export.Flags().StringP("file", "f", "-", "Path to write the project to (- for stdout)")
 
 	reload := newStackReloadCommand()
 
-	cmd.AddCommand(state, update, reload)
+	cmd.AddCommand(state, update, export, reload)
 	return cmd
 }
 
@@ -172,6 +186,7 @@ Use flags to clean only specific parts:
 	}
 	cmd.Flags().Bool("processes", false, "Kill zombie processes only (skip file removal)")
 	cmd.Flags().Bool("files", false, "Remove generated files only (skip process management)")
+	cmd.Flags().Duration("grace", 5*time.Second, "Time to wait after SIGTERM before force-killing a process (0 kills immediately)")
 	return cmd
 }
 
@@ -187,10 +202,12 @@ func newStackDoctorCommand() *cobra.Command {
 - Zombie processes
 - Process-compose connectivity
 
-Provides actionable suggestions for fixing detected issues.`,
+Provides actionable suggestions for fixing detected issues.
+Exits non-zero when any issue is found, so it can gate CI jobs.`,
 		RunE: stackDoctorRun,
 	}
 	cmd.Flags().Bool("verbose", false, "Show detailed diagnostic information")
+	cmd.Flags().Bool("json", false, "Output the diagnostic report as JSON")
 	return cmd
 }
 
@@ -228,6 +245,7 @@ func stackStatusRun(cmd *cobra.Command, args []string) error {
 func stackCleanRun(cmd *cobra.Command, args []string) error {
 	processesOnly, _ := cmd.Flags().GetBool("processes")
 	filesOnly, _ := cmd.Flags().GetBool("files")
+	grace, _ := cmd.Flags().GetDuration("grace")
 
 	// Determine what to clean
 	// If neither flag is set, do both (full clean)
@@ -259,7 +277,7 @@ func stackCleanRun(cmd *cobra.Command, args []string) error {
 
 		killedAny := false
 		for _, port := range ports {
-			if killed, err := killProcessOnPort(port); err != nil {
+			if killed, err := killProcessOnPort(port, grace); err != nil {
 				fmt.Fprintf(out, "  ⚠ Port %d: %v\n", port, err)
 			} else if killed {
 				fmt.Fprintf(out, "  ✓ Port %d: killed process\n", port)
@@ -290,180 +308,6 @@ func stackCleanRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func stackDoctorRun(cmd *cobra.Command, args []string) error {
-	verbose, _ := cmd.Flags().GetBool("verbose")
-	out := cmd.OutOrStdout()
-
-	fmt.Fprintln(out, "🔍 Running stack diagnostics...")
-	fmt.Fprintln(out)
-
-	issues := 0
-	warnings := 0
-
-	// 1. Check port availability
-	fmt.Fprintln(out, "→ Checking port availability...")
-	ports, err := getStackPorts()
-	if err != nil {
-		fmt.Fprintf(out, "  ⚠ Could not determine stack ports: %v\n", err)
-		warnings++
-	} else {
-		for _, port := range ports {
-			if isPortBusy(port) {
-				if verbose {
-					fmt.Fprintf(out, "  ✓ Port %d: in use (OK if stack is running)\n", port)
-				}
-			} else {
-				if verbose {
-					fmt.Fprintf(out, "  • Port %d: available\n", port)
-				}
-			}
-		}
-		if !verbose {
-			fmt.Fprintln(out, "  ✓ Port check complete")
-		}
-	}
-
-	// 2. Check process-compose connectivity
-	fmt.Fprintln(out, "\n→ Checking process-compose...")
-	port := process.ComposePort(args)
-	states, pcErr := process.FetchComposeProcesses(cmd.Context(), port)
-	if pcErr != nil {
-		if errors.Is(pcErr, process.ErrComposeUnavailable) {
-			fmt.Fprintf(out, "  ℹ Process-compose not running (port %d)\n", port)
-			fmt.Fprintln(out, "    Run: go run ./cmd/core stack up")
-		} else {
-			fmt.Fprintf(out, "  ❌ Process-compose error: %v\n", pcErr)
-			issues++
-		}
-	} else {
-		fmt.Fprintf(out, "  ✓ Process-compose running (%d processes)\n", len(states))
-
-		// Check individual process health
-		if verbose {
-			for _, state := range states {
-				status := "✓"
-				if !state.IsRunning {
-					status = "❌"
-					issues++
-				}
-				fmt.Fprintf(out, "    %s %s: %s (restarts: %d)\n",
-					status, state.Name, state.Status, state.Restarts)
-			}
-		}
-	}
-
-	// 3. Check health endpoints
-	fmt.Fprintln(out, "\n→ Checking health endpoints...")
-	cfg := runtimecfg.Load()
-	healthChecks := []struct {
-		name string
-		url  string
-		port int
-	}{
-		{"NATS", cfg.Services.NATSHTtp + "/healthz", 8222},
-		{"PocketBase", cfg.Services.PocketBase + "/api/health", 8090},
-		{"Caddy", cfg.Services.Caddy + "/api/health", 2015},
-	}
-
-	for _, hc := range healthChecks {
-		if !isPortBusy(hc.port) {
-			if verbose {
-				fmt.Fprintf(out, "  • %s: not running (port %d not in use)\n", hc.name, hc.port)
-			}
-			continue
-		}
-
-		resp, err := http.Get(hc.url)
-		if err != nil {
-			fmt.Fprintf(out, "  ❌ %s: health check failed (%v)\n", hc.name, err)
-			issues++
-		} else {
-			resp.Body.Close()
-			if resp.StatusCode == 200 {
-				fmt.Fprintf(out, "  ✓ %s: healthy\n", hc.name)
-			} else {
-				fmt.Fprintf(out, "  ⚠ %s: returned status %d\n", hc.name, resp.StatusCode)
-				warnings++
-			}
-		}
-	}
-
-	// 4. Check .data directory
-	fmt.Fprintln(out, "\n→ Checking .data directory...")
-	dataDir := ".data/core"
-	if stat, err := os.Stat(dataDir); err == nil {
-		if !stat.IsDir() {
-			fmt.Fprintf(out, "  ❌ %s exists but is not a directory\n", dataDir)
-			issues++
-		} else {
-			fmt.Fprintf(out, "  ✓ %s exists\n", dataDir)
-
-			// Check for tokens
-			flyToken := ".data/core/fly/settings.json"
-			if _, err := os.Stat(flyToken); err == nil {
-				fmt.Fprintln(out, "  ✓ Fly.io token found")
-			} else if verbose {
-				fmt.Fprintln(out, "  • Fly.io token not found (optional)")
-			}
-
-			cfToken := ".data/core/cloudflare/settings.json"
-			if _, err := os.Stat(cfToken); err == nil {
-				fmt.Fprintln(out, "  ✓ Cloudflare token found")
-			} else if verbose {
-				fmt.Fprintln(out, "  • Cloudflare token not found (optional)")
-			}
-		}
-	} else {
-		fmt.Fprintf(out, "  ⚠ %s not found (deployment tokens unavailable)\n", dataDir)
-		warnings++
-	}
-
-	// 5. Check for zombie processes
-	fmt.Fprintln(out, "\n→ Checking for zombie processes...")
-	if ports != nil {
-		foundZombies := false
-		if pcErr != nil && errors.Is(pcErr, process.ErrComposeUnavailable) {
-			// process-compose not running, check if any ports are in use
-			for _, port := range ports {
-				if isPortBusy(port) {
-					fmt.Fprintf(out, "  ⚠ Port %d in use but process-compose not running\n", port)
-					warnings++
-					foundZombies = true
-					if !verbose {
-						fmt.Fprintln(out, "    Run: go run ./cmd/core stack clean --processes")
-					}
-				}
-			}
-		}
-		if !foundZombies {
-			fmt.Fprintln(out, "  ✓ No zombie processes detected")
-		}
-	}
-
-	// Summary
-	fmt.Fprintln(out, "\n"+strings.Repeat("━", 60))
-	if issues == 0 && warnings == 0 {
-		fmt.Fprintln(out, "✅ All checks passed! Stack is healthy.")
-	} else {
-		if issues > 0 {
-			fmt.Fprintf(out, "❌ Found %d issue(s)\n", issues)
-		}
-		if warnings > 0 {
-			fmt.Fprintf(out, "⚠  Found %d warning(s)\n", warnings)
-		}
-		fmt.Fprintln(out, "\nSuggested actions:")
-		if pcErr != nil && errors.Is(pcErr, process.ErrComposeUnavailable) {
-			fmt.Fprintln(out, "  • Start stack: go run ./cmd/core stack up")
-		}
-		if issues > 0 {
-			fmt.Fprintln(out, "  • Check logs: go run ./cmd/core stack processes")
-			fmt.Fprintln(out, "  • Clean and restart: go run ./cmd/core stack clean && go run ./cmd/core stack up")
-		}
-	}
-
-	return nil
-}
-
 func stackProcessesRun(cmd *cobra.Command, args []string) error {
 	port := composePortFromCmd(cmd)
 	jsonOut, _ := cmd.Flags().GetBool("json")
@@ -508,6 +352,7 @@ func stackProjectUpdate(cmd *cobra.Command, args []string) error {
 	port := composePortFromCmd(cmd)
 	file, _ := cmd.Flags().GetString("file")
 	jsonOut, _ := cmd.Flags().GetBool("json")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	if file == "" {
 		return errors.New("no update payload provided; use --file <path> or --file - for stdin")
 	}
@@ -532,6 +377,9 @@ func stackProjectUpdate(cmd *cobra.Command, args []string) error {
 	if err := json.Unmarshal(data, &payload); err != nil {
 		return fmt.Errorf("invalid JSON payload: %w", err)
 	}
+	if dryRun {
+		return previewProjectUpdate(cmd, port, data, jsonOut)
+	}
 	result, err := process.UpdateComposeProject(cmd.Context(), port, data)
 	if err != nil {
 		return err
@@ -555,6 +403,51 @@ func stackProjectUpdate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func stackProjectExport(cmd *cobra.Command, args []string) error {
+	port := composePortFromCmd(cmd)
+	file, _ := cmd.Flags().GetString("file")
+	project, err := process.FetchComposeProject(cmd.Context(), port)
+	if err != nil {
+		return err
+	}
+	data, err := process.MarshalComposeProject(project)
+	if err != nil {
+		return err
+	}
+	if file == "" || file == "-" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return fmt.Errorf("write project: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported %d processes from port %d to %s\n", len(project.Processes), port, file)
+	return nil
+}
+
+// previewProjectUpdate diffs the update payload against the running project
+// without applying it.
+func previewProjectUpdate(cmd *cobra.Command, port int, data []byte, jsonOut bool) error {
+	proposed, err := process.ParseComposeProject(data)
+	if err != nil {
+		return err
+	}
+	current, err := process.FetchComposeProject(cmd.Context(), port)
+	if err != nil {
+		return fmt.Errorf("fetch current project: %w", err)
+	}
+	diff := process.DiffComposeProjects(current, proposed)
+	if jsonOut {
+		return writeJSON(cmd.OutOrStdout(), map[string]any{"port": port, "dry_run": true, "diff": diff})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Dry run: changes to Process Compose project on port %d\n", port)
+	fmt.Fprint(cmd.OutOrStdout(), diff.String())
+	if diff.Empty() {
+		fmt.Fprintln(cmd.OutOrStdout())
+	}
+	return nil
+}
+
 func printServiceExpectations(out io.Writer, stackRunning bool) {
 	services, err := collectServiceStatuses()
 	if err != nil {
@@ -844,7 +737,8 @@ func newStackProcessCommand() *cobra.Command {
 	}
 	logs.Flags().Int("lines", 100, "Number of log lines to fetch (0 for all available)")
 	logs.Flags().Int("end-offset", 0, "Offset from the end of the log before reading (0 for latest)")
-	logs.Flags().Bool("json", false, "Output logs as JSON")
+	logs.Flags().Bool("json", false, "Output logs as JSON (one object per line with --follow)")
+	logs.Flags().BoolP("follow", "f", false, "Stream new log lines until interrupted")
 
 	truncate := &cobra.Command{
 		Use:   "truncate NAME",
@@ -869,7 +763,25 @@ func newStackProcessCommand() *cobra.Command {
 	}
 	list.Flags().Bool("json", false, "Output processes as JSON")
 
-	cmd.AddCommand(start, stop, restart, scale, logs, truncate, info, list)
+	wait := &cobra.Command{
+		Use:   "wait NAME",
+		Short: "Block until a process is running, healthy, or stopped",
+		Long: `Poll Process Compose until the named process reaches the desired state.
+Exits non-zero if the timeout elapses first, which makes it usable for
+ordering steps in deploy scripts.
+
+Examples:
+  core stack process wait nats --for healthy --timeout 60s
+  core stack process wait pocketbase --for stopped`,
+		Args: cobra.ExactArgs(1),
+		RunE: stackProcessWait,
+	}
+	wait.Flags().String("for", string(process.ComposeConditionHealthy), "State to wait for (running, healthy, stopped)")
+	wait.Flags().Duration("timeout", 60*time.Second, "Maximum time to wait")
+	wait.Flags().Duration("interval", 500*time.Millisecond, "Polling interval")
+	wait.Flags().Bool("json", false, "Output the final process state as JSON")
+
+	cmd.AddCommand(start, stop, restart, scale, logs, truncate, info, list, wait)
 	return cmd
 }
 
@@ -884,6 +796,67 @@ func newStackReloadCommand() *cobra.Command {
 	return cmd
 }
 
+func newStackRestartCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the whole stack in dependency order",
+		Long: `Stop every stack process in reverse dependency order, then start them
+again in dependency order (NATS, PocketBase, Caddy), waiting for each to
+become healthy before starting the next. Dependencies come from each
+service's manifest. Stops at the first process that does not come healthy.`,
+		Args: cobra.NoArgs,
+		RunE: stackRestartRun,
+	}
+	cmd.Flags().Int("compose-port", 0, "Process Compose port (defaults to PC_PORT_NUM or 28081)")
+	cmd.Flags().Duration("timeout", 60*time.Second, "Maximum time to wait for each process to stop or become healthy")
+	cmd.Flags().Duration("interval", 500*time.Millisecond, "Polling interval")
+	return cmd
+}
+
+func stackRestartRun(cmd *cobra.Command, _ []string) error {
+	port := composePortFromCmd(cmd)
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	interval, _ := cmd.Flags().GetDuration("interval")
+
+	deps, err := process.StackDependencies()
+	if err != nil {
+		return err
+	}
+	order, err := process.DependencyOrder(deps)
+	if err != nil {
+		return fmt.Errorf("stack dependencies: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	out := cmd.OutOrStdout()
+	started := time.Now()
+	err = process.RestartComposeStack(ctx, port, order, process.RestartStackOptions{
+		Timeout:  timeout,
+		Interval: interval,
+		Progress: func(action, name string) {
+			switch action {
+			case "stopped":
+				fmt.Fprintf(out, "%s %s stopped\n", colorize("✓", colorGray), name)
+			case "healthy":
+				fmt.Fprintf(out, "%s %s healthy\n", colorize("✓", colorGreen), name)
+			default:
+				fmt.Fprintf(out, "→ %s %s...\n", action, name)
+			}
+		},
+	})
+	if err != nil {
+		if errors.Is(err, process.ErrComposeUnavailable) {
+			return fmt.Errorf("restart stack: process compose is not running on port %d (start it with 'core stack up')", port)
+		}
+		return fmt.Errorf("restart stack: %w", err)
+	}
+	fmt.Fprintf(out, "Restarted %s (%s)\n", strings.Join(order, " → "), time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
 func stackProcessStart(cmd *cobra.Command, args []string) error {
 	port := composePortFromCmd(cmd)
 	name := args[0]
@@ -944,6 +917,47 @@ func stackProcessInfo(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func stackProcessWait(cmd *cobra.Command, args []string) error {
+	port := composePortFromCmd(cmd)
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	forValue, _ := cmd.Flags().GetString("for")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	name := args[0]
+
+	cond, err := process.ParseComposeCondition(forValue)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	state, err := process.WaitForComposeProcess(ctx, port, name, cond, interval)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			if state != nil {
+				return fmt.Errorf("timed out after %s waiting for %s to be %s (status: %s)", timeout, name, cond, state.Status)
+			}
+			return fmt.Errorf("timed out after %s waiting for %s to be %s (process not found)", timeout, name, cond)
+		}
+		return err
+	}
+	if jsonOut {
+		return writeJSON(cmd.OutOrStdout(), map[string]any{"port": port, "condition": cond, "process": state})
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Process %s is %s (%s)\n", name, cond, time.Since(started).Round(time.Millisecond))
+	return nil
+}
+
 func stackProcessRestart(cmd *cobra.Command, args []string) error {
 	port := composePortFromCmd(cmd)
 	name := args[0]
@@ -1016,6 +1030,10 @@ func stackProcessLogs(cmd *cobra.Command, args []string) error {
 		endOffset = 0
 	}
 	name := args[0]
+
+	if follow, _ := cmd.Flags().GetBool("follow"); follow {
+		return stackProcessFollowLogs(cmd, port, name, lines, jsonOut)
+	}
 	
 	// Try reading from log file first (when log_location is configured)
 	logs, err := readProcessLogsFromFile(name, lines, endOffset)
@@ -1041,6 +1059,27 @@ func stackProcessLogs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// stackProcessFollowLogs streams logs until Ctrl+C, replaying the last lines
+// first. JSON output is newline-delimited so each line parses on its own.
+func stackProcessFollowLogs(cmd *cobra.Command, port int, name string, lines int, jsonOut bool) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	enc := json.NewEncoder(out)
+	return process.StreamComposeProcessLogs(ctx, port, name, lines, func(line process.ComposeLogLine) error {
+		if jsonOut {
+			return enc.Encode(line)
+		}
+		if strings.HasSuffix(line.Message, "\n") {
+			_, err := fmt.Fprint(out, line.Message)
+			return err
+		}
+		_, err := fmt.Fprintln(out, line.Message)
+		return err
+	})
+}
+
 func stackProcessTruncate(cmd *cobra.Command, args []string) error {
 	port := composePortFromCmd(cmd)
 	jsonOut, _ := cmd.Flags().GetBool("json")
@@ -1269,30 +1308,44 @@ func isPortBusy(port int) bool {
 	return true
 }
 
-// killProcessOnPort kills any process listening on the given port.
-// Returns (true, nil) if a process was killed, (false, nil) if no process found.
-func killProcessOnPort(port int) (bool, error) {
+// killProcessOnPort stops any process listening on the given port. It sends
+// SIGTERM first (where the platform has it) so services can flush their data
+// dirs, waits up to grace for the port to free, and only then falls back to
+// SIGKILL.
+// Returns (true, nil) if a process was stopped, (false, nil) if no process found.
+func killProcessOnPort(port int, grace time.Duration) (bool, error) {
 	if !isPortBusy(port) {
 		return false, nil
 	}
 
-	// Use lsof to find the PID listening on this port
-	cmd := fmt.Sprintf("lsof -ti :%d", port)
-	output, err := exec.Command("sh", "-c", cmd).Output()
+	pids, err := listenerPIDs(port)
 	if err != nil {
-		// No process found or lsof failed
+		return false, fmt.Errorf("list listeners: %w", err)
+	}
+	if len(pids) == 0 {
 		return false, nil
 	}
 
-	pidStr := strings.TrimSpace(string(output))
-	if pidStr == "" {
-		return false, nil
+	if grace > 0 {
+		var termErr error
+		for _, pid := range pids {
+			termErr = errors.Join(termErr, terminatePID(pid))
+		}
+		if termErr == nil {
+			deadline := time.Now().Add(grace)
+			for time.Now().Before(deadline) {
+				if !isPortBusy(port) {
+					return true, nil
+				}
+				time.Sleep(100 * time.Millisecond)
+			}
+		}
 	}
 
-	// Kill the process
-	killCmd := fmt.Sprintf("kill -9 %s", pidStr)
-	if err := exec.Command("sh", "-c", killCmd).Run(); err != nil {
-		return false, fmt.Errorf("kill failed: %w", err)
+	for _, pid := range pids {
+		if err := killPID(pid); err != nil && isPortBusy(port) {
+			return false, fmt.Errorf("kill pid %d: %w", pid, err)
+		}
 	}
 
 	// Give it a moment to die
@@ -1356,6 +1409,9 @@ including starts, stops, crashes, restarts, and health changes.`,
 
 	cmd.AddCommand(newStackObserveAdapterCommand())
 	cmd.AddCommand(newStackObserveWatchCommand())
+	cmd.AddCommand(newStackObserveMetricsCommand())
+	cmd.AddCommand(newStackObserveRecordCommand())
+	cmd.AddCommand(newStackObserveReplayCommand())
 
 	return cmd
 }
@@ -1365,6 +1421,10 @@ func newStackObserveAdapterCommand() *cobra.Command {
 		composePort  int
 		natsURL      string
 		pollInterval time.Duration
+		retention    time.Duration
+		debounce     observability.Debounce
+		retry        observability.Retry
+		metricsAddr  string
 	)
 
 	cmd := &cobra.Command{
@@ -1378,12 +1438,23 @@ Events are published to subjects like:
   core.process.{name}.crashed
   core.process.{name}.healthy
 
+Events that fail to publish (e.g. while NATS restarts) are buffered and
+retried with backoff. Events that exhaust their retries are dead-lettered to
+--dead-letter-file and/or --dead-letter-subject; the file can be replayed with
+'observe replay'. With --metrics-addr the adapter serves:
+  process_events_buffered              events waiting to be retried
+  process_events_dropped_total         events discarded from a full buffer
+  process_events_dead_lettered_total   events that exhausted their retries
+
 Run this adapter in the background to enable real-time process monitoring.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			adapter, err := observability.NewAdapter(observability.Config{
 				ComposePort:  composePort,
 				NATSURL:      natsURL,
 				PollInterval: pollInterval,
+				Retention:    retention,
+				Debounce:     debounce,
+				Retry:        retry,
 			})
 			if err != nil {
 				return fmt.Errorf("create adapter: %w", err)
@@ -1393,10 +1464,27 @@ Run this adapter in the background to enable real-time process monitoring.`,
 				return fmt.Errorf("start adapter: %w", err)
 			}
 
+			if metricsAddr != "" {
+				registry := prometheus.NewRegistry()
+				registry.MustRegister(adapter.Collectors()...)
+				mux := http.NewServeMux()
+				mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+				srv := &http.Server{Addr: metricsAddr, Handler: mux}
+				go func() {
+					if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						fmt.Fprintf(cmd.ErrOrStderr(), "metrics server: %v\n", err)
+					}
+				}()
+				defer srv.Close()
+			}
+
 			fmt.Fprintln(cmd.OutOrStdout(), "Event adapter running...")
 			fmt.Fprintf(cmd.OutOrStdout(), "  Process Compose: http://127.0.0.1:%d\n", composePort)
 			fmt.Fprintf(cmd.OutOrStdout(), "  NATS: %s\n", natsURL)
 			fmt.Fprintf(cmd.OutOrStdout(), "  Poll interval: %s\n", pollInterval)
+			if metricsAddr != "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "  Metrics: http://%s/metrics\n", metricsAddr)
+			}
 			fmt.Fprintln(cmd.OutOrStdout(), "\nPress Ctrl+C to stop")
 
 			// Wait for interrupt
@@ -1412,6 +1500,14 @@ Run this adapter in the background to enable real-time process monitoring.`,
 	cmd.Flags().IntVar(&composePort, "compose-port", 28081, "Process Compose API port")
 	cmd.Flags().StringVar(&natsURL, "nats-url", runtimecfg.Load().Services.NATS, "NATS server URL")
 	cmd.Flags().DurationVar(&pollInterval, "poll-interval", 2*time.Second, "How often to poll for state changes")
+	cmd.Flags().DurationVar(&retention, "retention", 24*time.Hour, "How long JetStream retains events for replay")
+	cmd.Flags().DurationVar(&debounce.Window, "debounce-window", 0, "Collapse flapping processes into one unstable event within this window (0 disables)")
+	cmd.Flags().IntVar(&debounce.Threshold, "debounce-threshold", 3, "Transitions within the debounce window that mark a process unstable")
+	cmd.Flags().IntVar(&retry.BufferSize, "retry-buffer", 1000, "Failed events held for retry before the oldest is dropped")
+	cmd.Flags().IntVar(&retry.MaxAttempts, "retry-attempts", 10, "Publish attempts before an event is dead-lettered")
+	cmd.Flags().StringVar(&retry.DeadLetterFile, "dead-letter-file", "", "Append events that exhaust their retries to this NDJSON file")
+	cmd.Flags().StringVar(&retry.DeadLetterSubject, "dead-letter-subject", "", "Publish events that exhaust their retries to this NATS subject")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve retry buffer metrics on this address (e.g. :9101)")
 
 	return cmd
 }
@@ -1421,7 +1517,12 @@ func newStackObserveWatchCommand() *cobra.Command {
 		natsURL    string
 		process    string
 		eventType  string
-		jsonOutput bool
+		jsonOutput   bool
+		since        time.Duration
+		minSeverity  string
+		execCommand  string
+		execInterval time.Duration
+		live         bool
 	)
 
 	cmd := &cobra.Command{
@@ -1440,8 +1541,54 @@ Examples:
   core stack observe watch --type crashed
 
   # Watch crashes for specific process
-  core stack observe watch --process pocketbase --type crashed`,
+  core stack observe watch --process pocketbase --type crashed
+
+  # Replay the last 10 minutes from JetStream, then keep following
+  core stack observe watch --since 10m
+
+  # Only show warnings and errors
+  core stack observe watch --min-severity warning
+
+  # Send crash alerts to ntfy (event JSON is passed on stdin)
+  core stack observe watch --min-severity error --exec 'curl -s -d @- ntfy.sh/my-alerts'
+
+  # Lowest-latency tailing over core NATS; events can be missed
+  core stack observe watch --live
+
+Events are delivered through JetStream and acknowledged once handled, so an
+alert that can't be queued for --exec is redelivered after --exec-interval.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if live && (execCommand != "" || since > 0) {
+				return fmt.Errorf("--live cannot be combined with --exec or --since")
+			}
+
+			threshold := observability.SeverityDebug
+			if minSeverity != "" {
+				parsed, err := observability.ParseSeverity(minSeverity)
+				if err != nil {
+					return err
+				}
+				threshold = parsed
+			}
+
+			var alerter *observability.ExecAlerter
+			if execCommand != "" {
+				a, err := observability.NewExecAlerter(observability.ExecAlerterConfig{
+					Command:     execCommand,
+					MinInterval: execInterval,
+				})
+				if err != nil {
+					return err
+				}
+				alerter = a
+				defer alerter.Close()
+			}
+
+			pattern, err := observeSubjectPattern(process, eventType)
+			if err != nil {
+				return err
+			}
+
 			consumer, err := observability.NewConsumer(natsURL)
 			if err != nil {
 				return fmt.Errorf("create consumer: %w", err)
@@ -1452,41 +1599,35 @@ Examples:
 				return fmt.Errorf("connect: %w", err)
 			}
 
-			// Build subscription pattern
-			var pattern string
-			if process != "" && eventType != "" {
-				pattern = observability.SubjectPattern(
-					observability.ForProcessAndType(process, observability.EventType(eventType)),
-				)
-			} else if process != "" {
-				pattern = observability.SubjectPattern(observability.ForProcess(process))
-			} else if eventType != "" {
-				pattern = observability.SubjectPattern(observability.ForEventType(observability.EventType(eventType)))
-			} else {
-				pattern = observability.SubjectPattern(observability.AllEvents())
-			}
-
 			fmt.Fprintf(cmd.OutOrStdout(), "Watching events: %s\n", pattern)
+			if since > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Replaying events from the last %s\n", since)
+			}
 			fmt.Fprintln(cmd.OutOrStdout(), "Press Ctrl+C to stop")
 			fmt.Fprintln(cmd.OutOrStdout())
 
 			// Subscribe with handler
 			handler := func(evt observability.Event) error {
-				if jsonOutput {
-					// JSON output
-					data, _ := evt.MarshalJSON()
-					fmt.Fprintln(cmd.OutOrStdout(), string(data))
-				} else {
-					// Human-readable output with timestamp and severity
-					timestamp := evt.Timestamp.Format("15:04:05.000")
-					severity := evt.Severity()
-					icon := severityIcon(severity)
-					fmt.Fprintf(cmd.OutOrStdout(), "%s %s %s\n", timestamp, icon, evt.String())
+				if !evt.Severity().AtLeast(threshold) {
+					return nil
+				}
+				// A full alert queue naks the event so JetStream redelivers it;
+				// it is printed once the alert has been queued.
+				if alerter != nil && !alerter.Notify(evt) {
+					fmt.Fprintf(cmd.ErrOrStderr(), "alert handler busy, retrying: %s\n", evt.String())
+					return fmt.Errorf("alert queue full")
 				}
+				printObservedEvent(cmd.OutOrStdout(), evt, jsonOutput)
 				return nil
 			}
 
-			if err := consumer.Subscribe(pattern, handler); err != nil {
+			// Watchers use an ephemeral consumer so each run can pick its own
+			// start point; --live opts into lossy core NATS delivery instead.
+			subOpts := observability.SubscribeOptions{Since: since, Live: live}
+			if alerter != nil {
+				subOpts.RedeliveryDelay = execInterval
+			}
+			if err := consumer.SubscribeWith(pattern, subOpts, handler); err != nil {
 				return fmt.Errorf("subscribe: %w", err)
 			}
 
@@ -1509,12 +1650,248 @@ Examples:
 
 	cmd.Flags().StringVar(&natsURL, "nats-url", runtimecfg.Load().Services.NATS, "NATS server URL")
 	cmd.Flags().StringVarP(&process, "process", "p", "", "Filter by process name")
-	cmd.Flags().StringVarP(&eventType, "type", "t", "", "Filter by event type (started, stopped, crashed, healthy, unhealthy)")
+	cmd.Flags().StringVarP(&eventType, "type", "t", "", "Filter by event type (started, stopped, crashed, restarted, healthy, unhealthy, status_changed, unstable, stable)")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output events as JSON")
+	cmd.Flags().DurationVar(&since, "since", 0, "Replay events from this far back before following (e.g. 10m)")
+	cmd.Flags().StringVar(&minSeverity, "min-severity", "", "Only show events at or above this severity (debug, info, warning, error)")
+	cmd.Flags().StringVar(&execCommand, "exec", "", "Shell command to run for each matching event (event JSON on stdin)")
+	cmd.Flags().DurationVar(&execInterval, "exec-interval", time.Second, "Minimum time between --exec invocations; events are redelivered after this long when the alert queue is full")
+	cmd.Flags().BoolVar(&live, "live", false, "Tail over core NATS without acks or replay (lowest latency, events may be missed)")
+
+	return cmd
+}
+
+func newStackObserveMetricsCommand() *cobra.Command {
+	var (
+		natsURL string
+		addr    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Export process events as Prometheus metrics",
+		Long: `Subscribe to process events from NATS and expose Prometheus metrics on /metrics.
+
+Metrics (labeled by process and namespace):
+  process_up               1 while the process is running, 0 otherwise
+  process_restarts_total   restarts observed since the exporter started
+  process_crashes_total    non-zero exits observed since the exporter started
+
+Examples:
+  core stack observe metrics --addr :9100`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			consumer, err := observability.NewConsumer(natsURL)
+			if err != nil {
+				return fmt.Errorf("create consumer: %w", err)
+			}
+			defer consumer.Close()
+
+			if err := consumer.Connect(); err != nil {
+				return fmt.Errorf("connect: %w", err)
+			}
+
+			exporter := observability.NewMetricsExporter()
+			if err := exporter.Subscribe(consumer); err != nil {
+				return err
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", exporter.Handler())
+			srv := &http.Server{Addr: addr, Handler: mux}
+
+			errCh := make(chan error, 1)
+			go func() {
+				if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					errCh <- err
+				}
+			}()
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Serving metrics on http://%s/metrics\n", addr)
+			fmt.Fprintln(cmd.OutOrStdout(), "Press Ctrl+C to stop")
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+			select {
+			case <-sigCh:
+				fmt.Fprintln(cmd.OutOrStdout(), "\nStopping...")
+			case <-cmd.Context().Done():
+			case err := <-errCh:
+				return fmt.Errorf("metrics server: %w", err)
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		},
+	}
+
+	cmd.Flags().StringVar(&natsURL, "nats-url", runtimecfg.Load().Services.NATS, "NATS server URL")
+	cmd.Flags().StringVar(&addr, "addr", ":9100", "Address to serve /metrics on")
 
 	return cmd
 }
 
+func newStackObserveRecordCommand() *cobra.Command {
+	var (
+		natsURL   string
+		out       string
+		process   string
+		eventType string
+		since     time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "record",
+		Short: "Record process events to a newline-delimited JSON file",
+		Long: `Subscribe to process events and append them to a file until interrupted.
+The file can be replayed offline with 'core stack observe replay'.
+
+Examples:
+  # Capture a crash storm as it happens
+  core stack observe record --out events.ndjson
+
+  # Capture the last 10 minutes still retained by JetStream, then keep recording
+  core stack observe record --out events.ndjson --since 10m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pattern, err := observeSubjectPattern(process, eventType)
+			if err != nil {
+				return err
+			}
+
+			consumer, err := observability.NewConsumer(natsURL)
+			if err != nil {
+				return fmt.Errorf("create consumer: %w", err)
+			}
+			defer consumer.Close()
+
+			if err := consumer.Connect(); err != nil {
+				return fmt.Errorf("connect: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Recording %s to %s\n", pattern, out)
+			fmt.Fprintln(cmd.OutOrStdout(), "Press Ctrl+C to stop")
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			subOpts := observability.SubscribeOptions{Since: since}
+			if err := observability.RecordToFileWith(ctx, consumer, out, pattern, subOpts); err != nil {
+				return fmt.Errorf("record: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "\nStopped recording")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&natsURL, "nats-url", runtimecfg.Load().Services.NATS, "NATS server URL")
+	cmd.Flags().StringVarP(&out, "out", "o", "events.ndjson", "File to append events to")
+	cmd.Flags().StringVarP(&process, "process", "p", "", "Filter by process name")
+	cmd.Flags().StringVarP(&eventType, "type", "t", "", "Filter by event type (started, stopped, crashed, restarted, healthy, unhealthy, status_changed, unstable, stable)")
+	cmd.Flags().DurationVar(&since, "since", 0, "Also record events from this far back (e.g. 10m)")
+
+	return cmd
+}
+
+func newStackObserveReplayCommand() *cobra.Command {
+	var (
+		in          string
+		speed       float64
+		jsonOutput  bool
+		minSeverity string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay recorded process events offline",
+		Long: `Replay a file written by 'core stack observe record' using the same output
+as 'observe watch'. Events keep their order and relative timing.
+
+Examples:
+  # Replay in real time
+  core stack observe replay --in events.ndjson
+
+  # Replay ten times faster, errors only
+  core stack observe replay --in events.ndjson --speed 10 --min-severity error
+
+  # Dump instantly
+  core stack observe replay --in events.ndjson --speed 0`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			threshold := observability.SeverityDebug
+			if minSeverity != "" {
+				parsed, err := observability.ParseSeverity(minSeverity)
+				if err != nil {
+					return err
+				}
+				threshold = parsed
+			}
+
+			f, err := os.Open(in)
+			if err != nil {
+				return fmt.Errorf("open %s: %w", in, err)
+			}
+			defer f.Close()
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+
+			filter := observability.BySeverityAtLeast(threshold)
+			err = observability.Replay(ctx, f, func(evt observability.Event) error {
+				if filter.Match(evt) {
+					printObservedEvent(cmd.OutOrStdout(), evt, jsonOutput)
+				}
+				return nil
+			}, speed)
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&in, "in", "i", "events.ndjson", "File recorded by 'observe record'")
+	cmd.Flags().Float64Var(&speed, "speed", 1, "Playback rate (1 = real time, 10 = ten times faster, 0 = no delay)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output events as JSON")
+	cmd.Flags().StringVar(&minSeverity, "min-severity", "", "Only show events at or above this severity (debug, info, warning, error)")
+
+	return cmd
+}
+
+// observeSubjectPattern builds the subscription pattern for the observe
+// --process/--type flags, rejecting unknown event types up front.
+func observeSubjectPattern(process, eventType string) (string, error) {
+	var opts []observability.SubjectOption
+	switch {
+	case eventType != "":
+		parsed, err := observability.ParseEventType(eventType)
+		if err != nil {
+			return "", err
+		}
+		if process != "" {
+			opts = append(opts, observability.ForProcessAndType(process, parsed))
+		} else {
+			opts = append(opts, observability.ForEventType(parsed))
+		}
+	case process != "":
+		opts = append(opts, observability.ForProcess(process))
+	default:
+		opts = append(opts, observability.AllEvents())
+	}
+	return observability.BuildSubjectPattern(opts...)
+}
+
+// printObservedEvent writes one event the way 'observe watch' displays it.
+func printObservedEvent(w io.Writer, evt observability.Event, jsonOutput bool) {
+	if jsonOutput {
+		data, _ := evt.MarshalJSON()
+		fmt.Fprintln(w, string(data))
+		return
+	}
+	// Human-readable output with timestamp and severity
+	timestamp := evt.Timestamp.Format("15:04:05.000")
+	fmt.Fprintf(w, "%s %s %s\n", timestamp, severityIcon(evt.Severity()), evt.String())
+}
+
 func severityIcon(severity observability.Severity) string {
 	switch severity {
 	case observability.SeverityError:
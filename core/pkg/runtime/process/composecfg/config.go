@@ -1,6 +1,9 @@
 package composecfg
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sort"
+)
 
 // Config describes Process Compose specific options for a process entry.
 type Config struct {
@@ -62,6 +65,19 @@ type Log struct {
 	Level      string `json:"level,omitempty"`
 }
 
+// Dependencies returns the names of the processes this one depends on, sorted.
+func (c *Config) Dependencies() []string {
+	if c == nil || len(c.DependsOn) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(c.DependsOn))
+	for name := range c.DependsOn {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Map converts the configuration into a generic map for template merging.
 func (c *Config) Map() map[string]any {
 	if c == nil {
@@ -10,8 +10,11 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 const (
@@ -404,3 +407,73 @@ func decodeComposeError(resp *http.Response) error {
 	}
 	return fmt.Errorf("process-compose: %s", resp.Status)
 }
+
+// ComposeLogLine is a single log line streamed from Process Compose.
+type ComposeLogLine struct {
+	Process string `json:"process"`
+	Message string `json:"message"`
+}
+
+// StreamComposeProcessLogs follows a process's logs over the Process Compose
+// websocket API, calling fn for each line until ctx is cancelled, the server
+// closes the stream, or fn returns an error. offset selects how many existing
+// lines to replay before following. Cancellation returns nil.
+func StreamComposeProcessLogs(ctx context.Context, port int, name string, offset int, fn func(ComposeLogLine) error) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("process name is required")
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if port <= 0 {
+		port = composeServerPort
+	}
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("follow", "true")
+	wsURL := fmt.Sprintf("ws://%s:%d/process/logs/ws?%s", composeDefaultHost, port, query.Encode())
+
+	dialer := websocket.Dialer{HandshakeTimeout: composeRequestTimeout}
+	conn, resp, err := dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		if resp != nil {
+			defer resp.Body.Close()
+			return decodeComposeError(resp)
+		}
+		if isConnErr(err) {
+			return ErrComposeUnavailable
+		}
+		return err
+	}
+	defer conn.Close()
+
+	// Unblock ReadJSON when the caller cancels.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		_ = conn.Close()
+	})
+	defer stop()
+
+	for {
+		var msg struct {
+			Message     string `json:"message"`
+			ProcessName string `json:"process_name"`
+		}
+		if err := conn.ReadJSON(&msg); err != nil {
+			if ctx.Err() != nil || websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return fmt.Errorf("stream logs for %s: %w", name, err)
+		}
+		process := msg.ProcessName
+		if process == "" {
+			process = name
+		}
+		if err := fn(ComposeLogLine{Process: process, Message: msg.Message}); err != nil {
+			return err
+		}
+	}
+}
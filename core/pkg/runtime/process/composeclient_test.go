@@ -0,0 +1,81 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestStreamComposeProcessLogs(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/process/logs/ws" || r.URL.Query().Get("name") != "nats" || r.URL.Query().Get("follow") != "true" {
+			http.Error(w, "unexpected request "+r.URL.String(), http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, line := range []string{"one", "two", "three"} {
+			_ = conn.WriteJSON(map[string]string{"message": line, "process_name": "nats"})
+		}
+		// Keep the stream open until the client goes away.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []string
+	errStop := errors.New("stop")
+	err := StreamComposeProcessLogs(ctx, port, "nats", 0, func(line ComposeLogLine) error {
+		if line.Process != "nats" {
+			t.Fatalf("unexpected process %q", line.Process)
+		}
+		got = append(got, line.Message)
+		if len(got) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Fatalf("got lines %v", got)
+	}
+
+	// Cancellation ends the stream cleanly.
+	cancelCtx, cancelStream := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- StreamComposeProcessLogs(cancelCtx, port, "nats", 0, func(ComposeLogLine) error { return nil })
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancelStream()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected nil on cancellation, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream did not stop after cancellation")
+	}
+}
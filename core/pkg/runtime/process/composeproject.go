@@ -0,0 +1,268 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ComposeProject is a Process Compose project definition in the JSON shape
+// accepted by POST /project. Process configs are kept as generic JSON objects
+// so fields this package does not model survive a round trip.
+type ComposeProject struct {
+	Processes map[string]map[string]any `json:"processes"`
+}
+
+// composeDerivedFields are filled in by Process Compose at load time (replica
+// bookkeeping, the resolved shell, the raw YAML). They are not part of a
+// project definition and Process Compose ignores them when comparing
+// processes on update. Args is derived as well but is compared, so it stays.
+var composeDerivedFields = []string{"OriginalConfig", "ReplicaNum", "ReplicaName", "Executable"}
+
+// ParseComposeProject decodes a project payload such as the one passed to
+// `core stack project update --file`.
+func ParseComposeProject(data []byte) (*ComposeProject, error) {
+	var project ComposeProject
+	if err := json.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("decode project: %w", err)
+	}
+	if project.Processes == nil {
+		return nil, fmt.Errorf("decode project: no processes defined")
+	}
+	return &project, nil
+}
+
+// FetchComposeProject reconstructs the running project definition from the
+// configuration Process Compose reports for each process. Replicas share one
+// definition and are collapsed back to it.
+func FetchComposeProject(ctx context.Context, port int) (*ComposeProject, error) {
+	states, err := FetchComposeProcesses(ctx, port)
+	if err != nil {
+		return nil, err
+	}
+	project := &ComposeProject{Processes: make(map[string]map[string]any, len(states))}
+	for _, st := range states {
+		cfg, err := fetchComposeProcessInfo(ctx, port, st.Name)
+		if err != nil {
+			return nil, fmt.Errorf("process %s: %w", st.Name, err)
+		}
+		name := st.Name
+		if base, ok := cfg["Name"].(string); ok && base != "" {
+			name = base
+		}
+		project.Processes[name] = cfg
+	}
+	return project, nil
+}
+
+func fetchComposeProcessInfo(ctx context.Context, port int, name string) (map[string]any, error) {
+	resp, err := composeDo(ctx, http.MethodGet, composeBaseURL(port)+"/process/info/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeComposeError(resp)
+	}
+	var cfg map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ProjectDiff describes what POST /project would do to the running project.
+// Process Compose replaces the whole process set, so processes missing from
+// the payload are removed.
+type ProjectDiff struct {
+	Added   []string      `json:"added,omitempty"`
+	Removed []string      `json:"removed,omitempty"`
+	Changed []ProcessDiff `json:"changed,omitempty"`
+}
+
+// ProcessDiff lists the changed fields of one process.
+type ProcessDiff struct {
+	Name   string        `json:"name"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// FieldChange is a single changed field. Path is dot-separated for nested
+// objects; Old or New is nil when the field is added or removed.
+type FieldChange struct {
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// Empty reports whether applying the project would change nothing.
+func (d ProjectDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffComposeProjects compares the running project with a proposed one.
+// Derived runtime fields are ignored and zero values count as unset, so a
+// hand-written payload that omits defaults does not show spurious changes.
+func DiffComposeProjects(current, proposed *ComposeProject) ProjectDiff {
+	var diff ProjectDiff
+	for name, next := range proposed.Processes {
+		prev, ok := current.Processes[name]
+		if !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		var fields []FieldChange
+		diffObjects("", comparableConfig(prev), comparableConfig(next), &fields)
+		if len(fields) > 0 {
+			sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+			diff.Changed = append(diff.Changed, ProcessDiff{Name: name, Fields: fields})
+		}
+	}
+	for name := range current.Processes {
+		if _, ok := proposed.Processes[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+	return diff
+}
+
+func comparableConfig(cfg map[string]any) map[string]any {
+	pruned, _ := pruneZero(definitionFields(cfg)).(map[string]any)
+	return pruned
+}
+
+// definitionFields copies cfg without the derived runtime fields.
+func definitionFields(cfg map[string]any) map[string]any {
+	out := make(map[string]any, len(cfg))
+	for k, v := range cfg {
+		out[k] = v
+	}
+	for _, k := range composeDerivedFields {
+		delete(out, k)
+	}
+	return out
+}
+
+// NormalizeComposeProject returns a copy of project with derived runtime
+// fields removed, leaving a definition that can be committed and later fed
+// back to POST /project unchanged. Zero values are kept: Process Compose
+// compares some fields with reflect.DeepEqual, so null and [] are not
+// interchangeable.
+func NormalizeComposeProject(project *ComposeProject) *ComposeProject {
+	out := &ComposeProject{Processes: make(map[string]map[string]any, len(project.Processes))}
+	for name, cfg := range project.Processes {
+		out.Processes[name] = definitionFields(cfg)
+	}
+	return out
+}
+
+// MarshalComposeProject renders project in its canonical export form:
+// normalized, indented and with object keys sorted, so exports of the same
+// project are byte-for-byte identical and diff cleanly under version control.
+func MarshalComposeProject(project *ComposeProject) ([]byte, error) {
+	data, err := json.MarshalIndent(NormalizeComposeProject(project), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode project: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// pruneZero drops zero-valued entries from JSON objects, recursively. Empty
+// objects and arrays collapse to nil.
+func pruneZero(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			if p := pruneZero(item); p != nil {
+				out[k] = p
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case []any:
+		if len(val) == 0 {
+			return nil
+		}
+		return val
+	case string:
+		if val == "" {
+			return nil
+		}
+	case bool:
+		if !val {
+			return nil
+		}
+	case float64:
+		if val == 0 {
+			return nil
+		}
+	}
+	return v
+}
+
+func diffObjects(prefix string, prev, next map[string]any, out *[]FieldChange) {
+	keys := make(map[string]struct{}, len(prev)+len(next))
+	for k := range prev {
+		keys[k] = struct{}{}
+	}
+	for k := range next {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		a, b := prev[k], next[k]
+		am, aObj := a.(map[string]any)
+		bm, bObj := b.(map[string]any)
+		switch {
+		case aObj && bObj:
+			diffObjects(path, am, bm, out)
+		case !reflect.DeepEqual(a, b):
+			*out = append(*out, FieldChange{Path: path, Old: a, New: b})
+		}
+	}
+}
+
+// String renders the diff in a compact, human-readable form.
+func (d ProjectDiff) String() string {
+	if d.Empty() {
+		return "No changes"
+	}
+	var b strings.Builder
+	for _, name := range d.Added {
+		fmt.Fprintf(&b, "+ %s (added)\n", name)
+	}
+	for _, name := range d.Removed {
+		fmt.Fprintf(&b, "- %s (removed)\n", name)
+	}
+	for _, pd := range d.Changed {
+		fmt.Fprintf(&b, "~ %s\n", pd.Name)
+		for _, f := range pd.Fields {
+			fmt.Fprintf(&b, "    %s: %s -> %s\n", f.Path, formatDiffValue(f.Old), formatDiffValue(f.New))
+		}
+	}
+	return b.String()
+}
+
+func formatDiffValue(v any) string {
+	if v == nil {
+		return "(unset)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
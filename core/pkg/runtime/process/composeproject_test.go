@@ -0,0 +1,138 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeComposeProjectServer serves /processes and /process/info/{name} for the
+// given process configs.
+func fakeComposeProjectServer(t *testing.T, configs map[string]map[string]any) int {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/processes":
+			var states []ComposeProcessState
+			for name := range configs {
+				states = append(states, ComposeProcessState{Name: name})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": states})
+		case strings.HasPrefix(r.URL.Path, "/process/info/"):
+			cfg, ok := configs[strings.TrimPrefix(r.URL.Path, "/process/info/")]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "no such process"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(cfg)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return port
+}
+
+func TestDiffComposeProjects(t *testing.T) {
+	port := fakeComposeProjectServer(t, map[string]map[string]any{
+		"nats": {
+			"Name": "nats", "Command": "nats-server", "Disabled": false, "Executable": "/bin/bash",
+			"RestartPolicy": map[string]any{"Restart": "always", "MaxRestarts": float64(0)},
+		},
+		"caddy": {"Name": "caddy", "Command": "caddy run"},
+	})
+	current, err := FetchComposeProject(context.Background(), port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proposed, err := ParseComposeProject([]byte(`{"processes": {
+		"nats": {"Name": "nats", "Command": "nats-server -js", "RestartPolicy": {"Restart": "always"}},
+		"web": {"Name": "web", "Command": "./web"}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := DiffComposeProjects(current, proposed)
+	if strings.Join(diff.Added, ",") != "web" || strings.Join(diff.Removed, ",") != "caddy" {
+		t.Fatalf("added=%v removed=%v", diff.Added, diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Name != "nats" {
+		t.Fatalf("changed = %+v", diff.Changed)
+	}
+	// Zero values and derived fields (Executable) are not reported.
+	fields := diff.Changed[0].Fields
+	if len(fields) != 1 || fields[0].Path != "Command" || fields[0].New != "nats-server -js" {
+		t.Fatalf("fields = %+v", fields)
+	}
+	if !strings.Contains(diff.String(), `Command: "nats-server" -> "nats-server -js"`) {
+		t.Errorf("unexpected rendering:\n%s", diff)
+	}
+
+	nested, _ := ParseComposeProject([]byte(`{"processes": {
+		"nats": {"Name": "nats", "Command": "nats-server", "RestartPolicy": {"Restart": "on_failure"}},
+		"caddy": {"Name": "caddy", "Command": "caddy run"}
+	}}`))
+	diff = DiffComposeProjects(current, nested)
+	if len(diff.Changed) != 1 || diff.Changed[0].Fields[0].Path != "RestartPolicy.Restart" {
+		t.Fatalf("nested diff = %+v", diff)
+	}
+}
+
+func TestComposeProjectExportRoundTrip(t *testing.T) {
+	configs := map[string]map[string]any{
+		"nats": {
+			"Name": "nats", "Command": "nats-server -js", "Args": []any{"-c", "nats-server -js"},
+			"Environment": []any{}, "Executable": "/bin/bash", "ReplicaNum": float64(0),
+			"ReplicaName": "nats", "OriginalConfig": "command: nats-server -js\n",
+			"DependsOn": map[string]any{"caddy": map[string]any{"condition": "process_healthy"}},
+		},
+		"caddy": {"Name": "caddy", "Command": "caddy run", "Environment": nil},
+	}
+	port := fakeComposeProjectServer(t, configs)
+
+	current, err := FetchComposeProject(context.Background(), port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exported, err := MarshalComposeProject(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := MarshalComposeProject(current)
+	if string(exported) != string(again) {
+		t.Fatal("export is not deterministic")
+	}
+	for _, volatile := range []string{"OriginalConfig", "ReplicaNum", "ReplicaName", "Executable"} {
+		if strings.Contains(string(exported), volatile) {
+			t.Errorf("export contains derived field %s:\n%s", volatile, exported)
+		}
+	}
+	if !strings.Contains(string(exported), `"Environment": []`) || !strings.Contains(string(exported), `"Environment": null`) {
+		t.Errorf("export should keep empty and null values distinct:\n%s", exported)
+	}
+
+	// Feeding the export back as an update must be a no-op.
+	reimported, err := ParseComposeProject(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffComposeProjects(current, reimported); !diff.Empty() {
+		t.Fatalf("round trip reported changes:\n%s", diff)
+	}
+	// Process Compose compares with reflect.DeepEqual, so the payload must
+	// match the normalized project exactly, not just up to zero values.
+	if !reflect.DeepEqual(NormalizeComposeProject(current), reimported) {
+		t.Fatal("reimported project differs from the exported one")
+	}
+}
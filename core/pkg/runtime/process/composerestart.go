@@ -0,0 +1,155 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	caddyservice "github.com/joeblew999/infra/core/services/caddy"
+	natssvc "github.com/joeblew999/infra/core/services/nats"
+	pocketbasesvc "github.com/joeblew999/infra/core/services/pocketbase"
+)
+
+// StackDependencies returns the dependency graph of the core stack, keyed by
+// Process Compose process name. Each service declares its own dependencies in
+// its manifest.
+func StackDependencies() (map[string][]string, error) {
+	natsSpec, err := natssvc.LoadSpec()
+	if err != nil {
+		return nil, fmt.Errorf("nats spec: %w", err)
+	}
+	pbSpec, err := pocketbasesvc.LoadSpec()
+	if err != nil {
+		return nil, fmt.Errorf("pocketbase spec: %w", err)
+	}
+	caddyCfg, err := caddyservice.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("caddy config: %w", err)
+	}
+	return map[string][]string{
+		"nats":       natsSpec.Dependencies(),
+		"pocketbase": pbSpec.Dependencies(),
+		"caddy":      caddyCfg.Dependencies(),
+	}, nil
+}
+
+// DependencyOrder sorts the graph so every process comes after the processes
+// it depends on. Ties are broken by name so the order is stable. Unknown
+// dependencies and cycles are errors.
+func DependencyOrder(deps map[string][]string) ([]string, error) {
+	pending := make(map[string]int, len(deps))
+	dependents := make(map[string][]string, len(deps))
+	for name, requires := range deps {
+		pending[name] = len(requires)
+		for _, dep := range requires {
+			if _, ok := deps[dep]; !ok {
+				return nil, fmt.Errorf("%s depends on unknown process %q", name, dep)
+			}
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready []string
+	for name, count := range pending {
+		if count == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := make([]string, 0, len(deps))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) != len(deps) {
+		var cycle []string
+		for name, count := range pending {
+			if count > 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("dependency cycle between %s", strings.Join(cycle, ", "))
+	}
+	return order, nil
+}
+
+// RestartStackOptions controls RestartComposeStack.
+type RestartStackOptions struct {
+	Timeout  time.Duration // Per-process limit for stopping and becoming healthy (default: 60s)
+	Interval time.Duration // Polling interval while waiting (default: 500ms)
+
+	// Progress, when set, is called before each step with the action
+	// ("stopping", "starting") or result ("stopped", "healthy").
+	Progress func(action, name string)
+}
+
+// RestartComposeStack stops the processes in reverse order, then starts them
+// in order, waiting for each to become healthy before starting the next. It
+// stops at the first process that fails to stop or come healthy.
+func RestartComposeStack(ctx context.Context, port int, order []string, opts RestartStackOptions) error {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 60 * time.Second
+	}
+	progress := opts.Progress
+	if progress == nil {
+		progress = func(string, string) {}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		name := order[i]
+		progress("stopping", name)
+		if err := StopComposeProcess(ctx, port, name); err != nil {
+			return fmt.Errorf("stop %s: %w", name, err)
+		}
+		if _, err := waitForRestartStep(ctx, port, name, ComposeConditionStopped, opts); err != nil {
+			return err
+		}
+		progress("stopped", name)
+	}
+
+	for _, name := range order {
+		progress("starting", name)
+		if err := StartComposeProcess(ctx, port, name); err != nil {
+			return fmt.Errorf("start %s: %w", name, err)
+		}
+		if _, err := waitForRestartStep(ctx, port, name, ComposeConditionHealthy, opts); err != nil {
+			return err
+		}
+		progress("healthy", name)
+	}
+	return nil
+}
+
+func waitForRestartStep(ctx context.Context, port int, name string, cond ComposeCondition, opts RestartStackOptions) (*ComposeProcessState, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	state, err := WaitForComposeProcess(waitCtx, port, name, cond, opts.Interval)
+	if err == nil {
+		return state, nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		status := "process not found"
+		if state != nil {
+			status = "status: " + state.Status
+			if state.HasHealthProbe && state.Health != "" {
+				status += ", health: " + state.Health
+			}
+		}
+		return state, fmt.Errorf("%s did not become %s within %s (%s)", name, cond, opts.Timeout, status)
+	}
+	return state, fmt.Errorf("wait for %s to be %s: %w", name, cond, err)
+}
@@ -0,0 +1,82 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStackDependencyOrder(t *testing.T) {
+	deps, err := StackDependencies()
+	if err != nil {
+		t.Fatalf("StackDependencies: %v", err)
+	}
+	order, err := DependencyOrder(deps)
+	if err != nil {
+		t.Fatalf("DependencyOrder: %v", err)
+	}
+	if want := []string{"nats", "pocketbase", "caddy"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+
+	if _, err := DependencyOrder(map[string][]string{"a": {"b"}, "b": {"a"}}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if _, err := DependencyOrder(map[string][]string{"a": {"missing"}}); err == nil {
+		t.Fatal("expected error for unknown dependency")
+	}
+}
+
+func TestRestartComposeStack(t *testing.T) {
+	var mu sync.Mutex
+	running := map[string]bool{"nats": true, "pocketbase": true, "caddy": true}
+	var calls []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/process/stop/"):
+			name := strings.TrimPrefix(r.URL.Path, "/process/stop/")
+			running[name] = false
+			calls = append(calls, "stop "+name)
+		case strings.HasPrefix(r.URL.Path, "/process/start/"):
+			name := strings.TrimPrefix(r.URL.Path, "/process/start/")
+			// caddy never comes back, to exercise fail-fast.
+			running[name] = name != "caddy"
+			calls = append(calls, "start "+name)
+		case r.URL.Path == "/processes":
+			var states []ComposeProcessState
+			for name, up := range running {
+				states = append(states, ComposeProcessState{Name: name, IsRunning: up})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": states})
+		}
+	}))
+	defer srv.Close()
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	err := RestartComposeStack(context.Background(), port, []string{"nats", "pocketbase", "caddy"}, RestartStackOptions{
+		Timeout:  100 * time.Millisecond,
+		Interval: 10 * time.Millisecond,
+	})
+	if err == nil || !strings.Contains(err.Error(), "caddy did not become healthy") {
+		t.Fatalf("expected caddy health failure, got %v", err)
+	}
+
+	want := []string{"stop caddy", "stop pocketbase", "stop nats", "start nats", "start pocketbase", "start caddy"}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,82 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ComposeCondition is a process state WaitForComposeProcess can block on.
+type ComposeCondition string
+
+const (
+	ComposeConditionRunning ComposeCondition = "running"
+	ComposeConditionHealthy ComposeCondition = "healthy"
+	ComposeConditionStopped ComposeCondition = "stopped"
+)
+
+// ParseComposeCondition validates a condition name (running, healthy, stopped).
+func ParseComposeCondition(value string) (ComposeCondition, error) {
+	switch cond := ComposeCondition(strings.ToLower(strings.TrimSpace(value))); cond {
+	case ComposeConditionRunning, ComposeConditionHealthy, ComposeConditionStopped:
+		return cond, nil
+	default:
+		return "", fmt.Errorf("unknown condition %q (want running, healthy, or stopped)", value)
+	}
+}
+
+// Satisfied reports whether the process state meets the condition. Processes
+// without a readiness probe count as healthy once they are running.
+func (c ComposeCondition) Satisfied(st ComposeProcessState) bool {
+	switch c {
+	case ComposeConditionRunning:
+		return st.IsRunning
+	case ComposeConditionHealthy:
+		if !st.IsRunning {
+			return false
+		}
+		return !st.HasHealthProbe || st.Health == "Ready"
+	case ComposeConditionStopped:
+		return !st.IsRunning
+	default:
+		return false
+	}
+}
+
+// WaitForComposeProcess polls Process Compose until the named process meets
+// the condition or ctx is done. Missing processes and an unreachable Process
+// Compose server are retried, since both are expected while a stack boots. On
+// timeout the last observed state (if any) is returned with the context error.
+func WaitForComposeProcess(ctx context.Context, port int, name string, cond ComposeCondition, interval time.Duration) (*ComposeProcessState, error) {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *ComposeProcessState
+	for {
+		state, err := FetchComposeProcess(ctx, port, name)
+		switch {
+		case err == nil:
+			last = state
+			if cond.Satisfied(*state) {
+				return state, nil
+			}
+		case errors.Is(err, ErrComposeProcessNotFound), errors.Is(err, ErrComposeUnavailable):
+			// Keep polling; the stack may still be starting.
+		case ctx.Err() != nil:
+			return last, ctx.Err()
+		default:
+			return last, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
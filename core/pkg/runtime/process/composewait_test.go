@@ -0,0 +1,83 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestComposeConditionSatisfied(t *testing.T) {
+	running := ComposeProcessState{IsRunning: true}
+	probing := ComposeProcessState{IsRunning: true, HasHealthProbe: true, Health: "Not Ready"}
+	ready := ComposeProcessState{IsRunning: true, HasHealthProbe: true, Health: "Ready"}
+	stopped := ComposeProcessState{}
+
+	cases := []struct {
+		cond  ComposeCondition
+		state ComposeProcessState
+		want  bool
+	}{
+		{ComposeConditionRunning, running, true},
+		{ComposeConditionRunning, stopped, false},
+		{ComposeConditionHealthy, running, true},
+		{ComposeConditionHealthy, probing, false},
+		{ComposeConditionHealthy, ready, true},
+		{ComposeConditionStopped, stopped, true},
+		{ComposeConditionStopped, ready, false},
+	}
+	for _, tc := range cases {
+		if got := tc.cond.Satisfied(tc.state); got != tc.want {
+			t.Errorf("%s.Satisfied(%+v) = %v, want %v", tc.cond, tc.state, got, tc.want)
+		}
+	}
+
+	if _, err := ParseComposeCondition("ready"); err == nil {
+		t.Fatal("expected error for unknown condition")
+	}
+}
+
+func TestWaitForComposeProcess(t *testing.T) {
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := polls.Add(1)
+		states := []ComposeProcessState{}
+		switch {
+		case n >= 3:
+			states = append(states, ComposeProcessState{Name: "nats", IsRunning: true, HasHealthProbe: true, Health: "Ready"})
+		case n == 2:
+			states = append(states, ComposeProcessState{Name: "nats", IsRunning: true, HasHealthProbe: true, Health: "Not Ready"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": states})
+	}))
+	defer srv.Close()
+
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	state, err := WaitForComposeProcess(ctx, port, "nats", ComposeConditionHealthy, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForComposeProcess: %v", err)
+	}
+	if state.Health != "Ready" || polls.Load() != 3 {
+		t.Fatalf("state=%+v polls=%d", state, polls.Load())
+	}
+
+	timeoutCtx, cancelTimeout := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelTimeout()
+	state, err = WaitForComposeProcess(timeoutCtx, port, "nats", ComposeConditionStopped, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if state == nil || !state.IsRunning {
+		t.Fatalf("expected last observed state on timeout, got %+v", state)
+	}
+}
@@ -204,16 +204,10 @@ func buildComposeDefinition(root string) (map[string]any, error) {
 	pbEnv := pbSpec.ResolveEnv(pbPaths)
 	pbArgs := relativeArgs(root, pbSpec.ResolveArgs(pbPaths))
 	pbEntry := composeProcessEntryWithName(root, "pocketbase", relativeCommand(root, pbPaths["pocketbase"]), pbArgs, pbEnv, pbSpec.ComposeOverrides())
-	ensureDependsOn(pbEntry, map[string]map[string]any{
-		"nats": {"condition": "process_healthy"},
-	})
 	processes["pocketbase"] = pbEntry
 
 	caddyEnv := caddyCfg.Process.Env
 	caddyEntry := composeProcessEntryWithName(root, "caddy", relativeCommand(root, caddyPaths["caddy"]), nil, caddyEnv, caddyCfg.ComposeOverrides())
-	ensureDependsOn(caddyEntry, map[string]map[string]any{
-		"pocketbase": {"condition": "process_healthy"},
-	})
 	processes["caddy"] = caddyEntry
 
 	return map[string]any{
@@ -296,20 +290,6 @@ func composeProcessEntryWithName(root, name, command string, args []string, env
 	return entry
 }
 
-func ensureDependsOn(entry map[string]any, defaults map[string]map[string]any) {
-	if len(defaults) == 0 {
-		return
-	}
-	if _, ok := entry["depends_on"]; ok {
-		return
-	}
-	depends := make(map[string]any, len(defaults))
-	for name, cfg := range defaults {
-		depends[name] = cloneValue(cfg)
-	}
-	entry["depends_on"] = depends
-}
-
 func ensureRestartPolicy(entry map[string]any) {
 	val, ok := entry["availability"]
 	if !ok {
@@ -57,6 +57,25 @@ func LoadConfig() (*Config, error) {
 	return &cfg, nil
 }
 
+// HealthPath is the proxied health endpoint Caddy serves on its HTTP port.
+const HealthPath = "/api/health"
+
+// HealthPort returns the port that serves HealthPath.
+func (c *Config) HealthPort() int {
+	return c.Ports.HTTP.Port
+}
+
+// HealthURL returns the local health endpoint derived from the manifest ports.
+func (c *Config) HealthURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d%s", c.HealthPort(), HealthPath)
+}
+
+// Dependencies returns the processes that must be healthy before Caddy starts,
+// as declared by process.compose.depends_on in the manifest.
+func (c *Config) Dependencies() []string {
+	return c.Process.Compose.Dependencies()
+}
+
 // ComposeOverrides returns the Process Compose overrides defined in the manifest.
 func (c *Config) ComposeOverrides() map[string]any {
 	if c == nil || c.Process.Compose == nil {
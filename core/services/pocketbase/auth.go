@@ -2,9 +2,9 @@
 package pocketbase
 
 import (
-	"bytes"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -43,8 +43,8 @@ WHAT'S INSIDE (single file)
 
 - Provider-agnostic (Google, Apple, …):
   GET  /api/ds/auth-methods?collection=users        → PB /auth-methods
-  GET  /api/ds/oauth-start?provider=...&redirect=.. → builds provider authURL with redirect_url
-  POST /api/ds/auth-with-oauth2?collection=users    → PB /auth-with-oauth2
+  GET  /api/ds/oauth-start?provider=...&redirect=.. → builds provider authURL with redirect_url; remembers state (httpOnly cookie)
+  POST /api/ds/auth-with-oauth2?collection=users&state=.. → PB /auth-with-oauth2 (state must match oauth-start, 10m TTL)
   POST /api/ds/request-otp                          → PB /request-otp
   POST /api/ds/auth-with-otp                        → PB /auth-with-otp (MFA continuation supported)
 
@@ -69,6 +69,7 @@ WHAT'S INSIDE (single file)
 func RegisterDatastarAuth(app *pocketbase.PocketBase, customFS *embed.FS) {
 	// Use custom FS if provided, otherwise use the package-level embedFS
 	registerDatastarRoutes(app)
+	registerAuthFanout(app)
 }
 
 func registerDatastarRoutes(app *pocketbase.PocketBase) {
@@ -158,7 +159,7 @@ func registerDatastarRoutes(app *pocketbase.PocketBase) {
 			}
 			hub.patch(rec.Id, toAuthSignal(rec))
 			return nil
-		}).Bind(apis.RequireGuestOnly())
+		}).Bind(apis.RequireGuestOnly()).BindFunc(authLimiter.middleware)
 
 		// Refresh (PB Go API)
 		se.Router.POST("/api/ds/refresh", func(e *core.RequestEvent) error {
@@ -206,15 +207,16 @@ func registerDatastarRoutes(app *pocketbase.PocketBase) {
 				return apis.NewBadRequestError("provider and redirect are required", nil)
 			}
 			u := base(e) + "/api/collections/" + url.PathEscape(collection) + "/auth-methods"
-			req, _ := http.NewRequestWithContext(e.Request.Context(), http.MethodGet, u, nil)
-			copyAuth(req, e.Request)
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := pbUpstream.do(e.Request.Context(), http.MethodGet, u, authHeader(e.Request), nil)
 			if err != nil {
-				return apis.NewApiError(500, "upstream error", err)
+				return upstreamError(err)
 			}
 			defer resp.Body.Close()
 			if resp.StatusCode >= 400 {
-				b, _ := io.ReadAll(resp.Body)
+				b, err := io.ReadAll(resp.Body)
+				if err != nil {
+					return upstreamError(err)
+				}
 				return apis.NewApiError(resp.StatusCode, "upstream: "+string(b), nil)
 			}
 			var am struct {
@@ -228,6 +230,9 @@ func registerDatastarRoutes(app *pocketbase.PocketBase) {
 				} `json:"oauth2"`
 			}
 			if err := json.NewDecoder(resp.Body).Decode(&am); err != nil {
+				if errors.Is(err, errResponseTooLarge) {
+					return upstreamError(err)
+				}
 				return apis.NewApiError(500, "decode error", err)
 			}
 			for _, p := range am.OAuth2.Providers {
@@ -239,6 +244,7 @@ func registerDatastarRoutes(app *pocketbase.PocketBase) {
 					q := au.Query()
 					q.Set("redirect_url", redirect)
 					au.RawQuery = q.Encode()
+					issueOAuthState(e.Response, e.Request, p.State, time.Now())
 					return e.JSON(http.StatusOK, map[string]any{
 						"authURL":  au.String(),
 						"state":    p.State,
@@ -253,12 +259,15 @@ func registerDatastarRoutes(app *pocketbase.PocketBase) {
 		se.Router.POST("/api/ds/request-otp", func(e *core.RequestEvent) error {
 			u := base(e) + "/api/collections/" + url.PathEscape(coll(e)) + "/request-otp" + keepQuery(e, "collection")
 			return forward(e, http.MethodPost, u)
-		})
+		}).BindFunc(authLimiter.middleware)
 		se.Router.POST("/api/ds/auth-with-otp", func(e *core.RequestEvent) error {
 			u := base(e) + "/api/collections/" + url.PathEscape(coll(e)) + "/auth-with-otp" + keepQuery(e, "collection")
 			return forward(e, http.MethodPost, u)
-		})
+		}).BindFunc(authLimiter.middleware)
 		se.Router.POST("/api/ds/auth-with-oauth2", func(e *core.RequestEvent) error {
+			if err := verifyOAuthState(e.Response, e.Request, time.Now()); err != nil {
+				return err
+			}
 			u := base(e) + "/api/collections/" + url.PathEscape(coll(e)) + "/auth-with-oauth2" + keepQuery(e, "collection")
 			return forward(e, http.MethodPost, u)
 		})
@@ -286,7 +295,7 @@ func registerDatastarRoutes(app *pocketbase.PocketBase) {
 		se.Router.POST("/api/ds/request-password-reset", func(e *core.RequestEvent) error {
 			u := base(e) + "/api/collections/" + url.PathEscape(coll(e)) + "/request-password-reset"
 			return forward(e, http.MethodPost, u)
-		})
+		}).BindFunc(authLimiter.middleware)
 
 		// Confirm password reset
 		se.Router.POST("/api/ds/confirm-password-reset", func(e *core.RequestEvent) error {
@@ -346,44 +355,44 @@ func registerDatastarRoutes(app *pocketbase.PocketBase) {
 				return apis.NewUnauthorizedError("authentication required", nil)
 			}
 			u := base(e) + "/api/collections/" + url.PathEscape(coll(e)) + "/records/" + url.PathEscape(e.Auth.Id)
-			req, _ := http.NewRequestWithContext(e.Request.Context(), http.MethodDelete, u, nil)
-			copyAuth(req, e.Request)
-			resp, err := http.DefaultClient.Do(req)
+			resp, err := pbUpstream.do(e.Request.Context(), http.MethodDelete, u, authHeader(e.Request), nil)
 			if err != nil {
-				return apis.NewApiError(500, "upstream error", err)
+				return upstreamError(err)
 			}
 			defer resp.Body.Close()
-			e.Response.WriteHeader(resp.StatusCode)
-			_, _ = io.Copy(e.Response, resp.Body)
-			return nil
+			return relay(e, resp)
 		}).Bind(apis.RequireAuth("users"))
 
 		// ---- Superuser Features ----
-		// Impersonate user (superuser only)
+		// Impersonate user (superuser only). auditImpersonationAttempts is
+		// bound ahead of the superuser check so rejected attempts are audited too.
 		se.Router.POST("/api/ds/impersonate", func(e *core.RequestEvent) error {
-			// RequireAuth middleware will ensure admin status
-			if e.Auth == nil {
+			// RequireSuperuserAuth already rejects other callers; check again
+			// here so the handler stays safe if it is ever rebound.
+			if e.Auth == nil || e.Auth.Collection().Name != core.CollectionNameSuperusers {
 				return apis.NewForbiddenError("superuser access required", nil)
 			}
-			var body struct {
-				UserID   string `json:"userId"`
-				Duration int    `json:"duration"` // seconds
-			}
+			var body impersonateRequest
 			if err := e.BindBody(&body); err != nil {
 				return apis.NewBadRequestError("invalid payload", err)
 			}
 			collection := coll(e)
-			_, err := e.App.FindRecordById(collection, body.UserID)
+
+			target, err := e.App.FindRecordById(collection, body.UserID)
 			if err != nil {
 				return apis.NewNotFoundError("user not found", err)
 			}
+			if audit, ok := e.Get(impersonationAuditKey).(*ImpersonationAudit); ok {
+				audit.TargetEmail = target.Email()
+			}
+
 			// Use PB's impersonate endpoint
 			u := base(e) + "/api/collections/" + url.PathEscape(collection) + "/impersonate/" + url.PathEscape(body.UserID)
 			if body.Duration > 0 {
 				u += "?duration=" + url.QueryEscape(strings.TrimSpace(fmt.Sprintf("%d", body.Duration)))
 			}
 			return forward(e, http.MethodPost, u)
-		}).Bind(apis.RequireAuth())
+		}).Bind(auditImpersonationAttempts(), apis.RequireSuperuserAuth())
 
 		return se.Next()
 	})
@@ -432,40 +441,37 @@ func keepQuery(e *core.RequestEvent, exclude string) string {
 	return "?" + dst.Encode()
 }
 
-func copyAuth(dst *http.Request, src *http.Request) {
+// authHeader carries the caller's Authorization over to the upstream request.
+func authHeader(src *http.Request) http.Header {
+	h := http.Header{}
 	if v := src.Header.Get("Authorization"); v != "" {
-		dst.Header.Set("Authorization", v)
+		h.Set("Authorization", v)
 	}
+	return h
 }
 
 func forwardGET(e *core.RequestEvent, upstream string) error {
-	req, _ := http.NewRequestWithContext(e.Request.Context(), http.MethodGet, upstream, nil)
-	copyAuth(req, e.Request)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := pbUpstream.do(e.Request.Context(), http.MethodGet, upstream, authHeader(e.Request), nil)
 	if err != nil {
-		return apis.NewApiError(500, "upstream error", err)
+		return upstreamError(err)
 	}
 	defer resp.Body.Close()
-	e.Response.Header().Set("Content-Type", "application/json")
-	e.Response.WriteHeader(resp.StatusCode)
-	_, _ = io.Copy(e.Response, resp.Body)
-	return nil
+	return relay(e, resp)
 }
 
 func forward(e *core.RequestEvent, method, upstream string) error {
-	body, _ := io.ReadAll(e.Request.Body)
-	req, _ := http.NewRequestWithContext(e.Request.Context(), method, upstream, bytes.NewReader(body))
-	copyAuth(req, e.Request)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	body, err := pbUpstream.readRequestBody(e)
+	if err != nil {
+		return err
+	}
+	header := authHeader(e.Request)
+	header.Set("Content-Type", "application/json")
+	resp, err := pbUpstream.do(e.Request.Context(), method, upstream, header, body)
 	if err != nil {
-		return apis.NewApiError(500, "upstream error", err)
+		return upstreamError(err)
 	}
 	defer resp.Body.Close()
-	e.Response.Header().Set("Content-Type", "application/json")
-	e.Response.WriteHeader(resp.StatusCode)
-	_, _ = io.Copy(e.Response, resp.Body)
-	return nil
+	return relay(e, resp)
 }
 
 // minimal $auth payload for the UI (don't leak token)
@@ -490,18 +496,21 @@ func toAuthSignal(rec *core.Record) map[string]any {
 }
 
 // ---- tiny SSE hub keyed by user id ----
+// Connections are local to this instance; with a NATS fan-out attached (see
+// auth_fanout.go) patches also reach users connected to other instances.
 
 type hubT struct {
-	mu   sync.RWMutex
-	conn map[string]map[*datastar.ServerSentEventGenerator]struct{}
+	mu     sync.RWMutex
+	conn   map[string]map[*datastar.ServerSentEventGenerator]struct{}
+	fanout *authFanout
 }
 
 func (h *hubT) add(userID string, s *datastar.ServerSentEventGenerator) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	if h.conn == nil {
 		h.conn = map[string]map[*datastar.ServerSentEventGenerator]struct{}{}
 	}
-	h.mu.Lock()
-	defer h.mu.Unlock()
 	if h.conn[userID] == nil {
 		h.conn[userID] = map[*datastar.ServerSentEventGenerator]struct{}{}
 	}
@@ -517,18 +526,39 @@ func (h *hubT) remove(userID string, s *datastar.ServerSentEventGenerator) {
 		}
 	}
 }
+
+// patch updates $auth for the user on this instance and, when fan-out is
+// enabled, on every other instance.
 func (h *hubT) patch(userID string, payload any) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
 	data, err := json.Marshal(map[string]any{"auth": payload})
 	if err != nil {
 		return
 	}
+	h.patchLocal(userID, data)
+
+	h.mu.RLock()
+	fanout := h.fanout
+	h.mu.RUnlock()
+	if fanout != nil {
+		fanout.publish(userID, data)
+	}
+}
+
+// patchLocal sends already-encoded signals to this instance's connections.
+func (h *hubT) patchLocal(userID string, data []byte) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 	for s := range h.conn[userID] {
 		_ = s.PatchSignals(data)
 	}
 }
 
+func (h *hubT) setFanout(f *authFanout) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fanout = f
+}
+
 var hub hubT
 
 // ---------------- HTML (Datastar) ----------------
@@ -0,0 +1,130 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// ImpersonationAuditSubject receives an ImpersonationAudit for every
+// /api/ds/impersonate call so it can be persisted and alerted on.
+const ImpersonationAuditSubject = "audit.impersonation"
+
+// ImpersonationAudit records a superuser impersonating a user.
+type ImpersonationAudit struct {
+	At               time.Time `json:"at"`
+	ActorID          string    `json:"actor_id"`
+	ActorEmail       string    `json:"actor_email,omitempty"`
+	TargetID         string    `json:"target_id"`
+	TargetEmail      string    `json:"target_email,omitempty"`
+	TargetCollection string    `json:"target_collection"`
+	// DurationSeconds is the requested token lifetime; 0 means the
+	// collection's default auth token duration.
+	DurationSeconds int    `json:"duration_seconds"`
+	RemoteIP        string `json:"remote_ip,omitempty"`
+	// Status is the HTTP status returned to the superuser.
+	Status  int    `json:"status"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// impersonationAuditKey stores the in-flight *ImpersonationAudit on the
+// request so the handler can add details only it knows.
+const impersonationAuditKey = "impersonationAudit"
+
+// impersonateRequest is the /api/ds/impersonate payload.
+type impersonateRequest struct {
+	UserID   string `json:"userId"`
+	Duration int    `json:"duration"` // seconds
+}
+
+// auditImpersonationAttempts audits every call to the route it is bound to,
+// including ones rejected by later middleware (e.g. the superuser check) and
+// ones that fail in the handler or upstream.
+func auditImpersonationAttempts() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Func: func(e *core.RequestEvent) error {
+			// The router makes the body rereadable, so the handler can bind it again.
+			var body impersonateRequest
+			_ = e.BindBody(&body)
+
+			audit := &ImpersonationAudit{
+				At:               time.Now().UTC(),
+				TargetID:         body.UserID,
+				TargetCollection: coll(e),
+				DurationSeconds:  body.Duration,
+				RemoteIP:         e.RealIP(),
+			}
+			if e.Auth != nil {
+				audit.ActorID = e.Auth.Id
+				audit.ActorEmail = e.Auth.Email()
+			}
+			e.Set(impersonationAuditKey, audit)
+
+			err := e.Next()
+			audit.Status = e.Status()
+			if err != nil {
+				audit.Error = err.Error()
+				var apiErr *router.ApiError
+				if errors.As(err, &apiErr) {
+					audit.Status = apiErr.Status
+				} else if audit.Status == 0 {
+					audit.Status = http.StatusInternalServerError
+				}
+			}
+			audit.Success = err == nil && audit.Status < 400
+			auditImpersonation(e, *audit)
+			return err
+		},
+	}
+}
+
+// auditImpersonation writes the event to the app logger and, when the NATS
+// fan-out is connected, publishes it on ImpersonationAuditSubject.
+func auditImpersonation(e *core.RequestEvent, audit ImpersonationAudit) {
+	e.App.Logger().Info("superuser impersonation",
+		"actor_id", audit.ActorID,
+		"actor_email", audit.ActorEmail,
+		"target_id", audit.TargetID,
+		"target_email", audit.TargetEmail,
+		"target_collection", audit.TargetCollection,
+		"duration_seconds", audit.DurationSeconds,
+		"remote_ip", audit.RemoteIP,
+		"status", audit.Status,
+		"success", audit.Success,
+		"error", audit.Error,
+	)
+
+	if data, err := json.Marshal(audit); err == nil {
+		hub.publishEvent(ImpersonationAuditSubject, data)
+	}
+}
+
+// publishEvent sends data on subject through the hub's NATS connection, if
+// the fan-out is attached.
+func (h *hubT) publishEvent(subject string, data []byte) {
+	h.mu.RLock()
+	fanout := h.fanout
+	h.mu.RUnlock()
+	if fanout != nil {
+		fanout.publishSubject(subject, data)
+	}
+}
+
+// publishSubject publishes data on subject while NATS is connected.
+func (f *authFanout) publishSubject(subject string, data []byte) {
+	if !f.nc.IsConnected() {
+		fmt.Fprintf(os.Stderr, "[pocketbase] nats disconnected, %s event only logged\n", subject)
+		return
+	}
+	if err := f.nc.Publish(subject, data); err != nil {
+		fmt.Fprintf(os.Stderr, "[pocketbase] publish %s failed: %v\n", subject, err)
+	}
+}
@@ -0,0 +1,58 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func TestImpersonationAuditPublished(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	sub, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	msgs, err := sub.SubscribeSync(ImpersonationAuditSubject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var h hubT
+	// Without a fan-out the event is only logged.
+	h.publishEvent(ImpersonationAuditSubject, []byte(`{}`))
+
+	fan, err := startAuthFanout(&h, ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fan.close()
+
+	want := ImpersonationAudit{ActorID: "admin1", TargetID: "user1", TargetCollection: "users", DurationSeconds: 60, Status: 200, Success: true}
+	data, _ := json.Marshal(want)
+	h.publishEvent(ImpersonationAuditSubject, data)
+
+	msg, err := msgs.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("audit event not published: %v", err)
+	}
+	var got ImpersonationAudit
+	if err := json.Unmarshal(msg.Data, &got); err != nil || got != want {
+		t.Fatalf("audit = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,128 @@
+package pocketbase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/core"
+
+	runtimecfg "github.com/joeblew999/infra/core/pkg/runtime/config"
+)
+
+// AuthSignalSubject carries $auth patches between PocketBase instances so a
+// user connected to one instance sees sign-in/out performed on another.
+const AuthSignalSubject = "pocketbase.auth.signal"
+
+// authSignalMsg is the wire format on AuthSignalSubject.
+type authSignalMsg struct {
+	Origin  string          `json:"origin"`
+	UserID  string          `json:"user_id"`
+	Signals json.RawMessage `json:"signals"`
+}
+
+// authFanout publishes local auth patches to NATS and applies patches from
+// other instances to the local hub.
+type authFanout struct {
+	nc     *nats.Conn
+	sub    *nats.Subscription
+	origin string
+}
+
+// registerAuthFanout attaches the NATS fan-out (and the shared rate limit
+// state) when the server starts and detaches it on terminate. An unreachable
+// NATS is not fatal: $auth patches then only reach users connected to this
+// instance and rate limits are tracked per instance.
+func registerAuthFanout(app *pocketbase.PocketBase) {
+	var fanout *authFanout
+	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+		natsURL := runtimecfg.Load().Services.NATS
+		f, err := startAuthFanout(&hub, natsURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[pocketbase] auth fan-out disabled, $auth updates stay local: %v\n", err)
+			return se.Next()
+		}
+		fanout = f
+		fmt.Fprintf(os.Stderr, "[pocketbase] auth fan-out via %s\n", natsURL)
+		if err := authLimiter.attachKV(f.nc); err != nil {
+			fmt.Fprintf(os.Stderr, "[pocketbase] auth rate limits stay local: %v\n", err)
+		}
+		return se.Next()
+	})
+	app.OnTerminate().BindFunc(func(e *core.TerminateEvent) error {
+		if fanout != nil {
+			authLimiter.detachKV()
+			hub.setFanout(nil)
+			fanout.close()
+		}
+		return e.Next()
+	})
+}
+
+// startAuthFanout connects the hub to NATS. Callers treat an error as "stay
+// local-only": the hub keeps working for users on this instance.
+func startAuthFanout(h *hubT, natsURL string) (*authFanout, error) {
+	origin, err := newOriginID()
+	if err != nil {
+		return nil, err
+	}
+	nc, err := nats.Connect(natsURL,
+		nats.Name("pocketbase-auth-hub"),
+		nats.Timeout(2*time.Second),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(time.Second),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+
+	f := &authFanout{nc: nc, origin: origin}
+	f.sub, err = nc.Subscribe(AuthSignalSubject, func(msg *nats.Msg) {
+		var in authSignalMsg
+		if err := json.Unmarshal(msg.Data, &in); err != nil || in.Origin == f.origin {
+			return
+		}
+		h.patchLocal(in.UserID, in.Signals)
+	})
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("subscribe %s: %w", AuthSignalSubject, err)
+	}
+	h.setFanout(f)
+	return f, nil
+}
+
+// publish forwards a patch to other instances. While NATS is disconnected
+// patches stay local rather than queueing stale auth state for later.
+func (f *authFanout) publish(userID string, signals []byte) {
+	if !f.nc.IsConnected() {
+		return
+	}
+	data, err := json.Marshal(authSignalMsg{Origin: f.origin, UserID: userID, Signals: signals})
+	if err != nil {
+		return
+	}
+	if err := f.nc.Publish(AuthSignalSubject, data); err != nil {
+		fmt.Fprintf(os.Stderr, "[pocketbase] auth fan-out publish failed: %v\n", err)
+	}
+}
+
+func (f *authFanout) close() {
+	if f.sub != nil {
+		_ = f.sub.Unsubscribe()
+	}
+	f.nc.Close()
+}
+
+func newOriginID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate instance id: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
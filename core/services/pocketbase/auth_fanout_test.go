@@ -0,0 +1,89 @@
+package pocketbase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/starfederation/datastar-go/datastar"
+)
+
+// syncRecorder is an http.ResponseWriter that is safe to read while the
+// fan-out subscription writes to it.
+type syncRecorder struct {
+	mu sync.Mutex
+	*httptest.ResponseRecorder
+}
+
+func (r *syncRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.Write(p)
+}
+
+func (r *syncRecorder) body() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Body.String()
+}
+
+func TestAuthFanoutReachesOtherInstance(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	var hubA, hubB hubT
+	fanA, err := startAuthFanout(&hubA, ns.ClientURL())
+	if err != nil {
+		t.Fatalf("start fan-out A: %v", err)
+	}
+	defer fanA.close()
+	fanB, err := startAuthFanout(&hubB, ns.ClientURL())
+	if err != nil {
+		t.Fatalf("start fan-out B: %v", err)
+	}
+	defer fanB.close()
+	if err := fanB.nc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &syncRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sse := datastar.NewSSE(rec, httptest.NewRequest(http.MethodGet, "/api/ds/sse", nil))
+	hubB.add("user-1", sse)
+
+	hubA.patch("user-1", map[string]any{"signedIn": true, "id": "user-1"})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(rec.body(), `"signedIn":true`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("patch from instance A never reached instance B; body=%q", rec.body())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAuthFanoutUnavailableStaysLocal(t *testing.T) {
+	var h hubT
+	if _, err := startAuthFanout(&h, "nats://127.0.0.1:1"); err == nil {
+		t.Fatal("expected connect error")
+	}
+
+	rec := &syncRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sse := datastar.NewSSE(rec, httptest.NewRequest(http.MethodGet, "/api/ds/sse", nil))
+	h.add("user-1", sse)
+	h.patch("user-1", map[string]any{"signedIn": false})
+
+	if !strings.Contains(rec.body(), `"signedIn":false`) {
+		t.Fatalf("local patch not delivered: %q", rec.body())
+	}
+}
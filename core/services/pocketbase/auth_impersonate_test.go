@@ -0,0 +1,165 @@
+// PocketBase v0.30's Collection.UnmarshalJSON recurses forever when
+// encoding/json is backed by json/v2, so this test needs the v1 implementation.
+
+//go:build !goexperiment.jsonv2
+
+package pocketbase
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	_ "github.com/pocketbase/pocketbase/migrations" // system collections
+)
+
+// newTestAuthServer boots a throwaway PocketBase with the Datastar routes and
+// returns its handler.
+func newTestAuthServer(t *testing.T) (*pocketbase.PocketBase, http.Handler) {
+	t.Helper()
+	app := pocketbase.NewWithConfig(pocketbase.Config{DefaultDataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatalf("bootstrap pocketbase: %v", err)
+	}
+	t.Cleanup(func() { _ = app.ResetBootstrapState() })
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatalf("migrate pocketbase: %v", err)
+	}
+
+	registerDatastarRoutes(app)
+	router, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	se := &core.ServeEvent{App: app, Router: router}
+	if err := app.OnServe().Trigger(se, func(e *core.ServeEvent) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	mux, err := router.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app, mux
+}
+
+func newTestRecord(t *testing.T, app core.App, collection, email string) *core.Record {
+	t.Helper()
+	c, err := app.FindCollectionByNameOrId(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := core.NewRecord(c)
+	rec.SetEmail(email)
+	rec.SetPassword("test-password-123")
+	if err := app.Save(rec); err != nil {
+		t.Fatalf("create %s record: %v", collection, err)
+	}
+	return rec
+}
+
+func TestImpersonateRequiresSuperuser(t *testing.T) {
+	app, handler := newTestAuthServer(t)
+	victim := newTestRecord(t, app, "users", "victim@example.com")
+	user := newTestRecord(t, app, "users", "user@example.com")
+
+	token, err := user.NewAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/ds/impersonate", strings.NewReader(`{"userId":"`+victim.Id+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("regular user impersonating: status = %d, want 403 (body %s)", rec.Code, rec.Body)
+	}
+}
+
+func TestImpersonateAuditsEveryAttempt(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	sub, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	msgs, err := sub.SubscribeSync(ImpersonationAuditSubject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	fan, err := startAuthFanout(&hub, ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		hub.setFanout(nil)
+		fan.close()
+	}()
+
+	app, handler := newTestAuthServer(t)
+	victim := newTestRecord(t, app, "users", "victim@example.com")
+	user := newTestRecord(t, app, "users", "user@example.com")
+	admin := newTestRecord(t, app, core.CollectionNameSuperusers, "admin@example.com")
+
+	for name, tc := range map[string]struct {
+		actor  *core.Record
+		target string
+		status int
+	}{
+		"anonymous":      {nil, victim.Id, http.StatusUnauthorized},
+		"regular user":   {user, victim.Id, http.StatusForbidden},
+		"missing target": {admin, "doesnotexist123", http.StatusNotFound},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/ds/impersonate", strings.NewReader(`{"userId":"`+tc.target+`","duration":60}`))
+			req.Header.Set("Content-Type", "application/json")
+			wantActor := ""
+			if tc.actor != nil {
+				token, err := tc.actor.NewAuthToken()
+				if err != nil {
+					t.Fatal(err)
+				}
+				req.Header.Set("Authorization", token)
+				wantActor = tc.actor.Id
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tc.status, rec.Body)
+			}
+
+			msg, err := msgs.NextMsg(2 * time.Second)
+			if err != nil {
+				t.Fatalf("attempt not audited: %v", err)
+			}
+			var got ImpersonationAudit
+			if err := json.Unmarshal(msg.Data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.ActorID != wantActor || got.TargetID != tc.target || got.DurationSeconds != 60 ||
+				got.Status != tc.status || got.Success || got.Error == "" {
+				t.Errorf("audit = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package pocketbase
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/apis"
+)
+
+const (
+	// oauthStateCookie holds the state issued by /api/ds/oauth-start until the
+	// provider redirects back to /ds/callback.
+	oauthStateCookie = "ds_oauth_state"
+	oauthStateTTL    = 10 * time.Minute
+)
+
+// issueOAuthState remembers state in a short-lived httpOnly cookie. The value
+// carries its own expiry so a stale cookie is rejected even if the browser
+// keeps it around.
+func issueOAuthState(w http.ResponseWriter, r *http.Request, state string, now time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state + "." + strconv.FormatInt(now.Add(oauthStateTTL).Unix(), 10),
+		Path:     "/api/ds/",
+		MaxAge:   int(oauthStateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		// Lax: the cookie must survive the top-level redirect back from the provider.
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// verifyOAuthState checks the state echoed back by the provider against the
+// cookie set by issueOAuthState and clears the cookie; a state is good for
+// one exchange only.
+func verifyOAuthState(w http.ResponseWriter, r *http.Request, now time.Time) error {
+	returned := r.URL.Query().Get("state")
+	c, err := r.Cookie(oauthStateCookie)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Path:     "/api/ds/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if returned == "" {
+		return apis.NewBadRequestError("missing OAuth2 state", nil)
+	}
+	if err != nil {
+		return apis.NewBadRequestError("no OAuth2 sign-in in progress, please start again", nil)
+	}
+	issued, expiresAt, ok := parseOAuthState(c.Value)
+	if !ok || now.After(expiresAt) {
+		return apis.NewBadRequestError("OAuth2 sign-in expired, please start again", nil)
+	}
+	if subtle.ConstantTimeCompare([]byte(issued), []byte(returned)) != 1 {
+		return apis.NewBadRequestError("OAuth2 state mismatch", nil)
+	}
+	return nil
+}
+
+func parseOAuthState(v string) (string, time.Time, bool) {
+	i := strings.LastIndexByte(v, '.')
+	if i <= 0 {
+		return "", time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(v[i+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return v[:i], time.Unix(unix, 0), true
+}
+
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
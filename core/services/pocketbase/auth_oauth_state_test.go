@@ -0,0 +1,60 @@
+package pocketbase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// startOAuth runs issueOAuthState and returns the cookie a browser would keep.
+func startOAuth(t *testing.T, state string, now time.Time) *http.Cookie {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	issueOAuthState(rec, httptest.NewRequest(http.MethodGet, "/api/ds/oauth-start", nil), state, now)
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("expected one httpOnly cookie, got %+v", cookies)
+	}
+	return cookies[0]
+}
+
+func callback(state string, c *http.Cookie) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/ds/auth-with-oauth2?collection=users&state="+state, nil)
+	if c != nil {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+func TestVerifyOAuthState(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := startOAuth(t, "abc123", now)
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		at     time.Time
+		wantOK bool
+	}{
+		{"match", callback("abc123", c), now.Add(time.Minute), true},
+		{"mismatch", callback("evil", c), now.Add(time.Minute), false},
+		{"missing state", callback("", c), now, false},
+		{"no cookie", callback("abc123", nil), now, false},
+		{"expired", callback("abc123", c), now.Add(oauthStateTTL + time.Second), false},
+		{"garbled cookie", callback("abc123", &http.Cookie{Name: oauthStateCookie, Value: "abc123"}), now, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			err := verifyOAuthState(rec, tt.req, tt.at)
+			if (err == nil) != tt.wantOK {
+				t.Fatalf("verifyOAuthState err=%v, want ok=%v", err, tt.wantOK)
+			}
+			cleared := rec.Result().Cookies()
+			if len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+				t.Fatalf("state cookie should be cleared after any exchange, got %+v", cleared)
+			}
+		})
+	}
+}
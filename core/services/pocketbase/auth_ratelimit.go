@@ -0,0 +1,237 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	// EnvAuthRateLimit is the sustained rate of auth attempts per IP, per minute.
+	EnvAuthRateLimit = "POCKETBASE_AUTH_RATE_PER_MINUTE"
+	// EnvAuthRateBurst is how many attempts an IP may make back to back.
+	EnvAuthRateBurst = "POCKETBASE_AUTH_RATE_BURST"
+
+	// authRateLimitBucket is the NATS KV bucket sharing limiter state.
+	authRateLimitBucket = "pocketbase_auth_ratelimit"
+
+	defaultAuthRatePerMinute = 10
+	defaultAuthRateBurst     = 5
+)
+
+// rateLimitConfig describes a token bucket: Burst tokens, refilled at Rate
+// tokens per second.
+type rateLimitConfig struct {
+	Rate  float64
+	Burst int
+}
+
+// loadRateLimitConfig reads the limiter settings from the environment.
+func loadRateLimitConfig() rateLimitConfig {
+	cfg := rateLimitConfig{Rate: defaultAuthRatePerMinute / 60.0, Burst: defaultAuthRateBurst}
+	if v, err := strconv.ParseFloat(os.Getenv(EnvAuthRateLimit), 64); err == nil && v > 0 {
+		cfg.Rate = v / 60.0
+	}
+	if v, err := strconv.Atoi(os.Getenv(EnvAuthRateBurst)); err == nil && v > 0 {
+		cfg.Burst = v
+	}
+	return cfg
+}
+
+// bucketState is a token bucket snapshot.
+type bucketState struct {
+	Tokens float64   `json:"tokens"`
+	Last   time.Time `json:"last"`
+}
+
+// take refills the bucket up to now and tries to spend one token. It returns
+// the new state, whether the attempt is allowed and, if not, how long until a
+// token becomes available.
+func (cfg rateLimitConfig) take(state *bucketState, now time.Time) (bucketState, bool, time.Duration) {
+	next := bucketState{Tokens: float64(cfg.Burst), Last: now}
+	if state != nil {
+		elapsed := now.Sub(state.Last).Seconds()
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		next.Tokens = math.Min(float64(cfg.Burst), state.Tokens+elapsed*cfg.Rate)
+	}
+	if next.Tokens >= 1 {
+		next.Tokens--
+		return next, true, 0
+	}
+	wait := time.Duration((1 - next.Tokens) / cfg.Rate * float64(time.Second))
+	return next, false, wait
+}
+
+// fullAfter is how long an idle bucket takes to refill completely; state
+// older than this carries no information.
+func (cfg rateLimitConfig) fullAfter() time.Duration {
+	return time.Duration(float64(cfg.Burst) / cfg.Rate * float64(time.Second))
+}
+
+// rateLimiter throttles auth attempts per client IP. State is kept in NATS KV
+// when attached so limits hold across instances, and in memory otherwise.
+type rateLimiter struct {
+	cfg rateLimitConfig
+	now func() time.Time
+
+	mu    sync.Mutex
+	local map[string]bucketState
+	kv    nats.KeyValue
+}
+
+func newRateLimiter(cfg rateLimitConfig) *rateLimiter {
+	return &rateLimiter{cfg: cfg, now: time.Now, local: make(map[string]bucketState)}
+}
+
+// attachKV switches the limiter to a shared NATS KV bucket, creating it if
+// needed.
+func (l *rateLimiter) attachKV(nc *nats.Conn) error {
+	js, err := nc.JetStream()
+	if err != nil {
+		return fmt.Errorf("jetstream: %w", err)
+	}
+	kv, err := js.KeyValue(authRateLimitBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		ttl := l.cfg.fullAfter()
+		if ttl < time.Minute {
+			ttl = time.Minute
+		}
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      authRateLimitBucket,
+			Description: "Per-IP token buckets for PocketBase auth endpoints",
+			TTL:         ttl,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("rate limit bucket: %w", err)
+	}
+	l.mu.Lock()
+	l.kv = kv
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *rateLimiter) detachKV() {
+	l.mu.Lock()
+	l.kv = nil
+	l.mu.Unlock()
+}
+
+// allow records an attempt for key and reports whether it may proceed.
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	kv := l.kv
+	l.mu.Unlock()
+
+	if kv != nil {
+		allowed, wait, err := l.allowShared(kv, key)
+		if err == nil {
+			return allowed, wait
+		}
+		fmt.Fprintf(os.Stderr, "[pocketbase] shared rate limit unavailable, using local state: %v\n", err)
+	}
+	return l.allowLocal(key)
+}
+
+func (l *rateLimiter) allowLocal(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var prev *bucketState
+	if st, ok := l.local[key]; ok {
+		prev = &st
+	}
+	next, allowed, wait := l.cfg.take(prev, now)
+	l.local[key] = next
+
+	// Drop buckets that have refilled so the map doesn't grow without bound.
+	if len(l.local) > 1024 {
+		cutoff := now.Add(-l.cfg.fullAfter())
+		for k, st := range l.local {
+			if st.Last.Before(cutoff) {
+				delete(l.local, k)
+			}
+		}
+	}
+	return allowed, wait
+}
+
+// allowShared updates the bucket in KV with optimistic concurrency, retrying
+// when another instance wrote the same key first.
+func (l *rateLimiter) allowShared(kv nats.KeyValue, key string) (bool, time.Duration, error) {
+	kvKey := rateLimitKey(key)
+	for attempt := 0; attempt < 5; attempt++ {
+		var prev *bucketState
+		var revision uint64
+		entry, err := kv.Get(kvKey)
+		switch {
+		case err == nil:
+			var st bucketState
+			if json.Unmarshal(entry.Value(), &st) == nil {
+				prev = &st
+			}
+			revision = entry.Revision()
+		case errors.Is(err, nats.ErrKeyNotFound):
+		default:
+			return false, 0, err
+		}
+
+		next, allowed, wait := l.cfg.take(prev, l.now())
+		data, err := json.Marshal(next)
+		if err != nil {
+			return false, 0, err
+		}
+		if revision == 0 {
+			_, err = kv.Create(kvKey, data)
+		} else {
+			_, err = kv.Update(kvKey, data, revision)
+		}
+		if err == nil {
+			return allowed, wait, nil
+		}
+		if !errors.Is(err, nats.ErrKeyExists) && !isWrongLastSequence(err) {
+			return false, 0, err
+		}
+	}
+	return false, 0, fmt.Errorf("rate limit key %s: too much contention", kvKey)
+}
+
+func isWrongLastSequence(err error) bool {
+	var apiErr *nats.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode == nats.JSErrCodeStreamWrongLastSequence
+}
+
+// rateLimitKey maps a client IP to a valid KV key (IPv6 colons are not allowed).
+func rateLimitKey(ip string) string {
+	return "ip." + strings.NewReplacer(":", "_", "%", "_").Replace(ip)
+}
+
+// middleware rejects requests over the limit with 429 and Retry-After.
+func (l *rateLimiter) middleware(e *core.RequestEvent) error {
+	allowed, wait := l.allow(e.RealIP())
+	if allowed {
+		return e.Next()
+	}
+	secs := int(math.Ceil(wait.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	e.Response.Header().Set("Retry-After", strconv.Itoa(secs))
+	return apis.NewTooManyRequestsError("Too many attempts, please try again later.", nil)
+}
+
+// authLimiter guards the brute-force sensitive auth routes.
+var authLimiter = newRateLimiter(loadRateLimitConfig())
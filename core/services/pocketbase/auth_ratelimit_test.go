@@ -0,0 +1,104 @@
+package pocketbase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func TestRateLimitConfigTake(t *testing.T) {
+	cfg := rateLimitConfig{Rate: 1, Burst: 2} // one token per second
+	now := time.Unix(1000, 0)
+
+	st, ok, _ := cfg.take(nil, now)
+	if !ok || st.Tokens != 1 {
+		t.Fatalf("first attempt: ok=%v tokens=%v", ok, st.Tokens)
+	}
+	st, ok, _ = cfg.take(&st, now)
+	if !ok || st.Tokens != 0 {
+		t.Fatalf("second attempt: ok=%v tokens=%v", ok, st.Tokens)
+	}
+	st, ok, wait := cfg.take(&st, now)
+	if ok || wait != time.Second {
+		t.Fatalf("over limit: ok=%v wait=%v", ok, wait)
+	}
+
+	// Half a second refills half a token: still blocked, half the wait.
+	st, ok, wait = cfg.take(&st, now.Add(500*time.Millisecond))
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("partial refill: ok=%v wait=%v", ok, wait)
+	}
+	// Refill never exceeds the burst.
+	st, _, _ = cfg.take(&st, now.Add(time.Hour))
+	if st.Tokens != 1 {
+		t.Fatalf("refill should cap at burst-1 after taking, got %v", st.Tokens)
+	}
+}
+
+func TestRateLimiterLocalPerIP(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newRateLimiter(rateLimitConfig{Rate: 1.0 / 60, Burst: 3})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.allow("10.0.0.1"); !ok {
+			t.Fatalf("attempt %d should be allowed", i+1)
+		}
+	}
+	ok, wait := l.allow("10.0.0.1")
+	if ok || wait != time.Minute {
+		t.Fatalf("4th attempt: ok=%v wait=%v", ok, wait)
+	}
+	if ok, _ := l.allow("10.0.0.2"); !ok {
+		t.Fatal("a different IP has its own bucket")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := l.allow("10.0.0.1"); !ok {
+		t.Fatal("a token should be available after a minute")
+	}
+}
+
+func TestRateLimiterSharedAcrossInstances(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	cfg := rateLimitConfig{Rate: 1.0 / 60, Burst: 2}
+	var limiters []*rateLimiter
+	for i := 0; i < 2; i++ {
+		nc, err := nats.Connect(ns.ClientURL())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer nc.Close()
+		l := newRateLimiter(cfg)
+		if err := l.attachKV(nc); err != nil {
+			t.Fatalf("attachKV: %v", err)
+		}
+		limiters = append(limiters, l)
+	}
+
+	// Spreading attempts across instances doesn't grant extra tokens.
+	ip := "2001:db8::1"
+	if ok, _ := limiters[0].allow(ip); !ok {
+		t.Fatal("first attempt should be allowed")
+	}
+	if ok, _ := limiters[1].allow(ip); !ok {
+		t.Fatal("second attempt should be allowed")
+	}
+	if ok, _ := limiters[0].allow(ip); ok {
+		t.Fatal("third attempt on either instance should be limited")
+	}
+	if ok, _ := limiters[1].allow(ip); ok {
+		t.Fatal("third attempt on either instance should be limited")
+	}
+}
@@ -0,0 +1,311 @@
+package pocketbase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// EnvProxyMaxBody caps proxied request and response bodies, in bytes.
+const EnvProxyMaxBody = "POCKETBASE_PROXY_MAX_BODY_BYTES"
+
+const (
+	defaultProxyMaxBody = 1 << 20 // 1 MiB
+
+	// upstreamTimeout bounds a whole proxied request, body included.
+	upstreamTimeout = 15 * time.Second
+	// upstreamGETAttempts is how often an idempotent GET is tried.
+	upstreamGETAttempts = 3
+	upstreamRetryDelay  = 100 * time.Millisecond
+
+	// After breakerThreshold consecutive upstream failures the proxy answers
+	// 503 for breakerCooldown instead of piling more requests onto PocketBase.
+	breakerThreshold = 5
+	breakerCooldown  = 30 * time.Second
+)
+
+var (
+	// errCircuitOpen is returned while the breaker is refusing upstream calls.
+	errCircuitOpen = errors.New("pocketbase upstream unavailable")
+	// errResponseTooLarge is returned when reading an upstream body past the limit.
+	errResponseTooLarge = errors.New("upstream response too large")
+)
+
+// loadProxyMaxBody reads the body size limit from the environment.
+func loadProxyMaxBody() int64 {
+	if v, err := strconv.ParseInt(os.Getenv(EnvProxyMaxBody), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultProxyMaxBody
+}
+
+// breakerState is where a circuitBreaker is in its closed → open → half-open cycle.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker trips after threshold consecutive failures and rejects calls
+// until cooldown has passed. It then goes half-open: a single probe is let
+// through and every other call is rejected until the probe succeeds (closing
+// the breaker) or fails (opening it again straight away).
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow reports whether a call may go upstream, and whether that call is the
+// half-open probe. A probe that ends without calling success or failure must
+// call release so the breaker does not wait on it forever.
+func (b *circuitBreaker) allow() (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerClosed:
+		return true, false
+	case breakerOpen:
+		if b.now().Before(b.openUntil) {
+			return false, false
+		}
+		b.state = breakerHalfOpen
+	}
+	if b.probing {
+		return false, false
+	}
+	b.probing = true
+	return true, true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.failures = 0
+	b.openUntil = time.Time{}
+	b.probing = false
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openUntil = b.now().Add(b.cooldown)
+		b.probing = false
+	}
+}
+
+// release frees the half-open probe slot after a probe that gave no verdict,
+// e.g. because its caller went away, so the next call can probe instead.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == breakerHalfOpen {
+		b.probing = false
+	}
+}
+
+// upstreamProxy sends the auth helpers' requests to PocketBase's own REST API.
+type upstreamProxy struct {
+	client     *http.Client
+	breaker    *circuitBreaker
+	timeout    time.Duration
+	retryDelay time.Duration
+	maxBody    int64
+}
+
+func newUpstreamProxy() *upstreamProxy {
+	return &upstreamProxy{
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				DialContext:           (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+				TLSHandshakeTimeout:   5 * time.Second,
+				ResponseHeaderTimeout: 10 * time.Second,
+				IdleConnTimeout:       90 * time.Second,
+				MaxIdleConnsPerHost:   16,
+			},
+		},
+		breaker:    newCircuitBreaker(breakerThreshold, breakerCooldown),
+		timeout:    upstreamTimeout,
+		retryDelay: upstreamRetryDelay,
+		maxBody:    loadProxyMaxBody(),
+	}
+}
+
+// do sends one proxied request. GETs are retried on transport errors and
+// gateway statuses; the returned body must be closed, which also releases the
+// request's timeout, and fails with errResponseTooLarge past maxBody bytes.
+func (p *upstreamProxy) do(ctx context.Context, method, target string, header http.Header, body []byte) (*http.Response, error) {
+	ok, probe := p.breaker.allow()
+	if !ok {
+		return nil, errCircuitOpen
+	}
+	if probe {
+		defer p.breaker.release()
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+
+	attempts := 1
+	if method == http.MethodGet {
+		attempts = upstreamGETAttempts
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				cancel()
+				return nil, lastErr
+			case <-time.After(p.retryDelay * time.Duration(attempt)):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			lastErr = err
+			if ctx.Err() == nil || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				p.breaker.failure()
+			}
+			continue
+		}
+		if isGatewayStatus(resp.StatusCode) {
+			p.breaker.failure()
+			if attempt < attempts-1 {
+				resp.Body.Close()
+				lastErr = errors.New(resp.Status)
+				continue
+			}
+		} else {
+			p.breaker.success()
+		}
+		resp.Body = &cancelOnClose{
+			Reader: &limitedReader{r: resp.Body, n: p.maxBody},
+			closer: resp.Body,
+			cancel: cancel,
+		}
+		return resp, nil
+	}
+	cancel()
+	return nil, lastErr
+}
+
+func isGatewayStatus(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// cancelOnClose releases a request context once its response body is done.
+type cancelOnClose struct {
+	io.Reader
+	closer io.Closer
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.closer.Close()
+	c.cancel()
+	return err
+}
+
+// limitedReader is io.LimitReader that fails instead of silently truncating.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// Anything left beyond the limit means the body is too large.
+		var probe [1]byte
+		if n, _ := l.r.Read(probe[:]); n > 0 {
+			return 0, errResponseTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// readRequestBody reads the inbound body, rejecting anything over maxBody
+// with 413.
+func (p *upstreamProxy) readRequestBody(e *core.RequestEvent) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(e.Response, e.Request.Body, p.maxBody))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, apis.NewApiError(http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", p.maxBody), nil)
+	}
+	if err != nil {
+		return nil, apis.NewBadRequestError("failed to read request body", err)
+	}
+	return body, nil
+}
+
+// relay copies an upstream JSON response to the client. The body is read in
+// full first so an oversized response can still be turned into an error.
+func relay(e *core.RequestEvent, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return upstreamError(err)
+	}
+	e.Response.Header().Set("Content-Type", "application/json")
+	e.Response.WriteHeader(resp.StatusCode)
+	_, _ = e.Response.Write(body)
+	return nil
+}
+
+// upstreamError maps a failed proxied request to the API error the client sees.
+func upstreamError(err error) error {
+	switch {
+	case errors.Is(err, errCircuitOpen):
+		return apis.NewApiError(http.StatusServiceUnavailable, "authentication backend unavailable, please try again shortly", nil)
+	case errors.Is(err, errResponseTooLarge):
+		return apis.NewApiError(http.StatusBadGateway, "upstream response too large", nil)
+	case errors.Is(err, context.DeadlineExceeded):
+		return apis.NewApiError(http.StatusGatewayTimeout, "upstream timeout", err)
+	default:
+		return apis.NewApiError(http.StatusBadGateway, "upstream error", err)
+	}
+}
+
+// pbUpstream is shared by all Datastar auth proxy routes.
+var pbUpstream = newUpstreamProxy()
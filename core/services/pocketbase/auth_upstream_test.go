@@ -0,0 +1,287 @@
+package pocketbase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+func testUpstreamProxy() *upstreamProxy {
+	p := newUpstreamProxy()
+	p.retryDelay = time.Millisecond
+	return p
+}
+
+func TestUpstreamProxyRetriesGET(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < upstreamGETAttempts {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "token" {
+			t.Errorf("authorization not forwarded")
+		}
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+
+	p := testUpstreamProxy()
+	resp, err := p.do(context.Background(), http.MethodGet, srv.URL, http.Header{"Authorization": {"token"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != `{"ok":true}` || calls.Load() != upstreamGETAttempts {
+		t.Fatalf("status=%d body=%s calls=%d", resp.StatusCode, body, calls.Load())
+	}
+
+	// POSTs are not idempotent and go out once.
+	calls.Store(0)
+	resp, err = p.do(context.Background(), http.MethodPost, srv.URL, nil, []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Fatalf("POST status=%d calls=%d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestUpstreamProxyTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	p := testUpstreamProxy()
+	p.timeout = 50 * time.Millisecond
+	_, err := p.do(context.Background(), http.MethodPost, srv.URL, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+	allowed := func() bool {
+		ok, _ := b.allow()
+		return ok
+	}
+
+	b.failure()
+	if !allowed() {
+		t.Fatal("one failure should not trip the breaker")
+	}
+	b.failure()
+	if allowed() {
+		t.Fatal("breaker should be open after threshold failures")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, probe := b.allow(); !ok || !probe {
+		t.Fatal("breaker should let a probe through after cooldown")
+	}
+	if allowed() {
+		t.Fatal("only one probe should be admitted while half-open")
+	}
+	b.release()
+	if ok, probe := b.allow(); !ok || !probe {
+		t.Fatal("a released probe slot should admit the next caller")
+	}
+	b.failure()
+	if allowed() {
+		t.Fatal("a failed probe should reopen the breaker")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := b.allow(); !ok {
+		t.Fatal("breaker should probe again after another cooldown")
+	}
+	b.success()
+	b.failure()
+	if !allowed() {
+		t.Fatal("success should close the breaker and reset the failure count")
+	}
+}
+
+func TestCircuitBreakerHalfOpenAdmitsOneProbe(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := newCircuitBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+	b.failure()
+	now = now.Add(time.Minute)
+
+	var admitted, probes atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, probe := b.allow(); ok {
+				admitted.Add(1)
+				if probe {
+					probes.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if admitted.Load() != 1 || probes.Load() != 1 {
+		t.Fatalf("half-open admitted %d calls (%d probes), want exactly one probe", admitted.Load(), probes.Load())
+	}
+}
+
+func TestUpstreamProxyHalfOpenRejectsDuringProbe(t *testing.T) {
+	var calls atomic.Int32
+	probeStarted := make(chan struct{})
+	finishProbe := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			close(probeStarted)
+			<-finishProbe
+		}
+	}))
+	defer srv.Close()
+
+	p := testUpstreamProxy()
+	p.breaker = newCircuitBreaker(1, 10*time.Millisecond)
+	resp, err := p.do(context.Background(), http.MethodPost, srv.URL, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	time.Sleep(20 * time.Millisecond)
+
+	probeDone := make(chan error, 1)
+	go func() {
+		resp, err := p.do(context.Background(), http.MethodPost, srv.URL, nil, nil)
+		if err == nil {
+			resp.Body.Close()
+		}
+		probeDone <- err
+	}()
+	<-probeStarted
+
+	var wg sync.WaitGroup
+	var rejected atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.do(context.Background(), http.MethodPost, srv.URL, nil, nil); errors.Is(err, errCircuitOpen) {
+				rejected.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if rejected.Load() != 10 {
+		t.Fatalf("%d of 10 calls during the probe were rejected, want all", rejected.Load())
+	}
+
+	close(finishProbe)
+	if err := <-probeDone; err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	resp, err = p.do(context.Background(), http.MethodPost, srv.URL, nil, nil)
+	if err != nil {
+		t.Fatalf("a successful probe should close the breaker: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 3 {
+		t.Fatalf("upstream calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestUpstreamProxyCircuitOpen(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	p := testUpstreamProxy()
+	for i := 0; i < breakerThreshold; i++ {
+		resp, err := p.do(context.Background(), http.MethodPost, srv.URL, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if _, err := p.do(context.Background(), http.MethodPost, srv.URL, nil, nil); !errors.Is(err, errCircuitOpen) {
+		t.Fatalf("expected open circuit, got %v", err)
+	}
+	if calls.Load() != breakerThreshold {
+		t.Fatalf("open circuit should not reach upstream, calls=%d", calls.Load())
+	}
+}
+
+func TestUpstreamProxyResponseLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("x", 16))
+	}))
+	defer srv.Close()
+
+	p := testUpstreamProxy()
+	for _, tt := range []struct {
+		limit   int64
+		wantErr error
+	}{
+		{16, nil},
+		{15, errResponseTooLarge},
+	} {
+		p.maxBody = tt.limit
+		resp, err := p.do(context.Background(), http.MethodGet, srv.URL, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("limit %d: err = %v, want %v", tt.limit, err, tt.wantErr)
+		}
+		if err == nil && len(body) != 16 {
+			t.Fatalf("limit %d: body truncated to %d bytes", tt.limit, len(body))
+		}
+	}
+}
+
+func TestReadRequestBodyLimit(t *testing.T) {
+	p := testUpstreamProxy()
+	p.maxBody = 8
+
+	read := func(body string) ([]byte, error) {
+		e := &core.RequestEvent{Event: router.Event{
+			Response: httptest.NewRecorder(),
+			Request:  httptest.NewRequest(http.MethodPost, "/api/ds/signup", strings.NewReader(body)),
+		}}
+		return p.readRequestBody(e)
+	}
+
+	if got, err := read(`{"a":1}`); err != nil || string(got) != `{"a":1}` {
+		t.Fatalf("small body: %q, %v", got, err)
+	}
+	_, err := read(`{"a":"too long"}`)
+	if err == nil || !strings.Contains(err.Error(), "exceeds 8 bytes") {
+		t.Fatalf("expected 413 error, got %v", err)
+	}
+}
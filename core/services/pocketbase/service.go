@@ -62,6 +62,19 @@ func LoadSpec() (*Spec, error) {
 	return &spec, nil
 }
 
+// HealthPath is the PocketBase API endpoint that reports server health.
+const HealthPath = "/api/health"
+
+// HealthPort returns the port that serves HealthPath.
+func (s *Spec) HealthPort() int {
+	return s.Ports.Primary.Port
+}
+
+// HealthURL returns the local health endpoint derived from the manifest ports.
+func (s *Spec) HealthURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d%s", s.HealthPort(), HealthPath)
+}
+
 // EnsureBinaries ensures the PocketBase binary is present.
 func (s *Spec) EnsureBinaries() (map[string]string, error) {
 	manifest := &runtimedep.Manifest{Binaries: s.Binaries}
@@ -142,6 +155,12 @@ func (s *Spec) ResolveArgs(paths map[string]string) []string {
 	return args
 }
 
+// Dependencies returns the processes that must be healthy before PocketBase starts,
+// as declared by process.compose.depends_on in the manifest.
+func (s *Spec) Dependencies() []string {
+	return s.Process.Compose.Dependencies()
+}
+
 // ComposeOverrides returns the optional Process Compose overrides.
 func (s *Spec) ComposeOverrides() map[string]any {
 	if s.Process.Compose == nil {
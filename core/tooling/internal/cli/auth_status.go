@@ -21,7 +21,7 @@ func newAuthStatusCommand(profileFlag *string) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			svc := app.New()
-			status, err := svc.Status(ctx, profiles.ContextOptions{ProfileOverride: strings.TrimSpace(*profileFlag)})
+			status, err := svc.Snapshot(ctx, profiles.ContextOptions{ProfileOverride: strings.TrimSpace(*profileFlag)})
 			if err != nil {
 				return err
 			}
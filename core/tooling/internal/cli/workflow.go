@@ -28,6 +28,7 @@ type deployOptions struct {
 	regionFlag  string
 	verbose     bool
 	noBrowser   bool
+	dryRun      bool
 	json        bool
 }
 
@@ -52,6 +53,7 @@ func newWorkflowDeployCommand(profileFlag *string) *cobra.Command {
 				Repo:      opts.repoFlag,
 				Verbose:   opts.verbose,
 				NoBrowser: opts.noBrowser,
+				DryRun:    opts.dryRun,
 				Stdin:     cmd.InOrStdin(),
 				Stdout:    cmd.OutOrStdout(),
 				Stderr:    cmd.ErrOrStderr(),
@@ -72,6 +74,7 @@ func newWorkflowDeployCommand(profileFlag *string) *cobra.Command {
 	cmd.Flags().StringVar(&opts.regionFlag, "region", "", "Primary region override")
 	cmd.Flags().BoolVar(&opts.verbose, "verbose", false, "Enable verbose pipeline logging")
 	cmd.Flags().BoolVar(&opts.noBrowser, "no-browser", false, "Do not automatically open authentication URLs")
+	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Show what would be built, released and routed without changing anything")
 	cmd.Flags().BoolVar(&opts.json, "json", false, "Stream newline-delimited JSON progress events")
 
 	return cmd
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	cf "github.com/cloudflare/cloudflare-go"
+	flyclient "github.com/superfly/fly-go"
+
+	"github.com/joeblew999/infra/core/tooling/pkg/orchestrator"
+)
+
+// Error categories returned by the façade. Match them with errors.Is; use
+// errors.As with *Error for the operation, provider and step that failed.
+var (
+	// ErrInvalidRequest means the request or the tooling configuration is
+	// incomplete, e.g. no Fly app name or an unknown profile.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrNotAuthenticated means a provider token is missing, expired or
+	// lacks the required permissions. Re-running the auth flow fixes it.
+	ErrNotAuthenticated = errors.New("not authenticated")
+	// ErrNotFound means a provider resource (app, organization, zone) does
+	// not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means the change clashes with existing state, such as an
+	// app name taken by another organization.
+	ErrConflict = errors.New("conflict")
+	// ErrProviderRejected means a provider API refused the request for any
+	// other reason, including rate limits and server errors.
+	ErrProviderRejected = errors.New("provider rejected request")
+	// ErrUnavailable means a provider or local service could not be reached.
+	ErrUnavailable = errors.New("provider unavailable")
+)
+
+// Error is a façade failure. Kind is one of the Err* categories, or nil when
+// the cause could not be classified; Err is the underlying error. Context
+// cancellation and deadlines stay reachable through errors.Is on Err.
+type Error struct {
+	Op       string                // façade method, e.g. "deploy"
+	Provider string                // "fly", "cloudflare", "ko" or "" when not provider-specific
+	Step     orchestrator.StepName // failed deploy step, if any
+	Kind     error
+	Err      error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Op)
+	if e.Provider != "" {
+		b.WriteString(" (" + e.Provider + ")")
+	}
+	b.WriteString(": ")
+	if e.Kind != nil && !errors.Is(e.Err, e.Kind) {
+		b.WriteString(e.Kind.Error() + ": ")
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+// Unwrap exposes both the category and the underlying error to errors.Is
+// and errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// stepProviders maps deploy steps to the provider they talk to.
+var stepProviders = map[orchestrator.StepName]string{
+	orchestrator.StepFlyAuth:        "fly",
+	orchestrator.StepCloudflareAuth: "cloudflare",
+	orchestrator.StepRelease:        "fly",
+	orchestrator.StepCloudflareDNS:  "cloudflare",
+}
+
+// wrapDeployError classifies a Deploy failure using the step that failed.
+func wrapDeployError(err error, result *DeployResult) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{Op: "deploy", Err: err}
+	if result != nil {
+		for _, step := range result.Steps {
+			if step.State == orchestrator.StepFailed {
+				e.Step = step.Name
+				e.Provider = stepProviders[step.Name]
+			}
+		}
+	}
+	switch {
+	case e.Step == "":
+		// Failed before any step ran: the profile or request is unusable.
+		e.Kind = classify(err, ErrInvalidRequest)
+	case e.Step == orchestrator.StepFlyAuth || e.Step == orchestrator.StepCloudflareAuth:
+		e.Kind = classify(err, ErrNotAuthenticated)
+	default:
+		e.Kind = classify(err, nil)
+	}
+	if e.Step == orchestrator.StepRelease && strings.Contains(err.Error(), "ko publish") {
+		e.Provider = "ko"
+	}
+	return e
+}
+
+// wrapError classifies a failure of a façade method that doesn't run steps.
+func wrapError(op string, err error, fallback error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Kind: classify(err, fallback), Err: err}
+}
+
+// classify maps provider and network errors to a category, returning
+// fallback when nothing more specific matches. Context errors are left
+// unclassified so callers can tell cancellation from provider failures.
+func classify(err error, fallback error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+
+	if errors.Is(err, flyclient.ErrNoAuthToken) {
+		return ErrNotAuthenticated
+	}
+	if errors.Is(err, flyclient.ErrNotFound) {
+		return ErrNotFound
+	}
+	var flyErr *flyclient.ApiError
+	if errors.As(err, &flyErr) {
+		switch {
+		case flyErr.Status == 401 || flyErr.Status == 403:
+			return ErrNotAuthenticated
+		case flyErr.Status == 404:
+			return ErrNotFound
+		case flyErr.Status == 409:
+			return ErrConflict
+		default:
+			return ErrProviderRejected
+		}
+	}
+
+	var cfErr interface{ Type() cf.ErrorType }
+	if errors.As(err, &cfErr) {
+		switch cfErr.Type() {
+		case cf.ErrorTypeAuthentication, cf.ErrorTypeAuthorization:
+			return ErrNotAuthenticated
+		case cf.ErrorTypeNotFound:
+			return ErrNotFound
+		}
+		if strings.Contains(strings.ToLower(err.Error()), "already exists") {
+			return ErrConflict
+		}
+		return ErrProviderRejected
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrUnavailable
+	}
+
+	// fly-go surfaces GraphQL failures as plain errors, and the release
+	// pipeline wraps most failures with fmt.Errorf, so fall back to the text.
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, "unable to resolve token", "load cloudflare token", "not authorized", "unauthorized", "authentication failed", "forbidden"):
+		return ErrNotAuthenticated
+	case containsAny(msg, "already exists", "already been taken", "conflict"):
+		return ErrConflict
+	case containsAny(msg, "could not find", "not found"):
+		return ErrNotFound
+	case containsAny(msg, "connection refused", "no such host", "i/o timeout"):
+		return ErrUnavailable
+	case containsAny(msg, "missing fly app name", "is required", "unable to resolve ko repository"):
+		return ErrInvalidRequest
+	}
+	return fallback
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	flyclient "github.com/superfly/fly-go"
+
+	"github.com/joeblew999/infra/core/tooling/pkg/orchestrator"
+)
+
+func failedAt(step orchestrator.StepName) *DeployResult {
+	return &DeployResult{Steps: []orchestrator.StepStatus{
+		{Name: orchestrator.StepFlyAuth, State: orchestrator.StepSucceeded},
+		{Name: step, State: orchestrator.StepFailed},
+	}}
+}
+
+func TestWrapDeployError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		result   *DeployResult
+		kind     error
+		provider string
+	}{
+		{"auth step", errors.New("fly authentication failed: token expired"), failedAt(orchestrator.StepCloudflareAuth), ErrNotAuthenticated, "cloudflare"},
+		{"no steps ran", errors.New("profile not configured"), &DeployResult{}, ErrInvalidRequest, ""},
+		{"fly 409", fmt.Errorf("create app %q: %w", "a", &flyclient.ApiError{Status: 409, Message: "taken"}), failedAt(orchestrator.StepRelease), ErrConflict, "fly"},
+		{"fly not found", fmt.Errorf("lookup app: %w", flyclient.ErrNotFound), failedAt(orchestrator.StepRelease), ErrNotFound, "fly"},
+		{"fly 500", &flyclient.ApiError{Status: 500, Message: "boom"}, failedAt(orchestrator.StepRelease), ErrProviderRejected, "fly"},
+		{"ko", errors.New("ko publish: dial tcp: connection refused"), failedAt(orchestrator.StepRelease), ErrUnavailable, "ko"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapDeployError(tt.err, tt.result)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("errors.Is(%v, %v) = false", err, tt.kind)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("underlying error not reachable from %v", err)
+			}
+			var appErr *Error
+			if !errors.As(err, &appErr) || appErr.Provider != tt.provider {
+				t.Fatalf("provider = %q, want %q", appErr.Provider, tt.provider)
+			}
+		})
+	}
+}
+
+func TestClassifyLeavesContextErrorsUnclassified(t *testing.T) {
+	err := wrapError("deploy", fmt.Errorf("ko publish: %w", context.DeadlineExceeded), ErrInvalidRequest)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline to be reachable, got %v", err)
+	}
+	for _, kind := range []error{ErrInvalidRequest, ErrUnavailable, ErrProviderRejected} {
+		if errors.Is(err, kind) {
+			t.Fatalf("context error classified as %v", kind)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+// Package httpapi is the web adaptor for the app façade: it mounts Status
+// and Deploy as JSON endpoints on a chi router so a browser UI can drive the
+// same workflows as the CLI and TUI.
+//
+//	GET  /status?profile=NAME     profile settings plus live Fly, Cloudflare
+//	                              and local stack state
+//	POST /deploy                  deploy; the response is an SSE stream of
+//	                              init, progress, prompt, result and error events
+//	POST /deploy/prompts?id=ID    answer a prompt raised by a running deploy
+//
+// Failed JSON requests get a body of the form {"error": "..."}.
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/joeblew999/infra/core/tooling/pkg/app"
+	"github.com/joeblew999/infra/core/tooling/pkg/orchestrator"
+	profiles "github.com/joeblew999/infra/core/tooling/pkg/profiles"
+	"github.com/joeblew999/infra/core/tooling/pkg/server/sse"
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
+)
+
+// maxDeployTimeout caps the timeout a client may ask for.
+const maxDeployTimeout = 2 * time.Hour
+
+var (
+	profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{0,64}$`)
+	// Fly app names: lowercase letters, digits and dashes.
+	appNamePattern = regexp.MustCompile(`^([a-z0-9][a-z0-9-]{0,62})?$`)
+	orgSlugPattern = regexp.MustCompile(`^([a-z0-9][a-z0-9-]{0,62})?$`)
+	regionPattern  = regexp.MustCompile(`^([a-z]{3})?$`)
+)
+
+// Option customises the handler.
+type Option func(*handler)
+
+// WithAuth guards every endpoint with middleware in place of the default
+// LoopbackOnly guard. To require a passkey login, pass the RequireSession
+// method of pkg/auth's AuthService. This package can't import pkg/auth
+// itself: it lives in the root module, and core/tooling depends only on core,
+// so the binary that links both wires them together.
+func WithAuth(mw func(http.Handler) http.Handler) Option {
+	return func(h *handler) {
+		if mw != nil {
+			h.auth = mw
+		}
+	}
+}
+
+// LoopbackOnly rejects requests that don't come from the local machine. It
+// is the default guard, since a deploy endpoint must never be open by
+// accident; use WithAuth to expose the API more widely.
+func LoopbackOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if ip := net.ParseIP(host); err != nil || ip == nil || !ip.IsLoopback() {
+			writeError(w, http.StatusForbidden, errors.New("only local requests are allowed"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type handler struct {
+	svc     *app.Service
+	streams *sse.Manager
+	auth    func(http.Handler) http.Handler
+}
+
+// New returns a router exposing svc over HTTP.
+func New(svc *app.Service, opts ...Option) chi.Router {
+	h := &handler{svc: svc, streams: sse.NewManager(svc), auth: LoopbackOnly}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	r := chi.NewRouter()
+	r.Use(h.auth)
+	r.Get("/status", h.status)
+	r.Post("/deploy", h.deploy)
+	r.Method(http.MethodPost, "/deploy/prompts", sse.PromptResponseHandler{Manager: h.streams})
+	return r
+}
+
+func (h *handler) status(w http.ResponseWriter, r *http.Request) {
+	opts, err := contextOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	status, err := h.svc.Status(r.Context(), opts)
+	if err != nil {
+		writeError(w, statusCode(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// DeployRequest is the body of POST /deploy.
+type DeployRequest struct {
+	Profile string `json:"profile"`
+	AppName string `json:"app_name"`
+	OrgSlug string `json:"org_slug"`
+	Region  string `json:"region"`
+	Verbose bool   `json:"verbose"`
+	// DryRun streams the planned changes instead of deploying.
+	DryRun bool `json:"dry_run"`
+	// Timeout is a Go duration such as "15m"; empty means the orchestrator default.
+	Timeout string `json:"timeout"`
+}
+
+func (h *handler) deploy(w http.ResponseWriter, r *http.Request) {
+	var req DeployRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	opts, err := req.options()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	h.streams.Stream(w, r, opts)
+}
+
+// options validates the request and converts it for the orchestrator.
+func (req DeployRequest) options() (orchestrator.DeployOptions, error) {
+	var errs []error
+	if !profileNamePattern.MatchString(req.Profile) {
+		errs = append(errs, fmt.Errorf("profile %q is not a valid profile name", req.Profile))
+	}
+	if !appNamePattern.MatchString(req.AppName) {
+		errs = append(errs, fmt.Errorf("app_name %q must be lowercase letters, digits and dashes", req.AppName))
+	}
+	if !orgSlugPattern.MatchString(req.OrgSlug) {
+		errs = append(errs, fmt.Errorf("org_slug %q must be lowercase letters, digits and dashes", req.OrgSlug))
+	}
+	if !regionPattern.MatchString(req.Region) {
+		errs = append(errs, fmt.Errorf("region %q must be a three-letter Fly region code", req.Region))
+	}
+	var timeout time.Duration
+	if req.Timeout != "" {
+		d, err := time.ParseDuration(req.Timeout)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("timeout: %w", err))
+		case d <= 0 || d > maxDeployTimeout:
+			errs = append(errs, fmt.Errorf("timeout must be between 0 and %s", maxDeployTimeout))
+		}
+		timeout = d
+	}
+	if err := errors.Join(errs...); err != nil {
+		return orchestrator.DeployOptions{}, err
+	}
+
+	return orchestrator.DeployOptions{
+		ProfileOverride: req.Profile,
+		Timeout:         timeout,
+		DeployRequest: types.DeployRequest{
+			AppName: req.AppName,
+			OrgSlug: req.OrgSlug,
+			Region:  req.Region,
+			Verbose: req.Verbose,
+			DryRun:  req.DryRun,
+			// There is no terminal to open a browser on.
+			NoBrowser: true,
+		},
+	}, nil
+}
+
+func contextOptions(r *http.Request) (profiles.ContextOptions, error) {
+	profile := r.URL.Query().Get("profile")
+	if !profileNamePattern.MatchString(profile) {
+		return profiles.ContextOptions{}, fmt.Errorf("profile %q is not a valid profile name", profile)
+	}
+	return profiles.ContextOptions{ProfileOverride: profile}, nil
+}
+
+// statusCode maps a façade error to an HTTP status.
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, app.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, app.ErrNotAuthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, app.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, app.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, app.ErrProviderRejected):
+		return http.StatusBadGateway
+	case errors.Is(err, app.ErrUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,166 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	sharedcfg "github.com/joeblew999/infra/core/pkg/shared/config"
+	"github.com/joeblew999/infra/core/tooling/pkg/app"
+	"github.com/joeblew999/infra/core/tooling/pkg/auth"
+	"github.com/joeblew999/infra/core/tooling/pkg/orchestrator"
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
+)
+
+func TestDeployValidation(t *testing.T) {
+	h := New(app.New())
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"bad json", `{`, "invalid request body"},
+		{"unknown field", `{"app":"x"}`, "unknown field"},
+		{"bad app name", `{"app_name":"My_App"}`, "app_name"},
+		{"bad region", `{"region":"europe"}`, "region"},
+		{"bad timeout", `{"timeout":"forever"}`, "timeout"},
+		{"timeout too long", `{"timeout":"48h"}`, "timeout must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/deploy", strings.NewReader(tt.body))
+			req.RemoteAddr = "127.0.0.1:5000"
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rec.Code)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || !strings.Contains(body["error"], tt.want) {
+				t.Errorf("body = %s, want an error mentioning %q", rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeployRequestOptions(t *testing.T) {
+	opts, err := DeployRequest{Profile: "prod", AppName: "infra-web", Region: "syd", DryRun: true, Timeout: "20m"}.options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.ProfileOverride != "prod" || opts.AppName != "infra-web" || opts.Region != "syd" || !opts.DryRun || opts.Timeout.Minutes() != 20 || !opts.NoBrowser {
+		t.Errorf("options = %+v", opts)
+	}
+}
+
+// recordingDeployer counts calls instead of touching Fly or Cloudflare.
+type recordingDeployer struct {
+	deployCalls int
+	planCalls   int
+}
+
+func (d *recordingDeployer) Deploy(context.Context, types.DeployRequest) (*types.DeployResult, error) {
+	d.deployCalls++
+	return &types.DeployResult{}, nil
+}
+
+func (d *recordingDeployer) Plan(context.Context, types.DeployRequest) ([]types.PlannedAction, error) {
+	d.planCalls++
+	return []types.PlannedAction{{Provider: "fly", Action: types.PlanUpdate, Target: "infra-web"}}, nil
+}
+
+// failingAuth fails the test if a deploy tries to authenticate.
+type failingAuth struct{ t *testing.T }
+
+func (a failingAuth) EnsureFly(context.Context, sharedcfg.ToolingProfile, auth.Options) error {
+	a.t.Error("dry run authenticated with Fly")
+	return errors.New("unexpected auth")
+}
+
+func (a failingAuth) EnsureCloudflare(context.Context, sharedcfg.ToolingProfile, auth.Options) error {
+	a.t.Error("dry run authenticated with Cloudflare")
+	return errors.New("unexpected auth")
+}
+
+func TestDeployDryRunOnlyPlans(t *testing.T) {
+	deployer := &recordingDeployer{}
+	orch := orchestrator.NewService(
+		orchestrator.WithAuthProvider(failingAuth{t}),
+		orchestrator.WithDeployerFactory(func(sharedcfg.ToolingProfile, string, string, string) orchestrator.Deployer {
+			return deployer
+		}),
+		orchestrator.WithProfileResolver(func(string) (sharedcfg.ToolingProfile, string) {
+			return sharedcfg.ToolingProfile{Name: "test", FlyApp: "infra-web"}, "test"
+		}),
+	)
+	h := New(app.New(app.WithOrchestrator(orch)))
+
+	req := httptest.NewRequest(http.MethodPost, "/deploy", strings.NewReader(`{"app_name":"infra-web","dry_run":true}`))
+	req.RemoteAddr = "127.0.0.1:5000"
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if deployer.deployCalls != 0 || deployer.planCalls != 1 {
+		t.Fatalf("deploy calls = %d, plan calls = %d, want 0 and 1", deployer.deployCalls, deployer.planCalls)
+	}
+	if strings.Contains(rec.Body.String(), "event: error") {
+		t.Fatalf("dry run streamed an error:\n%s", rec.Body)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	remote := httptest.NewRequest(http.MethodGet, "/status?profile=prod", nil)
+	remote.RemoteAddr = "203.0.113.7:5000"
+
+	rec := httptest.NewRecorder()
+	New(app.New()).ServeHTTP(rec, remote)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("remote request with default guard: status = %d, want 403", rec.Code)
+	}
+
+	denyAll := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+	rec = httptest.NewRecorder()
+	New(app.New(), WithAuth(denyAll)).ServeHTTP(rec, remote)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("custom auth: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestStatusRejectsBadProfile(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/status?profile=../etc", nil)
+	req.RemoteAddr = "[::1]:5000"
+	rec := httptest.NewRecorder()
+	New(app.New()).ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestStatusCodeUsesErrorCategories(t *testing.T) {
+	tests := []struct {
+		kind error
+		want int
+	}{
+		{app.ErrInvalidRequest, http.StatusBadRequest},
+		{app.ErrNotAuthenticated, http.StatusUnauthorized},
+		{app.ErrConflict, http.StatusConflict},
+		{app.ErrProviderRejected, http.StatusBadGateway},
+		{nil, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		err := &app.Error{Op: "deploy", Kind: tt.kind, Err: errors.New("boom")}
+		if got := statusCode(err); got != tt.want {
+			t.Errorf("statusCode(%v) = %d, want %d", err, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 type Service struct {
 	auth         *auth.Service
 	orchestrator *orchestrator.Service
+	sources      statusSources
 }
 
 // Option customises Service construction.
@@ -40,6 +41,7 @@ func New(opts ...Option) *Service {
 	svc := &Service{
 		auth:         auth.New(),
 		orchestrator: orchestrator.NewService(),
+		sources:      defaultStatusSources(),
 	}
 	for _, opt := range opts {
 		opt(svc)
@@ -57,17 +59,52 @@ func (s *Service) Auth() *auth.Service {
 	return s.auth
 }
 
-// Deploy executes the deployment synchronously.
-func (s *Service) Deploy(ctx context.Context, opts orchestrator.DeployOptions) (*orchestrator.DeployResult, error) {
-	return s.orchestrator.Deploy(ctx, opts)
+// DeployRequest configures Deploy; set Rollback to undo completed steps when
+// a later one fails, and Progress to receive Events as the deploy runs.
+type DeployRequest = orchestrator.DeployOptions
+
+// Event is a structured progress update (step started, succeeded or failed,
+// plus percent complete) that CLI, TUI and web adaptors can render alike.
+type Event = orchestrator.ProgressEvent
+
+// DeployResult reports the deployment, including the status of each step.
+type DeployResult = orchestrator.DeployResult
+
+// Deploy builds the image with KO, releases it to Fly, and routes it through
+// Cloudflare, in that order. On failure the result is still returned so
+// callers can see which steps ran and what was rolled back.
+//
+// Set DryRun on the request to get the planned changes in DeployResult.Plan
+// instead; nothing is authenticated, built or deployed.
+//
+// Errors are *Error values: ErrNotAuthenticated when an auth step fails,
+// ErrInvalidRequest when the profile or request is incomplete, and
+// ErrNotFound, ErrConflict, ErrProviderRejected or ErrUnavailable when Fly,
+// KO or Cloudflare fail later on.
+func (s *Service) Deploy(ctx context.Context, req DeployRequest) (*DeployResult, error) {
+	result, err := s.orchestrator.Deploy(ctx, req)
+	return result, wrapDeployError(err, result)
 }
 
 // Launch executes the deployment asynchronously and returns channels suitable for SSE streaming.
-func (s *Service) Launch(ctx context.Context, opts orchestrator.DeployOptions) (*orchestrator.StreamAdapter, <-chan *orchestrator.DeployResult, <-chan error) {
-	return s.orchestrator.Launch(ctx, opts)
+// Errors on the error channel are classified like those of Deploy, but
+// without the failed step.
+func (s *Service) Launch(ctx context.Context, opts DeployRequest) (*orchestrator.StreamAdapter, <-chan *DeployResult, <-chan error) {
+	adapter, resultCh, errCh := s.orchestrator.Launch(ctx, opts)
+	wrapped := make(chan error, 1)
+	go func() {
+		defer close(wrapped)
+		for err := range errCh {
+			wrapped <- wrapError("deploy", err, nil)
+		}
+	}()
+	return adapter, resultCh, wrapped
 }
 
-// Status returns the current profile + provider settings snapshot.
-func (s *Service) Status(ctx context.Context, opts profiles.ContextOptions) (orchestrator.Status, error) {
-	return orchestrator.StatusSnapshot(ctx, opts)
+// Snapshot returns the current profile + provider settings from local config,
+// without querying any provider; Status adds the live state. It fails only
+// with ErrInvalidRequest, when the tooling context can't be resolved.
+func (s *Service) Snapshot(ctx context.Context, opts profiles.ContextOptions) (orchestrator.Status, error) {
+	status, err := orchestrator.StatusSnapshot(ctx, opts)
+	return status, wrapError("snapshot", err, ErrInvalidRequest)
 }
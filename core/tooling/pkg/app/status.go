@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/joeblew999/infra/core/pkg/runtime/process"
+	sharedcfg "github.com/joeblew999/infra/core/pkg/shared/config"
+	"github.com/joeblew999/infra/core/tooling/pkg/cloudflare"
+	"github.com/joeblew999/infra/core/tooling/pkg/fly"
+	"github.com/joeblew999/infra/core/tooling/pkg/orchestrator"
+	profiles "github.com/joeblew999/infra/core/tooling/pkg/profiles"
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
+)
+
+// ProviderState summarises whether a provider could be queried.
+type ProviderState string
+
+const (
+	// ProviderOK means the provider answered.
+	ProviderOK ProviderState = "ok"
+	// ProviderUnknown means the provider is not configured for this profile.
+	ProviderUnknown ProviderState = "unknown"
+	// ProviderError means the provider is configured but the query failed.
+	ProviderError ProviderState = "error"
+)
+
+// FlyStatus is the live state of the Fly app.
+type FlyStatus struct {
+	State ProviderState      `json:"state"`
+	Error string             `json:"error,omitempty"`
+	Info  *types.FlyLiveInfo `json:"info,omitempty"`
+}
+
+// CloudflareStatus is the live state of the Cloudflare route.
+type CloudflareStatus struct {
+	State ProviderState             `json:"state"`
+	Error string                    `json:"error,omitempty"`
+	Info  *types.CloudflareLiveInfo `json:"info,omitempty"`
+}
+
+// LocalStackStatus is the state of the local process-compose stack.
+type LocalStackStatus struct {
+	Running   bool                          `json:"running"`
+	Port      int                           `json:"port"`
+	Error     string                        `json:"error,omitempty"`
+	Processes []process.ComposeProcessState `json:"processes,omitempty"`
+}
+
+// StackStatus answers "what is currently deployed?" across Fly, Cloudflare,
+// and the local stack.
+type StackStatus struct {
+	orchestrator.Status
+	FlyApp     FlyStatus        `json:"fly_app"`
+	Route      CloudflareStatus `json:"route"`
+	LocalStack LocalStackStatus `json:"local_stack"`
+}
+
+// statusSources are the provider queries behind Status; tests replace them.
+type statusSources struct {
+	describeFly        func(context.Context, sharedcfg.ToolingProfile, string) (types.FlyLiveInfo, error)
+	describeCloudflare func(context.Context, sharedcfg.ToolingProfile, cloudflare.Settings, string) (types.CloudflareLiveInfo, error)
+	composeProcesses   func(context.Context, int) ([]process.ComposeProcessState, error)
+}
+
+func defaultStatusSources() statusSources {
+	return statusSources{
+		describeFly:        fly.DescribeFly,
+		describeCloudflare: cloudflare.DescribeCloudflare,
+		composeProcesses:   process.FetchComposeProcesses,
+	}
+}
+
+// Status queries Fly, Cloudflare, and the local stack concurrently. Only
+// failing to resolve the tooling context is an error; an unconfigured or
+// unreachable provider is reported in its section instead, so the only
+// error category is ErrInvalidRequest.
+func (s *Service) Status(ctx context.Context, opts profiles.ContextOptions) (StackStatus, error) {
+	ctxInfo, err := profiles.ResolveContext(opts)
+	if err != nil {
+		return StackStatus{}, wrapError("status", err, ErrInvalidRequest)
+	}
+
+	status := StackStatus{Status: orchestrator.CachedStatus(ctxInfo)}
+	src := s.sources
+	appName := strings.TrimSpace(ctxInfo.Profile.FlyApp)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		if appName == "" {
+			status.FlyApp = FlyStatus{State: ProviderUnknown, Error: "no Fly app configured"}
+			return
+		}
+		info, err := src.describeFly(ctx, ctxInfo.Profile, appName)
+		if err != nil {
+			status.FlyApp = FlyStatus{State: ProviderError, Error: err.Error()}
+			return
+		}
+		status.FlyApp = FlyStatus{State: ProviderOK, Info: &info}
+	}()
+	go func() {
+		defer wg.Done()
+		if ctxInfo.Cloudflare.ZoneID == "" && ctxInfo.Cloudflare.ZoneName == "" {
+			status.Route = CloudflareStatus{State: ProviderUnknown, Error: "no Cloudflare zone configured"}
+			return
+		}
+		info, err := src.describeCloudflare(ctx, ctxInfo.Profile, ctxInfo.Cloudflare, appName)
+		if err != nil {
+			status.Route = CloudflareStatus{State: ProviderError, Error: err.Error()}
+			return
+		}
+		status.Route = CloudflareStatus{State: ProviderOK, Info: &info}
+	}()
+	go func() {
+		defer wg.Done()
+		port := process.ComposePort(nil)
+		status.LocalStack.Port = port
+		states, err := src.composeProcesses(ctx, port)
+		switch {
+		case errors.Is(err, process.ErrComposeUnavailable):
+		case err != nil:
+			status.LocalStack.Error = err.Error()
+		default:
+			status.LocalStack.Running = true
+			status.LocalStack.Processes = states
+		}
+	}()
+	wg.Wait()
+
+	return status, nil
+}
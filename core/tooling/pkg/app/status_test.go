@@ -0,0 +1,62 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/infra/core/pkg/runtime/process"
+	sharedcfg "github.com/joeblew999/infra/core/pkg/shared/config"
+	"github.com/joeblew999/infra/core/tooling/pkg/cloudflare"
+	profiles "github.com/joeblew999/infra/core/tooling/pkg/profiles"
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
+)
+
+func TestStatusToleratesUnconfiguredProviders(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("CORE_APP_ROOT", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "core"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "core", "go.mod"), []byte("module example.com/core\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := cloudflare.SaveSettings(cloudflare.Settings{ZoneName: "example.com", UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := New()
+	svc.sources = statusSources{
+		describeFly: func(context.Context, sharedcfg.ToolingProfile, string) (types.FlyLiveInfo, error) {
+			return types.FlyLiveInfo{Status: "deployed"}, nil
+		},
+		describeCloudflare: func(context.Context, sharedcfg.ToolingProfile, cloudflare.Settings, string) (types.CloudflareLiveInfo, error) {
+			return types.CloudflareLiveInfo{}, errors.New("token expired")
+		},
+		composeProcesses: func(context.Context, int) ([]process.ComposeProcessState, error) {
+			return nil, process.ErrComposeUnavailable
+		},
+	}
+
+	status, err := svc.Status(context.Background(), profiles.ContextOptions{RepoRoot: dir})
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	if status.Profile.FlyApp == "" {
+		if status.FlyApp.State != ProviderUnknown {
+			t.Errorf("fly state = %q, want unknown without an app", status.FlyApp.State)
+		}
+	} else if status.FlyApp.State != ProviderOK || status.FlyApp.Info.Status != "deployed" {
+		t.Errorf("fly = %+v", status.FlyApp)
+	}
+	if status.Route.State != ProviderError || status.Route.Error != "token expired" {
+		t.Errorf("route = %+v, want error state", status.Route)
+	}
+	if status.LocalStack.Running || status.LocalStack.Error != "" {
+		t.Errorf("local stack = %+v, want stopped without error", status.LocalStack)
+	}
+}
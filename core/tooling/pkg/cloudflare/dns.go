@@ -7,50 +7,20 @@ import (
 
 	cf "github.com/cloudflare/cloudflare-go"
 	sharedcfg "github.com/joeblew999/infra/core/pkg/shared/config"
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
 )
 
 // EnsureAppHostname ensures a CNAME record routes the Cloudflare hostname to the Fly app.
 // Returns the hostname when managed or an empty string if no action was required.
 func EnsureAppHostname(ctx context.Context, profile sharedcfg.ToolingProfile, settings Settings, appName string) (string, error) {
-	zoneName := strings.TrimSpace(settings.ZoneName)
-	zoneID := strings.TrimSpace(settings.ZoneID)
-	if zoneName == "" && zoneID == "" {
-		return "", nil
-	}
-
-	hostname := strings.TrimSpace(settings.AppDomain)
+	hostname := appHostname(settings, appName)
 	if hostname == "" {
-		if zoneName == "" {
-			return "", nil
-		}
-		hostname = fmt.Sprintf("%s.%s", strings.TrimSpace(appName), zoneName)
-	} else if zoneName != "" && !strings.Contains(hostname, ".") {
-		hostname = fmt.Sprintf("%s.%s", hostname, zoneName)
-	}
-
-	tokenPath := strings.TrimSpace(profile.CloudflareTokenPath)
-	if tokenPath == "" {
-		tokenPath = DefaultTokenPath()
-	}
-
-	token, err := LoadToken(tokenPath)
-	if err != nil {
-		return "", fmt.Errorf("load cloudflare token: %w", err)
+		return "", nil
 	}
 
-	api, err := cf.NewWithAPIToken(token)
+	api, zoneID, err := zoneClient(profile, settings)
 	if err != nil {
-		return "", fmt.Errorf("create cloudflare client: %w", err)
-	}
-
-	if zoneID == "" && zoneName != "" {
-		zoneID, err = api.ZoneIDByName(zoneName)
-		if err != nil {
-			return "", fmt.Errorf("resolve zone %s: %w", zoneName, err)
-		}
-	}
-	if zoneID == "" {
-		return "", fmt.Errorf("cloudflare zone id missing")
+		return "", err
 	}
 
 	target := fmt.Sprintf("%s.fly.dev", strings.TrimSpace(appName))
@@ -63,7 +33,7 @@ func EnsureAppHostname(ctx context.Context, profile sharedcfg.ToolingProfile, se
 
 	if len(records) > 0 {
 		record := records[0]
-		if strings.EqualFold(strings.TrimSpace(record.Content), target) && record.Proxied != nil && *record.Proxied == proxied {
+		if recordCurrent(record, target, proxied) {
 			return hostname, nil
 		}
 		update := cf.UpdateDNSRecordParams{
@@ -92,3 +62,101 @@ func EnsureAppHostname(ctx context.Context, profile sharedcfg.ToolingProfile, se
 	}
 	return hostname, nil
 }
+
+// PlanAppHostname reports what EnsureAppHostname would do without changing
+// any records. It returns ok=false when no hostname is managed. Lookup
+// failures are reported in the action's Note.
+func PlanAppHostname(ctx context.Context, profile sharedcfg.ToolingProfile, settings Settings, appName string) (action types.PlannedAction, ok bool) {
+	hostname := appHostname(settings, appName)
+	if hostname == "" {
+		return types.PlannedAction{}, false
+	}
+	target := fmt.Sprintf("%s.fly.dev", strings.TrimSpace(appName))
+	action = types.PlannedAction{
+		Provider: "cloudflare",
+		Action:   types.PlanUpdate,
+		Target:   hostname,
+		Desired:  "CNAME " + target + " (proxied)",
+	}
+
+	api, zoneID, err := zoneClient(profile, settings)
+	if err != nil {
+		action.Note = "current record unknown: " + err.Error()
+		return action, true
+	}
+	records, _, err := api.ListDNSRecords(ctx, cf.ZoneIdentifier(zoneID), cf.ListDNSRecordsParams{Name: hostname, Type: "CNAME"})
+	switch {
+	case err != nil:
+		action.Note = "current record unknown: list dns records: " + err.Error()
+	case len(records) == 0:
+		action.Action = types.PlanCreate
+	default:
+		record := records[0]
+		action.Current = "CNAME " + strings.TrimSpace(record.Content)
+		if record.Proxied != nil && *record.Proxied {
+			action.Current += " (proxied)"
+		}
+		if recordCurrent(record, target, true) {
+			action.Action = types.PlanNoChange
+		}
+	}
+	return action, true
+}
+
+// appHostname is the hostname settings manage for appName, or "" when no
+// zone is configured.
+func appHostname(settings Settings, appName string) string {
+	zoneName := strings.TrimSpace(settings.ZoneName)
+	zoneID := strings.TrimSpace(settings.ZoneID)
+	if zoneName == "" && zoneID == "" {
+		return ""
+	}
+
+	hostname := strings.TrimSpace(settings.AppDomain)
+	if hostname == "" {
+		if zoneName == "" {
+			return ""
+		}
+		hostname = fmt.Sprintf("%s.%s", strings.TrimSpace(appName), zoneName)
+	} else if zoneName != "" && !strings.Contains(hostname, ".") {
+		hostname = fmt.Sprintf("%s.%s", hostname, zoneName)
+	}
+	return hostname
+}
+
+// zoneClient builds an API client from the profile's token and resolves the
+// zone ID from settings.
+func zoneClient(profile sharedcfg.ToolingProfile, settings Settings) (*cf.API, string, error) {
+	zoneName := strings.TrimSpace(settings.ZoneName)
+	zoneID := strings.TrimSpace(settings.ZoneID)
+
+	tokenPath := strings.TrimSpace(profile.CloudflareTokenPath)
+	if tokenPath == "" {
+		tokenPath = DefaultTokenPath()
+	}
+
+	token, err := LoadToken(tokenPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("load cloudflare token: %w", err)
+	}
+
+	api, err := cf.NewWithAPIToken(token)
+	if err != nil {
+		return nil, "", fmt.Errorf("create cloudflare client: %w", err)
+	}
+
+	if zoneID == "" && zoneName != "" {
+		zoneID, err = api.ZoneIDByName(zoneName)
+		if err != nil {
+			return nil, "", fmt.Errorf("resolve zone %s: %w", zoneName, err)
+		}
+	}
+	if zoneID == "" {
+		return nil, "", fmt.Errorf("cloudflare zone id missing")
+	}
+	return api, zoneID, nil
+}
+
+func recordCurrent(record cf.DNSRecord, target string, proxied bool) bool {
+	return strings.EqualFold(strings.TrimSpace(record.Content), target) && record.Proxied != nil && *record.Proxied == proxied
+}
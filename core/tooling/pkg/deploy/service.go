@@ -46,43 +46,26 @@ func (s *Service) Deploy(ctx context.Context, opts Options) (*Result, error) {
 		out = io.Discard
 	}
 
-	// Resolve settings
-	appName := strings.TrimSpace(profiles.FirstNonEmpty(opts.AppName, s.profile.FlyApp))
-	if appName == "" {
-		return nil, fmt.Errorf("missing Fly app name")
-	}
-
-	flySettings, _ := flyprefs.LoadSettings()
-	orgSlug := strings.TrimSpace(profiles.FirstNonEmpty(opts.OrgSlug, flySettings.OrgSlug, s.profile.FlyOrg))
-	region := strings.TrimSpace(profiles.FirstNonEmpty(opts.Region, flySettings.RegionCode, s.profile.FlyRegion))
-
-	repo := strings.TrimSpace(opts.Repo)
-	if repo == "" {
-		repo = strings.TrimSpace(s.profile.KORepository)
-	}
-	if repo == "" {
-		repo = fmt.Sprintf("registry.fly.io/%s", appName)
+	target, err := s.resolve(opts)
+	if err != nil {
+		return nil, err
 	}
 
-	importPath := profiles.FirstNonEmpty(s.profile.ImportPath, "./cmd/core")
-	koOutput := filepath.Join(s.coreDir, profiles.FirstNonEmpty(s.profile.KoConfig, ".ko.yaml"))
-	flyOutput := filepath.Join(s.repoRoot, profiles.FirstNonEmpty(s.profile.FlyConfig, "fly.toml"))
-
 	// Generate config
 	fmt.Fprintln(out, "⚙️  Generating configuration files...")
-	_, err := configinit.Run(ctx, configinit.Options{
+	_, err = configinit.Run(ctx, configinit.Options{
 		Profile:     s.profile,
 		ProfileName: s.profileName,
 		RepoRoot:    s.repoRoot,
 		CoreDir:     s.coreDir,
-		AppName:     appName,
-		OrgSlug:     orgSlug,
-		Region:      region,
-		Repository:  repo,
+		AppName:     target.appName,
+		OrgSlug:     target.orgSlug,
+		Region:      target.region,
+		Repository:  target.repo,
 		Force:       true,
 		SkipPrompt:  true,
-		KoOutput:    koOutput,
-		FlyOutput:   flyOutput,
+		KoOutput:    target.koOutput,
+		FlyOutput:   target.flyOutput,
 		Stdout:      out,
 		Stderr:      opts.Stderr,
 		Stdin:       opts.Stdin,
@@ -92,24 +75,10 @@ func (s *Service) Deploy(ctx context.Context, opts Options) (*Result, error) {
 	}
 	fmt.Fprintln(out)
 
-	tokenPath := profiles.FirstNonEmpty(s.profile.TokenPath, flyprefs.DefaultTokenPath())
-
 	// Build and deploy
 	fmt.Fprintln(out, "🏗️  Building and deploying...")
 	fmt.Fprintln(out)
-	result, err := releasepkg.Run(ctx, releasepkg.Options{
-		AppName:      appName,
-		ConfigPath:   flyOutput,
-		KoConfigPath: koOutput,
-		ImportPath:   importPath,
-		TokenFile:    tokenPath,
-		Tags:         []string{"latest"},
-		Verbose:      opts.Verbose,
-		CoreDir:      s.coreDir,
-		OrgSlug:      orgSlug,
-		Profile:      s.profileName,
-		Repository:   repo,
-	})
+	result, err := releasepkg.Run(ctx, s.releaseOptions(target, opts.Verbose))
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +88,81 @@ func (s *Service) Deploy(ctx context.Context, opts Options) (*Result, error) {
 		ReleaseSummary: result.ReleaseSummary,
 		ReleaseID:      result.ReleaseID,
 		Elapsed:        result.Elapsed,
-		AppName:        appName,
-		OrgSlug:        orgSlug,
+		AppName:        target.appName,
+		OrgSlug:        target.orgSlug,
+	}, nil
+}
+
+// Plan reports what Deploy would do with opts: the config files it would
+// write and the build and release actions, without changing anything.
+func (s *Service) Plan(ctx context.Context, opts Options) ([]types.PlannedAction, error) {
+	target, err := s.resolve(opts)
+	if err != nil {
+		return nil, err
+	}
+	actions := []types.PlannedAction{
+		{Provider: "config", Action: types.PlanWrite, Target: target.koOutput, Desired: "ko config for " + target.repo},
+		{Provider: "config", Action: types.PlanWrite, Target: target.flyOutput, Desired: "fly config for " + target.appName},
+	}
+	release, err := releasepkg.Plan(ctx, s.releaseOptions(target, opts.Verbose))
+	if err != nil {
+		return nil, err
+	}
+	return append(actions, release...), nil
+}
+
+// target is the app, registry and file locations a deploy resolves to.
+type target struct {
+	appName    string
+	orgSlug    string
+	region     string
+	repo       string
+	importPath string
+	koOutput   string
+	flyOutput  string
+}
+
+func (s *Service) resolve(opts Options) (target, error) {
+	appName := strings.TrimSpace(profiles.FirstNonEmpty(opts.AppName, s.profile.FlyApp))
+	if appName == "" {
+		return target{}, fmt.Errorf("missing Fly app name")
+	}
+
+	flySettings, _ := flyprefs.LoadSettings()
+	orgSlug := strings.TrimSpace(profiles.FirstNonEmpty(opts.OrgSlug, flySettings.OrgSlug, s.profile.FlyOrg))
+	region := strings.TrimSpace(profiles.FirstNonEmpty(opts.Region, flySettings.RegionCode, s.profile.FlyRegion))
+
+	repo := strings.TrimSpace(opts.Repo)
+	if repo == "" {
+		repo = strings.TrimSpace(s.profile.KORepository)
+	}
+	if repo == "" {
+		repo = fmt.Sprintf("registry.fly.io/%s", appName)
+	}
+
+	return target{
+		appName:    appName,
+		orgSlug:    orgSlug,
+		region:     region,
+		repo:       repo,
+		importPath: profiles.FirstNonEmpty(s.profile.ImportPath, "./cmd/core"),
+		koOutput:   filepath.Join(s.coreDir, profiles.FirstNonEmpty(s.profile.KoConfig, ".ko.yaml")),
+		flyOutput:  filepath.Join(s.repoRoot, profiles.FirstNonEmpty(s.profile.FlyConfig, "fly.toml")),
 	}, nil
 }
+
+func (s *Service) releaseOptions(t target, verbose bool) releasepkg.Options {
+	return releasepkg.Options{
+		AppName:      t.appName,
+		ConfigPath:   t.flyOutput,
+		KoConfigPath: t.koOutput,
+		ImportPath:   t.importPath,
+		TokenFile:    profiles.FirstNonEmpty(s.profile.TokenPath, flyprefs.DefaultTokenPath()),
+		Tags:         []string{"latest"},
+		Verbose:      verbose,
+		CoreDir:      s.coreDir,
+		OrgSlug:      t.orgSlug,
+		Profile:      s.profileName,
+		Repository:   t.repo,
+	}
+}
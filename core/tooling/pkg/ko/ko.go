@@ -12,6 +12,7 @@ import (
 	"github.com/google/ko/pkg/commands"
 
 	sharedcfg "github.com/joeblew999/infra/core/pkg/shared/config"
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
 )
 
 // PublishOptions controls how the ko publisher runs.
@@ -39,17 +40,9 @@ type PublishOptions struct {
 
 func Publish(ctx context.Context, opts PublishOptions) ([]string, error) {
 	env := map[string]string{"KO_CONFIG": opts.ConfigPath}
-	repo := strings.TrimSpace(opts.Repo)
-	if repo == "" && opts.Env != nil {
-		repo = strings.TrimSpace(opts.Env["KO_DOCKER_REPO"])
-	}
-	if repo == "" {
-		if inherited, ok := os.LookupEnv("KO_DOCKER_REPO"); ok {
-			repo = strings.TrimSpace(inherited)
-		}
-	}
-	if repo == "" {
-		return nil, fmt.Errorf("ko publish: repository not provided (Repo or KO_DOCKER_REPO required)")
+	repo, err := resolveRepo(opts)
+	if err != nil {
+		return nil, err
 	}
 	if len(opts.Env) > 0 {
 		for k, v := range opts.Env {
@@ -120,6 +113,45 @@ func Publish(ctx context.Context, opts PublishOptions) ([]string, error) {
 	return refs, nil
 }
 
+// Plan reports the images Publish would build and push, without running ko.
+func Plan(opts PublishOptions) (types.PlannedAction, error) {
+	repo, err := resolveRepo(opts)
+	if err != nil {
+		return types.PlannedAction{}, err
+	}
+	refs := []string{repo}
+	if len(opts.Tags) > 0 {
+		refs = refs[:0]
+		for _, tag := range opts.Tags {
+			refs = append(refs, fmt.Sprintf("%s:%s", repo, tag))
+		}
+	}
+	return types.PlannedAction{
+		Provider: "ko",
+		Action:   types.PlanBuild,
+		Target:   strings.Join(opts.Args, " "),
+		Desired:  strings.Join(refs, ", "),
+	}, nil
+}
+
+// resolveRepo picks KO_DOCKER_REPO from opts.Repo, opts.Env or the process
+// environment, in that order.
+func resolveRepo(opts PublishOptions) (string, error) {
+	repo := strings.TrimSpace(opts.Repo)
+	if repo == "" && opts.Env != nil {
+		repo = strings.TrimSpace(opts.Env["KO_DOCKER_REPO"])
+	}
+	if repo == "" {
+		if inherited, ok := os.LookupEnv("KO_DOCKER_REPO"); ok {
+			repo = strings.TrimSpace(inherited)
+		}
+	}
+	if repo == "" {
+		return "", fmt.Errorf("ko publish: repository not provided (Repo or KO_DOCKER_REPO required)")
+	}
+	return repo, nil
+}
+
 func setEnv(values map[string]string) func() {
 	if len(values) == 0 {
 		return func() {}
@@ -48,6 +48,9 @@ func (a *StreamAdapter) Emitter() ProgressEmitter {
 		}
 		msg := types.ProgressMessage{
 			Phase:   string(evt.Phase),
+			Step:    string(evt.Step),
+			State:   string(evt.State),
+			Percent: evt.Percent,
 			Message: evt.Message,
 			Details: evt.Details,
 			Time:    evt.Time,
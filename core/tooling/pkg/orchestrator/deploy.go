@@ -76,16 +76,35 @@ type DeployOptions struct {
 	types.DeployRequest
 	Emitter  ProgressEmitter
 	Prompter auth.Prompter
+	// Progress, if set, also receives every progress event. Sends never block:
+	// events are dropped while the channel is full. The caller owns the
+	// channel and closes it after Deploy returns.
+	Progress chan<- ProgressEvent
+	// Rollback holds per-step hooks. When a step fails, the hooks of the steps
+	// that already succeeded run in reverse order.
+	Rollback map[StepName]RollbackFunc
 }
 
 // DeployResult captures the outcome of a deployment workflow.
 type DeployResult struct {
 	ProfileName string
 	Profile     string
+	Hostname    string
+	Steps       []StepStatus
+	// DryRun is set when the result is a plan; Plan lists what a real
+	// deployment would do.
+	DryRun bool
+	Plan   []types.PlannedAction
 	types.DeployResult
 }
 
-// Deploy runs the full deployment workflow.
+// Deploy runs the full deployment workflow: Fly and Cloudflare auth, KO build
+// and Fly release, then Cloudflare DNS. If a step fails, the returned result
+// is non-nil and records every step that ran.
+//
+// With DryRun set, Deploy skips authentication and only asks each provider
+// what it would change, using whatever credentials are already stored. The
+// plan is emitted as PhasePlanned events and returned in DeployResult.Plan.
 func (s *Service) Deploy(ctx context.Context, opts DeployOptions) (*DeployResult, error) {
 	if s.auth == nil {
 		s.auth = auth.New()
@@ -113,12 +132,24 @@ func (s *Service) Deploy(ctx context.Context, opts DeployOptions) (*DeployResult
 	if emitter == nil {
 		emitter = NewTextEmitter(out)
 	}
+	if opts.Progress != nil {
+		emitter = combineEmitters(emitter, NewChannelEmitter(opts.Progress))
+	}
 
-	emit := func(phase ProgressPhase, message string, details map[string]string) {
+	var result *DeployResult
+	emitEvent := func(evt ProgressEvent) {
 		if emitter != nil {
-			emitter.Emit(ProgressEvent{Phase: phase, Message: message, Details: details, Time: time.Now().UTC()})
+			evt.Percent = result.percent()
+			evt.Time = time.Now().UTC()
+			emitter.Emit(evt)
 		}
 	}
+	emit := func(phase ProgressPhase, message string, details map[string]string) {
+		emitEvent(ProgressEvent{Phase: phase, Message: message, Details: details})
+	}
+	emitStep := func(phase ProgressPhase, step StepName, state StepState, message string, details map[string]string) {
+		emitEvent(ProgressEvent{Phase: phase, Step: step, State: state, Message: message, Details: details})
+	}
 
 	emit(PhaseStarted, "🚀 Starting deployment workflow...", nil)
 
@@ -160,55 +191,108 @@ func (s *Service) Deploy(ctx context.Context, opts DeployOptions) (*DeployResult
 		Prompter:  prompter,
 	}
 
-	emit(PhaseFlyAuth, "Authenticating with Fly.io...", nil)
-	if err := s.auth.EnsureFly(ctx, profile, authOpts); err != nil {
-		emit(PhaseFailed, "Fly authentication failed.", map[string]string{"error": err.Error()})
-		return nil, fmt.Errorf("fly authentication failed: %w", err)
+	result = &DeployResult{ProfileName: profileName, Profile: profile.Name}
+
+	factory := s.makeDeployer
+	if factory == nil {
+		factory = func(profile sharedcfg.ToolingProfile, profileName, repoRoot, coreDir string) Deployer {
+			return deploy.New(profile, profileName, repoRoot, coreDir)
+		}
+	}
+	deployer := factory(profile, profileName, repoRoot, coreDir)
+
+	if req.DryRun {
+		plan, err := deployer.Plan(ctx, req)
+		if err != nil {
+			emit(PhaseFailed, "Failed to plan deployment.", map[string]string{"error": err.Error()})
+			return nil, fmt.Errorf("plan deployment: %w", err)
+		}
+		appName := profiles.FirstNonEmpty(req.AppName, profile.FlyApp)
+		if dns, ok := cloudflare.PlanAppHostname(ctx, profile, cloudflareSettings, appName); ok {
+			plan = append(plan, dns)
+		}
+		result.DryRun = true
+		result.Plan = plan
+		for _, action := range plan {
+			emit(PhasePlanned, action.String(), map[string]string{
+				"provider": action.Provider,
+				"action":   string(action.Action),
+				"target":   action.Target,
+				"current":  action.Current,
+				"desired":  action.Desired,
+				"note":     action.Note,
+			})
+		}
+		emit(PhaseSucceeded, fmt.Sprintf("📝 Dry run complete: %d planned actions, nothing changed.", len(plan)), map[string]string{
+			"profile": profileName,
+		})
+		return result, nil
+	}
+
+	fail := func(step StepName, message string, err error) (*DeployResult, error) {
+		emitStep(PhaseFailed, step, StepFailed, message, map[string]string{"error": err.Error()})
+		rollback(ctx, opts.Rollback, result)
+		return result, err
+	}
+
+	emitStep(PhaseFlyAuth, StepFlyAuth, StepRunning, "Authenticating with Fly.io...", nil)
+	if err := runStep(result, StepFlyAuth, func() error {
+		return s.auth.EnsureFly(ctx, profile, authOpts)
+	}); err != nil {
+		return fail(StepFlyAuth, "Fly authentication failed.", fmt.Errorf("fly authentication failed: %w", err))
 	}
 	flySettings, _ = flyprefs.LoadSettings()
-	emit(PhaseFlyAuthCompleted, "Fly authentication complete.", map[string]string{
+	emitStep(PhaseFlyAuthCompleted, StepFlyAuth, StepSucceeded, "Fly authentication complete.", map[string]string{
 		"org":    flySettings.OrgSlug,
 		"region": flySettings.RegionCode,
 	})
 
-	emit(PhaseCloudflareAuth, "Authenticating with Cloudflare...", nil)
-	if err := s.auth.EnsureCloudflare(ctx, profile, authOpts); err != nil {
-		emit(PhaseFailed, "Cloudflare authentication failed.", map[string]string{"error": err.Error()})
-		return nil, fmt.Errorf("cloudflare authentication failed: %w", err)
+	emitStep(PhaseCloudflareAuth, StepCloudflareAuth, StepRunning, "Authenticating with Cloudflare...", nil)
+	if err := runStep(result, StepCloudflareAuth, func() error {
+		return s.auth.EnsureCloudflare(ctx, profile, authOpts)
+	}); err != nil {
+		return fail(StepCloudflareAuth, "Cloudflare authentication failed.", fmt.Errorf("cloudflare authentication failed: %w", err))
 	}
 	cloudflareSettings, _ = cloudflare.LoadSettings()
-	emit(PhaseCloudflareComplete, "Cloudflare authentication complete.", map[string]string{
+	emitStep(PhaseCloudflareComplete, StepCloudflareAuth, StepSucceeded, "Cloudflare authentication complete.", map[string]string{
 		"zone":   cloudflareSettings.ZoneName,
 		"bucket": cloudflareSettings.R2Bucket,
 	})
 
-	factory := s.makeDeployer
-	if factory == nil {
-		factory = func(profile sharedcfg.ToolingProfile, profileName, repoRoot, coreDir string) Deployer {
-			return deploy.New(profile, profileName, repoRoot, coreDir)
-		}
-	}
-
-	deployer := factory(profile, profileName, repoRoot, coreDir)
-	emit(PhaseDeploying, "🏗️  Building and deploying...", map[string]string{
+	emitStep(PhaseDeploying, StepRelease, StepRunning, "🏗️  Building and deploying...", map[string]string{
 		"profile":   profileName,
 		"repo_root": repoRoot,
 		"core_dir":  coreDir,
 		"app":       req.AppName,
 	})
 
-	res, err := deployer.Deploy(ctx, req)
-	if err != nil {
-		emit(PhaseFailed, "Deployment failed.", map[string]string{"error": err.Error()})
-		return nil, err
+	if err := runStep(result, StepRelease, func() error {
+		res, err := deployer.Deploy(ctx, req)
+		if err != nil {
+			return err
+		}
+		result.DeployResult = *res
+		return nil
+	}); err != nil {
+		return fail(StepRelease, "Deployment failed.", err)
 	}
+	res := &result.DeployResult
+	emitStep(PhaseDeployed, StepRelease, StepSucceeded, "Release deployed.", map[string]string{
+		"app":        res.AppName,
+		"release_id": res.ReleaseID,
+	})
 
-	emit(PhaseCloudflareDNS, "Configuring Cloudflare DNS...", nil)
-	hostname, dnsErr := cloudflare.EnsureAppHostname(ctx, profile, cloudflareSettings, res.AppName)
-	if dnsErr != nil {
-		emit(PhaseFailed, "Cloudflare DNS configuration failed.", map[string]string{"error": dnsErr.Error()})
-		return nil, dnsErr
+	emitStep(PhaseCloudflareDNS, StepCloudflareDNS, StepRunning, "Configuring Cloudflare DNS...", nil)
+	if err := runStep(result, StepCloudflareDNS, func() error {
+		hostname, err := cloudflare.EnsureAppHostname(ctx, profile, cloudflareSettings, res.AppName)
+		result.Hostname = hostname
+		return err
+	}); err != nil {
+		return fail(StepCloudflareDNS, "Cloudflare DNS configuration failed.", err)
 	}
+	emitStep(PhaseCloudflareDNSComplete, StepCloudflareDNS, StepSucceeded, "Cloudflare DNS configured.", map[string]string{
+		"hostname": result.Hostname,
+	})
 
 	details := map[string]string{
 		"image":      res.ImageReference,
@@ -224,17 +308,13 @@ func (s *Service) Deploy(ctx context.Context, opts DeployOptions) (*DeployResult
 		"cf_account": cloudflareSettings.AccountID,
 		"cf_bucket":  cloudflareSettings.R2Bucket,
 	}
-	if hostname != "" {
-		details["cf_hostname"] = hostname
+	if result.Hostname != "" {
+		details["cf_hostname"] = result.Hostname
 	}
 
 	emit(PhaseSucceeded, "✅ Deployment successful!", details)
 
-	return &DeployResult{
-		ProfileName:  profileName,
-		Profile:      profile.Name,
-		DeployResult: *res,
-	}, nil
+	return result, nil
 }
 
 // Launch starts the deployment workflow using a stream adapter and returns
@@ -265,4 +345,3 @@ func (s *Service) Launch(ctx context.Context, opts DeployOptions) (*StreamAdapte
 
 	return adapter, resultCh, errCh
 }
-
@@ -2,6 +2,7 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"io"
 	"testing"
 	"time"
@@ -27,9 +28,11 @@ func (f *fakeAuthProvider) EnsureCloudflare(ctx context.Context, profile sharedc
 }
 
 type fakeDeployer struct {
-	calls int
-	opts  types.DeployRequest
-	res   *types.DeployResult
+	calls     int
+	planCalls int
+	opts      types.DeployRequest
+	res       *types.DeployResult
+	plan      []types.PlannedAction
 }
 
 func (f *fakeDeployer) Deploy(ctx context.Context, opts types.DeployRequest) (*types.DeployResult, error) {
@@ -41,6 +44,12 @@ func (f *fakeDeployer) Deploy(ctx context.Context, opts types.DeployRequest) (*t
 	return &types.DeployResult{}, nil
 }
 
+func (f *fakeDeployer) Plan(ctx context.Context, opts types.DeployRequest) ([]types.PlannedAction, error) {
+	f.planCalls++
+	f.opts = opts
+	return f.plan, nil
+}
+
 func TestDeployEmitsProgressAndInvokesDependencies(t *testing.T) {
 	ctx := context.Background()
 	profile := sharedcfg.ToolingProfile{Name: "test", FlyApp: "my-app"}
@@ -119,7 +128,9 @@ func TestDeployEmitsProgressAndInvokesDependencies(t *testing.T) {
 		PhaseCloudflareAuth,
 		PhaseCloudflareComplete,
 		PhaseDeploying,
+		PhaseDeployed,
 		PhaseCloudflareDNS,
+		PhaseCloudflareDNSComplete,
 		PhaseSucceeded,
 	}
 	if len(events) != len(expectedPhases) {
@@ -131,6 +142,21 @@ func TestDeployEmitsProgressAndInvokesDependencies(t *testing.T) {
 		}
 	}
 
+	states := map[StepName][]StepState{}
+	for _, evt := range events {
+		if evt.Step != "" {
+			states[evt.Step] = append(states[evt.Step], evt.State)
+		}
+	}
+	for _, step := range deploySteps {
+		if got := states[step]; len(got) != 2 || got[0] != StepRunning || got[1] != StepSucceeded {
+			t.Errorf("step %s states = %v, want [running succeeded]", step, got)
+		}
+	}
+	if last := events[len(events)-2]; last.Percent != 100 {
+		t.Errorf("final step event percent = %d, want 100", last.Percent)
+	}
+
 	success := events[len(events)-1]
 	if success.Details["image"] != deployed.res.ImageReference {
 		t.Fatalf("expected success image %q, got %q", deployed.res.ImageReference, success.Details["image"])
@@ -143,6 +169,54 @@ func TestDeployEmitsProgressAndInvokesDependencies(t *testing.T) {
 	}
 }
 
+func TestDeployDryRunOnlyPlans(t *testing.T) {
+	profile := sharedcfg.ToolingProfile{Name: "test", FlyApp: "my-app"}
+	authProvider := &fakeAuthProvider{}
+	deployed := &fakeDeployer{
+		plan: []types.PlannedAction{
+			{Provider: "ko", Action: types.PlanBuild, Target: "./cmd/core", Desired: "registry.fly.io/my-app:latest"},
+			{Provider: "fly", Action: types.PlanUpdate, Target: "my-app", Current: "registry.fly.io/my-app:old", Desired: "registry.fly.io/my-app:latest"},
+		},
+	}
+	svc := NewService(
+		WithAuthProvider(authProvider),
+		WithDeployerFactory(func(sharedcfg.ToolingProfile, string, string, string) Deployer {
+			return deployed
+		}),
+		WithProfileResolver(func(string) (sharedcfg.ToolingProfile, string) {
+			return profile, profile.Name
+		}),
+	)
+
+	var planned []ProgressEvent
+	result, err := svc.Deploy(context.Background(), DeployOptions{
+		DeployRequest: types.DeployRequest{AppName: "my-app", DryRun: true, Stdout: io.Discard, Stderr: io.Discard},
+		Emitter: ProgressEmitterFunc(func(evt ProgressEvent) {
+			if evt.Phase == PhasePlanned {
+				planned = append(planned, evt)
+			}
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Deploy() error = %v", err)
+	}
+	if authProvider.flyCalls != 0 || authProvider.cloudflareCalls != 0 {
+		t.Fatalf("dry run authenticated: fly=%d cloudflare=%d", authProvider.flyCalls, authProvider.cloudflareCalls)
+	}
+	if deployed.calls != 0 || deployed.planCalls != 1 {
+		t.Fatalf("expected only Plan to be called, got deploy=%d plan=%d", deployed.calls, deployed.planCalls)
+	}
+	if !result.DryRun || len(result.Plan) < len(deployed.plan) {
+		t.Fatalf("expected dry-run result with the deployer's plan, got %#v", result)
+	}
+	if len(planned) != len(result.Plan) {
+		t.Fatalf("expected one planned event per action, got %d events for %d actions", len(planned), len(result.Plan))
+	}
+	if planned[1].Details["current"] != "registry.fly.io/my-app:old" {
+		t.Fatalf("unexpected planned event details: %#v", planned[1].Details)
+	}
+}
+
 func TestLaunchUsesAdapter(t *testing.T) {
 	ctx := context.Background()
 	profile := sharedcfg.ToolingProfile{Name: "test", FlyApp: "my-app"}
@@ -203,3 +277,107 @@ func TestLaunchUsesAdapter(t *testing.T) {
 		t.Fatal("expected progress messages")
 	}
 }
+
+type failingDeployer struct{ err error }
+
+func (f failingDeployer) Deploy(context.Context, types.DeployRequest) (*types.DeployResult, error) {
+	return nil, f.err
+}
+
+func (f failingDeployer) Plan(context.Context, types.DeployRequest) ([]types.PlannedAction, error) {
+	return nil, f.err
+}
+
+func TestDeployRollsBackCompletedStepsOnFailure(t *testing.T) {
+	profile := sharedcfg.ToolingProfile{Name: "test", FlyApp: "my-app"}
+	releaseErr := errors.New("ko build failed")
+
+	svc := NewService(
+		WithAuthProvider(&fakeAuthProvider{}),
+		WithDeployerFactory(func(sharedcfg.ToolingProfile, string, string, string) Deployer {
+			return failingDeployer{err: releaseErr}
+		}),
+		WithProfileResolver(func(string) (sharedcfg.ToolingProfile, string) {
+			return profile, profile.Name
+		}),
+	)
+
+	var rolledBack []StepName
+	hook := func(name StepName) RollbackFunc {
+		return func(ctx context.Context, result *DeployResult) error {
+			rolledBack = append(rolledBack, name)
+			return nil
+		}
+	}
+
+	result, err := svc.Deploy(context.Background(), DeployOptions{
+		DeployRequest: types.DeployRequest{AppName: "my-app", Stdout: io.Discard, Stderr: io.Discard},
+		Emitter:       ProgressEmitterFunc(func(ProgressEvent) {}),
+		Rollback: map[StepName]RollbackFunc{
+			StepFlyAuth:        hook(StepFlyAuth),
+			StepCloudflareAuth: hook(StepCloudflareAuth),
+			StepCloudflareDNS:  hook(StepCloudflareDNS),
+		},
+	})
+	if !errors.Is(err, releaseErr) {
+		t.Fatalf("Deploy() error = %v, want %v", err, releaseErr)
+	}
+	if result == nil {
+		t.Fatal("expected partial result on failure")
+	}
+
+	wantStates := []StepStatus{
+		{Name: StepFlyAuth, State: StepRolledBack},
+		{Name: StepCloudflareAuth, State: StepRolledBack},
+		{Name: StepRelease, State: StepFailed},
+	}
+	if len(result.Steps) != len(wantStates) {
+		t.Fatalf("steps = %+v", result.Steps)
+	}
+	for i, want := range wantStates {
+		if got := result.Steps[i]; got.Name != want.Name || got.State != want.State {
+			t.Errorf("step %d = %s/%s, want %s/%s", i, got.Name, got.State, want.Name, want.State)
+		}
+	}
+	if len(rolledBack) != 2 || rolledBack[0] != StepCloudflareAuth || rolledBack[1] != StepFlyAuth {
+		t.Errorf("rollback order = %v, want [cloudflare_auth fly_auth]", rolledBack)
+	}
+}
+
+func TestDeployProgressChannel(t *testing.T) {
+	profile := sharedcfg.ToolingProfile{Name: "test", FlyApp: "my-app"}
+	svc := NewService(
+		WithAuthProvider(&fakeAuthProvider{}),
+		WithDeployerFactory(func(sharedcfg.ToolingProfile, string, string, string) Deployer {
+			return &fakeDeployer{res: &types.DeployResult{AppName: "my-app"}}
+		}),
+		WithProfileResolver(func(string) (sharedcfg.ToolingProfile, string) {
+			return profile, profile.Name
+		}),
+	)
+
+	// Room for a few events only: the rest must be dropped, not block.
+	progress := make(chan ProgressEvent, 3)
+	if _, err := svc.Deploy(context.Background(), DeployOptions{
+		DeployRequest: types.DeployRequest{AppName: "my-app", Stdout: io.Discard, Stderr: io.Discard},
+		Emitter:       ProgressEmitterFunc(func(ProgressEvent) {}),
+		Progress:      progress,
+	}); err != nil {
+		t.Fatalf("Deploy() error = %v", err)
+	}
+	close(progress)
+
+	var got []ProgressEvent
+	for evt := range progress {
+		got = append(got, evt)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 buffered events, got %d", len(got))
+	}
+	if got[1].Step != StepFlyAuth || got[1].State != StepRunning || got[1].Percent != 0 {
+		t.Errorf("event 1 = %+v", got[1])
+	}
+	if got[2].Step != StepFlyAuth || got[2].State != StepSucceeded || got[2].Percent != 25 {
+		t.Errorf("event 2 = %+v", got[2])
+	}
+}
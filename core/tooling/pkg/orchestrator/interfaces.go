@@ -17,6 +17,8 @@ type AuthProvider interface {
 // Deployer executes the final deployment steps.
 type Deployer interface {
 	Deploy(context.Context, types.DeployRequest) (*types.DeployResult, error)
+	// Plan reports what Deploy would do without changing anything.
+	Plan(context.Context, types.DeployRequest) ([]types.PlannedAction, error)
 }
 
 type deployerFactory func(sharedcfg.ToolingProfile, string, string, string) Deployer
@@ -11,20 +11,28 @@ import (
 type ProgressPhase string
 
 const (
-	PhaseStarted            ProgressPhase = "started"
-	PhaseFlyAuth            ProgressPhase = "fly_auth"
-	PhaseFlyAuthCompleted   ProgressPhase = "fly_auth_completed"
-	PhaseCloudflareAuth     ProgressPhase = "cloudflare_auth"
-	PhaseCloudflareComplete ProgressPhase = "cloudflare_auth_completed"
-	PhaseCloudflareDNS      ProgressPhase = "cloudflare_dns"
-	PhaseDeploying          ProgressPhase = "deploying"
-	PhaseSucceeded          ProgressPhase = "succeeded"
-	PhaseFailed             ProgressPhase = "failed"
+	PhaseStarted               ProgressPhase = "started"
+	PhaseFlyAuth               ProgressPhase = "fly_auth"
+	PhaseFlyAuthCompleted      ProgressPhase = "fly_auth_completed"
+	PhaseCloudflareAuth        ProgressPhase = "cloudflare_auth"
+	PhaseCloudflareComplete    ProgressPhase = "cloudflare_auth_completed"
+	PhaseCloudflareDNS         ProgressPhase = "cloudflare_dns"
+	PhaseCloudflareDNSComplete ProgressPhase = "cloudflare_dns_completed"
+	PhaseDeploying             ProgressPhase = "deploying"
+	PhaseDeployed              ProgressPhase = "deployed"
+	PhasePlanned               ProgressPhase = "planned"
+	PhaseSucceeded             ProgressPhase = "succeeded"
+	PhaseFailed                ProgressPhase = "failed"
 )
 
-// ProgressEvent describes a deploy-stage update.
+// ProgressEvent describes a deploy-stage update. Step and State are set for
+// events that start or finish a workflow step; Percent is the share of steps
+// completed so far.
 type ProgressEvent struct {
 	Phase   ProgressPhase     `json:"phase"`
+	Step    StepName          `json:"step,omitempty"`
+	State   StepState         `json:"state,omitempty"`
+	Percent int               `json:"percent"`
 	Message string            `json:"message"`
 	Details map[string]string `json:"details,omitempty"`
 	Time    time.Time         `json:"time"`
@@ -40,6 +48,17 @@ type ProgressEmitterFunc func(ProgressEvent)
 
 func (f ProgressEmitterFunc) Emit(evt ProgressEvent) { f(evt) }
 
+// NewChannelEmitter forwards events to ch without blocking; events are
+// dropped while ch is full so a slow consumer never stalls the workflow.
+func NewChannelEmitter(ch chan<- ProgressEvent) ProgressEmitter {
+	return ProgressEmitterFunc(func(evt ProgressEvent) {
+		select {
+		case ch <- evt:
+		default:
+		}
+	})
+}
+
 // TextEmitter renders events as human-readable text.
 type TextEmitter struct {
 	out io.Writer
@@ -70,10 +89,12 @@ func (t *TextEmitter) Emit(evt ProgressEvent) {
 			}
 		}
 		fmt.Fprintln(t.out)
-	case PhaseDeploying:
+	case PhaseDeploying, PhaseDeployed, PhaseCloudflareDNSComplete:
 		if evt.Message != "" {
 			fmt.Fprintln(t.out, evt.Message)
 		}
+	case PhasePlanned:
+		fmt.Fprintf(t.out, "  %s\n", evt.Message)
 	case PhaseSucceeded:
 		fmt.Fprintln(t.out, evt.Message)
 		fmt.Fprintln(t.out)
@@ -28,6 +28,25 @@ func StatusSnapshot(ctx context.Context, opts profiles.ContextOptions) (Status,
 		return Status{}, err
 	}
 
+	status := CachedStatus(ctxInfo)
+
+	if status.Profile.FlyApp != "" {
+		if live, err := fly.DescribeFly(ctx, ctxInfo.Profile, status.Profile.FlyApp); err == nil {
+			status.FlyLive = &live
+		}
+	}
+
+	if status.Cloudflare.ZoneID != "" || status.Cloudflare.ZoneName != "" {
+		if live, err := cloudflare.DescribeCloudflare(ctx, ctxInfo.Profile, ctxInfo.Cloudflare, status.Profile.FlyApp); err == nil {
+			status.CloudflareLive = &live
+		}
+	}
+
+	return status, nil
+}
+
+// CachedStatus summarises a resolved context without querying providers.
+func CachedStatus(ctxInfo profiles.Context) Status {
 	flySummary := types.FlySettingsSummary{
 		OrgSlug:    ctxInfo.Fly.OrgSlug,
 		RegionCode: ctxInfo.Fly.RegionCode,
@@ -54,7 +73,7 @@ func StatusSnapshot(ctx context.Context, opts profiles.ContextOptions) (Status,
 		KORepository: ctxInfo.Profile.KORepository,
 	}
 
-	status := Status{
+	return Status{
 		ProfileName: ctxInfo.ProfileName,
 		Profile:     profileSummary,
 		RepoRoot:    ctxInfo.RepoRoot,
@@ -62,18 +81,4 @@ func StatusSnapshot(ctx context.Context, opts profiles.ContextOptions) (Status,
 		Fly:         flySummary,
 		Cloudflare:  cfSummary,
 	}
-
-	if profileSummary.FlyApp != "" {
-		if live, err := fly.DescribeFly(ctx, ctxInfo.Profile, profileSummary.FlyApp); err == nil {
-			status.FlyLive = &live
-		}
-	}
-
-	if cfSummary.ZoneID != "" || cfSummary.ZoneName != "" {
-		if live, err := cloudflare.DescribeCloudflare(ctx, ctxInfo.Profile, ctxInfo.Cloudflare, profileSummary.FlyApp); err == nil {
-			status.CloudflareLive = &live
-		}
-	}
-
-	return status, nil
 }
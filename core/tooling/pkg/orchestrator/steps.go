@@ -0,0 +1,95 @@
+package orchestrator
+
+import (
+	"context"
+	"time"
+)
+
+// StepName identifies a deployment workflow step.
+type StepName string
+
+const (
+	StepFlyAuth        StepName = "fly_auth"
+	StepCloudflareAuth StepName = "cloudflare_auth"
+	// StepRelease builds and pushes the image with KO, then deploys it to Fly.
+	StepRelease       StepName = "release"
+	StepCloudflareDNS StepName = "cloudflare_dns"
+)
+
+// deploySteps lists the workflow steps in order.
+var deploySteps = []StepName{StepFlyAuth, StepCloudflareAuth, StepRelease, StepCloudflareDNS}
+
+// StepState is the state of a step.
+type StepState string
+
+const (
+	StepRunning        StepState = "running"
+	StepSucceeded      StepState = "succeeded"
+	StepFailed         StepState = "failed"
+	StepRolledBack     StepState = "rolled_back"
+	StepRollbackFailed StepState = "rollback_failed"
+)
+
+// StepStatus records how a step went.
+type StepStatus struct {
+	Name     StepName  `json:"name"`
+	State    StepState `json:"state"`
+	Error    string    `json:"error,omitempty"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// RollbackFunc undoes a completed step after a later step failed. It receives
+// the partial result so it can see what was deployed.
+type RollbackFunc func(ctx context.Context, result *DeployResult) error
+
+// percent reports how much of the workflow has succeeded so far.
+func (r *DeployResult) percent() int {
+	if r == nil {
+		return 0
+	}
+	done := 0
+	for _, step := range r.Steps {
+		if step.State == StepSucceeded {
+			done++
+		}
+	}
+	return done * 100 / len(deploySteps)
+}
+
+// runStep runs fn as step name and records its status on result.
+func runStep(result *DeployResult, name StepName, fn func() error) error {
+	status := StepStatus{Name: name, Started: time.Now().UTC()}
+	err := fn()
+	status.Finished = time.Now().UTC()
+	if err != nil {
+		status.State = StepFailed
+		status.Error = err.Error()
+	} else {
+		status.State = StepSucceeded
+	}
+	result.Steps = append(result.Steps, status)
+	return err
+}
+
+// rollback runs the hooks of completed steps in reverse order. It runs even
+// when ctx is already done, since a timeout is a common reason to roll back.
+func rollback(ctx context.Context, hooks map[StepName]RollbackFunc, result *DeployResult) {
+	if len(hooks) == 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	for i := len(result.Steps) - 1; i >= 0; i-- {
+		step := &result.Steps[i]
+		hook := hooks[step.Name]
+		if step.State != StepSucceeded || hook == nil {
+			continue
+		}
+		if err := hook(ctx, result); err != nil {
+			step.State = StepRollbackFailed
+			step.Error = err.Error()
+			continue
+		}
+		step.State = StepRolledBack
+	}
+}
@@ -19,6 +19,7 @@ import (
 	flyutil "github.com/joeblew999/infra/core/tooling/pkg/fly"
 
 	"github.com/joeblew999/infra/core/tooling/pkg/ko"
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
 )
 
 // Options controls the end-to-end Fly deployment pipeline.
@@ -55,24 +56,10 @@ func Run(ctx context.Context, opts Options) (Result, error) {
 	start := time.Now()
 
 	toolingCfg := sharedcfg.Tooling()
-	profile := toolingCfg.Active
-	if strings.TrimSpace(opts.Profile) != "" {
-		if selected, ok := toolingCfg.Profiles[strings.TrimSpace(opts.Profile)]; ok {
-			profile = selected
-		}
-	}
+	profile := selectProfile(toolingCfg, opts.Profile)
 
-	if opts.AppName == "" {
-		return result, fmt.Errorf("fly pipeline: app name is required")
-	}
-	if opts.ConfigPath == "" {
-		return result, fmt.Errorf("fly pipeline: config path is required")
-	}
-	if opts.ImportPath == "" {
-		return result, fmt.Errorf("fly pipeline: import path is required")
-	}
-	if opts.Tag == "" {
-		opts.Tag = "latest"
+	if err := validate(&opts); err != nil {
+		return result, err
 	}
 
 	token, err := resolveToken(opts.Token, opts.TokenFile)
@@ -80,15 +67,7 @@ func Run(ctx context.Context, opts Options) (Result, error) {
 		return result, err
 	}
 
-	client.SetBaseURL(toolingCfg.Active.FlyAPIBase)
-	log := flyutil.NewLogger("tooling-pipeline", opts.Verbose)
-
-	cl := client.NewClientFromOptions(client.ClientOptions{
-		AccessToken: token,
-		Name:        "core-pipeline",
-		Version:     "dev",
-		Logger:      log,
-	})
+	cl := newClient(toolingCfg, token, opts.Verbose)
 
 	var org *client.Organization
 	app, err := cl.GetApp(ctx, opts.AppName)
@@ -112,6 +91,154 @@ func Run(ctx context.Context, opts Options) (Result, error) {
 	}
 	defer os.RemoveAll(dockerConfigDir)
 
+	koOpts, err := publishOptions(profile, opts)
+	if err != nil {
+		return result, err
+	}
+	koOpts.Env = map[string]string{"DOCKER_CONFIG": dockerConfigDir}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer cancel()
+
+	refs, err := ko.Publish(ctx, koOpts)
+	if err != nil {
+		return result, fmt.Errorf("ko publish: %w", err)
+	}
+	imageRef := refs[len(refs)-1]
+	result.ImageReference = imageRef
+
+	if opts.PlatformVersion == "" {
+		opts.PlatformVersion = app.PlatformVersion
+	}
+	if opts.PlatformVersion == "" {
+		opts.PlatformVersion = "machines"
+	}
+
+	if alreadyCurrent(app, imageRef, koOpts.Tags[len(koOpts.Tags)-1]) {
+		result.Skipped = true
+		result.ReleaseSummary = "No changes detected; existing release already uses this image"
+		result.Elapsed = time.Since(start)
+		return result, nil
+	}
+
+	releaseSummary, err := flyutil.Deploy(ctx, flyutil.DeployOptions{
+		AccessToken:     token,
+		AppName:         opts.AppName,
+		ImageRef:        imageRef,
+		ConfigPath:      opts.ConfigPath,
+		PlatformVersion: opts.PlatformVersion,
+		Strategy:        opts.Strategy,
+		Verbose:         opts.Verbose,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.ReleaseSummary = releaseSummary
+	result.ReleaseID = extractReleaseID(releaseSummary)
+	result.Elapsed = time.Since(start)
+	logSummary(ctx, result)
+	return result, nil
+}
+
+// Plan reports what Run would do with opts without building, pushing or
+// deploying anything. Fly lookups that fail (for example without a token)
+// are reported as notes on the planned actions rather than as errors.
+func Plan(ctx context.Context, opts Options) ([]types.PlannedAction, error) {
+	toolingCfg := sharedcfg.Tooling()
+	profile := selectProfile(toolingCfg, opts.Profile)
+
+	if err := validate(&opts); err != nil {
+		return nil, err
+	}
+	koOpts, err := publishOptions(profile, opts)
+	if err != nil {
+		return nil, err
+	}
+	build, err := ko.Plan(koOpts)
+	if err != nil {
+		return nil, err
+	}
+	imageRef := fmt.Sprintf("%s:%s", koOpts.Repo, koOpts.Tags[len(koOpts.Tags)-1])
+
+	release := types.PlannedAction{
+		Provider: "fly",
+		Action:   types.PlanUpdate,
+		Target:   opts.AppName,
+		Desired:  imageRef,
+	}
+	actions := []types.PlannedAction{build}
+
+	token, err := resolveToken(opts.Token, opts.TokenFile)
+	if err != nil {
+		release.Note = "current release unknown: " + err.Error()
+		return append(actions, release), nil
+	}
+	app, err := newClient(toolingCfg, token, opts.Verbose).GetApp(ctx, opts.AppName)
+	switch {
+	case isNotFound(err):
+		actions = append(actions, types.PlannedAction{
+			Provider: "fly",
+			Action:   types.PlanCreate,
+			Target:   opts.AppName,
+			Desired:  "app in organization " + orgLabel(opts.OrgSlug),
+		})
+		release.Action = types.PlanCreate
+	case err != nil:
+		release.Note = fmt.Sprintf("lookup app %q: %v", opts.AppName, err)
+	default:
+		release.Current = currentImage(app)
+		if alreadyCurrent(app, imageRef, koOpts.Tags[len(koOpts.Tags)-1]) {
+			release.Action = types.PlanNoChange
+		}
+	}
+	return append(actions, release), nil
+}
+
+func orgLabel(slug string) string {
+	if strings.TrimSpace(slug) == "" {
+		return "(first available)"
+	}
+	return strings.TrimSpace(slug)
+}
+
+func selectProfile(toolingCfg sharedcfg.ToolingSettings, name string) sharedcfg.ToolingProfile {
+	profile := toolingCfg.Active
+	if strings.TrimSpace(name) != "" {
+		if selected, ok := toolingCfg.Profiles[strings.TrimSpace(name)]; ok {
+			profile = selected
+		}
+	}
+	return profile
+}
+
+func validate(opts *Options) error {
+	if opts.AppName == "" {
+		return fmt.Errorf("fly pipeline: app name is required")
+	}
+	if opts.ConfigPath == "" {
+		return fmt.Errorf("fly pipeline: config path is required")
+	}
+	if opts.ImportPath == "" {
+		return fmt.Errorf("fly pipeline: import path is required")
+	}
+	if opts.Tag == "" {
+		opts.Tag = "latest"
+	}
+	return nil
+}
+
+func newClient(toolingCfg sharedcfg.ToolingSettings, token string, verbose bool) *client.Client {
+	client.SetBaseURL(toolingCfg.Active.FlyAPIBase)
+	return client.NewClientFromOptions(client.ClientOptions{
+		AccessToken: token,
+		Name:        "core-pipeline",
+		Version:     "dev",
+		Logger:      flyutil.NewLogger("tooling-pipeline", verbose),
+	})
+}
+
+// publishOptions resolves the ko config, repository and tags for opts.
+func publishOptions(profile sharedcfg.ToolingProfile, opts Options) (ko.PublishOptions, error) {
 	koConfigPath := strings.TrimSpace(opts.KoConfigPath)
 	if koConfigPath == "" {
 		candidate := strings.TrimSpace(profile.KoConfig)
@@ -127,9 +254,6 @@ func Run(ctx context.Context, opts Options) (Result, error) {
 	koOpts := ko.PublishOptions{
 		ConfigPath: koConfigPath,
 		Args:       []string{opts.ImportPath},
-		Env: map[string]string{
-			"DOCKER_CONFIG": dockerConfigDir,
-		},
 		WorkingDir: opts.CoreDir,
 		Bare:       true,
 	}
@@ -163,50 +287,10 @@ func Run(ctx context.Context, opts Options) (Result, error) {
 		koOpts.Tags = []string{"latest"}
 	}
 	if strings.TrimSpace(koOpts.Repo) == "" {
-		return result, fmt.Errorf("fly pipeline: unable to resolve KO repository (provide --repository or set %s)", sharedcfg.EnvVarToolingKORepository)
+		return ko.PublishOptions{}, fmt.Errorf("fly pipeline: unable to resolve KO repository (provide --repository or set %s)", sharedcfg.EnvVarToolingKORepository)
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
-	defer cancel()
-
-	refs, err := ko.Publish(ctx, koOpts)
-	if err != nil {
-		return result, fmt.Errorf("ko publish: %w", err)
-	}
-	imageRef := refs[len(refs)-1]
-	result.ImageReference = imageRef
-
-	if opts.PlatformVersion == "" {
-		opts.PlatformVersion = app.PlatformVersion
-	}
-	if opts.PlatformVersion == "" {
-		opts.PlatformVersion = "machines"
-	}
-
-	if alreadyCurrent(app, imageRef, koOpts.Tags[len(koOpts.Tags)-1]) {
-		result.Skipped = true
-		result.ReleaseSummary = "No changes detected; existing release already uses this image"
-		result.Elapsed = time.Since(start)
-		return result, nil
-	}
-
-	releaseSummary, err := flyutil.Deploy(ctx, flyutil.DeployOptions{
-		AccessToken:     token,
-		AppName:         opts.AppName,
-		ImageRef:        imageRef,
-		ConfigPath:      opts.ConfigPath,
-		PlatformVersion: opts.PlatformVersion,
-		Strategy:        opts.Strategy,
-		Verbose:         opts.Verbose,
-	})
-	if err != nil {
-		return result, err
-	}
-	result.ReleaseSummary = releaseSummary
-	result.ReleaseID = extractReleaseID(releaseSummary)
-	result.Elapsed = time.Since(start)
-	logSummary(ctx, result)
-	return result, nil
+	return koOpts, nil
 }
 
 func resolveToken(raw, tokenFile string) (string, error) {
@@ -221,18 +305,25 @@ func resolveToken(raw, tokenFile string) (string, error) {
 }
 
 func alreadyCurrent(app *client.App, imageRef, tag string) bool {
-	if app == nil || app.ImageDetails.Repository == "" {
+	current := currentImage(app)
+	if current == "" {
 		return false
 	}
-
-	current := fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(app.ImageDetails.Registry, "/"), strings.TrimPrefix(app.ImageDetails.Repository, "/"), app.ImageDetails.Tag)
-
 	if imageRef == current {
 		return true
 	}
 	return strings.HasSuffix(imageRef, ":"+tag) && current == imageRef
 }
 
+// currentImage is the registry/repository:tag of the app's release, or ""
+// when it has none.
+func currentImage(app *client.App) string {
+	if app == nil || app.ImageDetails.Repository == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(app.ImageDetails.Registry, "/"), strings.TrimPrefix(app.ImageDetails.Repository, "/"), app.ImageDetails.Tag)
+}
+
 func resolveOrganization(ctx context.Context, cl *client.Client, slug string) (*client.Organization, error) {
 	if strings.TrimSpace(slug) != "" {
 		org, err := cl.GetOrganizationBySlug(ctx, slug)
@@ -1,6 +1,13 @@
 package release
 
-import "testing"
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	types "github.com/joeblew999/infra/core/tooling/pkg/types"
+)
 
 func TestExtractReleaseID(t *testing.T) {
 	tests := []struct {
@@ -17,3 +24,27 @@ func TestExtractReleaseID(t *testing.T) {
 		}
 	}
 }
+
+func TestPlanWithoutTokenNotesUnknownRelease(t *testing.T) {
+	actions, err := Plan(context.Background(), Options{
+		AppName:    "my-app",
+		ConfigPath: "fly.toml",
+		ImportPath: "./cmd/core",
+		TokenFile:  filepath.Join(t.TempDir(), "missing-token"),
+		Tags:       []string{"v1"},
+		Repository: "registry.fly.io/my-app",
+	})
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	if len(actions) != 2 {
+		t.Fatalf("expected build and release actions, got %#v", actions)
+	}
+	build, release := actions[0], actions[1]
+	if build.Action != types.PlanBuild || build.Desired != "registry.fly.io/my-app:v1" {
+		t.Fatalf("unexpected build action: %#v", build)
+	}
+	if release.Desired != "registry.fly.io/my-app:v1" || !strings.Contains(release.Note, "current release unknown") {
+		t.Fatalf("unexpected release action: %#v", release)
+	}
+}
@@ -46,22 +46,27 @@ func (m *Manager) LaunchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	flusher, ok := w.(http.Flusher)
-	if !ok {
-		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
-		return
-	}
-
 	var req LaunchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	opts := orchestrator.DeployOptions{
+	m.Stream(w, r, orchestrator.DeployOptions{
 		ProfileOverride: req.Profile,
 		DeployRequest:   req.Request,
 		Timeout:         req.Timeout,
+	})
+}
+
+// Stream launches a deploy and streams its init, progress, prompt, result
+// and error events to w until the deploy finishes or the client goes away.
+// Callers decode and validate the request themselves.
+func (m *Manager) Stream(w http.ResponseWriter, r *http.Request, opts orchestrator.DeployOptions) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
 	}
 
 	adapter, resultCh, errCh := m.svc.Launch(r.Context(), opts)
@@ -179,7 +184,7 @@ func (h PromptResponseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 // StatusHandler emits the status snapshot as JSON.
 func (m *Manager) StatusHandler(w http.ResponseWriter, r *http.Request) {
 	profile := r.URL.Query().Get("profile")
-	status, err := m.svc.Status(r.Context(), profiles.ContextOptions{ProfileOverride: profile})
+	status, err := m.svc.Snapshot(r.Context(), profiles.ContextOptions{ProfileOverride: profile})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("status error: %v", err), http.StatusInternalServerError)
 		return
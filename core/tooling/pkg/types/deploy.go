@@ -13,9 +13,12 @@ type DeployRequest struct {
 	Repo      string
 	Verbose   bool
 	NoBrowser bool
-	Stdin     io.Reader
-	Stdout    io.Writer
-	Stderr    io.Writer
+	// DryRun plans the deployment without authenticating, building or
+	// changing anything.
+	DryRun bool
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 // DeployResult captures the outcome of a release.
@@ -0,0 +1,40 @@
+package types
+
+import "fmt"
+
+// PlanAction is what a deploy would do to a resource.
+type PlanAction string
+
+const (
+	PlanCreate   PlanAction = "create"
+	PlanUpdate   PlanAction = "update"
+	PlanBuild    PlanAction = "build"
+	PlanWrite    PlanAction = "write"
+	PlanNoChange PlanAction = "none"
+)
+
+// PlannedAction is one change a dry-run deploy would make. Current and
+// Desired hold the before/after values when the provider could look them
+// up; Note explains anything the plan could not determine.
+type PlannedAction struct {
+	Provider string     `json:"provider"`
+	Action   PlanAction `json:"action"`
+	Target   string     `json:"target"`
+	Current  string     `json:"current,omitempty"`
+	Desired  string     `json:"desired,omitempty"`
+	Note     string     `json:"note,omitempty"`
+}
+
+func (a PlannedAction) String() string {
+	s := fmt.Sprintf("[%s] %s %s", a.Provider, a.Action, a.Target)
+	switch {
+	case a.Current != "" && a.Desired != "":
+		s += fmt.Sprintf(": %s → %s", a.Current, a.Desired)
+	case a.Desired != "":
+		s += ": " + a.Desired
+	}
+	if a.Note != "" {
+		s += " (" + a.Note + ")"
+	}
+	return s
+}
@@ -5,6 +5,9 @@ import "time"
 // ProgressMessage is the JSON-friendly representation of a deploy progress event.
 type ProgressMessage struct {
 	Phase   string            `json:"phase"`
+	Step    string            `json:"step,omitempty"`
+	State   string            `json:"state,omitempty"`
+	Percent int               `json:"percent"`
 	Message string            `json:"message"`
 	Details map[string]string `json:"details,omitempty"`
 	Time    time.Time         `json:"time"`
@@ -3,13 +3,14 @@ package ai
 import (
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
-	"github.com/joeblew999/infra/pkg/dep"
 	"github.com/joeblew999/infra/pkg/log"
 )
 
@@ -241,17 +242,11 @@ func CopyDefaultMCPConfig() error {
 	return nil
 }
 
-// loadDefaultMCPConfig loads the default MCP configuration from our package
+// loadDefaultMCPConfig parses the embedded default MCP configuration
 func loadDefaultMCPConfig() (*ClaudeMCPConfig, error) {
-	configFile := "pkg/ai/claude-mcp-default.json"
-	data, err := os.ReadFile(configFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read default MCP config: %w", err)
-	}
-
 	var config ClaudeMCPConfig
-	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse default MCP config: %w", err)
+	if err := json.Unmarshal(defaultMCPConfig, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded config: %w", err)
 	}
 
 	return &config, nil
@@ -292,27 +287,8 @@ type ClaudeRunner struct {
 
 // NewClaudeRunner creates a new claude runner
 func NewClaudeRunner() *ClaudeRunner {
-	// Get the claude binary path from dep system
-	binaryPath, err := dep.Get("claude")
-	if err != nil {
-		log.Info("Claude binary not found, attempting to install", "error", err)
-		// Try to install claude automatically for idempotent behavior
-		if installErr := dep.InstallBinary("claude", false); installErr != nil {
-			log.Warn("Could not auto-install claude", "install_error", installErr)
-			// Fallback to system claude if available
-			binaryPath = "claude"
-		} else {
-			// Try to get the path again after installation
-			binaryPath, err = dep.Get("claude")
-			if err != nil {
-				log.Warn("Could not get claude path after installation", "error", err)
-				binaryPath = "claude"
-			}
-		}
-	}
-	
 	return &ClaudeRunner{
-		binaryPath: binaryPath,
+		binaryPath: resolveBinary("claude"),
 	}
 }
 
@@ -394,7 +370,34 @@ func (r *ClaudeRunner) MCPList() error {
 
 // MCPAdd adds an MCP server to Claude
 func (r *ClaudeRunner) MCPAdd(name, command string) error {
-	return r.RunInteractive("mcp", "add", name, command)
+	return r.MCPAddWithEnv(name, command, nil)
+}
+
+// MCPAddWithEnv adds an MCP server to Claude with KEY=VALUE environment
+// entries passed through `-e`
+func (r *ClaudeRunner) MCPAddWithEnv(name, command string, env []string) error {
+	args := []string{"mcp", "add"}
+	for _, kv := range env {
+		args = append(args, "-e", kv)
+	}
+	args = append(args, name, command)
+	return r.RunInteractive(args...)
+}
+
+// installMCPServer validates server, expands its environment and adds it
+func (r *ClaudeRunner) installMCPServer(server ClaudeMCPServer) error {
+	if err := server.Validate(); err != nil {
+		return err
+	}
+	env, err := server.ExpandEnv()
+	if err != nil {
+		return err
+	}
+	fullCommand := server.Command + " " + strings.Join(server.Args, " ")
+	if err := r.MCPAddWithEnv(server.Name, fullCommand, env); err != nil {
+		return fmt.Errorf("failed to install %s: %w", server.Name, err)
+	}
+	return nil
 }
 
 // MCPRemove removes an MCP server from Claude
@@ -402,33 +405,201 @@ func (r *ClaudeRunner) MCPRemove(name string) error {
 	return r.RunInteractive("mcp", "remove", name)
 }
 
-// InstallDefaultMCP installs the default MCP servers from config
-func (r *ClaudeRunner) InstallDefaultMCP() error {
-	// Use embedded default config
-	var config ClaudeMCPConfig
-	if err := json.Unmarshal(defaultMCPConfig, &config); err != nil {
-		return fmt.Errorf("failed to parse embedded config: %w", err)
+// MCPInstalled returns the names of the MCP servers Claude already has.
+func (r *ClaudeRunner) MCPInstalled() ([]string, error) {
+	output, err := r.RunWithOutput("mcp", "list")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP servers: %w", err)
 	}
+	return parseMCPList(string(output)), nil
+}
 
-	for _, server := range config.Servers {
-		fullCommand := server.Command + " " + strings.Join(server.Args, " ")
-		
-		if err := r.MCPAdd(server.Name, fullCommand); err != nil {
-			return fmt.Errorf("failed to install %s: %w", server.Name, err)
+// parseMCPList extracts server names from `claude mcp list` output, whose
+// entries look like "name: command args - ✓ Connected".
+func parseMCPList(output string) []string {
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		name, _, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			continue
 		}
-		fmt.Printf("✅ Installed %s: %s\n", server.Name, fullCommand)
+		names = append(names, name)
 	}
+	return names
+}
 
-	fmt.Println("🎉 Default MCP servers installed successfully!")
+// Validate checks that the server can be passed to `claude mcp add`.
+func (s ClaudeMCPServer) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("MCP server has no name")
+	}
+	if strings.TrimSpace(s.Command) == "" {
+		return fmt.Errorf("MCP server %s has no command", s.Name)
+	}
 	return nil
 }
 
+// ExpandEnv returns the server's environment as sorted KEY=VALUE entries,
+// expanding ${VAR} references from the process environment. Referencing a
+// variable that is not set is an error, since the server would start without
+// the credentials it needs.
+func (s ClaudeMCPServer) ExpandEnv() ([]string, error) {
+	keys := make([]string, 0, len(s.Env))
+	for key := range s.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		var missing []string
+		value := os.Expand(s.Env[key], func(name string) string {
+			v, ok := os.LookupEnv(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return v
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("MCP server %s: %s requires %s to be set", s.Name, key, strings.Join(missing, ", "))
+		}
+		env = append(env, key+"="+value)
+	}
+	return env, nil
+}
+
+// MCPSyncResult summarises what SyncMCP changed.
+type MCPSyncResult struct {
+	Added   []string
+	Removed []string
+	Skipped []string         // already installed
+	Failed  map[string]error // servers that could not be added or removed
+}
+
+// Err joins the per-server failures, or returns nil if there were none.
+func (r *MCPSyncResult) Err() error {
+	var errs []error
+	for _, name := range r.failedNames() {
+		errs = append(errs, r.Failed[name])
+	}
+	return errors.Join(errs...)
+}
+
+func (r *MCPSyncResult) failedNames() []string {
+	names := make([]string, 0, len(r.Failed))
+	for name := range r.Failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *MCPSyncResult) fail(name string, err error) {
+	if r.Failed == nil {
+		r.Failed = make(map[string]error)
+	}
+	r.Failed[name] = err
+}
+
+// SyncMCP reconciles Claude's MCP servers with the embedded default config:
+// missing servers are added and, if prune is set, servers not in the config
+// are removed. A server that fails, e.g. because its environment variables
+// are unset, is recorded in Failed and the rest are still synced; the error
+// is only for problems that stop the sync as a whole.
+func (r *ClaudeRunner) SyncMCP(prune bool) (*MCPSyncResult, error) {
+	config, err := loadDefaultMCPConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(config.Servers))
+	for _, server := range config.Servers {
+		if err := server.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid embedded config: %w", err)
+		}
+		if wanted[server.Name] {
+			return nil, fmt.Errorf("invalid embedded config: duplicate MCP server %s", server.Name)
+		}
+		wanted[server.Name] = true
+	}
+
+	names, err := r.MCPInstalled()
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]bool, len(names))
+	for _, name := range names {
+		installed[name] = true
+	}
+
+	result := &MCPSyncResult{}
+	for _, server := range config.Servers {
+		if installed[server.Name] {
+			result.Skipped = append(result.Skipped, server.Name)
+			continue
+		}
+		if err := r.installMCPServer(server); err != nil {
+			result.fail(server.Name, err)
+			continue
+		}
+		result.Added = append(result.Added, server.Name)
+	}
+
+	if prune {
+		for _, name := range names {
+			if wanted[name] {
+				continue
+			}
+			if err := r.MCPRemove(name); err != nil {
+				result.fail(name, fmt.Errorf("failed to remove %s: %w", name, err))
+				continue
+			}
+			result.Removed = append(result.Removed, name)
+		}
+	}
+
+	return result, nil
+}
+
+// InstallDefaultMCP installs the default MCP servers from config, skipping
+// any that are already installed. Servers that fail don't stop the others
+// from being installed; their errors are returned together at the end.
+func (r *ClaudeRunner) InstallDefaultMCP() error {
+	result, err := r.SyncMCP(false)
+	if err != nil {
+		return err
+	}
+	printMCPSyncResult(result)
+	return result.Err()
+}
+
+func printMCPSyncResult(result *MCPSyncResult) {
+	for _, name := range result.Added {
+		fmt.Printf("✅ Installed %s\n", name)
+	}
+	for _, name := range result.Skipped {
+		fmt.Printf("⏭️  %s already installed\n", name)
+	}
+	for _, name := range result.Removed {
+		fmt.Printf("🗑️  Removed %s\n", name)
+	}
+	for _, name := range result.failedNames() {
+		fmt.Printf("❌ %s: %v\n", name, result.Failed[name])
+	}
+	if len(result.Failed) > 0 {
+		fmt.Printf("⚠️  MCP servers partly synced (%d added, %d removed, %d unchanged, %d failed)\n",
+			len(result.Added), len(result.Removed), len(result.Skipped), len(result.Failed))
+		return
+	}
+	fmt.Printf("🎉 MCP servers in sync (%d added, %d removed, %d unchanged)\n",
+		len(result.Added), len(result.Removed), len(result.Skipped))
+}
+
 // PresetList lists all available preset MCP servers from the default config
 func (r *ClaudeRunner) PresetList() error {
-	// Use embedded default config
-	var config ClaudeMCPConfig
-	if err := json.Unmarshal(defaultMCPConfig, &config); err != nil {
-		return fmt.Errorf("failed to parse embedded config: %w", err)
+	config, err := loadDefaultMCPConfig()
+	if err != nil {
+		return err
 	}
 
 	fmt.Println("📋 Available Preset MCP Servers")
@@ -458,10 +629,9 @@ func (r *ClaudeRunner) PresetList() error {
 
 // InstallMCPByName installs a specific MCP server by name from the default config
 func (r *ClaudeRunner) InstallMCPByName(serverName string) error {
-	// Use embedded default config
-	var config ClaudeMCPConfig
-	if err := json.Unmarshal(defaultMCPConfig, &config); err != nil {
-		return fmt.Errorf("failed to parse embedded config: %w", err)
+	config, err := loadDefaultMCPConfig()
+	if err != nil {
+		return err
 	}
 
 	// Find the requested server
@@ -483,13 +653,11 @@ func (r *ClaudeRunner) InstallMCPByName(serverName string) error {
 	}
 
 	// Install the specific server
-	fullCommand := targetServer.Command + " " + strings.Join(targetServer.Args, " ")
-	
-	if err := r.MCPAdd(targetServer.Name, fullCommand); err != nil {
-		return fmt.Errorf("failed to install %s: %w", targetServer.Name, err)
+	if err := r.installMCPServer(*targetServer); err != nil {
+		return err
 	}
-	
-	fmt.Printf("✅ Installed %s: %s\n", targetServer.Name, fullCommand)
+
+	fmt.Printf("✅ Installed %s: %s %s\n", targetServer.Name, targetServer.Command, strings.Join(targetServer.Args, " "))
 	return nil
 }
 
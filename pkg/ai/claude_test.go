@@ -1,6 +1,11 @@
 package ai
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -34,3 +39,128 @@ func TestInstallDefaultMCP(t *testing.T) {
 		t.Skipf("InstallDefaultMCP failed: %v", err)
 	}
 }
+
+func TestParseMCPList(t *testing.T) {
+	output := `Checking MCP server health...
+
+github: npx -y @modelcontextprotocol/server-github - ✓ Connected
+fetch: npx -y @modelcontextprotocol/server-fetch - ✗ Failed to connect
+`
+	got := parseMCPList(output)
+	if len(got) != 2 || got[0] != "github" || got[1] != "fetch" {
+		t.Errorf("parseMCPList = %v, want [github fetch]", got)
+	}
+
+	if got := parseMCPList("No MCP servers configured. Use `claude mcp add` to add a server."); len(got) != 0 {
+		t.Errorf("expected no servers, got %v", got)
+	}
+}
+
+func TestClaudeMCPServerValidate(t *testing.T) {
+	if err := (ClaudeMCPServer{Name: "fetch", Command: "npx"}).Validate(); err != nil {
+		t.Errorf("valid server rejected: %v", err)
+	}
+	if err := (ClaudeMCPServer{Command: "npx"}).Validate(); err == nil {
+		t.Error("expected error for missing name")
+	}
+	if err := (ClaudeMCPServer{Name: "fetch"}).Validate(); err == nil {
+		t.Error("expected error for missing command")
+	}
+}
+
+func TestCopyDefaultMCPConfigOutsideRepo(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	if err := CopyDefaultMCPConfig(); err != nil {
+		t.Fatalf("CopyDefaultMCPConfig: %v", err)
+	}
+
+	config, err := LoadMCPConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := loadDefaultMCPConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Servers) == 0 || len(config.Servers) != len(want.Servers) {
+		t.Errorf("copied %d servers, want %d", len(config.Servers), len(want.Servers))
+	}
+}
+
+func TestClaudeMCPServerExpandEnv(t *testing.T) {
+	t.Setenv("TEST_MCP_TOKEN", "secret")
+	server := ClaudeMCPServer{
+		Name: "github",
+		Env: map[string]string{
+			"GITHUB_PERSONAL_ACCESS_TOKEN": "${TEST_MCP_TOKEN}",
+			"MODE":                         "readonly",
+		},
+	}
+
+	env, err := server.ExpandEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(env) != 2 || env[0] != "GITHUB_PERSONAL_ACCESS_TOKEN=secret" || env[1] != "MODE=readonly" {
+		t.Errorf("ExpandEnv = %v", env)
+	}
+
+	server.Env["API_KEY"] = "${TEST_MCP_UNSET_VAR}"
+	if _, err := server.ExpandEnv(); err == nil || !strings.Contains(err.Error(), "TEST_MCP_UNSET_VAR") {
+		t.Errorf("expected error naming the unset variable, got %v", err)
+	}
+}
+
+func TestNewRunnerUnknownTool(t *testing.T) {
+	if _, err := NewRunner("copilot"); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+}
+
+// fakeClaude writes a claude stand-in that lists no servers and logs every
+// `mcp add` to the returned file.
+func fakeClaude(t *testing.T) (*ClaudeRunner, string) {
+	t.Helper()
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "added")
+	script := "#!/bin/sh\nif [ \"$1 $2\" = \"mcp add\" ]; then echo \"$*\" >> " + logPath + "; fi\n"
+	bin := filepath.Join(dir, "claude")
+	if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return &ClaudeRunner{binaryPath: bin}, logPath
+}
+
+func TestSyncMCPContinuesPastMissingEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake claude is a shell script")
+	}
+	t.Setenv("GITHUB_TOKEN", "")
+	os.Unsetenv("GITHUB_TOKEN")
+
+	runner, logPath := fakeClaude(t)
+	result, err := runner.SyncMCP(false)
+	if err != nil {
+		t.Fatalf("SyncMCP: %v", err)
+	}
+
+	if want := []string{"filesystem", "fetch", "playwright"}; !reflect.DeepEqual(result.Added, want) {
+		t.Errorf("Added = %v, want %v", result.Added, want)
+	}
+	if err := result.Failed["github"]; err == nil || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Errorf("Failed[github] = %v, want an error naming GITHUB_TOKEN", err)
+	}
+	if err := result.Err(); err == nil {
+		t.Error("Err() = nil, want the github failure")
+	}
+
+	added, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(added), "mcp add"); n != 3 {
+		t.Errorf("claude mcp add ran %d times, want 3:\n%s", n, added)
+	}
+}
@@ -96,6 +96,7 @@ func newClaudeMCPCmd() *cobra.Command {
 		newClaudeMCPPresetListCmd(),
 		newClaudeMCPPresetInstallCmd(),
 		newClaudeMCPPresetInstallAllCmd(),
+		newClaudeMCPPresetSyncCmd(),
 	)
 
 	return mcpCmd
@@ -192,6 +193,27 @@ func newClaudeMCPPresetInstallAllCmd() *cobra.Command {
 	}
 }
 
+func newClaudeMCPPresetSyncCmd() *cobra.Command {
+	var prune bool
+	cmd := &cobra.Command{
+		Use:   "preset-sync",
+		Short: "Sync MCP servers with the presets",
+		Long:  "Install preset MCP servers that are missing and, with --prune, remove servers that are not presets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runner := ai.NewClaudeRunner()
+			result, err := runner.SyncMCP(prune)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Added: %d, removed: %d, unchanged: %d, failed: %d\n",
+				len(result.Added), len(result.Removed), len(result.Skipped), len(result.Failed))
+			return result.Err()
+		},
+	}
+	cmd.Flags().BoolVar(&prune, "prune", false, "Remove installed servers that are not presets")
+	return cmd
+}
+
 func runMCPListClaude() error {
 	runner := ai.NewClaudeRunner()
 	return runner.MCPList()
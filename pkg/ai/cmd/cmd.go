@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/joeblew999/infra/pkg/ai"
 	"github.com/joeblew999/infra/pkg/dep"
@@ -25,11 +26,39 @@ func NewAICmd() *cobra.Command {
 	aiCmd.AddCommand(
 		NewGooseCmd(),
 		NewClaudeCmd(),
+		newSessionCmd(),
 	)
 
 	return aiCmd
 }
 
+func newSessionCmd() *cobra.Command {
+	var tool string
+	cmd := &cobra.Command{
+		Use:   "session [session-name]",
+		Short: "Start an interactive session with any AI backend",
+		Long: fmt.Sprintf(`Start an interactive session with the chosen AI CLI backend (%s)
+
+Examples:
+  go run . ai session
+  go run . ai session --tool goose my-session`, strings.Join(ai.Runners, ", ")),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			runner, err := ai.NewRunner(tool)
+			if err != nil {
+				return err
+			}
+			sessionName := ""
+			if len(args) > 0 {
+				sessionName = args[0]
+			}
+			return runner.Session(sessionName)
+		},
+	}
+	cmd.Flags().StringVar(&tool, "tool", "claude", "AI backend to use")
+	return cmd
+}
+
 func configureAITools() error {
 	fmt.Println("🔧 Configuring AI tools...")
 
@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// CrushRunner executes crush commands with proper binary path resolution
+type CrushRunner struct {
+	binaryPath string
+}
+
+// NewCrushRunner creates a new crush runner
+func NewCrushRunner() *CrushRunner {
+	return &CrushRunner{
+		binaryPath: resolveBinary("crush"),
+	}
+}
+
+// Run executes a crush command with the given arguments
+func (r *CrushRunner) Run(args ...string) error {
+	cmd := exec.Command(r.binaryPath, args...)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("crush command failed: %w", err)
+	}
+	return nil
+}
+
+// RunWithOutput executes a crush command and returns the output
+func (r *CrushRunner) RunWithOutput(args ...string) ([]byte, error) {
+	cmd := exec.Command(r.binaryPath, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return output, fmt.Errorf("crush command failed: %w", err)
+	}
+	return output, nil
+}
+
+// RunInteractive executes a crush command with interactive input/output
+func (r *CrushRunner) RunInteractive(args ...string) error {
+	cmd := exec.Command(r.binaryPath, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("crush interactive command failed: %w", err)
+	}
+	return nil
+}
+
+// Session starts the Crush TUI. Crush keeps sessions per project and has no
+// flag to pick one by name, so sessionName is only logged.
+func (r *CrushRunner) Session(sessionName string) error {
+	log.Info("Starting Crush session", "session", sessionName)
+	return r.RunInteractive()
+}
+
+// Prompt runs a single non-interactive prompt
+func (r *CrushRunner) Prompt(prompt string) ([]byte, error) {
+	return r.RunWithOutput("run", prompt)
+}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"os/exec"
 
-	"github.com/joeblew999/infra/pkg/dep"
 	"github.com/joeblew999/infra/pkg/log"
 )
 
@@ -16,27 +15,8 @@ type GooseRunner struct {
 
 // NewGooseRunner creates a new goose runner
 func NewGooseRunner() *GooseRunner {
-	// Get the goose binary path from dep system
-	binaryPath, err := dep.Get("goose")
-	if err != nil {
-		log.Info("Goose binary not found, attempting to install", "error", err)
-		// Try to install goose automatically for idempotent behavior
-		if installErr := dep.InstallBinary("goose", false); installErr != nil {
-			log.Warn("Could not auto-install goose", "install_error", installErr)
-			// Fallback to system goose if available
-			binaryPath = "goose"
-		} else {
-			// Try to get the path again after installation
-			binaryPath, err = dep.Get("goose")
-			if err != nil {
-				log.Warn("Could not get goose path after installation", "error", err)
-				binaryPath = "goose"
-			}
-		}
-	}
-	
 	return &GooseRunner{
-		binaryPath: binaryPath,
+		binaryPath: resolveBinary("goose"),
 	}
 }
 
@@ -0,0 +1,63 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joeblew999/infra/pkg/dep"
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// AIRunner is the common surface of the AI CLI backends. Tool-specific
+// features, such as Claude's MCP management, stay on the concrete runners.
+type AIRunner interface {
+	Run(args ...string) error
+	RunWithOutput(args ...string) ([]byte, error)
+	RunInteractive(args ...string) error
+	Session(sessionName string) error
+}
+
+var (
+	_ AIRunner = (*ClaudeRunner)(nil)
+	_ AIRunner = (*GooseRunner)(nil)
+	_ AIRunner = (*CrushRunner)(nil)
+)
+
+// Runners lists the tools accepted by NewRunner.
+var Runners = []string{"claude", "goose", "crush"}
+
+// NewRunner creates the runner for the named AI CLI tool
+func NewRunner(tool string) (AIRunner, error) {
+	switch tool {
+	case "claude":
+		return NewClaudeRunner(), nil
+	case "goose":
+		return NewGooseRunner(), nil
+	case "crush":
+		return NewCrushRunner(), nil
+	default:
+		return nil, fmt.Errorf("unknown AI tool %q (available: %s)", tool, strings.Join(Runners, ", "))
+	}
+}
+
+// resolveBinary finds a tool through the dep system, installing it if it is
+// missing, and falls back to the bare name so a copy on PATH still works.
+func resolveBinary(name string) string {
+	binaryPath, err := dep.Get(name)
+	if err == nil {
+		return binaryPath
+	}
+
+	log.Info("Binary not found, attempting to install", "binary", name, "error", err)
+	if installErr := dep.InstallBinary(name, false); installErr != nil {
+		log.Warn("Could not auto-install binary", "binary", name, "install_error", installErr)
+		return name
+	}
+
+	binaryPath, err = dep.Get(name)
+	if err != nil {
+		log.Warn("Could not get binary path after installation", "binary", name, "error", err)
+		return name
+	}
+	return binaryPath
+}
@@ -1,11 +1,22 @@
 package auth
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+
 	"github.com/joeblew999/infra/pkg/config"
 )
 
@@ -105,7 +116,8 @@ func TestInMemoryUserStore(t *testing.T) {
 
 func TestInMemorySessionStore(t *testing.T) {
 	// Test session storage with test isolation
-	store := NewInMemorySessionStore()
+	store := NewInMemorySessionStore(SessionStoreConfig{})
+	defer store.Close()
 
 	// Test user session creation
 	sessionID := "test-session-123"
@@ -178,7 +190,8 @@ func TestAuthServiceConfiguration(t *testing.T) {
 	}
 
 	users := NewInMemoryUserStore()
-	sessions := NewInMemorySessionStore()
+	sessions := NewInMemorySessionStore(SessionStoreConfig{})
+	defer sessions.Close()
 	webDir := "test-web-dir"
 
 	// Create auth service
@@ -227,3 +240,427 @@ func TestAuthServiceConfiguration(t *testing.T) {
 
 	t.Logf("✅ Auth config saved: %s", configFile)
 }
+
+func TestInMemorySessionStoreExpiry(t *testing.T) {
+	now := time.Unix(1000, 0)
+	for _, sliding := range []bool{false, true} {
+		store := NewInMemorySessionStore(SessionStoreConfig{TTL: 10 * time.Minute, Sliding: sliding})
+		store.now = func() time.Time { return now }
+
+		// Requested TTLs above the store TTL are capped.
+		if err := store.CreateUserSession("s1", "alice", time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(8 * time.Minute)
+		if _, err := store.GetUserSession("s1"); err != nil {
+			t.Fatalf("sliding=%v: session should be alive after 8m: %v", sliding, err)
+		}
+		now = now.Add(8 * time.Minute)
+		_, err := store.GetUserSession("s1")
+		if sliding && err != nil {
+			t.Fatalf("sliding session should have been extended by the previous hit: %v", err)
+		}
+		if !sliding && !errors.Is(err, ErrSessionNotFound) {
+			t.Fatalf("fixed session should have expired, got err=%v", err)
+		}
+		store.Close()
+	}
+}
+
+func TestInMemorySessionStoreJanitor(t *testing.T) {
+	store := NewInMemorySessionStore(SessionStoreConfig{TTL: 20 * time.Millisecond})
+	defer store.Close()
+	if err := store.CreateUserSession("s1", "alice", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		store.mu.Lock()
+		n := len(store.userSessions)
+		store.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("janitor did not evict the expired session")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNATSSessionStoreTTL(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+
+	store, err := NewNATSSessionStore(nc, SessionStoreConfig{TTL: time.Minute, Sliding: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// Re-opening with another TTL updates the existing bucket.
+	store2, err := NewNATSSessionStore(nc, SessionStoreConfig{TTL: 5 * time.Minute})
+	if err != nil {
+		t.Fatalf("reopen with new TTL: %v", err)
+	}
+	defer store2.Close()
+	status, err := store2.kv.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.TTL() != 5*time.Minute {
+		t.Fatalf("bucket TTL = %s, want 5m", status.TTL())
+	}
+
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	if err := store.CreateUserSession("s1", "alice", 0); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(50 * time.Second)
+	if user, err := store.GetUserSession("s1"); err != nil || user != "alice" {
+		t.Fatalf("GetUserSession = %q, %v", user, err)
+	}
+	now = now.Add(50 * time.Second)
+	if _, err := store.GetUserSession("s1"); err != nil {
+		t.Fatalf("sliding session should still be valid: %v", err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := store.GetUserSession("s1"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("expected expired session, got %v", err)
+	}
+
+	// Entries from before sessions carried an expiry hold a bare user ID.
+	if _, err := store.kv.Put("legacy", []byte("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetUserSession("legacy"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("legacy session: got %v, want ErrSessionNotFound", err)
+	}
+	if _, err := store.kv.Get("legacy"); !errors.Is(err, nats.ErrKeyNotFound) {
+		t.Errorf("legacy session should be deleted, got %v", err)
+	}
+}
+
+func TestCredentialMetadata(t *testing.T) {
+	store := NewInMemoryUserStore()
+	if _, err := store.GetOrCreateUser("alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	if err := store.AddCredential("alice", &webauthn.Credential{ID: []byte("key-1")}, "Work laptop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddCredential("alice", &webauthn.Credential{ID: []byte("key-2")}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	user, _ := store.GetUser("alice")
+	if got := user.Credentials[0].Name; got != "Work laptop" {
+		t.Errorf("name = %q, want %q", got, "Work laptop")
+	}
+	if got := user.Credentials[1].Name; got != "Passkey 2" {
+		t.Errorf("default name = %q, want %q", got, "Passkey 2")
+	}
+	if user.Credentials[0].CreatedAt.Before(before) || !user.Credentials[0].LastUsedAt.IsZero() {
+		t.Errorf("unexpected timestamps: %+v", user.Credentials[0])
+	}
+	if len(user.WebAuthnCredentials()) != 2 {
+		t.Fatalf("WebAuthnCredentials() = %d credentials, want 2", len(user.WebAuthnCredentials()))
+	}
+
+	used := &webauthn.Credential{ID: []byte("key-2"), Authenticator: webauthn.Authenticator{SignCount: 7}}
+	if err := store.RecordCredentialUse("alice", used); err != nil {
+		t.Fatal(err)
+	}
+	if c := user.Credentials[1]; c.LastUsedAt.IsZero() || c.Authenticator.SignCount != 7 {
+		t.Errorf("credential use not recorded: %+v", c)
+	}
+	if err := store.RecordCredentialUse("alice", &webauthn.Credential{ID: []byte("unknown")}); err == nil {
+		t.Error("expected error for unknown credential")
+	}
+
+	if err := store.RenameCredentialByIndex("alice", 1, "Phone"); err != nil {
+		t.Fatal(err)
+	}
+	if got := user.Credentials[1].Name; got != "Phone" {
+		t.Errorf("renamed = %q, want Phone", got)
+	}
+	if err := store.RenameCredentialByIndex("alice", 5, "x"); err == nil {
+		t.Error("expected out of range error")
+	}
+	if err := store.RenameCredentialByIndex("alice", 0, ""); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
+
+func TestDiscoverableLoginLookup(t *testing.T) {
+	users := NewInMemoryUserStore()
+	alice, _ := users.GetOrCreateUser("alice")
+	if err := users.AddCredential("alice", &webauthn.Credential{ID: []byte("alice-key")}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.AddCredential("alice", &webauthn.Credential{ID: []byte("alice-key-2")}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := users.GetUserByCredentialID([]byte("alice-key"))
+	if err != nil || got != alice {
+		t.Fatalf("GetUserByCredentialID = %v, %v", got, err)
+	}
+
+	if err := users.RemoveCredentialByIndex("alice", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.GetUserByCredentialID([]byte("alice-key")); err == nil {
+		t.Error("removed credential should no longer resolve")
+	}
+	if err := users.RemoveCredential("alice", []byte("alice-key-2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := users.GetUserByCredentialID([]byte("alice-key-2")); err == nil {
+		t.Error("removed credential should no longer resolve")
+	}
+
+	sessions := NewInMemorySessionStore(SessionStoreConfig{})
+	defer sessions.Close()
+	svc, err := NewWebAuthnService(WebAuthnConfig{RPID: "localhost", RPDisplayName: "Test", RPOrigins: []string{"https://localhost"}}, users, sessions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options, token, err := svc.BeginDiscoverableLogin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(options.Response.AllowedCredentials); n != 0 {
+		t.Errorf("discoverable login should not list credentials, got %d", n)
+	}
+	session, err := sessions.GetWebAuthnSession(token)
+	if err != nil {
+		t.Fatalf("session not stored: %v", err)
+	}
+	if len(session.UserID) != 0 {
+		t.Error("discoverable session must not be bound to a user")
+	}
+
+	// Registration prefers a discoverable credential so security keys that
+	// can't store one still work; the usernameless flow requires it.
+	for _, usePlatform := range []bool{false, true} {
+		creation, _, err := svc.BeginRegistrationWithPlatform("bob", usePlatform)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sel := creation.Response.AuthenticatorSelection
+		if sel.ResidentKey != protocol.ResidentKeyRequirementPreferred {
+			t.Errorf("platform=%v: residentKey = %q, want preferred", usePlatform, sel.ResidentKey)
+		}
+		if sel.RequireResidentKey != nil && *sel.RequireResidentKey {
+			t.Errorf("platform=%v: requireResidentKey should be false", usePlatform)
+		}
+
+		creation, _, err = svc.BeginDiscoverableRegistration("bob", usePlatform)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sel = creation.Response.AuthenticatorSelection
+		if sel.ResidentKey != protocol.ResidentKeyRequirementRequired {
+			t.Errorf("platform=%v: discoverable residentKey = %q, want required", usePlatform, sel.ResidentKey)
+		}
+		if sel.RequireResidentKey == nil || !*sel.RequireResidentKey {
+			t.Errorf("platform=%v: discoverable requireResidentKey should be true", usePlatform)
+		}
+	}
+}
+
+func TestLogoutInvalidatesSession(t *testing.T) {
+	sessions := NewInMemorySessionStore(SessionStoreConfig{})
+	defer sessions.Close()
+	svc, err := NewAuthService(WebAuthnConfig{RPID: "localhost", RPDisplayName: "Test", RPOrigins: []string{"https://localhost"}},
+		NewInMemoryUserStore(), sessions, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := svc.NewAuthRouter()
+
+	for _, datastarRequest := range []bool{true, false} {
+		if err := svc.CreateUserSession("sess-1", "alice", 0); err != nil {
+			t.Fatal(err)
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/logout", strings.NewReader("{}"))
+		req.AddCookie(&http.Cookie{Name: "session", Value: "sess-1"})
+		if datastarRequest {
+			req.Header.Set("Datastar-Request", "true")
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if _, err := svc.GetUserSession("sess-1"); err == nil {
+			t.Errorf("datastar=%v: session should be deleted", datastarRequest)
+		}
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].MaxAge >= 0 {
+			t.Errorf("datastar=%v: session cookie not expired: %+v", datastarRequest, cookies)
+		}
+		body := rec.Body.String()
+		if datastarRequest && !strings.Contains(body, `"signedIn":false`) {
+			t.Errorf("expected $auth signed-out patch, got %q", body)
+		}
+		if !datastarRequest && !strings.Contains(body, `"logged out"`) {
+			t.Errorf("expected JSON response, got %q", body)
+		}
+	}
+}
+
+func TestDatastarHandlersFS(t *testing.T) {
+	assets := fstest.MapFS{
+		"auth.css":                        {Data: []byte("body{}")},
+		"auth.js":                         {Data: []byte("console.log(1)")},
+		"fragments/logged-in-header.html": {Data: []byte(`<span>{{.UserID}}</span>`)},
+	}
+	h := NewDatastarHandlersFS(nil, assets)
+
+	if h.templates.Lookup("logged-in-header.html") == nil {
+		t.Fatal("fragment templates should be parsed from the embedded FS")
+	}
+
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+	for path, want := range map[string]string{"/auth.css": "body{}", "/auth.js": "console.log(1)"} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("GET %s = %d %q, want %q", path, rec.Code, rec.Body.String(), want)
+		}
+	}
+
+	// Without assets the static routes are not mounted at all.
+	bare := chi.NewRouter()
+	NewDatastarHandlers(nil, "").RegisterRoutes(bare)
+	rec := httptest.NewRecorder()
+	bare.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth.css", nil))
+	if rec.Code == http.StatusOK {
+		t.Error("auth.css should not be served without web assets")
+	}
+}
+
+func TestCredentialManagementUsesSession(t *testing.T) {
+	users := NewInMemoryUserStore()
+	for _, name := range []string{"alice", "bob"} {
+		if _, err := users.GetOrCreateUser(name); err != nil {
+			t.Fatal(err)
+		}
+		if err := users.AddCredential(name, &webauthn.Credential{ID: []byte(name + "-key")}, name+"'s key"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sessions := NewInMemorySessionStore(SessionStoreConfig{})
+	defer sessions.Close()
+	svc, err := NewAuthService(WebAuthnConfig{RPID: "localhost", RPDisplayName: "Test", RPOrigins: []string{"https://localhost"}},
+		users, sessions, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserSession("alice-session", "alice", 0); err != nil {
+		t.Fatal(err)
+	}
+	router := svc.NewAuthRouter()
+
+	send := func(method, path, body string, cookie bool) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Datastar-Request", "true")
+		if cookie {
+			req.AddCookie(&http.Cookie{Name: "session", Value: "alice-session"})
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Without a session nothing changes, whatever username is claimed.
+	if code := send(http.MethodPatch, "/credentials/0", `{"username":"bob","credentialName":"pwned"}`, false); code != http.StatusUnauthorized {
+		t.Errorf("rename without session: status %d, want 401", code)
+	}
+	if code := send(http.MethodDelete, "/credentials/0", `{"username":"bob"}`, false); code != http.StatusUnauthorized {
+		t.Errorf("delete without session: status %d, want 401", code)
+	}
+
+	// With alice's session, a client-supplied "bob" is ignored.
+	send(http.MethodPatch, "/credentials/0", `{"username":"bob","credentialName":"Laptop"}`, true)
+	if alice, _ := users.GetUser("alice"); alice.Credentials[0].Name != "Laptop" {
+		t.Errorf("alice's passkey should be renamed, got %q", alice.Credentials[0].Name)
+	}
+	send(http.MethodDelete, "/credentials/0", `{"username":"bob"}`, true)
+
+	bob, _ := users.GetUser("bob")
+	if len(bob.Credentials) != 1 || bob.Credentials[0].Name != "bob's key" {
+		t.Errorf("bob's passkeys changed through alice's session: %+v", bob.Credentials)
+	}
+	alice, _ := users.GetUser("alice")
+	if len(alice.Credentials) != 0 {
+		t.Errorf("alice's passkey should be deleted, got %+v", alice.Credentials)
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	sessions := NewInMemorySessionStore(SessionStoreConfig{})
+	defer sessions.Close()
+	svc, err := NewAuthService(WebAuthnConfig{RPID: "localhost", RPDisplayName: "Test", RPOrigins: []string{"https://localhost"}},
+		NewInMemoryUserStore(), sessions, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.CreateUserSession("alice-session", "alice", 0); err != nil {
+		t.Fatal(err)
+	}
+	guarded := svc.RequireSession(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for name, tc := range map[string]struct {
+		cookie string
+		want   int
+	}{
+		"no cookie":       {"", http.StatusUnauthorized},
+		"unknown session": {"forged", http.StatusUnauthorized},
+		"valid session":   {"alice-session", http.StatusNoContent},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/deploy", nil)
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "session", Value: tc.cookie})
+		}
+		rec := httptest.NewRecorder()
+		guarded.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", name, rec.Code, tc.want)
+		}
+	}
+}
+
+func TestAuthServiceSessionConfigFromStore(t *testing.T) {
+	sessions := NewInMemorySessionStore(SessionStoreConfig{TTL: 2 * time.Hour, Sliding: true})
+	defer sessions.Close()
+	svc, err := NewAuthService(WebAuthnConfig{RPID: "localhost", RPDisplayName: "Test", RPOrigins: []string{"https://localhost"}},
+		NewInMemoryUserStore(), sessions, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc.webauthn.sessionTTL != 2*time.Hour || !svc.sessionConfig.Sliding {
+		t.Errorf("session config = ttl %s, sliding %v; want the store's 2h sliding", svc.webauthn.sessionTTL, svc.sessionConfig.Sliding)
+	}
+}
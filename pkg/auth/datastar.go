@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/joeblew999/infra/pkg/log"
@@ -27,7 +30,10 @@ func init() {
 
 // Store holds Datastar signals for auth flows
 type Store struct {
-	Username string `json:"username"`
+	Username       string `json:"username"`
+	CredentialName string `json:"credentialName"`
+	// Discoverable registers a passkey that must support usernameless login.
+	Discoverable bool `json:"discoverable"`
 }
 
 // PageData holds the template data for rendering pages
@@ -40,27 +46,37 @@ type PageData struct {
 type DatastarHandlers struct {
 	authService *WebAuthnService
 	templates   *template.Template
-	webDir      string
+	// assets holds index.html, auth.css, auth.js and fragments/*.html. It is
+	// nil when the handlers run without a web UI.
+	assets fs.FS
 }
 
-// NewDatastarHandlers creates new Datastar handlers
+// NewDatastarHandlers creates new Datastar handlers that load their web
+// assets from webDir on disk. An empty webDir serves the SSE routes only.
 func NewDatastarHandlers(authService *WebAuthnService, webDir string) *DatastarHandlers {
-	// Load HTML templates from pkg/auth/web/fragments
+	var assets fs.FS
+	if webDir != "" {
+		assets = os.DirFS(webDir)
+	}
+	return NewDatastarHandlersFS(authService, assets)
+}
+
+// NewDatastarHandlersFS creates Datastar handlers that serve and parse their
+// web assets from assets, typically an embed.FS, so single-binary and
+// container deployments do not depend on the working directory. Files are
+// expected at the root of assets; use fs.Sub for an embed.FS rooted higher up.
+func NewDatastarHandlersFS(authService *WebAuthnService, assets fs.FS) *DatastarHandlers {
 	templates := template.New("")
 	templates.Funcs(template.FuncMap{
 		"add": func(a, b int) int { return a + b },
 	})
 
-	// Load fragment templates if webDir is provided
-	if webDir != "" {
-		globPattern := webDir + "/fragments/*.html"
-		log.Debug("Loading auth fragment templates", "pattern", globPattern)
-
-		// Use ParseGlob but don't panic if no files found (for container deployments)
-		if _, err := templates.ParseGlob(globPattern); err != nil {
-			log.Debug("No fragment templates found, continuing without auth fragments", "pattern", globPattern, "error", err)
+	if assets != nil {
+		const pattern = "fragments/*.html"
+		// Missing fragments are not fatal: the SSE routes fall back to plain HTML.
+		if _, err := templates.ParseFS(assets, pattern); err != nil {
+			log.Debug("No fragment templates found, continuing without auth fragments", "pattern", pattern, "error", err)
 		} else {
-			// Log loaded templates in debug mode
 			templateNames := make([]string, 0, len(templates.Templates()))
 			for _, tmpl := range templates.Templates() {
 				templateNames = append(templateNames, tmpl.Name())
@@ -72,7 +88,7 @@ func NewDatastarHandlers(authService *WebAuthnService, webDir string) *DatastarH
 	return &DatastarHandlers{
 		authService: authService,
 		templates:   templates,
-		webDir:      webDir,
+		assets:      assets,
 	}
 }
 
@@ -82,17 +98,21 @@ func (h *DatastarHandlers) RegisterRoutes(r chi.Router) {
 	r.Post("/register/finish", h.RegisterFinish)
 	r.Post("/login/start", h.LoginStart)
 	r.Post("/login/finish", h.LoginFinish)
+	r.Post("/login/discoverable/start", h.DiscoverableLoginStart)
+	r.Post("/login/discoverable/finish", h.DiscoverableLoginFinish)
 
 	// Credential management routes
 	r.Get("/credentials", h.ShowCredentials)
 	r.Get("/credentials/list", h.ListCredentials)
+	r.Patch("/credentials/{index}", h.RenameCredential)
 	r.Delete("/credentials/{index}", h.DeleteCredential)
 
 	// Session status routes
 	r.Get("/session/status", h.CheckSessionStatus)
+	r.Post("/logout", h.Logout)
 
-	// Serve static files if webDir is configured
-	if h.webDir != "" {
+	// Serve static files if web assets are configured
+	if h.assets != nil {
 		r.Get("/", h.ServeIndex)
 		r.Get("/auth.css", h.ServeCSS)
 		r.Get("/auth.js", h.ServeJS)
@@ -120,9 +140,7 @@ func (h *DatastarHandlers) renderTemplate(w http.ResponseWriter, currentPath, te
 		footerHTML = ""
 	}
 
-	// Load template from filesystem
-	templatePath := h.webDir + "/" + templateName + ".html"
-	tmpl, err := template.ParseFiles(templatePath)
+	tmpl, err := template.ParseFS(h.assets, templateName+".html")
 	if err != nil {
 		log.Error("Error loading template", "template", templateName, "error", err)
 		http.Error(w, "Template not found", http.StatusInternalServerError)
@@ -145,13 +163,13 @@ func (h *DatastarHandlers) renderTemplate(w http.ResponseWriter, currentPath, te
 // ServeCSS serves the auth.css file
 func (h *DatastarHandlers) ServeCSS(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/css")
-	http.ServeFile(w, r, h.webDir+"/auth.css")
+	http.ServeFileFS(w, r, h.assets, "auth.css")
 }
 
 // ServeJS serves the auth.js file
 func (h *DatastarHandlers) ServeJS(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/javascript")
-	http.ServeFile(w, r, h.webDir+"/auth.js")
+	http.ServeFileFS(w, r, h.assets, "auth.js")
 }
 
 // RegisterStart handles /register/start with Datastar SSE
@@ -173,7 +191,11 @@ func (h *DatastarHandlers) RegisterStart(w http.ResponseWriter, r *http.Request)
 	userAgent := r.Header.Get("User-Agent")
 	usePlatform := strings.Contains(userAgent, "Safari") && !strings.Contains(userAgent, "Chrome")
 
-	options, token, err := h.authService.BeginRegistrationWithPlatform(store.Username, usePlatform)
+	begin := h.authService.BeginRegistrationWithPlatform
+	if store.Discoverable {
+		begin = h.authService.BeginDiscoverableRegistration
+	}
+	options, token, err := begin(store.Username, usePlatform)
 	if err != nil {
 		sse := datastar.NewSSE(w, r)
 		sse.PatchElements(fmt.Sprintf(`<div id="log">Registration error: %s</div>`, err.Error()))
@@ -188,24 +210,25 @@ func (h *DatastarHandlers) RegisterStart(w http.ResponseWriter, r *http.Request)
 
 	// Convert options to JSON and execute WebAuthn
 	optionsJSON, _ := json.Marshal(options)
+	nameJSON, _ := json.Marshal(store.CredentialName)
 	script := fmt.Sprintf(`
 		(async () => {
 			try {
 				const options = %s;
 				window.currentToken = '%s';
 				const response = await startRegistration(options);
-				// Send both token and WebAuthn response to finish endpoint
+				// Send token, passkey name and WebAuthn response to finish endpoint
 				await fetch('/register/finish', {
 					method: 'POST',
 					headers: {'Content-Type': 'application/json'},
-					body: JSON.stringify({token: '%s', response: response})
+					body: JSON.stringify({token: '%s', name: %s, response: response})
 				});
 				document.getElementById('log').textContent = 'Registration completed successfully!';
 			} catch (e) {
 				document.getElementById('log').textContent = 'Registration failed: ' + e.message;
 			}
 		})();
-	`, string(optionsJSON), token, token)
+	`, string(optionsJSON), token, token, string(nameJSON))
 
 	sse.ExecuteScript(script)
 }
@@ -266,6 +289,7 @@ func (h *DatastarHandlers) LoginStart(w http.ResponseWriter, r *http.Request) {
 func (h *DatastarHandlers) RegisterFinish(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Token    string `json:"token"`
+		Name     string `json:"name"`
 		Response any    `json:"response"`
 	}
 
@@ -276,7 +300,7 @@ func (h *DatastarHandlers) RegisterFinish(w http.ResponseWriter, r *http.Request
 	}
 
 	// Convert response to proper WebAuthn format and finish registration
-	user, err := h.authService.FinishRegistrationFromJSON(req.Token, req.Response)
+	user, err := h.authService.FinishRegistrationFromJSON(req.Token, req.Response, strings.TrimSpace(req.Name))
 	if err != nil {
 		sse := datastar.NewSSE(w, r)
 		sse.PatchElements(fmt.Sprintf(`<div id="log">Registration failed: %s</div>`, err.Error()))
@@ -330,6 +354,72 @@ func (h *DatastarHandlers) LoginFinish(w http.ResponseWriter, r *http.Request) {
 	`, user.WebAuthnName(), sessionID, string(seed)))
 }
 
+// DiscoverableLoginStart handles /login/discoverable/start: a passkey login
+// without a username, where the authenticator offers the accounts it holds
+func (h *DatastarHandlers) DiscoverableLoginStart(w http.ResponseWriter, r *http.Request) {
+	options, token, err := h.authService.BeginDiscoverableLogin()
+	if err != nil {
+		sse := datastar.NewSSE(w, r)
+		sse.PatchElements(fmt.Sprintf(`<div id="log">Login error: %s</div>`, err.Error()))
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	sse.PatchElements(`<div id="log">Choose a passkey...</div>`)
+
+	optionsJSON, _ := json.Marshal(options)
+	script := fmt.Sprintf(`
+		(async () => {
+			try {
+				const options = %s;
+				const response = await startAuthentication(options);
+				await fetch('/login/discoverable/finish', {
+					method: 'POST',
+					headers: {'Content-Type': 'application/json'},
+					body: JSON.stringify({token: '%s', response: response})
+				});
+				document.getElementById('log').textContent = 'Login completed successfully!';
+			} catch (e) {
+				document.getElementById('log').textContent = 'Login failed: ' + e.message;
+			}
+		})();
+	`, string(optionsJSON), token)
+
+	sse.ExecuteScript(script)
+}
+
+// DiscoverableLoginFinish handles /login/discoverable/finish (called from WebAuthn JS)
+func (h *DatastarHandlers) DiscoverableLoginFinish(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token    string `json:"token"`
+		Response any    `json:"response"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		sse := datastar.NewSSE(w, r)
+		sse.PatchElements(`<div id="log">Invalid request</div>`)
+		return
+	}
+
+	user, sessionID, err := h.authService.FinishDiscoverableLoginFromJSON(req.Token, req.Response)
+	if err != nil {
+		sse := datastar.NewSSE(w, r)
+		sse.PatchElements(fmt.Sprintf(`<div id="log">Login failed: %s</div>`, err.Error()))
+		return
+	}
+
+	kp, _ := nkeys.CreateUser()
+	seed, _ := kp.Seed()
+
+	sse := datastar.NewSSE(w, r)
+	sse.PatchElements(fmt.Sprintf(`
+		<div id="log">Login successful for %s!<br>
+		Session: %s<br>
+		NATS Seed: %s<br>
+		<button data-on-click="@get('/dashboard')">Go to Dashboard</button></div>
+	`, user.WebAuthnName(), sessionID, string(seed)))
+}
+
 // ShowCredentials displays the credentials management section
 func (h *DatastarHandlers) ShowCredentials(w http.ResponseWriter, r *http.Request) {
 	store := &Store{}
@@ -379,15 +469,18 @@ func (h *DatastarHandlers) ListCredentials(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	user, err := h.authService.GetUserCredentials(store.Username)
+	h.patchCredentialsList(datastar.NewSSE(w, r), store.Username)
+}
+
+// patchCredentialsList renders the credentials list fragment for username.
+// Handlers that already consumed the request signals call it directly.
+func (h *DatastarHandlers) patchCredentialsList(sse *datastar.ServerSentEventGenerator, username string) {
+	user, err := h.authService.GetUserCredentials(username)
 	if err != nil {
-		sse := datastar.NewSSE(w, r)
 		sse.PatchElements(`<div style="text-align: center; padding: 1rem; color: #dc3545;">No credentials found for this user</div>`)
 		return
 	}
 
-	sse := datastar.NewSSE(w, r)
-
 	if len(user.Credentials) == 0 {
 		sse.PatchElements(`
 			<div id="credentials-list">
@@ -405,49 +498,119 @@ func (h *DatastarHandlers) ListCredentials(w http.ResponseWriter, r *http.Reques
 		credentialsHTML += fmt.Sprintf(`
 			<div class="credential-item">
 				<div class="credential-info">
-					<strong>Passkey %d</strong><br>
+					<strong>%s</strong><br>
 					<div class="credential-id">ID: %.20s...</div>
-					<div class="credential-meta">Uses: %d</div>
+					<div class="credential-meta">Added: %s · Last used: %s · Uses: %d</div>
 				</div>
+				<button data-on-click="$credentialName = prompt('Rename passkey', %s) || ''; $credentialName && @patch('/credentials/%d')"
+						class="btn" style="font-size: 0.75rem; padding: 0.25rem 0.5rem;">
+					Rename
+				</button>
 				<button data-on-click="@delete('/credentials/%d')"
-						data-signals-username="'%s'"
 						class="btn btn-danger" style="font-size: 0.75rem; padding: 0.25rem 0.5rem;">
 					Delete
 				</button>
 			</div>
-		`, i+1, string(cred.ID), cred.Authenticator.SignCount, i, store.Username)
+		`, template.HTMLEscapeString(cred.Name), string(cred.ID),
+			formatCredentialTime(cred.CreatedAt), formatCredentialTime(cred.LastUsedAt), cred.Authenticator.SignCount,
+			template.HTMLEscapeString(jsString(cred.Name)), i, i)
 	}
 	credentialsHTML += `</div>`
 
 	sse.PatchElements(credentialsHTML)
 }
 
-// DeleteCredential removes a credential by index
-func (h *DatastarHandlers) DeleteCredential(w http.ResponseWriter, r *http.Request) {
+// formatCredentialTime renders a credential timestamp, "never" when unset
+func formatCredentialTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// jsString quotes s as a JavaScript string literal
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// sessionUser returns the user logged in with the request's session cookie.
+// Credential management acts on this user only, never on a username the
+// client sends, so one user can't touch another's passkeys.
+func (h *DatastarHandlers) sessionUser(r *http.Request) (string, bool) {
+	sessionCookie, err := r.Cookie("session")
+	if err != nil {
+		return "", false
+	}
+	username, err := h.authService.GetUserSession(sessionCookie.Value)
+	if err != nil || username == "" {
+		return "", false
+	}
+	return username, true
+}
+
+// RenameCredential sets the friendly name of one of the logged-in user's
+// credentials by index
+func (h *DatastarHandlers) RenameCredential(w http.ResponseWriter, r *http.Request) {
+	username, ok := h.sessionUser(r)
+	if !ok {
+		http.Error(w, "Please log in first", http.StatusUnauthorized)
+		return
+	}
+
 	store := &Store{}
 	if err := datastar.ReadSignals(r, store); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	indexStr := chi.URLParam(r, "index")
-	index, err := strconv.Atoi(indexStr)
+	index, err := strconv.Atoi(chi.URLParam(r, "index"))
 	if err != nil {
 		sse := datastar.NewSSE(w, r)
 		sse.PatchElements(`<div id="log">Invalid credential index</div>`)
 		return
 	}
 
-	if store.Username == "" {
+	name := strings.TrimSpace(store.CredentialName)
+	if name == "" {
 		sse := datastar.NewSSE(w, r)
-		sse.PatchElements(`<div id="log">Error: Username required</div>`)
+		sse.PatchElements(`<div id="log">Error: Passkey name required</div>`)
 		return
 	}
 
-	err = h.authService.DeleteUserCredential(store.Username, index)
+	if err := h.authService.RenameUserCredential(username, index, name); err != nil {
+		sse := datastar.NewSSE(w, r)
+		sse.PatchElements(fmt.Sprintf(`<div id="log">Failed to rename passkey: %s</div>`, template.HTMLEscapeString(err.Error())))
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	sse.PatchElements(`<div id="log">Passkey renamed.</div>`)
+
+	// Refresh the credentials list
+	h.patchCredentialsList(sse, username)
+}
+
+// DeleteCredential removes one of the logged-in user's credentials by index
+func (h *DatastarHandlers) DeleteCredential(w http.ResponseWriter, r *http.Request) {
+	username, ok := h.sessionUser(r)
+	if !ok {
+		http.Error(w, "Please log in first", http.StatusUnauthorized)
+		return
+	}
+
+	indexStr := chi.URLParam(r, "index")
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		sse := datastar.NewSSE(w, r)
+		sse.PatchElements(`<div id="log">Invalid credential index</div>`)
+		return
+	}
+
+	err = h.authService.DeleteUserCredential(username, index)
 	if err != nil {
 		sse := datastar.NewSSE(w, r)
-		sse.PatchElements(fmt.Sprintf(`<div id="log">Failed to delete passkey: %s</div>`, err.Error()))
+		sse.PatchElements(fmt.Sprintf(`<div id="log">Failed to delete passkey: %s</div>`, template.HTMLEscapeString(err.Error())))
 		return
 	}
 
@@ -455,7 +618,7 @@ func (h *DatastarHandlers) DeleteCredential(w http.ResponseWriter, r *http.Reque
 	sse.PatchElements(`<div id="log">Passkey deleted successfully!</div>`)
 
 	// Refresh the credentials list
-	h.ListCredentials(w, r)
+	h.patchCredentialsList(sse, username)
 }
 
 // CheckSessionStatus checks if user is logged in and shows appropriate UI
@@ -492,3 +655,37 @@ func (h *DatastarHandlers) CheckSessionStatus(w http.ResponseWriter, r *http.Req
 	// Hide the auth section
 	sse.ExecuteScript(`document.getElementById('auth-section').style.display = 'none';`)
 }
+
+// Logout handles /logout: it deletes the session from the store, expires the
+// session cookie and, for Datastar requests, patches $auth to signed out.
+// Other clients get the legacy JSON response.
+func (h *DatastarHandlers) Logout(w http.ResponseWriter, r *http.Request) {
+	if sessionCookie, err := r.Cookie("session"); err == nil {
+		if err := h.authService.DeleteUserSession(sessionCookie.Value); err != nil {
+			log.Warn("Failed to delete session on logout", "error", err)
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     "session",
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if r.Header.Get("Datastar-Request") != "true" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "logged out"})
+		return
+	}
+
+	sse := datastar.NewSSE(w, r)
+	sse.MarshalAndPatchSignals(map[string]any{"auth": map[string]any{"signedIn": false}})
+	sse.PatchElements(`<div id="session-status"></div>`)
+	sse.PatchElements(`<div id="user-actions"></div>`)
+	sse.PatchElements(`<div id="log">Logged out.</div>`)
+	sse.ExecuteScript(`const el = document.getElementById('auth-section'); if (el) el.style.display = '';`)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -35,9 +36,21 @@ var (
 )
 
 func init() {
-	// Initialize user and session stores
-	userStore := auth.NewInMemoryUserStore()
+	// Initialize user and session stores. Passkeys persist in PocketBase when
+	// it is running; otherwise they are lost on restart.
+	var userStore auth.UserStore
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	pbUserStore, err := auth.NewPocketBaseUserStore(ctx, auth.PocketBaseUserStoreConfig{})
+	cancel()
+	if err != nil {
+		log.Printf("PocketBase not available, using in-memory users: %v", err)
+		userStore = auth.NewInMemoryUserStore()
+	} else {
+		userStore = pbUserStore
+	}
+
 	var sessionStore auth.SessionStore
+	sessionConfig := auth.SessionStoreConfig{TTL: 30 * time.Minute, Sliding: true}
 
 	// Initialize NATS (optional - fallback to in-memory if not available)
 	natsURL := os.Getenv("NATS_URL")
@@ -45,16 +58,15 @@ func init() {
 		natsURL = config.GetNATSURL()
 	}
 
-	var err error
 	nc, err = nats.Connect(natsURL)
 	if err != nil {
 		log.Printf("NATS not available, using in-memory sessions: %v", err)
-		sessionStore = auth.NewInMemorySessionStore()
+		sessionStore = auth.NewInMemorySessionStore(sessionConfig)
 	} else {
-		natsSessionStore, err := auth.NewNATSSessionStore(nc)
+		natsSessionStore, err := auth.NewNATSSessionStore(nc, sessionConfig)
 		if err != nil {
 			log.Printf("NATS KV not available, using in-memory sessions: %v", err)
-			sessionStore = auth.NewInMemorySessionStore()
+			sessionStore = auth.NewInMemorySessionStore(sessionConfig)
 		} else {
 			sessionStore = natsSessionStore
 		}
@@ -143,7 +155,9 @@ func main() {
 	fmt.Println("")
 
 	// Start Caddy with config generation and background startup
-	caddy.StartWithConfig(&config)
+	if _, err := caddy.StartWithConfig(&config, caddy.WaitUntilReady(10*time.Second)); err != nil {
+		log.Fatalf("Caddy failed to start: %v", err)
+	}
 
 	// Wait for interrupt signal
 	c := make(chan os.Signal, 1)
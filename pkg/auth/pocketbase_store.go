@@ -0,0 +1,388 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+
+	"github.com/joeblew999/infra/pkg/config"
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// DefaultPocketBaseUserCollection is the collection holding passkey users.
+const DefaultPocketBaseUserCollection = "webauthn_users"
+
+// PocketBaseUserStoreConfig configures NewPocketBaseUserStore.
+type PocketBaseUserStoreConfig struct {
+	// URL of the PocketBase server (default: local PocketBase port).
+	URL string
+	// AdminEmail and AdminPassword authenticate as a PocketBase superuser
+	// (default: CORE_POCKETBASE_ADMIN_EMAIL / CORE_POCKETBASE_ADMIN_PASSWORD,
+	// matching the core pocketbase service bootstrap).
+	AdminEmail    string
+	AdminPassword string
+	// Collection name (default: DefaultPocketBaseUserCollection).
+	Collection string
+	// HTTPClient used for API calls (default: 10s timeout).
+	HTTPClient *http.Client
+}
+
+func (c PocketBaseUserStoreConfig) withDefaults() PocketBaseUserStoreConfig {
+	if c.URL == "" {
+		c.URL = config.FormatLocalHTTP(config.GetPocketBasePort())
+	}
+	c.URL = strings.TrimRight(c.URL, "/")
+	if c.AdminEmail == "" {
+		c.AdminEmail = envOrDefault("CORE_POCKETBASE_ADMIN_EMAIL", "admin@localhost")
+	}
+	if c.AdminPassword == "" {
+		c.AdminPassword = envOrDefault("CORE_POCKETBASE_ADMIN_PASSWORD", "changeme123")
+	}
+	if c.Collection == "" {
+		c.Collection = DefaultPocketBaseUserCollection
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return c
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// PocketBaseUserStore implements UserStore on a PocketBase collection through
+// the PocketBase REST API, so passkeys survive restarts. Records are only
+// accessible to superusers.
+type PocketBaseUserStore struct {
+	cfg PocketBaseUserStoreConfig
+
+	mu    sync.Mutex
+	token string
+}
+
+// pbUserRecord is the collection record layout.
+type pbUserRecord struct {
+	ID          string       `json:"id,omitempty"`
+	Username    string       `json:"username"`
+	UserID      string       `json:"user_id"`
+	DisplayName string       `json:"display_name"`
+	Credentials []Credential `json:"credentials"`
+}
+
+// NewPocketBaseUserStore connects to PocketBase as a superuser and creates the
+// user collection on first use.
+func NewPocketBaseUserStore(ctx context.Context, cfg PocketBaseUserStoreConfig) (*PocketBaseUserStore, error) {
+	s := &PocketBaseUserStore{cfg: cfg.withDefaults()}
+	if err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.migrate(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate creates the user collection if it does not exist yet.
+func (s *PocketBaseUserStore) migrate(ctx context.Context) error {
+	err := s.do(ctx, http.MethodGet, "/api/collections/"+url.PathEscape(s.cfg.Collection), nil, nil)
+	if err == nil {
+		return nil
+	}
+	if !isPBNotFound(err) {
+		return fmt.Errorf("check collection %s: %w", s.cfg.Collection, err)
+	}
+
+	name := s.cfg.Collection
+	schema := map[string]any{
+		"name": name,
+		"type": "base",
+		"fields": []map[string]any{
+			{"name": "username", "type": "text", "required": true},
+			{"name": "user_id", "type": "text", "required": true},
+			{"name": "display_name", "type": "text"},
+			{"name": "credentials", "type": "json", "maxSize": 1 << 20},
+			{"name": "created", "type": "autodate", "onCreate": true},
+			{"name": "updated", "type": "autodate", "onCreate": true, "onUpdate": true},
+		},
+		"indexes": []string{
+			fmt.Sprintf("CREATE UNIQUE INDEX idx_%s_username ON %s (username)", name, name),
+			fmt.Sprintf("CREATE UNIQUE INDEX idx_%s_user_id ON %s (user_id)", name, name),
+		},
+	}
+	if err := s.do(ctx, http.MethodPost, "/api/collections", schema, nil); err != nil {
+		return fmt.Errorf("create collection %s: %w", name, err)
+	}
+	log.Info("Created PocketBase passkey user collection", "collection", name)
+	return nil
+}
+
+// GetUser retrieves a user by username
+func (s *PocketBaseUserStore) GetUser(username string) (*User, error) {
+	rec, err := s.findOne(context.Background(), "username = "+pbQuote(username))
+	if err != nil {
+		return nil, err
+	}
+	return rec.user(), nil
+}
+
+// GetUserByID retrieves a user by WebAuthn user ID
+func (s *PocketBaseUserStore) GetUserByID(userID string) (*User, error) {
+	rec, err := s.findOne(context.Background(), "user_id = "+pbQuote(userID))
+	if err != nil {
+		return nil, err
+	}
+	return rec.user(), nil
+}
+
+// GetUserByCredentialID retrieves the user owning a credential
+func (s *PocketBaseUserStore) GetUserByCredentialID(credentialID []byte) (*User, error) {
+	// Credential IDs are stored base64 encoded in the JSON field; narrow with a
+	// substring match, then confirm the exact ID.
+	encoded := base64.StdEncoding.EncodeToString(credentialID)
+	recs, err := s.list(context.Background(), "credentials ~ "+pbQuote(encoded))
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range recs {
+		for _, cred := range rec.Credentials {
+			if bytes.Equal(cred.ID, credentialID) {
+				return rec.user(), nil
+			}
+		}
+	}
+	return nil, errors.New("credential not found")
+}
+
+// GetOrCreateUser retrieves an existing user or creates a new one
+func (s *PocketBaseUserStore) GetOrCreateUser(username string) (*User, error) {
+	if user, err := s.GetUser(username); err == nil {
+		return user, nil
+	}
+
+	rec := pbUserRecord{
+		Username:    username,
+		UserID:      uuid.New().String(),
+		DisplayName: username,
+		Credentials: []Credential{},
+	}
+	var created pbUserRecord
+	if err := s.do(context.Background(), http.MethodPost, s.recordsPath(""), rec, &created); err != nil {
+		return nil, fmt.Errorf("create user %s: %w", username, err)
+	}
+	return created.user(), nil
+}
+
+// AddCredential adds a named credential to a user
+func (s *PocketBaseUserStore) AddCredential(username string, credential *webauthn.Credential, name string) error {
+	return s.updateUser(username, func(user *User) error {
+		user.AddCredential(credential, name, time.Now())
+		return nil
+	})
+}
+
+// RecordCredentialUse updates a credential after a successful login
+func (s *PocketBaseUserStore) RecordCredentialUse(username string, credential *webauthn.Credential) error {
+	return s.updateUser(username, func(user *User) error {
+		if !user.RecordCredentialUse(credential, time.Now()) {
+			return errors.New("credential not found")
+		}
+		return nil
+	})
+}
+
+// RenameCredentialByIndex renames a credential by its index
+func (s *PocketBaseUserStore) RenameCredentialByIndex(username string, index int, name string) error {
+	return s.updateUser(username, func(user *User) error {
+		return user.RenameCredentialByIndex(index, name)
+	})
+}
+
+// RemoveCredential removes a credential by its ID
+func (s *PocketBaseUserStore) RemoveCredential(username string, credentialID []byte) error {
+	return s.updateUser(username, func(user *User) error {
+		if !user.RemoveCredential(credentialID) {
+			return errors.New("credential not found")
+		}
+		return nil
+	})
+}
+
+// RemoveCredentialByIndex removes a credential by its index
+func (s *PocketBaseUserStore) RemoveCredentialByIndex(username string, index int) error {
+	return s.updateUser(username, func(user *User) error {
+		return user.RemoveCredentialByIndex(index)
+	})
+}
+
+// updateUser loads a user, applies fn and writes the credentials back.
+func (s *PocketBaseUserStore) updateUser(username string, fn func(*User) error) error {
+	ctx := context.Background()
+	rec, err := s.findOne(ctx, "username = "+pbQuote(username))
+	if err != nil {
+		return err
+	}
+	user := rec.user()
+	if err := fn(user); err != nil {
+		return err
+	}
+	patch := map[string]any{"credentials": user.Credentials}
+	if err := s.do(ctx, http.MethodPatch, s.recordsPath(rec.ID), patch, nil); err != nil {
+		return fmt.Errorf("update user %s: %w", username, err)
+	}
+	return nil
+}
+
+func (s *PocketBaseUserStore) findOne(ctx context.Context, filter string) (*pbUserRecord, error) {
+	recs, err := s.list(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, errors.New("user not found")
+	}
+	return &recs[0], nil
+}
+
+func (s *PocketBaseUserStore) list(ctx context.Context, filter string) ([]pbUserRecord, error) {
+	q := url.Values{}
+	q.Set("filter", filter)
+	q.Set("perPage", "50")
+	q.Set("skipTotal", "true")
+	var page struct {
+		Items []pbUserRecord `json:"items"`
+	}
+	if err := s.do(ctx, http.MethodGet, s.recordsPath("")+"?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+func (s *PocketBaseUserStore) recordsPath(id string) string {
+	p := "/api/collections/" + url.PathEscape(s.cfg.Collection) + "/records"
+	if id != "" {
+		p += "/" + url.PathEscape(id)
+	}
+	return p
+}
+
+// authenticate obtains a superuser token.
+func (s *PocketBaseUserStore) authenticate(ctx context.Context) error {
+	body := map[string]string{"identity": s.cfg.AdminEmail, "password": s.cfg.AdminPassword}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := s.send(ctx, http.MethodPost, "/api/collections/_superusers/auth-with-password", "", body, &resp); err != nil {
+		return fmt.Errorf("pocketbase superuser auth: %w", err)
+	}
+	s.mu.Lock()
+	s.token = resp.Token
+	s.mu.Unlock()
+	return nil
+}
+
+// do sends an authenticated request, re-authenticating once when the
+// superuser token has expired.
+func (s *PocketBaseUserStore) do(ctx context.Context, method, path string, in, out any) error {
+	s.mu.Lock()
+	token := s.token
+	s.mu.Unlock()
+
+	err := s.send(ctx, method, path, token, in, out)
+	var apiErr *pbError
+	if errors.As(err, &apiErr) && apiErr.Status == http.StatusUnauthorized {
+		if err := s.authenticate(ctx); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		token = s.token
+		s.mu.Unlock()
+		err = s.send(ctx, method, path, token, in, out)
+	}
+	return err
+}
+
+func (s *PocketBaseUserStore) send(ctx context.Context, method, path, token string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.cfg.URL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	resp, err := s.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &pbError{Status: resp.StatusCode, Body: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// pbError is a non-2xx PocketBase API response.
+type pbError struct {
+	Status int
+	Body   string
+}
+
+func (e *pbError) Error() string {
+	return fmt.Sprintf("pocketbase: HTTP %d: %s", e.Status, e.Body)
+}
+
+func isPBNotFound(err error) bool {
+	var apiErr *pbError
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound
+}
+
+// pbQuote quotes a value for a PocketBase filter expression. The filter
+// scanner only understands \" inside double quotes, so that is all we escape.
+func pbQuote(v string) string {
+	return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+}
+
+func (r *pbUserRecord) user() *User {
+	creds := r.Credentials
+	if creds == nil {
+		creds = []Credential{}
+	}
+	return &User{
+		ID:          []byte(r.UserID),
+		Name:        r.Username,
+		DisplayName: r.DisplayName,
+		Credentials: creds,
+	}
+}
@@ -0,0 +1,126 @@
+// PocketBase v0.30's Collection.UnmarshalJSON recurses forever when
+// encoding/json is backed by json/v2, so this test needs the v1 implementation.
+
+//go:build !goexperiment.jsonv2
+
+package auth
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	_ "github.com/pocketbase/pocketbase/migrations" // system collections
+)
+
+// newTestPocketBase starts a throwaway PocketBase with a superuser and returns
+// its URL.
+func newTestPocketBase(t *testing.T, email, password string) string {
+	t.Helper()
+	app := core.NewBaseApp(core.BaseAppConfig{DataDir: t.TempDir()})
+	if err := app.Bootstrap(); err != nil {
+		t.Fatalf("bootstrap pocketbase: %v", err)
+	}
+	t.Cleanup(func() { _ = app.ResetBootstrapState() })
+	if err := app.RunAllMigrations(); err != nil {
+		t.Fatalf("migrate pocketbase: %v", err)
+	}
+
+	superusers, err := app.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := core.NewRecord(superusers)
+	admin.SetEmail(email)
+	admin.SetPassword(password)
+	if err := app.Save(admin); err != nil {
+		t.Fatalf("create superuser: %v", err)
+	}
+
+	router, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux, err := router.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestPocketBaseUserStore(t *testing.T) {
+	cfg := PocketBaseUserStoreConfig{
+		URL:           newTestPocketBase(t, "admin@example.com", "test-password-123"),
+		AdminEmail:    "admin@example.com",
+		AdminPassword: "test-password-123",
+	}
+	ctx := context.Background()
+
+	store, err := NewPocketBaseUserStore(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewPocketBaseUserStore: %v", err)
+	}
+
+	user, err := store.GetOrCreateUser(`alice "the admin"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := store.GetOrCreateUser(`alice "the admin"`)
+	if err != nil || string(again.ID) != string(user.ID) {
+		t.Fatalf("GetOrCreateUser should return the existing user: %v, %v", again, err)
+	}
+	if _, err := store.GetUser("bob"); err == nil {
+		t.Error("expected error for unknown user")
+	}
+
+	cred := &webauthn.Credential{ID: []byte{0xfb, 0xff, 0x01}, PublicKey: []byte("pk"), Authenticator: webauthn.Authenticator{SignCount: 1}}
+	if err := store.AddCredential(user.Name, cred, "Laptop"); err != nil {
+		t.Fatal(err)
+	}
+	used := *cred
+	used.Authenticator.SignCount = 2
+	if err := store.RecordCredentialUse(user.Name, &used); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RenameCredentialByIndex(user.Name, 0, "Work laptop"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second store (e.g. after a restart) sees the same data; the
+	// collection already exists so the migration is a no-op.
+	reopened, err := NewPocketBaseUserStore(ctx, cfg)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	byID, err := reopened.GetUserByID(string(user.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(byID.Credentials) != 1 {
+		t.Fatalf("credentials = %d, want 1", len(byID.Credentials))
+	}
+	got := byID.Credentials[0]
+	if got.Name != "Work laptop" || got.Authenticator.SignCount != 2 || got.CreatedAt.IsZero() || got.LastUsedAt.IsZero() {
+		t.Errorf("credential not persisted: %+v", got)
+	}
+	if string(got.PublicKey) != "pk" {
+		t.Errorf("public key = %q, want pk", got.PublicKey)
+	}
+
+	owner, err := reopened.GetUserByCredentialID(cred.ID)
+	if err != nil || owner.Name != user.Name {
+		t.Fatalf("GetUserByCredentialID = %v, %v", owner, err)
+	}
+
+	if err := reopened.RemoveCredential(user.Name, cred.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reopened.GetUserByCredentialID(cred.ID); err == nil {
+		t.Error("removed credential should no longer resolve")
+	}
+}
@@ -16,20 +16,25 @@ import (
 type AuthService struct {
 	webauthn        *WebAuthnService
 	datastarHandler *DatastarHandlers
+	sessionConfig   SessionStoreConfig
 }
 
-// NewAuthService creates a complete auth service with all handlers
+// NewAuthService creates a complete auth service with all handlers. The
+// lifetime of login sessions and their cookies comes from the session store.
 func NewAuthService(config WebAuthnConfig, users UserStore, sessions SessionStore, webDir string) (*AuthService, error) {
 	webauthnService, err := NewWebAuthnService(config, users, sessions)
 	if err != nil {
 		return nil, err
 	}
+	sessionConfig := sessions.SessionConfig().withDefaults()
+	webauthnService.sessionTTL = sessionConfig.TTL
 
 	datastarHandler := NewDatastarHandlers(webauthnService, webDir)
 
 	return &AuthService{
 		webauthn:        webauthnService,
 		datastarHandler: datastarHandler,
+		sessionConfig:   sessionConfig,
 	}, nil
 }
 
@@ -46,7 +51,6 @@ func (s *AuthService) RegisterRoutes(r chi.Router) {
 	
 	// Additional routes
 	r.Get("/dashboard", s.dashboard)
-	r.Post("/logout", s.logout)
 	r.Post("/login/conditional", s.conditionalLogin)
 	// SECURITY: Test user creation route removed for production safety
 }
@@ -73,6 +77,19 @@ func (s *AuthService) DeleteUserSession(sessionID string) error {
 	return s.webauthn.DeleteUserSession(sessionID)
 }
 
+// RequireSession is middleware that rejects requests without a valid login
+// session cookie with 401, so other HTTP APIs can sit behind the same
+// passkey login instead of rolling their own auth.
+func (s *AuthService) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.datastarHandler.sessionUser(r); !ok {
+			http.Error(w, "Please log in first", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // SECURITY: Removed CreateTestUser method - should only be used in tests
 
 // CreateUserSession wraps the webauthn service method
@@ -115,6 +132,7 @@ func (s *AuthService) beginRegister(w http.ResponseWriter, r *http.Request) {
 func (s *AuthService) finishRegister(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Token string                                `json:"token"`
+		Name  string                                `json:"name"`
 		Resp  protocol.ParsedCredentialCreationData `json:"response"`
 	}
 	if err := s.decode(r, &req); err != nil {
@@ -122,7 +140,7 @@ func (s *AuthService) finishRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := s.webauthn.FinishRegistration(req.Token, &req.Resp)
+	user, err := s.webauthn.FinishRegistration(req.Token, &req.Resp, req.Name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -176,15 +194,20 @@ func (s *AuthService) finishLogin(w http.ResponseWriter, r *http.Request) {
 	kp, _ := nkeys.CreateUser()
 	seed, _ := kp.Seed()
 
-	// Set session cookie
-	http.SetCookie(w, &http.Cookie{
+	// Set session cookie. Sliding sessions outlive any fixed max age, so the
+	// cookie then lasts for the browser session and the store decides expiry.
+	cookie := &http.Cookie{
 		Name:     "session",
 		Value:    sessionID,
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   true,
 		SameSite: http.SameSiteLaxMode,
-	})
+	}
+	if !s.sessionConfig.Sliding {
+		cookie.MaxAge = int(s.sessionConfig.TTL.Seconds())
+	}
+	http.SetCookie(w, cookie)
 
 	s.writeJSON(w, map[string]any{
 		"status":   "ok",
@@ -206,30 +229,22 @@ func (s *AuthService) dashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tpl := template.Must(template.ParseFiles(s.datastarHandler.webDir + "/dashboard.html"))
+	if s.datastarHandler.assets == nil {
+		http.Error(w, "Dashboard not available", http.StatusNotFound)
+		return
+	}
+	tpl, err := template.ParseFS(s.datastarHandler.assets, "dashboard.html")
+	if err != nil {
+		log.Error("Error loading template", "template", "dashboard", "error", err)
+		http.Error(w, "Template not found", http.StatusInternalServerError)
+		return
+	}
 	tpl.Execute(w, map[string]any{
 		"UserID":    userID,
 		"SessionID": sessionCookie.Value,
 	})
 }
 
-func (s *AuthService) logout(w http.ResponseWriter, r *http.Request) {
-	sessionCookie, err := r.Cookie("session")
-	if err == nil {
-		s.webauthn.DeleteUserSession(sessionCookie.Value)
-	}
-
-	http.SetCookie(w, &http.Cookie{
-		Name:     "session",
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-	})
-
-	s.writeJSON(w, map[string]string{"status": "logged out"})
-}
-
 func (s *AuthService) conditionalLogin(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		CredentialID string `json:"credentialId"`
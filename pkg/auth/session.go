@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -16,30 +19,127 @@ type SessionStore interface {
 	CreateUserSession(sessionID, userID string, ttl time.Duration) error
 	GetUserSession(sessionID string) (string, error)
 	DeleteUserSession(sessionID string) error
+	// SessionConfig returns the session lifetime settings the store was
+	// built with, defaults applied.
+	SessionConfig() SessionStoreConfig
+}
+
+// SessionStoreConfig controls how long user sessions live.
+type SessionStoreConfig struct {
+	// TTL is how long a session lives after creation, or after its last use
+	// when Sliding is set (default: 30m).
+	TTL time.Duration
+	// Sliding extends a session by TTL on every successful GetUserSession.
+	Sliding bool
+}
+
+// DefaultSessionTTL is used when SessionStoreConfig.TTL is zero.
+const DefaultSessionTTL = 30 * time.Minute
+
+func (c SessionStoreConfig) withDefaults() SessionStoreConfig {
+	if c.TTL <= 0 {
+		c.TTL = DefaultSessionTTL
+	}
+	return c
+}
+
+// sessionTTL picks the lifetime of a new session: the requested ttl, capped
+// at the store TTL (the NATS bucket evicts at that age regardless).
+func (c SessionStoreConfig) sessionTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.TTL {
+		return c.TTL
+	}
+	return ttl
+}
+
+// ErrSessionNotFound is returned for unknown or expired user sessions.
+var ErrSessionNotFound = errors.New("session not found")
+
+// userSession is a stored user session.
+type userSession struct {
+	UserID    string        `json:"user_id"`
+	TTL       time.Duration `json:"ttl"`
+	ExpiresAt time.Time     `json:"expires_at"`
 }
 
 // InMemorySessionStore implements SessionStore using in-memory storage
 type InMemorySessionStore struct {
+	cfg SessionStoreConfig
+	now func() time.Time
+
+	mu               sync.Mutex
 	webauthnSessions map[string]webauthn.SessionData
-	userSessions     map[string]string
+	userSessions     map[string]userSession
+
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
-// NewInMemorySessionStore creates a new in-memory session store
-func NewInMemorySessionStore() *InMemorySessionStore {
-	return &InMemorySessionStore{
+// NewInMemorySessionStore creates a new in-memory session store. A background
+// janitor evicts expired sessions until Close is called.
+func NewInMemorySessionStore(cfg SessionStoreConfig) *InMemorySessionStore {
+	s := &InMemorySessionStore{
+		cfg:              cfg.withDefaults(),
+		now:              time.Now,
 		webauthnSessions: make(map[string]webauthn.SessionData),
-		userSessions:     make(map[string]string),
+		userSessions:     make(map[string]userSession),
+		stop:             make(chan struct{}),
 	}
+	go s.janitor()
+	return s
+}
+
+// Close stops the janitor.
+func (s *InMemorySessionStore) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+func (s *InMemorySessionStore) janitor() {
+	interval := s.cfg.TTL / 2
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.evictExpired()
+		}
+	}
+}
+
+// evictExpired removes user sessions past their expiry.
+func (s *InMemorySessionStore) evictExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for id, sess := range s.userSessions {
+		if now.After(sess.ExpiresAt) {
+			delete(s.userSessions, id)
+		}
+	}
+}
+
+// SessionConfig returns the store's session lifetime settings.
+func (s *InMemorySessionStore) SessionConfig() SessionStoreConfig {
+	return s.cfg
 }
 
 // StoreWebAuthnSession stores a WebAuthn session
 func (s *InMemorySessionStore) StoreWebAuthnSession(token string, session webauthn.SessionData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.webauthnSessions[token] = session
 	return nil
 }
 
 // GetWebAuthnSession retrieves a WebAuthn session
 func (s *InMemorySessionStore) GetWebAuthnSession(token string) (*webauthn.SessionData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	session, exists := s.webauthnSessions[token]
 	if !exists {
 		return nil, errors.New("session not found")
@@ -49,59 +149,114 @@ func (s *InMemorySessionStore) GetWebAuthnSession(token string) (*webauthn.Sessi
 
 // DeleteWebAuthnSession deletes a WebAuthn session
 func (s *InMemorySessionStore) DeleteWebAuthnSession(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	delete(s.webauthnSessions, token)
 	return nil
 }
 
-// CreateUserSession creates a user session
+// CreateUserSession creates a user session that expires after ttl (capped at
+// the store TTL; zero means the store TTL)
 func (s *InMemorySessionStore) CreateUserSession(sessionID, userID string, ttl time.Duration) error {
-	s.userSessions[sessionID] = userID
+	ttl = s.cfg.sessionTTL(ttl)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.userSessions[sessionID] = userSession{UserID: userID, TTL: ttl, ExpiresAt: s.now().Add(ttl)}
 	return nil
 }
 
-// GetUserSession retrieves a user session
+// GetUserSession retrieves a user session, extending it when sliding
+// expiration is enabled
 func (s *InMemorySessionStore) GetUserSession(sessionID string) (string, error) {
-	userID, exists := s.userSessions[sessionID]
-	if !exists {
-		return "", errors.New("session not found")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, exists := s.userSessions[sessionID]
+	now := s.now()
+	if !exists || now.After(sess.ExpiresAt) {
+		delete(s.userSessions, sessionID)
+		return "", ErrSessionNotFound
+	}
+	if s.cfg.Sliding {
+		sess.ExpiresAt = now.Add(sess.TTL)
+		s.userSessions[sessionID] = sess
 	}
-	return userID, nil
+	return sess.UserID, nil
 }
 
 // DeleteUserSession deletes a user session
 func (s *InMemorySessionStore) DeleteUserSession(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	delete(s.userSessions, sessionID)
 	return nil
 }
 
+// sessionBucket is the NATS KV bucket holding user sessions.
+const sessionBucket = "auth_sessions"
+
 // NATSSessionStore implements SessionStore using NATS KV
 type NATSSessionStore struct {
+	cfg              SessionStoreConfig
+	now              func() time.Time
 	kv               nats.KeyValue
 	webauthnSessions *InMemorySessionStore // Fallback for WebAuthn sessions
 }
 
-// NewNATSSessionStore creates a new NATS-based session store
-func NewNATSSessionStore(nc *nats.Conn) (*NATSSessionStore, error) {
+// NewNATSSessionStore creates a new NATS-based session store. The bucket TTL
+// is set to cfg.TTL, so idle sessions are evicted by the server.
+func NewNATSSessionStore(nc *nats.Conn, cfg SessionStoreConfig) (*NATSSessionStore, error) {
+	cfg = cfg.withDefaults()
 	js, err := nc.JetStream()
 	if err != nil {
 		return nil, err
 	}
 
-	kv, err := js.CreateKeyValue(&nats.KeyValueConfig{
-		Bucket:   "auth_sessions",
+	kvConfig := &nats.KeyValueConfig{
+		Bucket:   sessionBucket,
 		Replicas: 1,
-		TTL:      30 * time.Minute,
-	})
+		TTL:      cfg.TTL,
+	}
+	kv, err := js.CreateKeyValue(kvConfig)
+	if errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		// Bucket exists with a different TTL: bring it in line.
+		kv, err = updateSessionBucketTTL(js, cfg.TTL)
+	}
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("session bucket: %w", err)
 	}
 
 	return &NATSSessionStore{
+		cfg:              cfg,
+		now:              time.Now,
 		kv:               kv,
-		webauthnSessions: NewInMemorySessionStore(),
+		webauthnSessions: NewInMemorySessionStore(cfg),
 	}, nil
 }
 
+// updateSessionBucketTTL changes the max age of an existing session bucket.
+func updateSessionBucketTTL(js nats.JetStreamContext, ttl time.Duration) (nats.KeyValue, error) {
+	info, err := js.StreamInfo("KV_" + sessionBucket)
+	if err != nil {
+		return nil, err
+	}
+	streamConfig := info.Config
+	streamConfig.MaxAge = ttl
+	if _, err := js.UpdateStream(&streamConfig); err != nil {
+		return nil, err
+	}
+	return js.KeyValue(sessionBucket)
+}
+
+// Close stops the janitor of the in-memory WebAuthn session fallback.
+func (s *NATSSessionStore) Close() {
+	s.webauthnSessions.Close()
+}
+
+// SessionConfig returns the store's session lifetime settings.
+func (s *NATSSessionStore) SessionConfig() SessionStoreConfig {
+	return s.cfg
+}
+
 // StoreWebAuthnSession stores a WebAuthn session (in-memory for short-term use)
 func (s *NATSSessionStore) StoreWebAuthnSession(token string, session webauthn.SessionData) error {
 	return s.webauthnSessions.StoreWebAuthnSession(token, session)
@@ -119,20 +274,51 @@ func (s *NATSSessionStore) DeleteWebAuthnSession(token string) error {
 
 // CreateUserSession creates a user session in NATS KV
 func (s *NATSSessionStore) CreateUserSession(sessionID, userID string, ttl time.Duration) error {
-	_, err := s.kv.Put(sessionID, []byte(userID))
-	return err
+	ttl = s.cfg.sessionTTL(ttl)
+	return s.put(sessionID, userSession{UserID: userID, TTL: ttl, ExpiresAt: s.now().Add(ttl)})
 }
 
-// GetUserSession retrieves a user session from NATS KV
+// GetUserSession retrieves a user session from NATS KV. With sliding
+// expiration the entry is rewritten, which also resets its bucket TTL.
 func (s *NATSSessionStore) GetUserSession(sessionID string) (string, error) {
 	entry, err := s.kv.Get(sessionID)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return "", ErrSessionNotFound
+	}
 	if err != nil {
 		return "", err
 	}
-	return string(entry.Value()), nil
+	var sess userSession
+	if err := json.Unmarshal(entry.Value(), &sess); err != nil {
+		// Entries written before sessions carried an expiry hold a bare user
+		// ID. They can't be trusted to expire, so treat them as logged out.
+		_ = s.kv.Delete(sessionID)
+		return "", ErrSessionNotFound
+	}
+	now := s.now()
+	if now.After(sess.ExpiresAt) {
+		_ = s.kv.Delete(sessionID)
+		return "", ErrSessionNotFound
+	}
+	if s.cfg.Sliding {
+		sess.ExpiresAt = now.Add(sess.TTL)
+		if err := s.put(sessionID, sess); err != nil {
+			return "", err
+		}
+	}
+	return sess.UserID, nil
 }
 
 // DeleteUserSession deletes a user session from NATS KV
 func (s *NATSSessionStore) DeleteUserSession(sessionID string) error {
 	return s.kv.Delete(sessionID)
-}
\ No newline at end of file
+}
+
+func (s *NATSSessionStore) put(sessionID string, sess userSession) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+	_, err = s.kv.Put(sessionID, data)
+	return err
+}
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
@@ -13,7 +15,16 @@ type User struct {
 	ID          []byte
 	Name        string
 	DisplayName string
-	Credentials []webauthn.Credential
+	Credentials []Credential
+}
+
+// Credential is a registered WebAuthn credential plus the metadata shown to
+// the user when managing passkeys
+type Credential struct {
+	webauthn.Credential
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at,omitempty"`
 }
 
 // WebAuthnID returns the user's WebAuthn ID
@@ -33,12 +44,49 @@ func (u *User) WebAuthnDisplayName() string {
 
 // WebAuthnCredentials returns the user's WebAuthn credentials
 func (u *User) WebAuthnCredentials() []webauthn.Credential {
-	return u.Credentials
+	creds := make([]webauthn.Credential, len(u.Credentials))
+	for i, cred := range u.Credentials {
+		creds[i] = cred.Credential
+	}
+	return creds
 }
 
-// AddCredential adds a credential to the user
-func (u *User) AddCredential(credential *webauthn.Credential) {
-	u.Credentials = append(u.Credentials, *credential)
+// AddCredential adds a credential to the user. An empty name defaults to
+// "Passkey N"
+func (u *User) AddCredential(credential *webauthn.Credential, name string, createdAt time.Time) {
+	if name == "" {
+		name = fmt.Sprintf("Passkey %d", len(u.Credentials)+1)
+	}
+	u.Credentials = append(u.Credentials, Credential{
+		Credential: *credential,
+		Name:       name,
+		CreatedAt:  createdAt,
+	})
+}
+
+// RecordCredentialUse stores the authenticator state returned by a successful
+// login and marks the credential as used
+func (u *User) RecordCredentialUse(credential *webauthn.Credential, usedAt time.Time) bool {
+	for i := range u.Credentials {
+		if string(u.Credentials[i].ID) == string(credential.ID) {
+			u.Credentials[i].Authenticator = credential.Authenticator
+			u.Credentials[i].LastUsedAt = usedAt
+			return true
+		}
+	}
+	return false
+}
+
+// RenameCredentialByIndex sets the friendly name of a credential
+func (u *User) RenameCredentialByIndex(index int, name string) error {
+	if index < 0 || index >= len(u.Credentials) {
+		return errors.New("credential index out of range")
+	}
+	if name == "" {
+		return errors.New("credential name required")
+	}
+	u.Credentials[index].Name = name
+	return nil
 }
 
 // RemoveCredential removes a credential by its ID
@@ -66,8 +114,11 @@ func (u *User) RemoveCredentialByIndex(index int) error {
 type UserStore interface {
 	GetUser(username string) (*User, error)
 	GetUserByID(userID string) (*User, error)
+	GetUserByCredentialID(credentialID []byte) (*User, error)
 	GetOrCreateUser(username string) (*User, error)
-	AddCredential(username string, credential *webauthn.Credential) error
+	AddCredential(username string, credential *webauthn.Credential, name string) error
+	RecordCredentialUse(username string, credential *webauthn.Credential) error
+	RenameCredentialByIndex(username string, index int, name string) error
 	RemoveCredential(username string, credentialID []byte) error
 	RemoveCredentialByIndex(username string, index int) error
 }
@@ -75,12 +126,15 @@ type UserStore interface {
 // InMemoryUserStore implements UserStore using in-memory storage
 type InMemoryUserStore struct {
 	users map[string]*User
+	// byCredential maps credential IDs to usernames for discoverable login
+	byCredential map[string]string
 }
 
 // NewInMemoryUserStore creates a new in-memory user store
 func NewInMemoryUserStore() *InMemoryUserStore {
 	return &InMemoryUserStore{
-		users: make(map[string]*User),
+		users:        make(map[string]*User),
+		byCredential: make(map[string]string),
 	}
 }
 
@@ -105,6 +159,15 @@ func (s *InMemoryUserStore) GetUserByID(userID string) (*User, error) {
 	return nil, errors.New("user not found")
 }
 
+// GetUserByCredentialID retrieves the user owning a credential
+func (s *InMemoryUserStore) GetUserByCredentialID(credentialID []byte) (*User, error) {
+	username, exists := s.byCredential[string(credentialID)]
+	if !exists {
+		return nil, errors.New("credential not found")
+	}
+	return s.GetUser(username)
+}
+
 // GetOrCreateUser retrieves an existing user or creates a new one
 func (s *InMemoryUserStore) GetOrCreateUser(username string) (*User, error) {
 	if user, exists := s.users[username]; exists {
@@ -115,23 +178,45 @@ func (s *InMemoryUserStore) GetOrCreateUser(username string) (*User, error) {
 		ID:          []byte(uuid.New().String()),
 		Name:        username,
 		DisplayName: username,
-		Credentials: []webauthn.Credential{},
+		Credentials: []Credential{},
 	}
 	s.users[username] = user
 	return user, nil
 }
 
-// AddCredential adds a credential to a user
-func (s *InMemoryUserStore) AddCredential(username string, credential *webauthn.Credential) error {
+// AddCredential adds a named credential to a user
+func (s *InMemoryUserStore) AddCredential(username string, credential *webauthn.Credential, name string) error {
 	user, exists := s.users[username]
 	if !exists {
 		return errors.New("user not found")
 	}
-	user.AddCredential(credential)
+	user.AddCredential(credential, name, time.Now())
+	s.byCredential[string(credential.ID)] = username
 	log.Debug("Added credential for user %s, total credentials: %d\n", username, len(user.Credentials))
 	return nil
 }
 
+// RecordCredentialUse updates a credential after a successful login
+func (s *InMemoryUserStore) RecordCredentialUse(username string, credential *webauthn.Credential) error {
+	user, exists := s.users[username]
+	if !exists {
+		return errors.New("user not found")
+	}
+	if !user.RecordCredentialUse(credential, time.Now()) {
+		return errors.New("credential not found")
+	}
+	return nil
+}
+
+// RenameCredentialByIndex renames a credential by its index
+func (s *InMemoryUserStore) RenameCredentialByIndex(username string, index int, name string) error {
+	user, exists := s.users[username]
+	if !exists {
+		return errors.New("user not found")
+	}
+	return user.RenameCredentialByIndex(index, name)
+}
+
 // RemoveCredential removes a credential by its ID
 func (s *InMemoryUserStore) RemoveCredential(username string, credentialID []byte) error {
 	user, exists := s.users[username]
@@ -141,6 +226,7 @@ func (s *InMemoryUserStore) RemoveCredential(username string, credentialID []byt
 	if !user.RemoveCredential(credentialID) {
 		return errors.New("credential not found")
 	}
+	delete(s.byCredential, string(credentialID))
 	log.Debug("Removed credential for user %s, remaining credentials: %d\n", username, len(user.Credentials))
 	return nil
 }
@@ -151,9 +237,14 @@ func (s *InMemoryUserStore) RemoveCredentialByIndex(username string, index int)
 	if !exists {
 		return errors.New("user not found")
 	}
+	if index < 0 || index >= len(user.Credentials) {
+		return errors.New("credential index out of range")
+	}
+	credentialID := user.Credentials[index].ID
 	if err := user.RemoveCredentialByIndex(index); err != nil {
 		return err
 	}
+	delete(s.byCredential, string(credentialID))
 	log.Debug("Removed credential at index %d for user %s, remaining credentials: %d\n", index, username, len(user.Credentials))
 	return nil
 }
\ No newline at end of file
@@ -2,12 +2,14 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
+	"github.com/joeblew999/infra/pkg/log"
 )
 
 // WebAuthnConfig holds configuration for WebAuthn setup
@@ -22,6 +24,8 @@ type WebAuthnService struct {
 	webauthn *webauthn.WebAuthn
 	users    UserStore
 	sessions SessionStore
+	// sessionTTL is the lifetime of login sessions; zero uses the store TTL.
+	sessionTTL time.Duration
 }
 
 // NewWebAuthnService creates a new WebAuthn service
@@ -43,8 +47,12 @@ func NewWebAuthnService(config WebAuthnConfig, users UserStore, sessions Session
 		AuthenticatorSelection: protocol.AuthenticatorSelection{
 			// No AuthenticatorAttachment specified - allows all authenticator types
 			// This works best across Safari, Chrome, Firefox, Edge
-			RequireResidentKey:      protocol.ResidentKeyNotRequired(),
-			UserVerification:        protocol.VerificationDiscouraged, // Most compatible setting
+			// Ask for a discoverable credential so usernameless login works,
+			// but accept security keys that can't store one;
+			// BeginDiscoverableRegistration requires it instead.
+			ResidentKey:        protocol.ResidentKeyRequirementPreferred,
+			RequireResidentKey: protocol.ResidentKeyNotRequired(),
+			UserVerification:   protocol.VerificationDiscouraged, // Most compatible setting
 		},
 	})
 	if err != nil {
@@ -80,6 +88,17 @@ func (w *WebAuthnService) BeginRegistration(username string) (*protocol.Credenti
 
 // BeginRegistrationWithPlatform starts registration with platform authenticator preference
 func (w *WebAuthnService) BeginRegistrationWithPlatform(username string, usePlatform bool) (*protocol.CredentialCreation, string, error) {
+	return w.beginRegistration(username, usePlatform, false)
+}
+
+// BeginDiscoverableRegistration starts registration of a passkey meant for
+// usernameless login, so the authenticator must store a discoverable
+// (resident) credential; ones that can't will refuse.
+func (w *WebAuthnService) BeginDiscoverableRegistration(username string, usePlatform bool) (*protocol.CredentialCreation, string, error) {
+	return w.beginRegistration(username, usePlatform, true)
+}
+
+func (w *WebAuthnService) beginRegistration(username string, usePlatform, discoverable bool) (*protocol.CredentialCreation, string, error) {
 	user, err := w.users.GetOrCreateUser(username)
 	if err != nil {
 		return nil, "", err
@@ -99,6 +118,10 @@ func (w *WebAuthnService) BeginRegistrationWithPlatform(username string, usePlat
 		// Allow all authenticator types for maximum compatibility
 		options.Response.AuthenticatorSelection.UserVerification = protocol.VerificationDiscouraged
 	}
+	if discoverable {
+		options.Response.AuthenticatorSelection.ResidentKey = protocol.ResidentKeyRequirementRequired
+		options.Response.AuthenticatorSelection.RequireResidentKey = protocol.ResidentKeyRequired()
+	}
 
 	token := uuid.New().String()
 	if err := w.sessions.StoreWebAuthnSession(token, *session); err != nil {
@@ -108,8 +131,9 @@ func (w *WebAuthnService) BeginRegistrationWithPlatform(username string, usePlat
 	return options, token, nil
 }
 
-// FinishRegistration completes the WebAuthn registration process
-func (w *WebAuthnService) FinishRegistration(token string, response *protocol.ParsedCredentialCreationData) (*User, error) {
+// FinishRegistration completes the WebAuthn registration process, storing the
+// new credential under the user-supplied name
+func (w *WebAuthnService) FinishRegistration(token string, response *protocol.ParsedCredentialCreationData, name string) (*User, error) {
 	session, err := w.sessions.GetWebAuthnSession(token)
 	if err != nil {
 		return nil, err
@@ -125,7 +149,7 @@ func (w *WebAuthnService) FinishRegistration(token string, response *protocol.Pa
 		return nil, err
 	}
 
-	if err := w.users.AddCredential(user.WebAuthnName(), credential); err != nil {
+	if err := w.users.AddCredential(user.WebAuthnName(), credential, name); err != nil {
 		return nil, err
 	}
 
@@ -165,16 +189,71 @@ func (w *WebAuthnService) FinishLogin(token string, response *protocol.ParsedCre
 		return nil, "", err
 	}
 
-	_, err = w.webauthn.ValidateLogin(user, *session, response)
+	credential, err := w.webauthn.ValidateLogin(user, *session, response)
 	if err != nil {
 		return nil, "", err
 	}
 
 	w.sessions.DeleteWebAuthnSession(token)
+	return w.completeLogin(user, credential)
+}
+
+// BeginDiscoverableLogin starts a usernameless login: the assertion request
+// has an empty allowCredentials list, so the authenticator offers its own
+// discoverable credentials for this relying party
+func (w *WebAuthnService) BeginDiscoverableLogin() (*protocol.CredentialAssertion, string, error) {
+	options, session, err := w.webauthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := uuid.New().String()
+	if err := w.sessions.StoreWebAuthnSession(token, *session); err != nil {
+		return nil, "", err
+	}
+
+	return options, token, nil
+}
+
+// FinishDiscoverableLogin completes a usernameless login, resolving the user
+// from the credential ID in the response
+func (w *WebAuthnService) FinishDiscoverableLogin(token string, response *protocol.ParsedCredentialAssertionData) (*User, string, error) {
+	session, err := w.sessions.GetWebAuthnSession(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var user *User
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		u, err := w.users.GetUserByCredentialID(rawID)
+		if err != nil {
+			return nil, err
+		}
+		if len(userHandle) > 0 && string(userHandle) != string(u.ID) {
+			return nil, errors.New("credential does not belong to the presented user handle")
+		}
+		user = u
+		return u, nil
+	}
+
+	_, credential, err := w.webauthn.ValidatePasskeyLogin(handler, *session, response)
+	if err != nil {
+		return nil, "", err
+	}
+
+	w.sessions.DeleteWebAuthnSession(token)
+	return w.completeLogin(user, credential)
+}
+
+// completeLogin records the credential use and creates the user session
+func (w *WebAuthnService) completeLogin(user *User, credential *webauthn.Credential) (*User, string, error) {
+	if err := w.users.RecordCredentialUse(user.WebAuthnName(), credential); err != nil {
+		log.Warn("Failed to record credential use", "username", user.WebAuthnName(), "error", err)
+	}
 
 	// Create user session
 	sessionID := uuid.New().String()
-	if err := w.sessions.CreateUserSession(sessionID, user.WebAuthnName(), 30*time.Minute); err != nil {
+	if err := w.sessions.CreateUserSession(sessionID, user.WebAuthnName(), w.sessionTTL); err != nil {
 		return nil, "", err
 	}
 
@@ -210,7 +289,7 @@ func (w *WebAuthnService) CreateTestUser(username string) (*User, error) {
 		},
 	}
 
-	if err := w.users.AddCredential(username, mockCredential); err != nil {
+	if err := w.users.AddCredential(username, mockCredential, "Test passkey"); err != nil {
 		return nil, err
 	}
 
@@ -218,7 +297,7 @@ func (w *WebAuthnService) CreateTestUser(username string) (*User, error) {
 }
 
 // FinishRegistrationFromJSON completes registration from JSON response
-func (w *WebAuthnService) FinishRegistrationFromJSON(token string, responseData any) (*User, error) {
+func (w *WebAuthnService) FinishRegistrationFromJSON(token string, responseData any, name string) (*User, error) {
 	// Convert any to JSON bytes and back to proper struct
 	jsonBytes, err := json.Marshal(responseData)
 	if err != nil {
@@ -235,28 +314,44 @@ func (w *WebAuthnService) FinishRegistrationFromJSON(token string, responseData
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	return w.FinishRegistration(token, parsedResponse)
+	return w.FinishRegistration(token, parsedResponse, name)
 }
 
 // FinishLoginFromJSON completes login from JSON response
 func (w *WebAuthnService) FinishLoginFromJSON(token string, responseData any) (*User, string, error) {
-	// Convert any to JSON bytes and back to proper struct
+	parsedResponse, err := parseAssertionJSON(responseData)
+	if err != nil {
+		return nil, "", err
+	}
+	return w.FinishLogin(token, parsedResponse)
+}
+
+// FinishDiscoverableLoginFromJSON completes a usernameless login from JSON response
+func (w *WebAuthnService) FinishDiscoverableLoginFromJSON(token string, responseData any) (*User, string, error) {
+	parsedResponse, err := parseAssertionJSON(responseData)
+	if err != nil {
+		return nil, "", err
+	}
+	return w.FinishDiscoverableLogin(token, parsedResponse)
+}
+
+// parseAssertionJSON converts a decoded JSON assertion response into its parsed form
+func parseAssertionJSON(responseData any) (*protocol.ParsedCredentialAssertionData, error) {
 	jsonBytes, err := json.Marshal(responseData)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to marshal response: %w", err)
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
 	}
 
 	var response protocol.CredentialAssertionResponse
 	if err := json.Unmarshal(jsonBytes, &response); err != nil {
-		return nil, "", fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	parsedResponse, err := response.Parse()
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to parse response: %w", err)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
-
-	return w.FinishLogin(token, parsedResponse)
+	return parsedResponse, nil
 }
 
 // CreateUserSession creates a new user session
@@ -269,6 +364,11 @@ func (w *WebAuthnService) GetUserCredentials(username string) (*User, error) {
 	return w.users.GetUser(username)
 }
 
+// RenameUserCredential sets the friendly name of a credential by index
+func (w *WebAuthnService) RenameUserCredential(username string, index int, name string) error {
+	return w.users.RenameCredentialByIndex(username, index, name)
+}
+
 // DeleteUserCredential removes a credential by index
 func (w *WebAuthnService) DeleteUserCredential(username string, index int) error {
 	return w.users.RemoveCredentialByIndex(username, index)
@@ -0,0 +1,214 @@
+package caddy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/joeblew999/infra/pkg/config"
+)
+
+// AccessLogSubject is the default NATS subject for republished access log
+// entries.
+const AccessLogSubject = "caddy.access"
+
+const (
+	accessLogRollSize = "10MiB"
+	accessLogRollKeep = 5
+
+	accessLogPollInterval = 250 * time.Millisecond
+)
+
+// DefaultAccessLogPath is where the runtime asks Caddy to write its JSON
+// access log.
+func DefaultAccessLogPath() string {
+	return filepath.Join(config.GetCaddyPath(), "access.log")
+}
+
+// AccessLogEntry is one line of Caddy's JSON access log.
+type AccessLogEntry struct {
+	TS       float64          `json:"ts"`
+	Logger   string           `json:"logger"`
+	Msg      string           `json:"msg"`
+	Request  AccessLogRequest `json:"request"`
+	Duration float64          `json:"duration"` // seconds
+	Size     int64            `json:"size"`
+	Status   int              `json:"status"`
+	// Raw is the line as Caddy wrote it, including fields not mapped here.
+	Raw json.RawMessage `json:"-"`
+}
+
+// AccessLogRequest is the request part of an AccessLogEntry.
+type AccessLogRequest struct {
+	RemoteIP string `json:"remote_ip"`
+	ClientIP string `json:"client_ip"`
+	Proto    string `json:"proto"`
+	Method   string `json:"method"`
+	Host     string `json:"host"`
+	URI      string `json:"uri"`
+}
+
+// Time converts the entry's Unix timestamp.
+func (e AccessLogEntry) Time() time.Time {
+	sec := int64(e.TS)
+	return time.Unix(sec, int64((e.TS-float64(sec))*float64(time.Second)))
+}
+
+// AccessLogHandler receives parsed entries; an error stops the tail.
+type AccessLogHandler func(AccessLogEntry) error
+
+// PublishAccessLog returns a handler that republishes each entry, as Caddy
+// wrote it, on subject.
+func PublishAccessLog(nc *nats.Conn, subject string) AccessLogHandler {
+	return func(entry AccessLogEntry) error {
+		if err := nc.Publish(subject, entry.Raw); err != nil {
+			return fmt.Errorf("publish access log entry: %w", err)
+		}
+		return nil
+	}
+}
+
+// TailAccessLog follows Caddy's JSON access log at path until ctx is done,
+// passing each new entry to handler. Entries already in the file are
+// skipped. When Caddy rolls the log (or the file is truncated or only
+// appears later) the new file is read from the start, so nothing written
+// after the tail began is lost. Lines that are not valid JSON are skipped.
+func TailAccessLog(ctx context.Context, path string, handler AccessLogHandler) error {
+	return tailAccessLog(ctx, path, handler, accessLogPollInterval)
+}
+
+func tailAccessLog(ctx context.Context, path string, handler AccessLogHandler, poll time.Duration) error {
+	t := &logTailer{path: path}
+	defer t.close()
+
+	if err := t.open(true); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		if err := t.readLines(handler); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		rotated, err := t.rotated()
+		if err != nil {
+			return err
+		}
+		if rotated {
+			// Drain whatever the old file got before it was rolled.
+			if err := t.readLines(handler); err != nil {
+				return err
+			}
+			if err := t.open(false); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// logTailer tracks the currently open log file across rotations.
+type logTailer struct {
+	path    string
+	file    *os.File
+	info    os.FileInfo
+	reader  *bufio.Reader
+	offset  int64
+	partial []byte
+}
+
+// open opens path if it exists. With atEnd the existing content is skipped.
+func (t *logTailer) open(atEnd bool) error {
+	f, err := os.Open(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open access log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("stat access log: %w", err)
+	}
+	var offset int64
+	if atEnd {
+		if offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return fmt.Errorf("seek access log: %w", err)
+		}
+	}
+	t.close()
+	t.file, t.info, t.offset, t.partial = f, info, offset, nil
+	t.reader = bufio.NewReader(f)
+	return nil
+}
+
+func (t *logTailer) close() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+}
+
+// readLines hands every complete line written so far to handler.
+func (t *logTailer) readLines(handler AccessLogHandler) error {
+	if t.file == nil {
+		return nil
+	}
+	for {
+		chunk, err := t.reader.ReadBytes('\n')
+		t.offset += int64(len(chunk))
+		t.partial = append(t.partial, chunk...)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read access log: %w", err)
+		}
+
+		line := t.partial
+		t.partial = nil
+		var entry AccessLogEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		entry.Raw = append(json.RawMessage(nil), bytesTrimNewline(line)...)
+		if err := handler(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// rotated reports whether path now names a different file than the one
+// open (rolled or newly created) or the open file was truncated.
+func (t *logTailer) rotated() (bool, error) {
+	info, err := os.Stat(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("stat access log: %w", err)
+	}
+	return t.file == nil || !os.SameFile(info, t.info) || info.Size() < t.offset, nil
+}
+
+func bytesTrimNewline(b []byte) []byte {
+	for len(b) > 0 && (b[len(b)-1] == '\n' || b[len(b)-1] == '\r') {
+		b = b[:len(b)-1]
+	}
+	return b
+}
@@ -0,0 +1,148 @@
+package caddy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func accessLine(uri string, status int) string {
+	return fmt.Sprintf(`{"level":"info","ts":1700000000.5,"logger":"http.log.access","msg":"handled request",`+
+		`"request":{"remote_ip":"127.0.0.1","proto":"HTTP/1.1","method":"GET","host":"localhost","uri":%q},`+
+		`"duration":0.002,"size":12,"status":%d}`+"\n", uri, status)
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// collector gathers URIs seen by the tailer.
+type collector struct {
+	mu   sync.Mutex
+	uris []string
+}
+
+func (c *collector) handle(e AccessLogEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uris = append(c.uris, e.Request.URI)
+	return nil
+}
+
+func (c *collector) waitFor(t *testing.T, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.Lock()
+		got := strings.Join(c.uris, ",")
+		c.mu.Unlock()
+		if got == want {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t.Fatalf("uris = %v, want %s", c.uris, want)
+}
+
+func TestTailAccessLogSurvivesRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	appendFile(t, path, accessLine("/old", 200))
+
+	var c collector
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- tailAccessLog(ctx, path, c.handle, 5*time.Millisecond) }()
+	time.Sleep(20 * time.Millisecond) // let the tailer open the file
+
+	// Existing entries are skipped; a line is only handled once complete.
+	appendFile(t, path, "not json\n"+accessLine("/a", 200)+`{"request":{"uri":"/b"`)
+	c.waitFor(t, "/a")
+	appendFile(t, path, "}}\n")
+	c.waitFor(t, "/a,/b")
+
+	// Caddy rolls the log by renaming it and starting a new file.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path+".1", accessLine("/late", 200))
+	appendFile(t, path, accessLine("/c", 500))
+	c.waitFor(t, "/a,/b,/late,/c")
+
+	// Truncation starts over from the top of the file.
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, accessLine("/d", 200))
+	c.waitFor(t, "/a,/b,/late,/c,/d")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPublishAccessLog(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+	sub, err := nc.SubscribeSync(AccessLogSubject)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	line := strings.TrimSpace(accessLine("/x", 200))
+	if err := PublishAccessLog(nc, AccessLogSubject)(AccessLogEntry{Raw: []byte(line)}); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(msg.Data) != line {
+		t.Fatalf("published %s", msg.Data)
+	}
+}
+
+func TestGenerateCaddyfileAccessLog(t *testing.T) {
+	cfg := CaddyConfig{Port: 8080, Target: "localhost:1337"}
+	if strings.Contains(GenerateCaddyfile(cfg), "log {") {
+		t.Fatal("access log should be off by default")
+	}
+	cfg.AccessLog = "/tmp/caddy/access.log"
+	out := GenerateCaddyfile(cfg)
+	for _, want := range []string{"output file /tmp/caddy/access.log", "format json", "roll_size"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Caddyfile missing %q:\n%s", want, out)
+		}
+	}
+}
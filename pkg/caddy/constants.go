@@ -3,10 +3,17 @@ package caddy
 import (
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/joeblew999/infra/pkg/config"
 )
 
+// Health check defaults for presets that front several backends.
+const (
+	defaultHealthPath   = "/health"
+	defaultFailDuration = 30 * time.Second
+)
+
 // Default ports and targets sourced from configuration package.
 func defaultMainTarget() string {
 	cfg := config.GetConfig()
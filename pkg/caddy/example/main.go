@@ -89,10 +89,10 @@ func main() {
 	fmt.Println("💡 Press Ctrl+C to stop all services")
 	fmt.Println("")
 	
-	runner.StartInBackground(".data/caddy/Caddyfile")
-
-	// Give Caddy a moment to start
-	time.Sleep(2 * time.Second)
+	if err := runner.StartInBackground(".data/caddy/Caddyfile", caddy.WaitUntilReady(10*time.Second)); err != nil {
+		fmt.Printf("Caddy failed to start: %v\n", err)
+		os.Exit(1)
+	}
 	
 	fmt.Println("🎉 All services are now running!")
 	fmt.Println("🌐 Open your browser to:")
@@ -0,0 +1,70 @@
+package caddy
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/infra/pkg/config"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/*.golden")
+
+// generatedAt matches the timestamp line so golden files stay stable.
+var generatedAt = regexp.MustCompile(`(?m)^# Generated at: .*$`)
+
+func TestGenerateCaddyfileGolden(t *testing.T) {
+	// Production mode leaves out tls/header blocks that depend on the host.
+	t.Setenv(config.EnvVarEnvironment, config.EnvProduction)
+
+	tests := []struct {
+		name string
+		cfg  CaddyConfig
+	}{
+		{"health_checks", CaddyConfig{
+			Port:   8080,
+			Target: "localhost:1337",
+			Routes: []ProxyRoute{
+				{Path: "/plain/*", Target: "localhost:3000"},
+				{Path: "/active/*", Target: "localhost:4000", HealthPath: "/healthz"},
+				{Path: "/passive/*", Target: "localhost:5000", FailDuration: 10 * time.Second},
+				{Path: "/both/*", Target: "localhost:6000", HealthPath: "/health", FailDuration: time.Minute},
+			},
+		}},
+		{"rate_limit", CaddyConfig{
+			Port:   8080,
+			Target: "localhost:1337",
+			Routes: []ProxyRoute{
+				ProxyRoute{Path: "/auth/*", Target: "localhost:5000"}.WithRateLimit(10, time.Minute),
+				ProxyRoute{Path: "/api/v1/*", Target: "localhost:4000", FailDuration: 10 * time.Second}.
+					WithRateLimit(100, time.Second).WithRateLimitByHeader("X-API-Key"),
+			},
+		}},
+		{"microservices", CaddyConfig{
+			Port:   8080,
+			Target: "localhost:1337",
+			Routes: MicroservicesConfig(8080).Routes[:4],
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generatedAt.ReplaceAllString(GenerateCaddyfile(tt.cfg), "# Generated at: <time>")
+			path := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("Caddyfile differs from %s:\n%s", path, got)
+			}
+		})
+	}
+}
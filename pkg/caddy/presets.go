@@ -79,6 +79,10 @@ func MicroservicesConfig(port int) CaddyConfig {
 		{Path: "/static/*", Target: "localhost:6000"},
 		{Path: "/ws/*", Target: "localhost:7000"},
 	}
+	for i := range routes {
+		routes[i].HealthPath = defaultHealthPath
+		routes[i].FailDuration = defaultFailDuration
+	}
 	routes = append(routes, infrastructureRoutes()...)
 	return CaddyConfig{
 		Port:   port,
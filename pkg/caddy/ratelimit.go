@@ -0,0 +1,139 @@
+package caddy
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Rate limiting comes from the caddy-ratelimit plugin, which stock Caddy
+// builds don't include.
+const (
+	RateLimitModule = "http.handlers.rate_limit"
+	RateLimitPlugin = "github.com/mholt/caddy-ratelimit"
+)
+
+// ErrRateLimitUnsupported is returned when a config uses RateLimit but the
+// caddy binary lacks the rate_limit handler.
+var ErrRateLimitUnsupported = errors.New("caddy binary does not include the rate_limit handler")
+
+// RateLimit caps requests to a route at Rate per Window, counted per client
+// IP or, with Header set, per value of that request header.
+type RateLimit struct {
+	Rate   int
+	Window time.Duration
+	Header string // e.g. "X-API-Key"; empty keys by client IP
+}
+
+// key returns the Caddy placeholder requests are bucketed by.
+func (l RateLimit) key() string {
+	if l.Header != "" {
+		return "{http.request.header." + l.Header + "}"
+	}
+	return "{remote_host}"
+}
+
+// WithRateLimit creates a copy of the route limited to rate requests per
+// window for each client IP. Needs a caddy built with RateLimitPlugin.
+func (r ProxyRoute) WithRateLimit(rate int, window time.Duration) ProxyRoute {
+	r.RateLimit = &RateLimit{Rate: rate, Window: window}
+	return r
+}
+
+// WithRateLimitByHeader creates a copy of the route whose rate limit is
+// counted per value of header instead of per client IP.
+func (r ProxyRoute) WithRateLimitByHeader(header string) ProxyRoute {
+	if r.RateLimit != nil {
+		limit := *r.RateLimit
+		limit.Header = header
+		r.RateLimit = &limit
+	}
+	return r
+}
+
+// AddRoute adds a fully specified route, e.g. one built with WithRateLimit,
+// to an existing config
+func (cfg CaddyConfig) AddRoute(route ProxyRoute) CaddyConfig {
+	cfg.Routes = append(cfg.Routes, route)
+	return cfg
+}
+
+// usesRateLimit reports whether any route is rate limited.
+func (cfg CaddyConfig) usesRateLimit() bool {
+	for _, route := range cfg.Routes {
+		if route.RateLimit != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitDirective renders the rate_limit handler for route at the given
+// indent. Each route gets its own zone, named after its exact path.
+func rateLimitDirective(route ProxyRoute, indent string) string {
+	limit := route.RateLimit
+	content := indent + "rate_limit {\n"
+	content += fmt.Sprintf("%s\tzone %s {\n", indent, zoneName(route.Path))
+	content += fmt.Sprintf("%s\t\tkey %s\n", indent, limit.key())
+	content += fmt.Sprintf("%s\t\tevents %d\n", indent, limit.Rate)
+	content += fmt.Sprintf("%s\t\twindow %s\n", indent, limit.Window)
+	content += indent + "\t}\n"
+	content += indent + "}\n"
+	return content
+}
+
+// zoneName turns a path matcher such as "/api/v1/*" into "api_v1_<hash>".
+// The readable part alone would collide for paths like "/api" and "/api/*",
+// which must not share a bucket, so a hash of the exact path is appended.
+func zoneName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, path)
+	name = strings.Trim(name, "_")
+	if name == "" {
+		name = "root"
+	}
+	sum := sha256.Sum256([]byte(path))
+	return name + "_" + hex.EncodeToString(sum[:4])
+}
+
+// HasModule reports whether the caddy binary includes the named module,
+// according to `caddy list-modules`.
+func (r *Runner) HasModule(name string) (bool, error) {
+	output, err := r.RunWithOutput("list-modules")
+	if err != nil {
+		return false, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == name {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// CheckPlugins verifies the caddy binary has the plugins cfg needs, so a
+// missing one fails with build instructions rather than a Caddyfile
+// parse error at startup.
+func (r *Runner) CheckPlugins(cfg CaddyConfig) error {
+	if !cfg.usesRateLimit() {
+		return nil
+	}
+	ok, err := r.HasModule(RateLimitModule)
+	if err != nil {
+		return fmt.Errorf("failed to list caddy modules: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: rebuild caddy with `xcaddy build --with %s` or remove RateLimit from the routes", ErrRateLimitUnsupported, RateLimitPlugin)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package caddy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeCaddy returns a runner whose binary prints modules for list-modules.
+func fakeCaddy(t *testing.T, modules ...string) *Runner {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake caddy binary is a shell script")
+	}
+	path := filepath.Join(t.TempDir(), "caddy")
+	script := "#!/bin/sh\ncat <<'EOF'\n" + strings.Join(modules, "\n") + "\nEOF\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return &Runner{binaryPath: path}
+}
+
+func TestCheckPluginsRateLimit(t *testing.T) {
+	limited := CaddyConfig{Target: "localhost:1337"}.
+		AddRoute(ProxyRoute{Path: "/auth/*", Target: "localhost:5000"}.WithRateLimit(5, time.Minute))
+
+	stock := fakeCaddy(t, "http.handlers.reverse_proxy", "http.handlers.file_server")
+	if err := stock.CheckPlugins(DefaultConfig()); err != nil {
+		t.Fatalf("config without rate limits should not need plugins: %v", err)
+	}
+	err := stock.CheckPlugins(limited)
+	if !errors.Is(err, ErrRateLimitUnsupported) || !strings.Contains(err.Error(), "xcaddy build --with "+RateLimitPlugin) {
+		t.Fatalf("expected missing plugin guidance, got %v", err)
+	}
+
+	custom := fakeCaddy(t, "http.handlers.reverse_proxy", RateLimitModule)
+	if err := custom.CheckPlugins(limited); err != nil {
+		t.Fatalf("CheckPlugins: %v", err)
+	}
+}
+
+func TestZoneName(t *testing.T) {
+	for path, prefix := range map[string]string{"/api/v1/*": "api_v1_", "/auth/*": "auth_", "/*": "root_"} {
+		if got := zoneName(path); !strings.HasPrefix(got, prefix) || len(got) != len(prefix)+8 {
+			t.Errorf("zoneName(%q) = %q, want %s<8 hex digits>", path, got, prefix)
+		}
+	}
+	if zoneName("/auth/*") != zoneName("/auth/*") {
+		t.Error("zoneName should be stable for the same path")
+	}
+
+	// These slug to the same readable name but are different routes.
+	seen := map[string]string{}
+	for _, path := range []string{"/api", "/api/*", "/api/", "api", "/api-*", "/api_"} {
+		name := zoneName(path)
+		if other, ok := seen[name]; ok {
+			t.Errorf("zoneName(%q) and zoneName(%q) collide as %q", path, other, name)
+		}
+		seen[name] = path
+	}
+}
@@ -0,0 +1,95 @@
+package caddy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultAdminAddr is where Caddy serves its admin API unless the config
+// sets the global "admin" option.
+const DefaultAdminAddr = "localhost:2019"
+
+// readyPollInterval is how often WaitReady polls the admin API.
+var readyPollInterval = 100 * time.Millisecond
+
+// WaitReady polls Caddy's admin API at adminAddr (host:port, DefaultAdminAddr
+// when empty) until GET /config/ answers 200 OK, or fails after timeout with
+// the last error seen.
+func WaitReady(adminAddr string, timeout time.Duration) error {
+	return waitReady(adminAddr, timeout, nil)
+}
+
+// waitReady is WaitReady that also gives up as soon as exited delivers,
+// i.e. when the Caddy process quits before becoming ready.
+func waitReady(adminAddr string, timeout time.Duration, exited <-chan error) error {
+	if adminAddr == "" {
+		adminAddr = DefaultAdminAddr
+	}
+	url := "http://" + adminAddr + "/config/"
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client := &http.Client{Timeout: time.Second}
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	lastErr := fmt.Errorf("no response")
+	for {
+		err := probeAdmin(ctx, client, url)
+		if err == nil {
+			return nil
+		}
+		// Keep the last real failure rather than our own deadline.
+		if ctx.Err() == nil {
+			lastErr = err
+		}
+		select {
+		case err := <-exited:
+			if err == nil {
+				err = fmt.Errorf("process exited")
+			}
+			return fmt.Errorf("caddy exited before becoming ready: %w", err)
+		case <-ctx.Done():
+			return fmt.Errorf("caddy admin API at %s not ready after %s: %w", adminAddr, timeout, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+func probeAdmin(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// StartOption customises StartInBackground and StartWithConfig.
+type StartOption func(*startOptions)
+
+type startOptions struct {
+	readyTimeout time.Duration
+	adminAddr    string
+}
+
+// WaitUntilReady makes the start call block until Caddy's admin API answers,
+// returning an error if Caddy exits first or isn't up within timeout.
+func WaitUntilReady(timeout time.Duration) StartOption {
+	return func(o *startOptions) { o.readyTimeout = timeout }
+}
+
+// WithAdminAddr sets the admin API address WaitUntilReady polls, for configs
+// that move it from DefaultAdminAddr.
+func WithAdminAddr(addr string) StartOption {
+	return func(o *startOptions) { o.adminAddr = addr }
+}
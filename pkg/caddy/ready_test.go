@@ -0,0 +1,58 @@
+package caddy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitReadyPollsUntilAdminAnswers(t *testing.T) {
+	readyPollInterval = 5 * time.Millisecond
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/config/" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer srv.Close()
+
+	if err := WaitReady(strings.TrimPrefix(srv.URL, "http://"), time.Second); err != nil {
+		t.Fatalf("WaitReady: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 probes, got %d", calls.Load())
+	}
+}
+
+func TestWaitReadyTimesOut(t *testing.T) {
+	readyPollInterval = 5 * time.Millisecond
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := WaitReady(strings.TrimPrefix(srv.URL, "http://"), 50*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected timeout with last status, got %v", err)
+	}
+}
+
+func TestWaitReadyStopsWhenProcessExits(t *testing.T) {
+	readyPollInterval = 5 * time.Millisecond
+	exited := make(chan error, 1)
+	exited <- errors.New("exit status 1")
+
+	err := waitReady("127.0.0.1:1", 5*time.Second, exited)
+	if err == nil || !strings.Contains(err.Error(), "exited before becoming ready") {
+		t.Fatalf("expected early exit error, got %v", err)
+	}
+}
@@ -52,13 +52,28 @@ func (r *Runner) RunWithOutput(args ...string) ([]byte, error) {
 	return output, nil
 }
 
-// StartInBackground starts Caddy in a goroutine with the specified config path
-func (r *Runner) StartInBackground(configPath string) {
+// StartInBackground starts Caddy in a goroutine with the specified config path.
+// It returns immediately unless WaitUntilReady is given, in which case it
+// waits for the admin API and reports Caddy failing to start.
+func (r *Runner) StartInBackground(configPath string, opts ...StartOption) error {
+	var o startOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	exited := make(chan error, 1)
 	go func() {
-		if err := r.Run("run", "--config", configPath); err != nil {
+		err := r.Run("run", "--config", configPath)
+		if err != nil {
 			fmt.Printf("Caddy failed: %v\n", err)
 		}
+		exited <- err
 	}()
+
+	if o.readyTimeout <= 0 {
+		return nil
+	}
+	return waitReady(o.adminAddr, o.readyTimeout, exited)
 }
 
 // Reload triggers a zero-downtime configuration reload for the running Caddy instance.
@@ -103,13 +118,28 @@ func (r *Runner) ReverseProxy(from string, to string) error {
 type ProxyRoute struct {
 	Path   string // URL path pattern (e.g., "/bento-playground/*")
 	Target string // Target URL (e.g., "localhost:4195")
+
+	// HealthPath enables active health checks: Caddy polls this URI on the
+	// target (e.g., "/health") and stops routing to it while it fails.
+	HealthPath string
+	// FailDuration enables passive health checks: a failed request or a 5xx
+	// response marks the target down for this long. Zero disables them.
+	FailDuration time.Duration
+	// RateLimit throttles requests before they reach Target; see WithRateLimit.
+	RateLimit *RateLimit
+}
+
+// healthChecked reports whether the route needs a reverse_proxy block.
+func (r ProxyRoute) healthChecked() bool {
+	return r.HealthPath != "" || r.FailDuration > 0
 }
 
 // CaddyConfig represents complete Caddy server configuration
 type CaddyConfig struct {
-	Port   int          // Main listening port
-	Target string       // Default reverse proxy target
-	Routes []ProxyRoute // Additional proxy routes
+	Port      int          // Main listening port
+	Target    string       // Default reverse proxy target
+	Routes    []ProxyRoute // Additional proxy routes
+	AccessLog string       // JSON access log file (see TailAccessLog); empty disables access logging
 }
 
 // DefaultConfig returns a default Caddy configuration
@@ -141,6 +171,14 @@ func GenerateCaddyfile(cfg CaddyConfig) string {
 	content += fmt.Sprintf("# - Routes: %d\n", len(cfg.Routes))
 	content += "#\n\n"
 
+	// rate_limit is a plugin directive with no default position, so it has
+	// to be ordered explicitly to run ahead of the proxy.
+	if cfg.usesRateLimit() {
+		content += "{\n"
+		content += "\torder rate_limit before reverse_proxy\n"
+		content += "}\n\n"
+	}
+
 	portStr := strconv.Itoa(cfg.Port)
 	if config.ShouldUseHTTPS() {
 		content += fmt.Sprintf("%s {\n", config.FormatLocalHostPort(portStr))
@@ -151,13 +189,26 @@ func GenerateCaddyfile(cfg CaddyConfig) string {
 	// Add specific routes first
 	for _, route := range cfg.Routes {
 		content += fmt.Sprintf("\thandle %s {\n", route.Path)
-		content += fmt.Sprintf("\t\treverse_proxy %s\n", route.Target)
+		if route.RateLimit != nil {
+			content += rateLimitDirective(route, "\t\t")
+		}
+		content += reverseProxyDirective(route, "\t\t")
 		content += "\t}\n"
 	}
 
 	// Add default reverse proxy
 	content += fmt.Sprintf("\treverse_proxy %s\n", cfg.Target)
 
+	if cfg.AccessLog != "" {
+		content += "\tlog {\n"
+		content += fmt.Sprintf("\t\toutput file %s {\n", cfg.AccessLog)
+		content += fmt.Sprintf("\t\t\troll_size %s\n", accessLogRollSize)
+		content += fmt.Sprintf("\t\t\troll_keep %d\n", accessLogRollKeep)
+		content += "\t\t}\n"
+		content += "\t\tformat json\n"
+		content += "\t}\n"
+	}
+
 	// Add development-specific headers for cache busting
 	if config.ShouldUseHTTPS() {
 		content += "\ttls internal\n"
@@ -173,6 +224,24 @@ func GenerateCaddyfile(cfg CaddyConfig) string {
 	return content
 }
 
+// reverseProxyDirective renders the reverse_proxy directive for route at the
+// given indent, with a block for any health checks.
+func reverseProxyDirective(route ProxyRoute, indent string) string {
+	if !route.healthChecked() {
+		return fmt.Sprintf("%sreverse_proxy %s\n", indent, route.Target)
+	}
+	content := fmt.Sprintf("%sreverse_proxy %s {\n", indent, route.Target)
+	if route.HealthPath != "" {
+		content += fmt.Sprintf("%s\thealth_uri %s\n", indent, route.HealthPath)
+	}
+	if route.FailDuration > 0 {
+		content += fmt.Sprintf("%s\tfail_duration %s\n", indent, route.FailDuration)
+		content += fmt.Sprintf("%s\tunhealthy_status 5xx\n", indent)
+	}
+	content += indent + "}\n"
+	return content
+}
+
 // GenerateCaddyfileSimple creates a Caddyfile with legacy signature for backward compatibility
 func GenerateCaddyfileSimple(port int, targetPort int) string {
 	targetPortStr := strconv.Itoa(targetPort)
@@ -0,0 +1,64 @@
+package caddy
+
+import (
+	"sort"
+
+	"github.com/joeblew999/infra/pkg/config"
+)
+
+// ServiceRoute describes a running service that Caddy should front.
+type ServiceRoute struct {
+	Name string
+	// Port is the service's local port; Target overrides it when the
+	// service is reached some other way.
+	Port   string
+	Target string
+	// PathPrefix is the Caddy path matcher (e.g. "/bento/*"). Empty or "/"
+	// makes the service the default reverse proxy target.
+	PathPrefix string
+}
+
+func (s ServiceRoute) target() string {
+	if s.Target != "" {
+		return s.Target
+	}
+	if s.Port != "" {
+		return config.FormatLocalHostPort(s.Port)
+	}
+	return ""
+}
+
+// ConfigFromServices builds a config that routes to exactly the given
+// services. The first root service becomes the default target (the web
+// server when none is given); routes are deduplicated by path, first one
+// wins, and sorted so regenerating for the same services is stable.
+func ConfigFromServices(services []ServiceRoute) CaddyConfig {
+	cfg := CaddyConfig{Port: defaultCaddyPort()}
+
+	seen := make(map[string]struct{})
+	for _, svc := range services {
+		target := svc.target()
+		if target == "" {
+			continue
+		}
+		if svc.PathPrefix == "" || svc.PathPrefix == "/" {
+			if cfg.Target == "" {
+				cfg.Target = target
+			}
+			continue
+		}
+		if _, ok := seen[svc.PathPrefix]; ok {
+			continue
+		}
+		seen[svc.PathPrefix] = struct{}{}
+		cfg.Routes = append(cfg.Routes, ProxyRoute{Path: svc.PathPrefix, Target: target})
+	}
+
+	if cfg.Target == "" {
+		cfg.Target = defaultMainTarget()
+	}
+	sort.Slice(cfg.Routes, func(i, j int) bool {
+		return cfg.Routes[i].Path < cfg.Routes[j].Path
+	})
+	return cfg
+}
@@ -0,0 +1,34 @@
+package caddy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfigFromServices(t *testing.T) {
+	cfg := ConfigFromServices([]ServiceRoute{
+		{Name: "Hugo", Port: "1313", PathPrefix: "/docs/*"},
+		{Name: "Web", Port: "1337"},
+		{Name: "Bento", Target: "127.0.0.1:4195", PathPrefix: "/bento/*"},
+		{Name: "Docs mirror", Port: "9999", PathPrefix: "/docs/*"},
+		{Name: "No port", PathPrefix: "/nothing/*"},
+	})
+
+	if cfg.Port != defaultCaddyPort() {
+		t.Errorf("port = %d, want default %d", cfg.Port, defaultCaddyPort())
+	}
+	if cfg.Target != "localhost:1337" {
+		t.Errorf("target = %s, want localhost:1337", cfg.Target)
+	}
+	want := []ProxyRoute{
+		{Path: "/bento/*", Target: "127.0.0.1:4195"},
+		{Path: "/docs/*", Target: "localhost:1313"},
+	}
+	if !reflect.DeepEqual(cfg.Routes, want) {
+		t.Errorf("routes = %+v, want %+v", cfg.Routes, want)
+	}
+
+	if got := ConfigFromServices(nil).Target; got != defaultMainTarget() {
+		t.Errorf("no root service should fall back to %s, got %s", defaultMainTarget(), got)
+	}
+}
@@ -27,6 +27,12 @@ func StartSupervised(cfg *CaddyConfig) error {
 	if err := dep.InstallBinary(config.BinaryCaddy, false); err != nil {
 		return fmt.Errorf("failed to ensure caddy binary: %w", err)
 	}
+	if cfg != nil {
+		binaryPath, _ := filepath.Abs(config.GetCaddyBinPath())
+		if err := (&Runner{binaryPath: binaryPath}).CheckPlugins(*cfg); err != nil {
+			return err
+		}
+	}
 
 	processCfg := service.NewConfig(
 		config.GetCaddyBinPath(),
@@ -37,21 +43,34 @@ func StartSupervised(cfg *CaddyConfig) error {
 	return service.Start("caddy", processCfg)
 }
 
+// StopSupervised stops the goreman-supervised Caddy process, if it is running.
+func StopSupervised() error {
+	if !goreman.IsRunning("caddy") {
+		return nil
+	}
+	return goreman.Stop("caddy")
+}
+
 // StartWithConfig writes the provided configuration then starts Caddy in the background using the Runner.
-func StartWithConfig(cfg *CaddyConfig) *Runner {
+// With WaitUntilReady it also waits for Caddy to come up.
+func StartWithConfig(cfg *CaddyConfig, opts ...StartOption) (*Runner, error) {
 	if cfg == nil {
 		configValue := NewPresetConfig(PresetDevelopment, defaultCaddyPort())
 		cfg = &configValue
 	}
 
 	if err := cfg.GenerateAndSave("Caddyfile"); err != nil {
-		fmt.Printf("Failed to generate Caddyfile: %v\n", err)
-		return nil
+		return nil, fmt.Errorf("failed to generate Caddyfile: %w", err)
 	}
 
 	runner := New()
-	runner.StartInBackground(defaultCaddyfilePath())
-	return runner
+	if err := runner.CheckPlugins(*cfg); err != nil {
+		return runner, err
+	}
+	if err := runner.StartInBackground(defaultCaddyfilePath(), opts...); err != nil {
+		return runner, err
+	}
+	return runner, nil
 }
 
 // ReloadWithConfig regenerates the Caddyfile using the provided configuration and reloads the running process.
@@ -62,6 +81,11 @@ func ReloadWithConfig(cfg *CaddyConfig) error {
 	}
 
 	runner := New()
+	if cfg != nil {
+		if err := runner.CheckPlugins(*cfg); err != nil {
+			return err
+		}
+	}
 	return runner.Reload(configPath)
 }
 
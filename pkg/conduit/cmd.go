@@ -1,8 +1,10 @@
 package conduit
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/joeblew999/infra/pkg/config"
 	"github.com/nats-io/nats.go"
@@ -75,8 +77,9 @@ var conduitStopCmd = &cobra.Command{
 // conduitStatusCmd shows process status
 var conduitStatusCmd = &cobra.Command{
 	Use:   "status",
-	Short: "Show Conduit process status",
-	Long:  `Display the current status of all Conduit processes.`,
+	Short: "Show Conduit process and pipeline status",
+	Long:  `Display the current status of all Conduit processes and the pipelines
+reported by Conduit's HTTP API.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		natsURL := config.GetNATSURL()
 		nc, err := nats.Connect(natsURL)
@@ -101,9 +104,43 @@ var conduitStatusCmd = &cobra.Command{
 		for name, state := range status {
 			fmt.Printf("% -30s: %s\n", name, state)
 		}
+
+		adminURL, _ := cmd.Flags().GetString("admin-url")
+		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
+		pipelines, err := Status(ctx, adminURL)
+		fmt.Println()
+		fmt.Println("Pipeline Status:")
+		fmt.Println("================")
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		printPipelineStatus(pipelines)
 	},
 }
 
+// printPipelineStatus renders the result of Status for conduit status
+func printPipelineStatus(status PipelineStatus) {
+	if len(status.Pipelines) == 0 {
+		fmt.Printf("No pipelines configured at %s\n", status.AdminURL)
+		return
+	}
+	for _, p := range status.Pipelines {
+		icon := "✅"
+		if p.State != PipelineRunning {
+			icon = "❌"
+		}
+		fmt.Printf("%s %-28s %-15s %d messages\n", icon, p.Name, p.State, p.Messages)
+		if p.Error != "" {
+			fmt.Printf("   error: %s\n", p.Error)
+		}
+		for _, c := range p.Connectors {
+			fmt.Printf("   %-12s %-22s %-30s %d messages\n", c.Type, c.Name, c.Plugin, c.Messages)
+		}
+	}
+}
+
 
 // conduitRestartCmd restarts Conduit and connectors
 var conduitRestartCmd = &cobra.Command{
@@ -258,6 +295,7 @@ func init() {
 	// Add debug flag to all commands
 	conduitStartCmd.Flags().Bool("debug", false, "Enable debug logging")
 	conduitRestartCmd.Flags().Bool("debug", false, "Enable debug logging")
+	conduitStatusCmd.Flags().String("admin-url", DefaultAdminURL, "Conduit HTTP API address for pipeline status")
 
 	// Add subcommands to root
 	Cmd.AddCommand(conduitStartCmd)
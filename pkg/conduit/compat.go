@@ -0,0 +1,138 @@
+package conduit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/joeblew999/infra/pkg/config"
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// StrictCompatibility makes Ensure fail, instead of warn, when an installed
+// connector is known to be incompatible with the installed Conduit core.
+var StrictCompatibility bool
+
+// Incompatibility describes a connector that won't work with the installed core.
+type Incompatibility struct {
+	Connector        string `json:"connector"`
+	ConnectorVersion string `json:"connector_version"`
+	CoreVersion      string `json:"core_version"`
+	Reason           string `json:"reason"`
+}
+
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("%s %s is incompatible with conduit %s: %s", i.Connector, i.ConnectorVersion, i.CoreVersion, i.Reason)
+}
+
+// CheckCompatibility compares the installed connectors against the installed
+// core using each connector's min_core_version/max_core_version range.
+// Connectors that aren't installed are skipped; a missing core is an error.
+func CheckCompatibility() ([]Incompatibility, error) {
+	if err := loadCoreConfig(); err != nil {
+		return nil, fmt.Errorf("failed to load Conduit core configuration: %w", err)
+	}
+	if err := loadConnectorsConfig(); err != nil {
+		return nil, fmt.Errorf("failed to load connectors configuration: %w", err)
+	}
+
+	coreVersion, ok := installedVersion(coreConfig.Conduit.Name)
+	if !ok {
+		return nil, fmt.Errorf("conduit core is not installed")
+	}
+	return checkCompatibility(coreVersion, connectorsConfig.Connectors, installedVersion), nil
+}
+
+// checkCompatibility applies each connector's core version range to
+// coreVersion. installed reports the installed version of a connector.
+func checkCompatibility(coreVersion string, connectors []ConduitBinary, installed func(name string) (string, bool)) []Incompatibility {
+	var found []Incompatibility
+	for _, c := range connectors {
+		version, ok := installed(c.Name)
+		if !ok {
+			continue
+		}
+		issue := Incompatibility{Connector: c.Name, ConnectorVersion: version, CoreVersion: coreVersion}
+		switch {
+		case c.MinCoreVersion != "" && compareVersions(coreVersion, c.MinCoreVersion) < 0:
+			issue.Reason = "requires conduit " + c.MinCoreVersion + " or newer"
+		case c.MaxCoreVersion != "" && compareVersions(coreVersion, c.MaxCoreVersion) >= 0:
+			issue.Reason = "requires conduit older than " + c.MaxCoreVersion
+		default:
+			continue
+		}
+		found = append(found, issue)
+	}
+	return found
+}
+
+// ensureCompatibility runs after Ensure has installed everything: it logs
+// each incompatibility and, with StrictCompatibility, fails.
+func ensureCompatibility() error {
+	issues, err := CheckCompatibility()
+	if err != nil {
+		return fmt.Errorf("failed to check connector compatibility: %w", err)
+	}
+	var errs []error
+	for _, issue := range issues {
+		log.Warn("Incompatible Conduit connector", "connector", issue.Connector, "version", issue.ConnectorVersion, "core_version", issue.CoreVersion, "reason", issue.Reason)
+		errs = append(errs, errors.New(issue.String()))
+	}
+	if StrictCompatibility {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// installedVersion reads the version recorded in a binary's _meta.json.
+func installedVersion(name string) (string, bool) {
+	path := filepath.Join(config.GetDepPath(), config.GetBinaryName(name))
+	data, err := os.ReadFile(getMetaPath(path))
+	if err != nil {
+		return "", false
+	}
+	var meta BinaryMeta
+	if err := json.Unmarshal(data, &meta); err != nil || meta.Version == "" {
+		return "", false
+	}
+	return meta.Version, true
+}
+
+// compareVersions compares dotted numeric versions such as v0.12.1,
+// ignoring a leading "v" and any pre-release suffix.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
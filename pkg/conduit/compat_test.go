@@ -0,0 +1,65 @@
+package conduit
+
+import "testing"
+
+func TestCheckCompatibility(t *testing.T) {
+	connectors := []ConduitBinary{
+		{Name: "conduit-connector-new", MinCoreVersion: "v0.13.0"},
+		{Name: "conduit-connector-old", MaxCoreVersion: "v0.12.0"},
+		{Name: "conduit-connector-ok", MinCoreVersion: "v0.10.0", MaxCoreVersion: "v0.13.0"},
+		{Name: "conduit-connector-missing", MinCoreVersion: "v1.0.0"},
+	}
+	installed := func(name string) (string, bool) {
+		if name == "conduit-connector-missing" {
+			return "", false
+		}
+		return "v1.2.3", true
+	}
+
+	issues := checkCompatibility("v0.12.1", connectors, installed)
+	if len(issues) != 2 {
+		t.Fatalf("got %d incompatibilities, want 2: %v", len(issues), issues)
+	}
+	if issues[0].Connector != "conduit-connector-new" || issues[0].Reason != "requires conduit v0.13.0 or newer" {
+		t.Errorf("issues[0] = %+v", issues[0])
+	}
+	if issues[1].Connector != "conduit-connector-old" || issues[1].CoreVersion != "v0.12.1" || issues[1].ConnectorVersion != "v1.2.3" {
+		t.Errorf("issues[1] = %+v", issues[1])
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v0.12.1", "v0.12.1", 0},
+		{"v0.12.1", "v0.13.0", -1},
+		{"0.13", "v0.12.9", 1},
+		{"v0.12.0-rc1", "v0.12.0", 0},
+		{"v0.9.0", "v0.10.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBundledConnectorsDeclareCoreRange(t *testing.T) {
+	core := getDefaultCoreConfig().Conduit
+	connectors := getDefaultConnectorsConfig().Connectors
+	for _, c := range connectors {
+		if c.MinCoreVersion == "" || c.MaxCoreVersion == "" {
+			t.Errorf("%s declares no core version range", c.Name)
+		}
+	}
+
+	installed := func(string) (string, bool) { return "v0.0.0", true }
+	if issues := checkCompatibility(core.Version, connectors, installed); len(issues) != 0 {
+		t.Errorf("bundled connectors should accept the bundled core %s: %v", core.Version, issues)
+	}
+	if issues := checkCompatibility(bundledMaxCoreVersion, connectors, installed); len(issues) != len(connectors) {
+		t.Errorf("core %s: got %d incompatibilities, want %d", bundledMaxCoreVersion, len(issues), len(connectors))
+	}
+}
@@ -23,8 +23,27 @@ type ConduitBinary struct {
 	ReleaseURL string              `json:"release_url"`
 	Assets     []dep.AssetSelector `json:"assets"`
 	Type       string              `json:"type"` // "core", "connector", "processor"
+
+	// MinCoreVersion and MaxCoreVersion (exclusive) bound the Conduit core
+	// versions a connector works with; see CheckCompatibility.
+	MinCoreVersion string `json:"min_core_version,omitempty"`
+	MaxCoreVersion string `json:"max_core_version,omitempty"`
+
+	// Optional binaries that fail to install are logged and skipped instead
+	// of failing Ensure.
+	Optional bool `json:"optional,omitempty"`
 }
 
+// bundledMinCoreVersion and bundledMaxCoreVersion (exclusive) are the core
+// versions the bundled connector pins were checked against. Conduit is
+// pre-1.0 and a minor release can change the connector protocol, so the range
+// stops at the next minor: bumping the core past it warns until the pins are
+// checked again and the range moved.
+const (
+	bundledMinCoreVersion = "v0.12.0"
+	bundledMaxCoreVersion = "v0.13.0"
+)
+
 //go:embed config/*.json
 var embeddedConfigs embed.FS
 
@@ -44,6 +63,11 @@ var processorsConfig = struct {
 // ConfigOverride allows runtime override of config directory
 var ConfigOverride string
 
+// EnableNATSConnector opts in to installing the NATS JetStream connector
+// alongside the other connectors. Its pin has not been checked against a
+// release yet, so it is off by default and a failed install only warns.
+var EnableNATSConnector bool
+
 // Ensure downloads and prepares all Conduit binaries from separate configuration files
 func Ensure(debug bool) error {
 	log.Info("Ensuring Conduit binaries...")
@@ -69,8 +93,16 @@ func Ensure(debug bool) error {
 	}
 
 	// Ensure all connectors
-	for _, connector := range connectorsConfig.Connectors {
+	connectors := connectorsConfig.Connectors
+	if EnableNATSConnector {
+		connectors = ensureNATSConnector(connectors)
+	}
+	for _, connector := range connectors {
 		if err := ensureBinary(connector, debug); err != nil {
+			if connector.Optional {
+				log.Warn("Skipping optional connector", "name", connector.Name, "error", err)
+				continue
+			}
 			return fmt.Errorf("failed to ensure connector %s: %w", connector.Name, err)
 		}
 	}
@@ -82,6 +114,10 @@ func Ensure(debug bool) error {
 		}
 	}
 
+	if err := ensureCompatibility(); err != nil {
+		return err
+	}
+
 	log.Info("Conduit binaries ensured.")
 	return nil
 }
@@ -336,11 +372,13 @@ func getDefaultConnectorsConfig() struct {
 	}{
 		Connectors: []ConduitBinary{
 			{
-				Name:       "conduit-connector-s3",
-				Repo:       "ConduitIO/conduit-connector-s3",
-				Version:    "v0.9.3",
-				ReleaseURL: "https://github.com/ConduitIO/conduit-connector-s3/releases/tag/v0.9.3",
-				Type:       "connector",
+				Name:           "conduit-connector-s3",
+				Repo:           "ConduitIO/conduit-connector-s3",
+				Version:        "v0.9.3",
+				ReleaseURL:     "https://github.com/ConduitIO/conduit-connector-s3/releases/tag/v0.9.3",
+				Type:           "connector",
+				MinCoreVersion: bundledMinCoreVersion,
+				MaxCoreVersion: bundledMaxCoreVersion,
 				Assets: []dep.AssetSelector{
 					{OS: "darwin", Arch: "amd64", Match: `conduit-connector-s3_.*_darwin_amd64\.tar\.gz$`},
 					{OS: "darwin", Arch: "arm64", Match: `conduit-connector-s3_.*_darwin_arm64\.tar\.gz$`},
@@ -350,11 +388,13 @@ func getDefaultConnectorsConfig() struct {
 				},
 			},
 			{
-				Name:       "conduit-connector-postgres",
-				Repo:       "ConduitIO/conduit-connector-postgres",
-				Version:    "v0.14.0",
-				ReleaseURL: "https://github.com/ConduitIO/conduit-connector-postgres/releases/tag/v0.14.0",
-				Type:       "connector",
+				Name:           "conduit-connector-postgres",
+				Repo:           "ConduitIO/conduit-connector-postgres",
+				Version:        "v0.14.0",
+				ReleaseURL:     "https://github.com/ConduitIO/conduit-connector-postgres/releases/tag/v0.14.0",
+				Type:           "connector",
+				MinCoreVersion: bundledMinCoreVersion,
+				MaxCoreVersion: bundledMaxCoreVersion,
 				Assets: []dep.AssetSelector{
 					{OS: "darwin", Arch: "amd64", Match: `conduit-connector-postgres_.*_darwin_amd64\.tar\.gz$`},
 					{OS: "darwin", Arch: "arm64", Match: `conduit-connector-postgres_.*_darwin_arm64\.tar\.gz$`},
@@ -364,10 +404,12 @@ func getDefaultConnectorsConfig() struct {
 				},
 			},
 			{
-				Name:    "conduit-connector-kafka",
-				Repo:    "ConduitIO/conduit-connector-kafka",
-				Version: "v0.8.0",
-				Type:    "connector",
+				Name:           "conduit-connector-kafka",
+				Repo:           "ConduitIO/conduit-connector-kafka",
+				Version:        "v0.8.0",
+				Type:           "connector",
+				MinCoreVersion: bundledMinCoreVersion,
+				MaxCoreVersion: bundledMaxCoreVersion,
 				Assets: []dep.AssetSelector{
 					{OS: "darwin", Arch: "amd64", Match: `conduit-connector-kafka_.*_darwin_amd64\.tar\.gz$`},
 					{OS: "darwin", Arch: "arm64", Match: `conduit-connector-kafka_.*_darwin_arm64\.tar\.gz$`},
@@ -377,10 +419,12 @@ func getDefaultConnectorsConfig() struct {
 				},
 			},
 			{
-				Name:    "conduit-connector-file",
-				Repo:    "ConduitIO/conduit-connector-file",
-				Version: "v0.7.0",
-				Type:    "connector",
+				Name:           "conduit-connector-file",
+				Repo:           "ConduitIO/conduit-connector-file",
+				Version:        "v0.7.0",
+				Type:           "connector",
+				MinCoreVersion: bundledMinCoreVersion,
+				MaxCoreVersion: bundledMaxCoreVersion,
 				Assets: []dep.AssetSelector{
 					{OS: "darwin", Arch: "amd64", Match: `conduit-connector-file_.*_darwin_amd64\.tar\.gz$`},
 					{OS: "darwin", Arch: "arm64", Match: `conduit-connector-file_.*_darwin_arm64\.tar\.gz$`},
@@ -0,0 +1,125 @@
+package conduit
+
+import (
+	"github.com/joeblew999/infra/pkg/config"
+	"github.com/joeblew999/infra/pkg/dep"
+)
+
+// NATSConnectorName is the binary name of the NATS JetStream connector.
+const NATSConnectorName = "conduit-connector-nats-jetstream"
+
+// NATSPlugin is how Conduit pipeline files refer to the standalone NATS
+// JetStream connector.
+const NATSPlugin = "standalone:nats-jetstream"
+
+// Connector types in a pipeline configuration
+const (
+	ConnectorSource      = "source"
+	ConnectorDestination = "destination"
+)
+
+// ConnectorConfig is one entry of a pipeline's "connectors" list in a Conduit
+// pipeline configuration file.
+type ConnectorConfig struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Plugin   string            `json:"plugin"`
+	Name     string            `json:"name,omitempty"`
+	Settings map[string]string `json:"settings"`
+}
+
+// NATSOption customises a connector built by NATSSource or NATSDestination.
+type NATSOption func(*ConnectorConfig)
+
+// WithConnectorID overrides the default "nats-source"/"nats-destination" ID,
+// needed when a pipeline has more than one NATS connector.
+func WithConnectorID(id string) NATSOption {
+	return func(c *ConnectorConfig) { c.ID = id }
+}
+
+// WithConnectionName sets the client name shown in NATS monitoring.
+func WithConnectionName(name string) NATSOption {
+	return WithNATSSetting("connectionName", name)
+}
+
+// WithCredentialsFile authenticates with a NATS .creds file.
+func WithCredentialsFile(path string) NATSOption {
+	return WithNATSSetting("credentialsFilePath", path)
+}
+
+// WithDurableName sets the JetStream durable consumer name of a source, so
+// it resumes where it left off after a restart.
+func WithDurableName(name string) NATSOption {
+	return WithNATSSetting("durableName", name)
+}
+
+// WithNATSSetting sets any other connector setting by its raw key.
+func WithNATSSetting(key, value string) NATSOption {
+	return func(c *ConnectorConfig) { c.Settings[key] = value }
+}
+
+// NATSSource returns a connector reading subject from the JetStream server
+// at url, or from the infra NATS server when url is empty.
+func NATSSource(subject, url string, opts ...NATSOption) ConnectorConfig {
+	return natsConnector(ConnectorSource, subject, url, opts)
+}
+
+// NATSDestination returns a connector publishing to subject on the
+// JetStream server at url, or on the infra NATS server when url is empty.
+func NATSDestination(subject, url string, opts ...NATSOption) ConnectorConfig {
+	return natsConnector(ConnectorDestination, subject, url, opts)
+}
+
+func natsConnector(typ, subject, url string, opts []NATSOption) ConnectorConfig {
+	if url == "" {
+		url = config.GetNATSURL()
+	}
+	c := ConnectorConfig{
+		ID:     "nats-" + typ,
+		Type:   typ,
+		Plugin: NATSPlugin,
+		Settings: map[string]string{
+			"urls":    url,
+			"subject": subject,
+		},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// natsConnectorBinary is the connectors.json entry for the NATS JetStream
+// connector. The release pin and asset names have not been checked against
+// the upstream release, so the entry is optional: installing it must not
+// break the rest of the stack.
+func natsConnectorBinary() ConduitBinary {
+	return ConduitBinary{
+		Name:           NATSConnectorName,
+		Repo:           "ConduitIO/conduit-connector-nats-jetstream",
+		Version:        "v0.4.0",
+		ReleaseURL:     "https://github.com/ConduitIO/conduit-connector-nats-jetstream/releases/tag/v0.4.0",
+		Type:           "connector",
+		MinCoreVersion: bundledMinCoreVersion,
+		MaxCoreVersion: bundledMaxCoreVersion,
+		Optional:       true,
+		Assets: []dep.AssetSelector{
+			{OS: "darwin", Arch: "amd64", Match: `conduit-connector-nats-jetstream_.*_darwin_amd64\.tar\.gz$`},
+			{OS: "darwin", Arch: "arm64", Match: `conduit-connector-nats-jetstream_.*_darwin_arm64\.tar\.gz$`},
+			{OS: "linux", Arch: "amd64", Match: `conduit-connector-nats-jetstream_.*_linux_amd64\.tar\.gz$`},
+			{OS: "linux", Arch: "arm64", Match: `conduit-connector-nats-jetstream_.*_linux_arm64\.tar\.gz$`},
+			{OS: "windows", Arch: "amd64", Match: `conduit-connector-nats-jetstream_.*_windows_amd64\.zip$`},
+		},
+	}
+}
+
+// ensureNATSConnector adds the NATS connector to connectors unless they
+// already list it; Ensure uses it when EnableNATSConnector is set.
+func ensureNATSConnector(connectors []ConduitBinary) []ConduitBinary {
+	for _, c := range connectors {
+		if c.Name == NATSConnectorName {
+			return connectors
+		}
+	}
+	return append(connectors, natsConnectorBinary())
+}
@@ -0,0 +1,48 @@
+package conduit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNATSConnectors(t *testing.T) {
+	src := NATSSource("orders.>", "nats://nats.internal:4222", WithDurableName("conduit-orders"))
+	if src.ID != "nats-source" || src.Type != ConnectorSource || src.Plugin != NATSPlugin {
+		t.Errorf("source = %+v", src)
+	}
+	if src.Settings["urls"] != "nats://nats.internal:4222" || src.Settings["subject"] != "orders.>" || src.Settings["durableName"] != "conduit-orders" {
+		t.Errorf("source settings = %v", src.Settings)
+	}
+
+	dst := NATSDestination("audit", "", WithConnectorID("audit-out"), WithCredentialsFile("/etc/nats/app.creds"))
+	if dst.ID != "audit-out" || dst.Type != ConnectorDestination || dst.Settings["urls"] == "" {
+		t.Errorf("destination = %+v", dst)
+	}
+
+	data, err := json.Marshal(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var block map[string]any
+	if err := json.Unmarshal(data, &block); err != nil || block["plugin"] != NATSPlugin {
+		t.Errorf("marshalled block = %s, %v", data, err)
+	}
+}
+
+func TestEnsureNATSConnector(t *testing.T) {
+	got := ensureNATSConnector(nil)
+	if len(got) != 1 || got[0].Name != NATSConnectorName {
+		t.Fatalf("missing NATS connector not added: %+v", got)
+	}
+	if !got[0].Optional {
+		t.Error("the NATS connector must be optional so a failed install does not fail Ensure")
+	}
+	if again := ensureNATSConnector(got); len(again) != 1 {
+		t.Errorf("NATS connector added twice: %+v", again)
+	}
+	for _, c := range getDefaultConnectorsConfig().Connectors {
+		if c.Name == NATSConnectorName {
+			t.Error("the NATS connector must be opt-in, not a default")
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package conduit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultAdminURL is where Conduit serves its HTTP API unless started with
+// -http.address.
+const DefaultAdminURL = "http://localhost:8080"
+
+// ErrNotRunning is returned by Status when nothing answers at the admin URL.
+var ErrNotRunning = errors.New("conduit is not running")
+
+// Pipeline states, from Conduit's STATUS_* values
+const (
+	PipelineRunning       = "running"
+	PipelineUserStopped   = "user_stopped"
+	PipelineSystemStopped = "system_stopped"
+	PipelineDegraded      = "degraded"
+)
+
+// PipelineStatus is a snapshot of every pipeline in a Conduit instance.
+type PipelineStatus struct {
+	AdminURL  string         `json:"admin_url"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Pipelines []PipelineInfo `json:"pipelines"`
+}
+
+// PipelineInfo describes one pipeline and its connectors.
+type PipelineInfo struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	State      string            `json:"state"`
+	Error      string            `json:"error,omitempty"`
+	Messages   int64             `json:"messages"`
+	Connectors []ConnectorStatus `json:"connectors"`
+}
+
+// ConnectorStatus describes one connector in a pipeline. Messages counts
+// records the connector has read or written, as far as Conduit's metrics
+// report them.
+type ConnectorStatus struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"` // "source" or "destination"
+	Plugin   string `json:"plugin"`
+	Messages int64  `json:"messages"`
+}
+
+// Healthy reports whether every pipeline is running.
+func (s PipelineStatus) Healthy() bool {
+	for _, p := range s.Pipelines {
+		if p.State != PipelineRunning {
+			return false
+		}
+	}
+	return true
+}
+
+// Status queries the Conduit HTTP API at adminURL for pipeline state,
+// connectors and message counts. It returns ErrNotRunning when Conduit
+// isn't listening there.
+func Status(ctx context.Context, adminURL string) (PipelineStatus, error) {
+	if adminURL == "" {
+		adminURL = DefaultAdminURL
+	}
+	adminURL = strings.TrimRight(adminURL, "/")
+	status := PipelineStatus{AdminURL: adminURL, CheckedAt: time.Now()}
+
+	var pipelines []apiPipeline
+	if err := getJSON(ctx, adminURL+"/v1/pipelines", &pipelines); err != nil {
+		return status, err
+	}
+	var connectors []apiConnector
+	if err := getJSON(ctx, adminURL+"/v1/connectors", &connectors); err != nil {
+		return status, err
+	}
+	// Message counts are best effort: /metrics may be disabled or proxied away.
+	counts, _ := getMetrics(ctx, adminURL+"/metrics")
+
+	byPipeline := make(map[string][]apiConnector)
+	for _, c := range connectors {
+		byPipeline[c.PipelineID] = append(byPipeline[c.PipelineID], c)
+	}
+
+	for _, p := range pipelines {
+		info := PipelineInfo{
+			ID:       p.ID,
+			Name:     p.Config.Name,
+			State:    pipelineState(p.State.Status),
+			Error:    p.State.Error,
+			Messages: counts.pipelines[p.Config.Name],
+		}
+		for _, c := range byPipeline[p.ID] {
+			typ := connectorType(c.Type)
+			info.Connectors = append(info.Connectors, ConnectorStatus{
+				ID:       c.ID,
+				Name:     c.Config.Name,
+				Type:     typ,
+				Plugin:   c.Plugin,
+				Messages: counts.connectors[connectorKey{p.Config.Name, c.Plugin, typ}],
+			})
+		}
+		status.Pipelines = append(status.Pipelines, info)
+	}
+	return status, nil
+}
+
+// apiPipeline and apiConnector mirror the parts of Conduit's v1 API we use.
+type apiPipeline struct {
+	ID    string `json:"id"`
+	State struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	} `json:"state"`
+	Config struct {
+		Name string `json:"name"`
+	} `json:"config"`
+}
+
+type apiConnector struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Plugin     string `json:"plugin"`
+	PipelineID string `json:"pipelineId"`
+	Config     struct {
+		Name string `json:"name"`
+	} `json:"config"`
+}
+
+// pipelineState maps "STATUS_RUNNING" and friends to the Pipeline* constants.
+func pipelineState(s string) string {
+	return strings.ToLower(strings.TrimPrefix(s, "STATUS_"))
+}
+
+// connectorType maps "TYPE_SOURCE" to "source".
+func connectorType(s string) string {
+	return strings.ToLower(strings.TrimPrefix(s, "TYPE_"))
+}
+
+func getJSON(ctx context.Context, url string, v any) error {
+	resp, err := get(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return nil
+}
+
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("%w (nothing listening at %s)", ErrNotRunning, url)
+		}
+		return nil, fmt.Errorf("failed to query %s: %w", url, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned %d: %s", url, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+type connectorKey struct{ pipeline, plugin, typ string }
+
+type messageCounts struct {
+	pipelines  map[string]int64
+	connectors map[connectorKey]int64
+}
+
+// Conduit records a histogram sample per processed record, so the _count
+// series are message counts.
+const (
+	pipelineMetric  = "conduit_pipeline_execution_duration_seconds_count"
+	connectorMetric = "conduit_connector_execution_duration_seconds_count"
+)
+
+// getMetrics reads message counts from Conduit's Prometheus endpoint.
+func getMetrics(ctx context.Context, url string) (messageCounts, error) {
+	counts := messageCounts{pipelines: map[string]int64{}, connectors: map[connectorKey]int64{}}
+	resp, err := get(ctx, url)
+	if err != nil {
+		return counts, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		name, labels, value, ok := parseMetricLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		switch name {
+		case pipelineMetric:
+			counts.pipelines[labels["pipeline_name"]] += value
+		case connectorMetric:
+			key := connectorKey{labels["pipeline_name"], labels["plugin"], labels["type"]}
+			counts.connectors[key] += value
+		}
+	}
+	return counts, scanner.Err()
+}
+
+// parseMetricLine parses a Prometheus text sample such as
+// `name{a="b",c="d"} 42`. Comments and malformed lines return ok=false.
+func parseMetricLine(line string) (name string, labels map[string]string, value int64, ok bool) {
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil, 0, false
+	}
+	series, rest, found := strings.Cut(line, " ")
+	if !found {
+		return "", nil, 0, false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, 0, false
+	}
+	f, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return "", nil, 0, false
+	}
+
+	labels = map[string]string{}
+	name, labelPart, hasLabels := strings.Cut(series, "{")
+	if hasLabels {
+		for _, pair := range strings.Split(strings.TrimSuffix(labelPart, "}"), ",") {
+			k, v, found := strings.Cut(pair, "=")
+			if found {
+				labels[k] = strings.Trim(v, `"`)
+			}
+		}
+	}
+	return name, labels, int64(f), true
+}
@@ -0,0 +1,65 @@
+package conduit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/pipelines", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id":"p1","state":{"status":"STATUS_RUNNING"},"config":{"name":"orders"}},
+			{"id":"p2","state":{"status":"STATUS_DEGRADED","error":"destination unreachable"},"config":{"name":"audit"}}
+		]`))
+	})
+	mux.HandleFunc("GET /v1/connectors", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id":"c1","type":"TYPE_SOURCE","plugin":"builtin:postgres","pipelineId":"p1","config":{"name":"pg"}},
+			{"id":"c2","type":"TYPE_DESTINATION","plugin":"builtin:s3","pipelineId":"p1","config":{"name":"bucket"}}
+		]`))
+	})
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`# HELP conduit_pipeline_execution_duration_seconds Amount of time records spent in a pipeline.
+# TYPE conduit_pipeline_execution_duration_seconds histogram
+conduit_pipeline_execution_duration_seconds_count{pipeline_name="orders"} 120
+conduit_connector_execution_duration_seconds_count{pipeline_name="orders",plugin="builtin:postgres",type="source"} 120
+conduit_connector_execution_duration_seconds_count{pipeline_name="orders",plugin="builtin:s3",type="destination"} 118
+`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	status, err := Status(context.Background(), srv.URL+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Pipelines) != 2 || status.Healthy() {
+		t.Fatalf("status = %+v, want two pipelines, not all healthy", status)
+	}
+
+	orders := status.Pipelines[0]
+	if orders.State != PipelineRunning || orders.Messages != 120 || len(orders.Connectors) != 2 {
+		t.Errorf("orders = %+v", orders)
+	}
+	if c := orders.Connectors[1]; c.Type != "destination" || c.Name != "bucket" || c.Messages != 118 {
+		t.Errorf("destination connector = %+v", c)
+	}
+	audit := status.Pipelines[1]
+	if audit.State != PipelineDegraded || audit.Error != "destination unreachable" || len(audit.Connectors) != 0 {
+		t.Errorf("audit = %+v", audit)
+	}
+}
+
+func TestStatusNotRunning(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	if _, err := Status(context.Background(), url); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("err = %v, want ErrNotRunning", err)
+	}
+}
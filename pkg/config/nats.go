@@ -5,10 +5,12 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const (
-	EnvVarNATSHost = "NATS_HOST"
+	EnvVarNATSHost             = "NATS_HOST"
+	EnvVarNATSReadinessTimeout = "NATS_READINESS_TIMEOUT"
 
 	NATSLogStreamName    = "LOGS"
 	NATSLogStreamSubject = "logs.app"
@@ -30,7 +32,8 @@ const (
 	NATSApplicationUserName = "infra"
 	NATSSystemUserName      = "sys"
 
-	defaultNATSHost = "localhost"
+	defaultNATSHost             = "localhost"
+	defaultNATSReadinessTimeout = 60 * time.Second
 )
 
 func GetNATSPort() string {
@@ -107,6 +110,18 @@ func GetNATSClusterName() string {
 	return NATSClusterNameLocal
 }
 
+// GetNATSClusterReadinessTimeout returns how long a rolling upgrade waits for
+// a restarted node to rejoin the cluster. Override with NATS_READINESS_TIMEOUT
+// (a Go duration such as "2m").
+func GetNATSClusterReadinessTimeout() time.Duration {
+	if value := os.Getenv(EnvVarNATSReadinessTimeout); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultNATSReadinessTimeout
+}
+
 func GetNATSDockerImage() string {
 	return NATSDockerImage
 }
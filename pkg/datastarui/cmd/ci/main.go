@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	wf "github.com/joeblew999/infra/pkg/datastarui/internal/workflow"
+)
+
+const version = "v0.1.0"
+
+// ci runs codegen and the Playwright suite back to back under one timeout,
+// stopping at the first failing stage.
+func main() {
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("locate working directory: %v", err)
+	}
+
+	defaultSrc := filepath.Join(wd, "pkg/datastarui/sampleapp")
+
+	cfg := wf.DefaultConfig()
+	opts := wf.RegisterFlags(flag.CommandLine, cfg, 7*time.Minute)
+	flag.Parse()
+
+	if opts.ShowVersion {
+		fmt.Println("datastarui ci", version)
+		return
+	}
+
+	srcDir, err := opts.ResolveSource(defaultSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts.Apply(&cfg)
+
+	if err := wf.CheckToolchain(cfg.Workflow); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	opts.Report("datastarui ci", version, srcDir, cfg)
+
+	log.Println("Stage 1/2: code generation")
+	if err := wf.Prepare(ctx, srcDir, cfg); err != nil {
+		log.Fatalf("code generation failed: %v", err)
+	}
+
+	log.Println("Stage 2/2: playwright suite")
+	if err := wf.Test(ctx, srcDir, cfg); err != nil {
+		log.Fatalf("playwright run failed: %v", err)
+	}
+
+	log.Println("Code generation and Playwright suite completed successfully")
+}
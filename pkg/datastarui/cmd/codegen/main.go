@@ -6,9 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 	"time"
 
 	wf "github.com/joeblew999/infra/pkg/datastarui/internal/workflow"
@@ -33,32 +31,15 @@ func main() {
 		return
 	}
 
-	srcDir := opts.Src
-	if strings.TrimSpace(srcDir) == "" {
-		srcDir = defaultSrc
-	}
-	srcDir, err = filepath.Abs(srcDir)
+	srcDir, err := opts.ResolveSource(defaultSrc)
 	if err != nil {
-		log.Fatalf("resolve src path: %v", err)
-	}
-
-	if _, err := os.Stat(srcDir); err != nil {
-		log.Fatalf("src path invalid: %v", err)
+		log.Fatal(err)
 	}
 
 	opts.Apply(&cfg)
 
-	switch cfg.Workflow {
-	case wf.WorkflowBun, "":
-		if _, err := exec.LookPath("bun"); err != nil {
-			log.Fatalf("bun runtime not found: %v", err)
-		}
-	case wf.WorkflowNode:
-		if _, err := exec.LookPath("pnpm"); err != nil {
-			log.Fatalf("pnpm binary not found: %v", err)
-		}
-	default:
-		log.Fatalf("unsupported workflow: %s", cfg.Workflow)
+	if err := wf.CheckToolchain(cfg.Workflow); err != nil {
+		log.Fatal(err)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
@@ -2,7 +2,11 @@ package workflow
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -25,7 +29,7 @@ type CLIOptions struct {
 // RegisterFlags populates the flag set with shared options and returns a struct
 // that will receive the parsed values.
 func RegisterFlags(fs *flag.FlagSet, cfg Config, defaultTimeout time.Duration) *CLIOptions {
-    opts := &CLIOptions{}
+	opts := &CLIOptions{}
 
 	fs.StringVar(&opts.TailwindInput, "tailwind-input", cfg.TailwindInput, "path to Tailwind input file")
 	fs.StringVar(&opts.TailwindOutput, "tailwind-output", cfg.TailwindOutput, "path to Tailwind output file")
@@ -77,6 +81,36 @@ func (o *CLIOptions) Report(runner, version, src string, cfg Config) {
 	log.Printf("config: src=%s tailwind-in=%s tailwind-out=%s content=%v binary=%s base-url=%s server=%v workflow=%s headed=%t", src, cfg.TailwindInput, cfg.TailwindOutput, cfg.TailwindContent, cfg.Binary, cfg.BaseURL, cfg.ServerCommand, cfg.Workflow, cfg.Headed)
 }
 
+// ResolveSource returns the absolute source directory, falling back to
+// defaultSrc when no --src was given, and checks that it exists.
+func (o *CLIOptions) ResolveSource(defaultSrc string) (string, error) {
+	srcDir := o.Src
+	if strings.TrimSpace(srcDir) == "" {
+		srcDir = defaultSrc
+	}
+	srcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return "", fmt.Errorf("resolve src path: %w", err)
+	}
+	if _, err := os.Stat(srcDir); err != nil {
+		return "", fmt.Errorf("src path invalid: %w", err)
+	}
+	return srcDir, nil
+}
+
+// CheckToolchain verifies the package manager required by the selected
+// workflow is on PATH.
+func CheckToolchain(mode WorkflowMode) error {
+	tc, err := selectToolchain(mode)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(tc.install[0]); err != nil {
+		return fmt.Errorf("%s not found: %w", tc.install[0], err)
+	}
+	return nil
+}
+
 func splitCSV(input string) []string {
 	parts := strings.Split(input, ",")
 	result := make([]string, 0, len(parts))
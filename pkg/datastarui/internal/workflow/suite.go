@@ -63,7 +63,13 @@ func Run(ctx context.Context, sourceDir string, cfg Config) error {
 	if err := Prepare(ctx, sourceDir, cfg); err != nil {
 		return err
 	}
+	return Test(ctx, sourceDir, cfg)
+}
 
+// Test starts the server and runs the Playwright suite without rebuilding
+// anything first. Use it after Prepare when the two stages should be reported
+// separately.
+func Test(ctx context.Context, sourceDir string, cfg Config) error {
 	srvCmd, err := startServer(ctx, sourceDir, cfg)
 	if err != nil {
 		return fmt.Errorf("start server: %w", err)
@@ -74,12 +80,12 @@ func Run(ctx context.Context, sourceDir string, cfg Config) error {
 		return fmt.Errorf("wait for server: %w", err)
 	}
 
-	playwrightRunner, err := selectPlaywrightRunner(cfg.Workflow)
+	tc, err := selectToolchain(cfg.Workflow)
 	if err != nil {
 		return err
 	}
 
-	if err := runCmd(ctx, sourceDir, os.Environ(), playwrightRunner.install[0], playwrightRunner.install[1:]...); err != nil {
+	if err := runCmd(ctx, sourceDir, os.Environ(), tc.playwrightInstall[0], tc.playwrightInstall[1:]...); err != nil {
 		return fmt.Errorf("playwright install failed: %w", err)
 	}
 
@@ -87,7 +93,7 @@ func Run(ctx context.Context, sourceDir string, cfg Config) error {
 	if cfg.Headed {
 		env = append(env, "PLAYWRIGHT_HEADED=1")
 	}
-	if err := runCmd(ctx, sourceDir, env, playwrightRunner.test[0], playwrightRunner.test[1:]...); err != nil {
+	if err := runCmd(ctx, sourceDir, env, tc.playwrightTest[0], tc.playwrightTest[1:]...); err != nil {
 		return fmt.Errorf("playwright suite failed: %w", err)
 	}
 
@@ -99,35 +105,21 @@ func Run(ctx context.Context, sourceDir string, cfg Config) error {
 // date. Call this when a package only needs refreshed assets without running
 // the Playwright suite.
 func Prepare(ctx context.Context, sourceDir string, cfg Config) error {
-	switch cfg.Workflow {
-	case "", WorkflowBun:
-		if err := runBunInstall(ctx, sourceDir); err != nil {
-			return fmt.Errorf("bun install failed: %w", err)
-		}
-
-		if err := runTemplGenerate(ctx, sourceDir); err != nil {
-			return fmt.Errorf("templ generate failed: %w", err)
-		}
-
-		if err := rebuildTailwindWith(ctx, sourceDir, cfg, []string{"bun", "x", "tailwindcss"}); err != nil {
-			return fmt.Errorf("tailwind rebuild failed: %w", err)
-		}
-
-	case WorkflowNode:
-		if err := runPnpmInstall(ctx, sourceDir); err != nil {
-			return fmt.Errorf("pnpm install failed: %w", err)
-		}
+	tc, err := selectToolchain(cfg.Workflow)
+	if err != nil {
+		return err
+	}
 
-		if err := runTemplGenerate(ctx, sourceDir); err != nil {
-			return fmt.Errorf("templ generate failed: %w", err)
-		}
+	if err := runCmd(ctx, sourceDir, os.Environ(), tc.install[0], tc.install[1:]...); err != nil {
+		return fmt.Errorf("%s install failed: %w", tc.install[0], err)
+	}
 
-		if err := rebuildTailwindWith(ctx, sourceDir, cfg, []string{"pnpm", "exec", "tailwindcss"}); err != nil {
-			return fmt.Errorf("tailwind rebuild failed: %w", err)
-		}
+	if err := runTemplGenerate(ctx, sourceDir); err != nil {
+		return fmt.Errorf("templ generate failed: %w", err)
+	}
 
-	default:
-		return fmt.Errorf("unsupported workflow: %s", cfg.Workflow)
+	if err := rebuildTailwindWith(ctx, sourceDir, cfg, tc.tailwind); err != nil {
+		return fmt.Errorf("tailwind rebuild failed: %w", err)
 	}
 
 	if err := runGoBuild(ctx, sourceDir, cfg); err != nil {
@@ -137,14 +129,6 @@ func Prepare(ctx context.Context, sourceDir string, cfg Config) error {
 	return nil
 }
 
-func runBunInstall(ctx context.Context, sourceDir string) error {
-	return runCmd(ctx, sourceDir, os.Environ(), "bun", "install")
-}
-
-func runPnpmInstall(ctx context.Context, sourceDir string) error {
-	return runCmd(ctx, sourceDir, os.Environ(), "pnpm", "install")
-}
-
 func runTemplGenerate(ctx context.Context, sourceDir string) error {
 	return runCmd(ctx, sourceDir, os.Environ(), "templ", "generate")
 }
@@ -287,24 +271,32 @@ func waitForHTTP(url string, timeout time.Duration) error {
 	return errors.New("timeout waiting for http endpoint")
 }
 
-func selectPlaywrightRunner(mode WorkflowMode) (*playwrightRunner, error) {
+// toolchain lists the package-manager commands a workflow uses for each stage.
+// Both workflows share templ and go build; only the JS side differs.
+type toolchain struct {
+	install           []string
+	tailwind          []string
+	playwrightInstall []string
+	playwrightTest    []string
+}
+
+func selectToolchain(mode WorkflowMode) (*toolchain, error) {
 	switch mode {
 	case "", WorkflowBun:
-		return &playwrightRunner{
-			install: []string{"bun", "x", "playwright", "install"},
-			test:    []string{"bun", "x", "playwright", "test"},
+		return &toolchain{
+			install:           []string{"bun", "install"},
+			tailwind:          []string{"bun", "x", "tailwindcss"},
+			playwrightInstall: []string{"bun", "x", "playwright", "install"},
+			playwrightTest:    []string{"bun", "x", "playwright", "test"},
 		}, nil
 	case WorkflowNode:
-		return &playwrightRunner{
-			install: []string{"pnpm", "exec", "playwright", "install"},
-			test:    []string{"pnpm", "exec", "playwright", "test"},
+		return &toolchain{
+			install:           []string{"pnpm", "install"},
+			tailwind:          []string{"pnpm", "exec", "tailwindcss"},
+			playwrightInstall: []string{"pnpm", "exec", "playwright", "install"},
+			playwrightTest:    []string{"pnpm", "exec", "playwright", "test"},
 		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported workflow: %s", mode)
 	}
 }
-
-type playwrightRunner struct {
-	install []string
-	test    []string
-}
@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSelectToolchain(t *testing.T) {
+	tests := []struct {
+		mode   WorkflowMode
+		runner string
+		other  string
+		prefix []string
+	}{
+		{"", "bun", "pnpm", []string{"bun", "x"}},
+		{WorkflowBun, "bun", "pnpm", []string{"bun", "x"}},
+		{WorkflowNode, "pnpm", "bun", []string{"pnpm", "exec"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			tc, err := selectToolchain(tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := []string{tt.runner, "install"}; !slices.Equal(tc.install, want) {
+				t.Errorf("install = %v, want %v", tc.install, want)
+			}
+			stages := map[string][]string{
+				"tailwind":           tc.tailwind,
+				"playwright install": tc.playwrightInstall,
+				"playwright test":    tc.playwrightTest,
+			}
+			for name, cmd := range stages {
+				if !slices.Equal(cmd[:2], tt.prefix) {
+					t.Errorf("%s = %v, want prefix %v", name, cmd, tt.prefix)
+				}
+				if slices.Contains(cmd, tt.other) {
+					t.Errorf("%s = %v mixes in %s", name, cmd, tt.other)
+				}
+			}
+			if got := strings.Join(tc.playwrightTest[2:], " "); got != "playwright test" {
+				t.Errorf("playwright test args = %q", got)
+			}
+		})
+	}
+
+	if _, err := selectToolchain("yarn"); err == nil {
+		t.Error("expected error for unsupported workflow")
+	}
+}
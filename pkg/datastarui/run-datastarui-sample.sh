@@ -11,9 +11,10 @@ fi
 
 if [[ $# -lt 1 ]]; then
   cat <<USAGE >&2
-Usage: $(basename "$0") <codegen|playwright|test|serve-test> [args...]
+Usage: $(basename "$0") <codegen|playwright|ci|test|serve-test> [args...]
   codegen       Run code generation against the sample app (--src injected).
   playwright    Run the Playwright helper against the sample app (--src injected).
+  ci            Run codegen then the Playwright suite in one pass (--src injected).
   test          Run Go tests for the sample app (default ./sampleapp).
   serve-test    Run Go tests, then launch the sample app server for manual inspection.
 USAGE
@@ -30,6 +31,9 @@ case "$command" in
   playwright)
     (cd "$SCRIPT_DIR" && GOWORK=off go run ./cmd/playwright --src "$APP_DIR" "$@")
     ;;
+  ci)
+    (cd "$SCRIPT_DIR" && GOWORK=off go run ./cmd/ci --src "$APP_DIR" "$@")
+    ;;
   test)
     if [[ $# -eq 0 ]]; then
       set -- ./sampleapp
@@ -1,6 +1,8 @@
 package build
 
 import (
+	"runtime"
+
 	"github.com/joeblew999/infra/pkg/deck"
 	"github.com/spf13/cobra"
 )
@@ -13,21 +15,39 @@ var testCmd = &cobra.Command{
 
 This command runs tests from the golden_tests.json catalog, verifying that
 the deck binary pipeline (decksh → XML → [decksvg|deckpng|deckpdf]) produces
-expected outputs for known good input files.`,
+expected outputs for known good input files.
+
+PNG outputs are compared pixel by pixel with a small tolerance for
+antialiasing noise; pass --strict to require identical pixels.`,
+}
+
+var (
+	testStrict   bool
+	testParallel int
+)
+
+// newTestRunner creates a golden test runner honouring --strict.
+func newTestRunner() (*deck.GoldenTestRunner, error) {
+	runner, err := deck.NewGoldenTestRunner(deck.GetBuildRoot())
+	if err != nil {
+		return nil, err
+	}
+	if testStrict {
+		runner.SetImageTolerance(deck.StrictImageTolerance)
+	}
+	return runner, nil
 }
 
 var testAllCmd = &cobra.Command{
 	Use:   "all",
 	Short: "Run all golden tests",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		buildDir := deck.GetBuildRoot()
-
-		runner, err := deck.NewGoldenTestRunner(buildDir)
+		runner, err := newTestRunner()
 		if err != nil {
 			return err
 		}
 
-		return runner.RunAllTests()
+		return runner.RunAllTestsParallel(testParallel)
 	},
 }
 
@@ -37,9 +57,8 @@ var testCategoryCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		category := args[0]
-		buildDir := deck.GetBuildRoot()
 
-		runner, err := deck.NewGoldenTestRunner(buildDir)
+		runner, err := newTestRunner()
 		if err != nil {
 			return err
 		}
@@ -64,7 +83,9 @@ var testCleanupCmd = &cobra.Command{
 }
 
 func init() {
+	testCmd.PersistentFlags().BoolVar(&testStrict, "strict", false, "Require pixel-exact PNG matches")
+	testAllCmd.Flags().IntVarP(&testParallel, "parallel", "p", runtime.NumCPU(), "Number of tests to run concurrently")
 	testCmd.AddCommand(testAllCmd)
 	testCmd.AddCommand(testCategoryCmd)
 	testCmd.AddCommand(testCleanupCmd)
-}
\ No newline at end of file
+}
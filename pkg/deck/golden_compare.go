@@ -0,0 +1,148 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"regexp"
+)
+
+// ImageTolerance controls how closely a generated PNG must match its golden
+// image.
+type ImageTolerance struct {
+	// PixelThreshold is the largest per-channel difference (0-255) for a
+	// pixel to still count as equal.
+	PixelThreshold uint8
+	// MaxDiffPercent is the share of differing pixels (0-100) a test may have.
+	MaxDiffPercent float64
+}
+
+var (
+	// StrictImageTolerance requires identical pixels.
+	StrictImageTolerance = ImageTolerance{}
+	// DefaultImageTolerance absorbs antialiasing and font hinting noise.
+	DefaultImageTolerance = ImageTolerance{PixelThreshold: 16, MaxDiffPercent: 0.5}
+)
+
+// ImageDiff summarises a pixel comparison.
+type ImageDiff struct {
+	SameSize       bool
+	DiffPixels     int
+	TotalPixels    int
+	MaxChannelDiff uint8
+}
+
+// DiffPercent is the share of differing pixels (0-100).
+func (d ImageDiff) DiffPercent() float64 {
+	if d.TotalPixels == 0 {
+		return 0
+	}
+	return float64(d.DiffPixels) * 100 / float64(d.TotalPixels)
+}
+
+// Within reports whether the diff is acceptable under tol.
+func (d ImageDiff) Within(tol ImageTolerance) bool {
+	return d.SameSize && d.DiffPercent() <= tol.MaxDiffPercent
+}
+
+func (d ImageDiff) String() string {
+	if !d.SameSize {
+		return "image sizes differ"
+	}
+	return fmt.Sprintf("%.2f%% of pixels differ (max channel diff %d)", d.DiffPercent(), d.MaxChannelDiff)
+}
+
+// diffImages compares two images pixel by pixel; a pixel differs when any
+// channel differs by more than threshold.
+func diffImages(a, b image.Image, threshold uint8) ImageDiff {
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Dx() != bb.Dx() || ab.Dy() != bb.Dy() {
+		return ImageDiff{}
+	}
+
+	diff := ImageDiff{SameSize: true, TotalPixels: ab.Dx() * ab.Dy()}
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			r1, g1, b1, a1 := a.At(ab.Min.X+x, ab.Min.Y+y).RGBA()
+			r2, g2, b2, a2 := b.At(bb.Min.X+x, bb.Min.Y+y).RGBA()
+			d := max(channelDiff(r1, r2), channelDiff(g1, g2), channelDiff(b1, b2), channelDiff(a1, a2))
+			if d > diff.MaxChannelDiff {
+				diff.MaxChannelDiff = d
+			}
+			if d > threshold {
+				diff.DiffPixels++
+			}
+		}
+	}
+	return diff
+}
+
+// channelDiff compares two 16-bit color channels at 8-bit precision.
+func channelDiff(c1, c2 uint32) uint8 {
+	v1, v2 := uint8(c1>>8), uint8(c2>>8)
+	if v1 > v2 {
+		return v1 - v2
+	}
+	return v2 - v1
+}
+
+// comparePNGFiles decodes both PNGs and compares them under tol.
+func comparePNGFiles(generated, golden string, tol ImageTolerance) (ImageDiff, error) {
+	a, err := decodePNGFile(generated)
+	if err != nil {
+		return ImageDiff{}, err
+	}
+	b, err := decodePNGFile(golden)
+	if err != nil {
+		return ImageDiff{}, err
+	}
+	return diffImages(a, b, tol.PixelThreshold), nil
+}
+
+func decodePNGFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// pdfVolatile matches PDF content that changes between otherwise identical
+// renders: info dictionary dates, the trailer file ID, XMP timestamps and the
+// byte offsets in the xref table that shift with them.
+var pdfVolatile = []*regexp.Regexp{
+	regexp.MustCompile(`/(CreationDate|ModDate)\s*\([^)]*\)`),
+	regexp.MustCompile(`/ID\s*\[\s*<[0-9A-Fa-f]*>\s*<[0-9A-Fa-f]*>\s*\]`),
+	regexp.MustCompile(`<xmp:(CreateDate|ModifyDate|MetadataDate)>[^<]*</xmp:(CreateDate|ModifyDate|MetadataDate)>`),
+	regexp.MustCompile(`(?m)^\d{10} \d{5} [nf]\s*$`),
+	regexp.MustCompile(`startxref\s+\d+`),
+}
+
+// normalizePDF blanks volatile metadata so two renders of the same deck
+// compare equal.
+func normalizePDF(data []byte) []byte {
+	for _, re := range pdfVolatile {
+		data = re.ReplaceAll(data, nil)
+	}
+	return data
+}
+
+// comparePDFFiles compares two PDFs ignoring volatile metadata.
+func comparePDFFiles(generated, golden string) (bool, error) {
+	a, err := os.ReadFile(generated)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", generated, err)
+	}
+	b, err := os.ReadFile(golden)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", golden, err)
+	}
+	return bytes.Equal(normalizePDF(a), normalizePDF(b)), nil
+}
@@ -0,0 +1,53 @@
+package deck
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDiffImages(t *testing.T) {
+	white := color.NRGBA{255, 255, 255, 255}
+	golden := solid(10, 10, white)
+
+	noisy := solid(10, 10, white)
+	noisy.SetNRGBA(0, 0, color.NRGBA{250, 250, 250, 255}) // antialiasing-sized
+	noisy.SetNRGBA(1, 0, color.NRGBA{0, 0, 0, 255})       // a real change
+
+	d := diffImages(noisy, golden, DefaultImageTolerance.PixelThreshold)
+	if d.DiffPixels != 1 || d.TotalPixels != 100 || d.MaxChannelDiff != 255 {
+		t.Fatalf("unexpected diff: %+v", d)
+	}
+	if d.Within(StrictImageTolerance) {
+		t.Error("strict tolerance should reject any differing pixel")
+	}
+	if !d.Within(ImageTolerance{PixelThreshold: 16, MaxDiffPercent: 1}) {
+		t.Errorf("1%% diff should pass a 1%% tolerance: %s", d)
+	}
+
+	if d := diffImages(solid(10, 11, white), golden, 255); d.Within(ImageTolerance{MaxDiffPercent: 100}) {
+		t.Error("images of different sizes must never match")
+	}
+}
+
+func TestNormalizePDF(t *testing.T) {
+	a := "1 0 obj\n<< /Producer (gofpdf) /CreationDate (D:20240101120000) >>\nendobj\nxref\n0 2\n0000000000 65535 f \n0000000015 00000 n \ntrailer\n<< /ID [<ABCD><ABCD>] >>\nstartxref\n123\n%%EOF"
+	b := "1 0 obj\n<< /Producer (gofpdf) /CreationDate (D:20250505090909) >>\nendobj\nxref\n0 2\n0000000000 65535 f \n0000000016 00000 n \ntrailer\n<< /ID [<1234><5678>] >>\nstartxref\n124\n%%EOF"
+	if string(normalizePDF([]byte(a))) != string(normalizePDF([]byte(b))) {
+		t.Errorf("volatile metadata should be ignored:\n%s\n---\n%s", normalizePDF([]byte(a)), normalizePDF([]byte(b)))
+	}
+	c := []byte(string(a[:len(a)-5]) + "changed%%EOF")
+	if string(normalizePDF([]byte(a))) == string(normalizePDF(c)) {
+		t.Error("content changes must still be detected")
+	}
+}
@@ -1,12 +1,15 @@
 package deck
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // GoldenTest represents a single golden test case
@@ -21,21 +24,22 @@ type GoldenTest struct {
 
 // GoldenTestCatalog represents the JSON catalog structure
 type GoldenTestCatalog struct {
-	Version     string        `json:"version"`
-	Description string        `json:"description"`
-	Generated   string        `json:"generated"`
-	SourceBase  string        `json:"source_base"`
-	TotalTests  int           `json:"total_tests"`
-	TestCases   []GoldenTest  `json:"test_cases"`
+	Version     string       `json:"version"`
+	Description string       `json:"description"`
+	Generated   string       `json:"generated"`
+	SourceBase  string       `json:"source_base"`
+	TotalTests  int          `json:"total_tests"`
+	TestCases   []GoldenTest `json:"test_cases"`
 }
 
 // GoldenTestRunner runs automated golden tests
 type GoldenTestRunner struct {
-	sourceDir   string
-	buildDir    string
-	outputDir   string
-	expectedDir string
-	goldenTests []GoldenTest
+	sourceDir      string
+	buildDir       string
+	outputDir      string
+	expectedDir    string
+	goldenTests    []GoldenTest
+	imageTolerance ImageTolerance
 }
 
 // NewGoldenTestRunner creates a new golden test runner using pkg/deck/testdata
@@ -45,11 +49,11 @@ func NewGoldenTestRunner(buildDir string) (*GoldenTestRunner, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve deck package dir: %w", err)
 	}
-	
+
 	sourceDir := filepath.Join(deckPkgDir, "unit-tests", "input")
 	outputDir := filepath.Join(deckPkgDir, "unit-tests", "output")
 	expectedDir := filepath.Join(deckPkgDir, "unit-tests", "expected")
-	
+
 	absBuildDir, err := filepath.Abs(buildDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve build dir: %w", err)
@@ -59,12 +63,13 @@ func NewGoldenTestRunner(buildDir string) (*GoldenTestRunner, error) {
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
-	
+
 	runner := &GoldenTestRunner{
-		sourceDir: sourceDir,
-		buildDir:  absBuildDir,
-		outputDir: outputDir,
-		expectedDir: expectedDir,
+		sourceDir:      sourceDir,
+		buildDir:       absBuildDir,
+		outputDir:      outputDir,
+		expectedDir:    expectedDir,
+		imageTolerance: DefaultImageTolerance,
 	}
 
 	// Load golden tests from JSON in pkg/deck
@@ -83,20 +88,32 @@ func NewGoldenTestRunner(buildDir string) (*GoldenTestRunner, error) {
 	return runner, nil
 }
 
+// SetImageTolerance sets how much a generated PNG may differ from its golden
+// image. Use StrictImageTolerance for pixel-exact comparison.
+func (r *GoldenTestRunner) SetImageTolerance(tol ImageTolerance) {
+	r.imageTolerance = tol
+}
+
 // TestResult represents the result of a single test
 type TestResult struct {
-	Name       string
-	Category   string
-	Passed     bool
-	XMLPassed  bool
-	SVGPassed  bool
-	PNGPassed  bool
-	PDFPassed  bool
-	Errors     []string
+	Name      string
+	Category  string
+	Passed    bool
+	XMLPassed bool
+	SVGPassed bool
+	PNGPassed bool
+	PDFPassed bool
+	Errors    []string
 }
 
 // RunTest runs a single golden test case with proper comparison
 func (r *GoldenTestRunner) RunTest(test GoldenTest) (*TestResult, error) {
+	return r.runTest(test, os.Stdout)
+}
+
+// runTest runs a test and writes its progress to w, so parallel runs can
+// buffer each test's output and print it in one piece.
+func (r *GoldenTestRunner) runTest(test GoldenTest, w io.Writer) (*TestResult, error) {
 	result := &TestResult{
 		Name:     test.Name,
 		Category: test.Category,
@@ -104,7 +121,7 @@ func (r *GoldenTestRunner) RunTest(test GoldenTest) (*TestResult, error) {
 		Errors:   []string{},
 	}
 
-	fmt.Printf("Running test: %s (%s)\n", test.Name, test.Category)
+	fmt.Fprintf(w, "Running test: %s (%s)\n", test.Name, test.Category)
 
 	// Build full path to DSH file
 	dshPath := filepath.Join(r.sourceDir, test.Input.Dsh)
@@ -137,12 +154,12 @@ func (r *GoldenTestRunner) RunTest(test GoldenTest) (*TestResult, error) {
 		if err := r.compareSVGGeneration(test, outputTestDir, baseName, result); err != nil {
 			return result, err
 		}
-		
+
 		// Step 3: XML → PNG comparison
 		if err := r.comparePNGGeneration(test, outputTestDir, baseName, result); err != nil {
 			return result, err
 		}
-		
+
 		// Step 4: XML → PDF comparison
 		if err := r.comparePDFGeneration(test, outputTestDir, baseName, result); err != nil {
 			return result, err
@@ -153,13 +170,13 @@ func (r *GoldenTestRunner) RunTest(test GoldenTest) (*TestResult, error) {
 	result.Passed = result.XMLPassed && result.SVGPassed && result.PNGPassed && result.PDFPassed
 
 	if result.Passed {
-		fmt.Printf("  ✓ Test passed (XML: ✓, SVG: ✓, PNG: ✓, PDF: ✓)\n")
+		fmt.Fprintf(w, "  ✓ Test passed (XML: ✓, SVG: ✓, PNG: ✓, PDF: ✓)\n")
 	} else {
-		fmt.Printf("  ✗ Test failed (XML: %s, SVG: %s, PNG: %s, PDF: %s)\n", 
+		fmt.Fprintf(w, "  ✗ Test failed (XML: %s, SVG: %s, PNG: %s, PDF: %s)\n",
 			boolToStatus(result.XMLPassed), boolToStatus(result.SVGPassed),
 			boolToStatus(result.PNGPassed), boolToStatus(result.PDFPassed))
 		for _, err := range result.Errors {
-			fmt.Printf("    - %s\n", err)
+			fmt.Fprintf(w, "    - %s\n", err)
 		}
 	}
 
@@ -187,7 +204,7 @@ func (r *GoldenTestRunner) compareXMLGeneration(test GoldenTest, dshPath, output
 	// Generate XML from DSH
 	outputXMLPath := filepath.Join(outputTestDir, baseName+".xml")
 	deckshPath := filepath.Join(r.buildDir, "bin", DeckshBinary)
-	
+
 	cmd := exec.Command(deckshPath, "-o", outputXMLPath, dshPath)
 	if err := cmd.Run(); err != nil {
 		result.XMLPassed = false
@@ -223,7 +240,7 @@ func (r *GoldenTestRunner) compareSVGGeneration(test GoldenTest, outputTestDir,
 	// Generate SVG from XML
 	xmlPath := filepath.Join(outputTestDir, baseName+".xml")
 	decksvgPath := filepath.Join(r.buildDir, "bin", DecksvgBinary)
-	
+
 	cmd := exec.Command(decksvgPath, xmlPath)
 	cmd.Dir = outputTestDir
 	if err := cmd.Run(); err != nil {
@@ -261,7 +278,7 @@ func (r *GoldenTestRunner) comparePNGGeneration(test GoldenTest, outputTestDir,
 	// Generate PNG from XML
 	xmlPath := filepath.Join(outputTestDir, baseName+".xml")
 	deckpngPath := filepath.Join(r.buildDir, "bin", DeckpngBinary)
-	
+
 	cmd := exec.Command(deckpngPath, xmlPath)
 	cmd.Dir = outputTestDir
 	if err := cmd.Run(); err != nil {
@@ -272,13 +289,13 @@ func (r *GoldenTestRunner) comparePNGGeneration(test GoldenTest, outputTestDir,
 
 	// Compare generated PNG with golden PNG
 	outputPNGPath := filepath.Join(outputTestDir, baseName+".png")
-	if equal, err := r.compareFiles(outputPNGPath, goldenPNGPath); err != nil {
+	if diff, err := comparePNGFiles(outputPNGPath, goldenPNGPath, r.imageTolerance); err != nil {
 		result.PNGPassed = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Failed to compare PNG files: %v", err))
 		return nil
-	} else if !equal {
+	} else if !diff.Within(r.imageTolerance) {
 		result.PNGPassed = false
-		result.Errors = append(result.Errors, "Generated PNG differs from golden PNG")
+		result.Errors = append(result.Errors, fmt.Sprintf("Generated PNG differs from golden PNG: %s", diff))
 		return nil
 	}
 
@@ -299,7 +316,7 @@ func (r *GoldenTestRunner) comparePDFGeneration(test GoldenTest, outputTestDir,
 	// Generate PDF from XML
 	xmlPath := filepath.Join(outputTestDir, baseName+".xml")
 	deckpdfPath := filepath.Join(r.buildDir, "bin", DeckpdfBinary)
-	
+
 	cmd := exec.Command(deckpdfPath, xmlPath)
 	cmd.Dir = outputTestDir
 	if err := cmd.Run(); err != nil {
@@ -310,7 +327,7 @@ func (r *GoldenTestRunner) comparePDFGeneration(test GoldenTest, outputTestDir,
 
 	// Compare generated PDF with golden PDF
 	outputPDFPath := filepath.Join(outputTestDir, baseName+".pdf")
-	if equal, err := r.compareFiles(outputPDFPath, goldenPDFPath); err != nil {
+	if equal, err := comparePDFFiles(outputPDFPath, goldenPDFPath); err != nil {
 		result.PDFPassed = false
 		result.Errors = append(result.Errors, fmt.Sprintf("Failed to compare PDF files: %v", err))
 		return nil
@@ -324,7 +341,7 @@ func (r *GoldenTestRunner) comparePDFGeneration(test GoldenTest, outputTestDir,
 	return nil
 }
 
-// compareFiles does byte-for-byte comparison of two files
+// compareFiles does byte-for-byte comparison of two files (XML and SVG)
 func (r *GoldenTestRunner) compareFiles(file1, file2 string) (bool, error) {
 	data1, err := os.ReadFile(file1)
 	if err != nil {
@@ -339,54 +356,112 @@ func (r *GoldenTestRunner) compareFiles(file1, file2 string) (bool, error) {
 	return string(data1) == string(data2), nil
 }
 
-// RunAllTests runs all golden tests
-func (r *GoldenTestRunner) RunAllTests() error {
-	fmt.Printf("Running %d golden tests...\n\n", len(r.goldenTests))
+// goldenSummary tallies test results. It is safe for concurrent use.
+type goldenSummary struct {
+	mu        sync.Mutex
+	total     int
+	passed    int
+	failed    int
+	xmlPassed int
+	svgPassed int
+	pngPassed int
+	pdfPassed int
+}
 
-	passed := 0
-	failed := 0
-	xmlPassed := 0
-	svgPassed := 0
-	pngPassed := 0
-	pdfPassed := 0
+func (s *goldenSummary) add(result *TestResult, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	for _, test := range r.goldenTests {
-		result, err := r.RunTest(test)
-		if err != nil {
-			fmt.Printf("  ✗ ERROR: %v\n\n", err)
-			failed++
-			continue
-		}
+	s.total++
+	if err != nil {
+		s.failed++
+		return
+	}
 
-		if result.Passed {
-			passed++
-		} else {
-			failed++
-		}
-		
-		if result.XMLPassed {
-			xmlPassed++
-		}
-		if result.SVGPassed {
-			svgPassed++
-		}
-		if result.PNGPassed {
-			pngPassed++
-		}
-		if result.PDFPassed {
-			pdfPassed++
-		}
+	if result.Passed {
+		s.passed++
+	} else {
+		s.failed++
+	}
+
+	if result.XMLPassed {
+		s.xmlPassed++
+	}
+	if result.SVGPassed {
+		s.svgPassed++
+	}
+	if result.PNGPassed {
+		s.pngPassed++
+	}
+	if result.PDFPassed {
+		s.pdfPassed++
 	}
+}
+
+func (s *goldenSummary) print(title string) {
+	fmt.Printf("\n%s:\n", title)
+	fmt.Printf("Overall: %d passed, %d failed\n", s.passed, s.failed)
+	fmt.Printf("XML Pipeline: %d passed, %d failed\n", s.xmlPassed, s.total-s.xmlPassed)
+	fmt.Printf("SVG Pipeline: %d passed, %d failed\n", s.svgPassed, s.total-s.svgPassed)
+	fmt.Printf("PNG Pipeline: %d passed, %d failed\n", s.pngPassed, s.total-s.pngPassed)
+	fmt.Printf("PDF Pipeline: %d passed, %d failed\n", s.pdfPassed, s.total-s.pdfPassed)
+}
+
+// runTests runs tests on up to concurrency workers. Each test writes to its
+// own mirrored output directory, so tests are independent; their progress
+// output is buffered and printed whole as each one finishes.
+func (r *GoldenTestRunner) runTests(tests []GoldenTest, concurrency int) *goldenSummary {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	summary := &goldenSummary{}
+	var printMu sync.Mutex
+	jobs := make(chan GoldenTest)
+	var wg sync.WaitGroup
+
+	for range min(concurrency, len(tests)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for test := range jobs {
+				var out bytes.Buffer
+				result, err := r.runTest(test, &out)
+				if err != nil {
+					fmt.Fprintf(&out, "  ✗ ERROR: %v\n\n", err)
+				}
+				summary.add(result, err)
+
+				printMu.Lock()
+				os.Stdout.Write(out.Bytes())
+				printMu.Unlock()
+			}
+		}()
+	}
+
+	for _, test := range tests {
+		jobs <- test
+	}
+	close(jobs)
+	wg.Wait()
+
+	return summary
+}
+
+// RunAllTests runs all golden tests
+func (r *GoldenTestRunner) RunAllTests() error {
+	return r.RunAllTestsParallel(1)
+}
+
+// RunAllTestsParallel runs all golden tests on up to concurrency workers.
+func (r *GoldenTestRunner) RunAllTestsParallel(concurrency int) error {
+	fmt.Printf("Running %d golden tests...\n\n", len(r.goldenTests))
 
-	fmt.Printf("\nResults Summary:\n")
-	fmt.Printf("Overall: %d passed, %d failed\n", passed, failed)
-	fmt.Printf("XML Pipeline: %d passed, %d failed\n", xmlPassed, len(r.goldenTests)-xmlPassed)
-	fmt.Printf("SVG Pipeline: %d passed, %d failed\n", svgPassed, len(r.goldenTests)-svgPassed)
-	fmt.Printf("PNG Pipeline: %d passed, %d failed\n", pngPassed, len(r.goldenTests)-pngPassed)
-	fmt.Printf("PDF Pipeline: %d passed, %d failed\n", pdfPassed, len(r.goldenTests)-pdfPassed)
-	
-	if failed > 0 {
-		return fmt.Errorf("%d tests failed", failed)
+	summary := r.runTests(r.goldenTests, concurrency)
+	summary.print("Results Summary")
+
+	if summary.failed > 0 {
+		return fmt.Errorf("%d tests failed", summary.failed)
 	}
 
 	return nil
@@ -407,50 +482,11 @@ func (r *GoldenTestRunner) RunTestsInCategory(category string) error {
 
 	fmt.Printf("Running %d tests in category '%s'...\n\n", len(categoryTests), category)
 
-	passed := 0
-	failed := 0
-	xmlPassed := 0
-	svgPassed := 0
-	pngPassed := 0
-	pdfPassed := 0
-
-	for _, test := range categoryTests {
-		result, err := r.RunTest(test)
-		if err != nil {
-			fmt.Printf("  ✗ ERROR: %v\n\n", err)
-			failed++
-			continue
-		}
-
-		if result.Passed {
-			passed++
-		} else {
-			failed++
-		}
-		
-		if result.XMLPassed {
-			xmlPassed++
-		}
-		if result.SVGPassed {
-			svgPassed++
-		}
-		if result.PNGPassed {
-			pngPassed++
-		}
-		if result.PDFPassed {
-			pdfPassed++
-		}
-	}
+	summary := r.runTests(categoryTests, 1)
+	summary.print(fmt.Sprintf("Results for '%s'", category))
 
-	fmt.Printf("\nResults for '%s':\n", category)
-	fmt.Printf("Overall: %d passed, %d failed\n", passed, failed)
-	fmt.Printf("XML Pipeline: %d passed, %d failed\n", xmlPassed, len(categoryTests)-xmlPassed)
-	fmt.Printf("SVG Pipeline: %d passed, %d failed\n", svgPassed, len(categoryTests)-svgPassed)
-	fmt.Printf("PNG Pipeline: %d passed, %d failed\n", pngPassed, len(categoryTests)-pngPassed)
-	fmt.Printf("PDF Pipeline: %d passed, %d failed\n", pdfPassed, len(categoryTests)-pdfPassed)
-	
-	if failed > 0 {
-		return fmt.Errorf("%d tests failed in category %s", failed, category)
+	if summary.failed > 0 {
+		return fmt.Errorf("%d tests failed in category %s", summary.failed, category)
 	}
 
 	return nil
@@ -469,4 +505,4 @@ func (r *GoldenTestRunner) CleanupTestOutputs() error {
 
 	fmt.Printf("Cleaned up test output directory: %s\n", r.outputDir)
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,25 @@
+package deck
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRunTestsParallelSummary(t *testing.T) {
+	dir := t.TempDir()
+	r := &GoldenTestRunner{sourceDir: dir, outputDir: dir, expectedDir: dir}
+
+	// Missing inputs fail fast without needing the deck binaries.
+	var tests []GoldenTest
+	for i := range 20 {
+		var tc GoldenTest
+		tc.Name = fmt.Sprintf("t%d", i)
+		tc.Input.Dsh = fmt.Sprintf("missing/t%d.dsh", i)
+		tests = append(tests, tc)
+	}
+
+	s := r.runTests(tests, 8)
+	if s.total != 20 || s.failed != 20 || s.passed != 0 || s.xmlPassed != 0 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+}
@@ -0,0 +1,76 @@
+package deck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/joeblew999/infra/pkg/config"
+)
+
+// compileDSH runs decksh on dsh markup and returns the deck XML.
+func compileDSH(dshInput string) ([]byte, error) {
+	deckshPath := GetBinaryPath(DeckshBinary)
+	if _, err := os.Stat(deckshPath); err != nil {
+		return nil, fmt.Errorf("decksh not built: %s", deckshPath)
+	}
+
+	cmd := exec.Command(deckshPath)
+	cmd.Stdin = strings.NewReader(dshInput)
+	cmd.Env = append(os.Environ(), "DECKFONTS="+config.GetFontPath())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("decksh failed: %w, output: %s", err, stderr.String())
+	}
+	return fixDeckXML(output), nil
+}
+
+// fixDeckXML quotes the bare color attributes some decksh versions emit.
+func fixDeckXML(data []byte) []byte {
+	fixed := strings.ReplaceAll(string(data), `color=red`, `color="red"`)
+	fixed = strings.ReplaceAll(fixed, `color=gray`, `color="gray"`)
+	return []byte(fixed)
+}
+
+// renderDeckXML writes markup to a scratch directory and runs tool (decksvg
+// or deckpng) on it, returning the 1-based page it produces. A zero size
+// leaves the page size to the tool.
+func renderDeckXML(tool, ext string, markup []byte, page int, size image.Point) ([]byte, error) {
+	toolPath := GetBinaryPath(tool)
+	if _, err := os.Stat(toolPath); err != nil {
+		return nil, fmt.Errorf("%s not built: %s", tool, toolPath)
+	}
+
+	dir, err := os.MkdirTemp("", "deck-render-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "deck.xml")
+	if err := os.WriteFile(input, markup, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write deck markup: %w", err)
+	}
+
+	args := []string{"-outdir", dir, "-pages", fmt.Sprintf("%d-%d", page, page)}
+	if size.X > 0 && size.Y > 0 {
+		args = append(args, "-pagesize", fmt.Sprintf("%d,%d", size.X, size.Y))
+	}
+	cmd := exec.Command(toolPath, append(args, input)...)
+	cmd.Env = append(os.Environ(), "DECKFONTS="+config.GetFontPath())
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w, output: %s", tool, err, string(output))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, SlideFilename("deck", page-1, ext)))
+	if err != nil {
+		return nil, fmt.Errorf("%s produced no output for page %d: %w", tool, page, err)
+	}
+	return data, nil
+}
@@ -254,12 +254,8 @@ func (w *Watcher) runDecksh(inputPath, outputPath string) error {
 		return fmt.Errorf("decksh failed: %w, output: %s", err, string(output))
 	}
 
-	// Fix XML attributes - add quotes around color values
-	fixedXML := strings.ReplaceAll(string(output), `color=red`, `color="red"`)
-	fixedXML = strings.ReplaceAll(fixedXML, `color=gray`, `color="gray"`)
-
 	// Write XML output
-	return os.WriteFile(outputPath, []byte(fixedXML), 0644)
+	return os.WriteFile(outputPath, fixDeckXML(output), 0644)
 }
 
 // runSvgdeck runs decksvg to convert XML to SVG
@@ -0,0 +1,112 @@
+package deck
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+)
+
+// Deck is the parsed form of the XML decksh produces: enough to enumerate
+// slides and their metadata, and to render any one of them.
+type Deck struct {
+	Title   string  `xml:"title"`
+	Creator string  `xml:"creator"`
+	Canvas  Canvas  `xml:"canvas"`
+	Slides  []Slide `xml:"slide"`
+
+	markup []byte
+}
+
+// Canvas is the deck's page size in pixels; zero when the deck doesn't set one.
+type Canvas struct {
+	Width  int `xml:"width,attr"`
+	Height int `xml:"height,attr"`
+}
+
+// Slide holds a slide's presentation metadata. Duration is deck's own
+// per-slide display time (e.g. "5s"), used by players to advance slides.
+type Slide struct {
+	Bg          string `xml:"bg,attr"`
+	Fg          string `xml:"fg,attr"`
+	Gradcolor1  string `xml:"gradcolor1,attr"`
+	Gradcolor2  string `xml:"gradcolor2,attr"`
+	GradPercent int    `xml:"gp,attr"`
+	Duration    string `xml:"duration,attr"`
+	Note        string `xml:"note"`
+}
+
+// ParseDSH compiles dsh markup with decksh and parses the result.
+func ParseDSH(dshInput string) (*Deck, error) {
+	markup, err := compileDSH(dshInput)
+	if err != nil {
+		return nil, err
+	}
+	return ParseXML(markup)
+}
+
+// ParseXML parses deck XML, as produced by decksh.
+func ParseXML(markup []byte) (*Deck, error) {
+	var d Deck
+	if err := xml.Unmarshal(markup, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse deck XML: %w", err)
+	}
+	d.markup = markup
+	return &d, nil
+}
+
+// SlideCount compiles dsh markup and returns how many slides it has.
+func SlideCount(dshInput string) (int, error) {
+	d, err := ParseDSH(dshInput)
+	if err != nil {
+		return 0, err
+	}
+	return len(d.Slides), nil
+}
+
+// SlideFilename is the name a rendered slide gets: base, the 1-based slide
+// number padded to five digits, and the format, e.g. "talk-00003.png". It
+// matches decksvg/deckpng's own naming and sorts in slide order.
+func SlideFilename(base string, index int, format string) string {
+	return fmt.Sprintf("%s-%05d.%s", base, index+1, format)
+}
+
+// RenderSlide renders the slide at index (0-based) as "svg" or "png".
+func (d *Deck) RenderSlide(index int, format string) ([]byte, error) {
+	if index < 0 || index >= len(d.Slides) {
+		return nil, fmt.Errorf("slide %d out of range: deck has %d slides", index, len(d.Slides))
+	}
+	var tool string
+	switch format {
+	case "svg":
+		tool = DecksvgBinary
+	case "png":
+		tool = DeckpngBinary
+	default:
+		return nil, fmt.Errorf("unsupported slide format %q", format)
+	}
+	size := image.Point{X: d.Canvas.Width, Y: d.Canvas.Height}
+	return renderDeckXML(tool, format, d.markup, index+1, size)
+}
+
+// RenderSlides renders every slide into dir, in order, named with
+// SlideFilename, and returns the paths written.
+func (d *Deck) RenderSlides(dir, base, format string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	paths := make([]string, 0, len(d.Slides))
+	for i := range d.Slides {
+		data, err := d.RenderSlide(i, format)
+		if err != nil {
+			return paths, err
+		}
+		path := filepath.Join(dir, SlideFilename(base, i, format))
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return paths, fmt.Errorf("failed to write slide %d: %w", i, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
@@ -0,0 +1,51 @@
+package deck
+
+import "testing"
+
+const sampleDeckXML = `<deck>
+<title>Quarterly review</title>
+<canvas width="1920" height="1080"/>
+<slide bg="black" fg="white" duration="5s">
+  <text xp="50" yp="50" sp="5">Intro</text>
+  <note>Say hello</note>
+</slide>
+<slide bg="white" gradcolor1="red" gradcolor2="blue" gp="50">
+  <rect xp="50" yp="50" wp="10" hp="10"/>
+</slide>
+<slide/>
+</deck>`
+
+func TestParseXML(t *testing.T) {
+	d, err := ParseXML([]byte(sampleDeckXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Title != "Quarterly review" || d.Canvas != (Canvas{1920, 1080}) {
+		t.Errorf("deck %+v", d)
+	}
+	if len(d.Slides) != 3 {
+		t.Fatalf("expected 3 slides, got %d", len(d.Slides))
+	}
+	want := Slide{Bg: "black", Fg: "white", Duration: "5s", Note: "Say hello"}
+	if d.Slides[0] != want {
+		t.Errorf("slide 0 = %+v, want %+v", d.Slides[0], want)
+	}
+	if s := d.Slides[1]; s.Gradcolor1 != "red" || s.Gradcolor2 != "blue" || s.GradPercent != 50 {
+		t.Errorf("slide 1 = %+v", s)
+	}
+	if _, err := d.RenderSlide(3, "svg"); err == nil {
+		t.Error("expected an out-of-range error")
+	}
+	if _, err := d.RenderSlide(0, "gif"); err == nil {
+		t.Error("expected an unsupported format error")
+	}
+}
+
+func TestSlideFilename(t *testing.T) {
+	if got := SlideFilename("talk", 0, "png"); got != "talk-00001.png" {
+		t.Errorf("got %s", got)
+	}
+	if got := SlideFilename("talk", 41, "svg"); got != "talk-00042.svg" {
+		t.Errorf("got %s", got)
+	}
+}
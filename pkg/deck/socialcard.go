@@ -0,0 +1,178 @@
+package deck
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/jpeg" // logo formats accepted by decksvg/deckpng
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// DefaultSocialCardSize is the OpenGraph image size most sites expect.
+var DefaultSocialCardSize = image.Point{X: 1200, Y: 630}
+
+// Social card colours and layout, as percentages of the canvas the way deck
+// markup positions everything (y grows upwards).
+const (
+	socialCardBackground = "white"
+	socialCardForeground = "rgb(32,32,32)"
+	socialCardAccent     = "rgb(0,102,204)"
+	socialCardSubtle     = "rgb(96,96,96)"
+	socialCardMargin     = 8.0
+	socialCardLogoBox    = 0.18 // logo height as a fraction of the card height
+)
+
+// RenderSocialCard lays out a one-slide deck with title, subtitle and an
+// optional logo (PNG or JPEG; empty for none) and renders it to an SVG of
+// the given size through decksvg. The layout depends only on the inputs,
+// so the same card always renders the same way.
+func RenderSocialCard(title, subtitle, logoPath string, size image.Point) ([]byte, error) {
+	return renderSocialCard(DecksvgBinary, "svg", title, subtitle, logoPath, size)
+}
+
+// RenderSocialCardPNG is RenderSocialCard rendered to PNG through deckpng.
+func RenderSocialCardPNG(title, subtitle, logoPath string, size image.Point) ([]byte, error) {
+	return renderSocialCard(DeckpngBinary, "png", title, subtitle, logoPath, size)
+}
+
+func renderSocialCard(tool, ext, title, subtitle, logoPath string, size image.Point) ([]byte, error) {
+	markup, err := socialCardXML(title, subtitle, logoPath, size)
+	if err != nil {
+		return nil, err
+	}
+	return renderDeckXML(tool, ext, markup, 1, size)
+}
+
+// deckXML, slideXML and friends are the subset of deck markup a card uses.
+type deckXML struct {
+	XMLName xml.Name  `xml:"deck"`
+	Canvas  canvasXML `xml:"canvas"`
+	Slide   slideXML  `xml:"slide"`
+}
+
+type canvasXML struct {
+	Width  int `xml:"width,attr"`
+	Height int `xml:"height,attr"`
+}
+
+type slideXML struct {
+	Bg    string    `xml:"bg,attr"`
+	Fg    string    `xml:"fg,attr"`
+	Rects []rectXML `xml:"rect"`
+	Texts []textXML `xml:"text"`
+	Image *imageXML `xml:"image,omitempty"`
+}
+
+type rectXML struct {
+	Xp    float64 `xml:"xp,attr"`
+	Yp    float64 `xml:"yp,attr"`
+	Wp    float64 `xml:"wp,attr"`
+	Hp    float64 `xml:"hp,attr"`
+	Color string  `xml:"color,attr"`
+}
+
+type textXML struct {
+	Xp    float64 `xml:"xp,attr"`
+	Yp    float64 `xml:"yp,attr"`
+	Sp    float64 `xml:"sp,attr"`
+	Wp    float64 `xml:"wp,attr,omitempty"`
+	Type  string  `xml:"type,attr,omitempty"`
+	Font  string  `xml:"font,attr"`
+	Color string  `xml:"color,attr"`
+	Text  string  `xml:",chardata"`
+}
+
+type imageXML struct {
+	Xp     float64 `xml:"xp,attr"`
+	Yp     float64 `xml:"yp,attr"`
+	Width  int     `xml:"width,attr"`
+	Height int     `xml:"height,attr"`
+	Scale  float64 `xml:"scale,attr"`
+	Name   string  `xml:"name,attr"`
+}
+
+// socialCardXML builds the card's deck markup: an accent bar along the
+// left edge, the title as a wrapped block, the subtitle beneath it and the
+// logo scaled into the bottom-right corner.
+func socialCardXML(title, subtitle, logoPath string, size image.Point) ([]byte, error) {
+	if size.X <= 0 || size.Y <= 0 {
+		return nil, fmt.Errorf("invalid social card size %dx%d", size.X, size.Y)
+	}
+	if title == "" {
+		return nil, fmt.Errorf("social card title is required")
+	}
+
+	// deck text sizes are a percentage of the canvas width; keep them
+	// proportional to the height so wide and square cards look alike.
+	aspect := float64(size.Y) / float64(size.X)
+	textWidth := 100 - 2*socialCardMargin
+
+	slide := slideXML{
+		Bg: socialCardBackground,
+		Fg: socialCardForeground,
+		Rects: []rectXML{
+			{Xp: 1, Yp: 50, Wp: 2, Hp: 100, Color: socialCardAccent},
+		},
+		Texts: []textXML{
+			{Xp: socialCardMargin, Yp: 72, Sp: round2(9 * aspect), Wp: textWidth, Type: "block", Font: "sans", Color: socialCardForeground, Text: title},
+		},
+	}
+	if subtitle != "" {
+		slide.Texts = append(slide.Texts, textXML{
+			Xp: socialCardMargin, Yp: 32, Sp: round2(5 * aspect), Wp: textWidth, Type: "block", Font: "sans", Color: socialCardSubtle, Text: subtitle,
+		})
+	}
+
+	if logoPath != "" {
+		logo, err := socialCardLogo(logoPath, size)
+		if err != nil {
+			return nil, err
+		}
+		slide.Image = logo
+	}
+
+	out, err := xml.MarshalIndent(deckXML{Canvas: canvasXML{Width: size.X, Height: size.Y}, Slide: slide}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode social card: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// socialCardLogo scales the logo to fit a square box in the bottom-right
+// corner, keeping its aspect ratio.
+func socialCardLogo(logoPath string, size image.Point) (*imageXML, error) {
+	abs, err := filepath.Abs(logoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve logo path: %w", err)
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open logo: %w", err)
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo %s: %w", logoPath, err)
+	}
+
+	box := socialCardLogoBox * float64(size.Y)
+	scale := math.Min(box/float64(cfg.Width), box/float64(cfg.Height))
+	// Centre the box one margin in from the right and bottom edges.
+	boxXp := 100 - socialCardMargin - 100*box/2/float64(size.X)
+	boxYp := socialCardMargin + 100*box/2/float64(size.Y)
+	return &imageXML{
+		Xp:     round2(boxXp),
+		Yp:     round2(boxYp),
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Scale:  round2(100 * scale),
+		Name:   abs,
+	}, nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,59 @@
+package deck
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSocialCardXML(t *testing.T) {
+	logo := filepath.Join(t.TempDir(), "logo.png")
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, solid(200, 100, color.NRGBA{0, 102, 204, 255})); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(logo, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	markup, err := socialCardXML("Fish & <Chips>", "Weekly digest", logo, DefaultSocialCardSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := socialCardXML("Fish & <Chips>", "Weekly digest", logo, DefaultSocialCardSize)
+	if !bytes.Equal(markup, again) {
+		t.Fatal("layout is not deterministic")
+	}
+
+	var d deckXML
+	if err := xml.Unmarshal(markup, &d); err != nil {
+		t.Fatalf("invalid deck markup: %v\n%s", err, markup)
+	}
+	if d.Canvas.Width != 1200 || d.Canvas.Height != 630 {
+		t.Errorf("canvas %+v", d.Canvas)
+	}
+	if len(d.Slide.Texts) != 2 || d.Slide.Texts[0].Text != "Fish & <Chips>" {
+		t.Errorf("texts %+v", d.Slide.Texts)
+	}
+	// A 200x100 logo fits a 113.4px box by its width.
+	if d.Slide.Image == nil || d.Slide.Image.Scale != 56.7 || d.Slide.Image.Name != logo {
+		t.Errorf("image %+v", d.Slide.Image)
+	}
+}
+
+func TestSocialCardXMLRejectsBadInput(t *testing.T) {
+	if _, err := socialCardXML("", "", "", DefaultSocialCardSize); err == nil {
+		t.Error("expected an error for a missing title")
+	}
+	if _, err := socialCardXML("Title", "", "", image.Point{}); err == nil {
+		t.Error("expected an error for an empty size")
+	}
+	if _, err := socialCardXML("Title", "", "missing.png", DefaultSocialCardSize); err == nil {
+		t.Error("expected an error for a missing logo")
+	}
+}
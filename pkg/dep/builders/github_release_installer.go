@@ -17,8 +17,18 @@ import (
 )
 
 
+// Install phases reported through GitHubReleaseInstaller.Progress
+const (
+	PhaseDownloading = "downloading"
+	PhaseExtracting  = "extracting"
+)
+
 // GitHubReleaseInstaller handles GitHub release-based binary installations
-type GitHubReleaseInstaller struct{}
+type GitHubReleaseInstaller struct {
+	// Progress, when set, receives download byte counts and phase changes
+	// in place of the terminal progress bar.
+	Progress func(phase string, downloaded, total int64)
+}
 
 // Note: We'll use the types from the main dep package to avoid duplication
 
@@ -27,6 +37,9 @@ type AssetSelector struct {
 	OS    string `json:"os"`
 	Arch  string `json:"arch"`
 	Match string `json:"match"` // Regular expression to match the asset filename
+
+	// Pattern is Match precompiled by the config loader; nil means compile Match.
+	Pattern *regexp.Regexp `json:"-"`
 }
 
 // Install downloads and installs a binary from GitHub releases
@@ -65,9 +78,20 @@ func (i *GitHubReleaseInstaller) Install(name, repo, version string, assets []As
 
 	// Download the asset
 	archivePath := filepath.Join(tempDir, asset.Name)
-	if err := util.DownloadFile(asset.BrowserDownloadURL, archivePath, true); err != nil {
+	releaseAsset := util.ReleaseAsset{Repo: repo, Version: version, Name: asset.Name, URL: asset.BrowserDownloadURL}
+	if i.Progress != nil {
+		err = util.DownloadReleaseAssetWithProgress(releaseAsset, archivePath, func(downloaded, total int64) {
+			i.Progress(PhaseDownloading, downloaded, total)
+		})
+	} else {
+		err = util.DownloadReleaseAsset(releaseAsset, archivePath, true)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to download asset: %w", err)
 	}
+	if i.Progress != nil {
+		i.Progress(PhaseExtracting, 0, 0)
+	}
 
 	// Extract the archive
 	extractDir := filepath.Join(tempDir, "extracted")
@@ -113,8 +137,15 @@ func (i *GitHubReleaseInstaller) getGitHubRelease(repo, version string) (*GitHub
 func (i *GitHubReleaseInstaller) selectAsset(release *GitHubRelease, selectors []AssetSelector) (*GitHubReleaseAsset, error) {
 	for _, selector := range selectors {
 		if selector.OS == runtime.GOOS && selector.Arch == runtime.GOARCH {
+			pattern := selector.Pattern
+			if pattern == nil {
+				var err error
+				if pattern, err = regexp.Compile(selector.Match); err != nil {
+					return nil, fmt.Errorf("invalid asset pattern %q: %w", selector.Match, err)
+				}
+			}
 			for _, asset := range release.Assets {
-				if matched, _ := regexp.MatchString(selector.Match, asset.Name); matched {
+				if pattern.MatchString(asset.Name) {
 					return &asset, nil
 				}
 			}
@@ -52,7 +52,8 @@ func (i *MacOSAppInstaller) Install(name, repo, version string, assets []AssetSe
 	}
 
 	log.Info("Downloading DMG", "name", name, "url", asset.URL, "output", dmgPath)
-	if err := util.DownloadFile(asset.URL, dmgPath, true); err != nil {
+	releaseAsset := util.ReleaseAsset{Repo: repo, Version: version, Name: asset.Name, URL: asset.URL}
+	if err := util.DownloadReleaseAsset(releaseAsset, dmgPath, true); err != nil {
 		return fmt.Errorf("failed to download %s.dmg: %w", name, err)
 	}
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/joeblew999/infra/pkg/log"
@@ -21,15 +22,28 @@ type GitHubRelease struct {
 	Prerelease bool   `json:"prerelease"`
 }
 
-// CheckGitHubRelease checks the latest GitHub release for a repository
+// githubAPIBase is the GitHub REST endpoint; tests point it at a fake server.
+var githubAPIBase = "https://api.github.com"
+
+// CheckGitHubRelease checks the latest GitHub release for a repository.
+// GITHUB_TOKEN, when set, is sent to lift the anonymous rate limit.
 func CheckGitHubRelease(owner, repo string) (GitHubRelease, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", owner, repo)
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest", githubAPIBase, owner, repo)
 
 	client := http.Client{
 		Timeout: 10 * time.Second,
 	}
 
-	resp, err := client.Get(url)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return GitHubRelease{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return GitHubRelease{}, fmt.Errorf("failed to fetch release: %w", err)
 	}
@@ -31,13 +31,12 @@ Examples:
 	}
 
 	collectAllCmd = &cobra.Command{
-		Use:   "all",
+		Use:   "all [binary...]",
 		Short: "Collect all configured binaries for all platforms",
 		Long: `Collect all binaries defined in dep.json for all configured platforms.
 This is useful for bulk collection before creating managed releases.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("🔄 Collection system not implemented yet")
-			fmt.Println("Would collect all configured binaries for all platforms")
+			runCollectAll(args)
 		},
 	}
 
@@ -83,6 +82,37 @@ func runCollect(binaryName, version string) {
 	displayCollectionResult(result, time.Since(start))
 }
 
+func runCollectAll(names []string) {
+	config := collection.DefaultConfig()
+	collector, err := collection.NewCrossPlatformCollector(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error creating collector: %v\n", err)
+		os.Exit(1)
+	}
+	collector.SetProgress(func(p collection.CollectionProgress) {
+		status := "✅"
+		if !p.Success {
+			status = "❌"
+		}
+		fmt.Printf("[%d/%d] %s %s %s %s\n", p.Completed, p.Total, status, p.Binary, p.Version, p.Platform)
+	})
+
+	fmt.Printf("🔄 Collecting into %s with %d concurrent downloads...\n\n", config.CollectionDir, config.ConcurrentLimit)
+	result, err := collector.CollectAll(context.Background(), names)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error collecting binaries: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n📊 %d/%d binaries collected, %d platforms ok, %d failed in %s\n",
+		result.SuccessCount, result.TotalCount, result.PlatformSuccessCount, result.PlatformFailureCount,
+		result.Duration.Round(time.Second))
+	fmt.Printf("📄 Index: %s\n", result.IndexPath)
+	if result.FailureCount > 0 {
+		os.Exit(1)
+	}
+}
+
 func displayCollectionResult(result *collection.CollectionResult, duration time.Duration) {
 	fmt.Printf("\n%s\n", strings.Repeat("=", 60))
 	fmt.Printf("📊 Collection Results for %s %s\n", result.Binary, result.Version)
@@ -27,6 +27,7 @@ Distribution:
   dep release:status  - Show release status
 
 Maintenance:
+  dep updates         - List binaries with newer GitHub releases
   dep clean           - Clean all dependency system data and caches`,
 	}
 
@@ -69,6 +70,7 @@ func init() {
 	Cmd.AddCommand(localCmd)
 	Cmd.AddCommand(collectCmd)
 	Cmd.AddCommand(releaseCmd)
+	Cmd.AddCommand(updatesCmd)
 	Cmd.AddCommand(cleanCmd)
 
 	attachLocalCommands()
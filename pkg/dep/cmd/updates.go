@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	dep "github.com/joeblew999/infra/pkg/dep"
+	"github.com/spf13/cobra"
+)
+
+var updatesCmd = &cobra.Command{
+	Use:   "updates",
+	Short: "List github-release binaries with newer upstream releases",
+	Long: `Check GitHub for the latest release of every github-release binary and
+compare it with the version pinned in dep.json. Read-only: dep.json is not
+changed. Set GITHUB_TOKEN to avoid the anonymous API rate limit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		updates, err := dep.CheckUpdates()
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tCURRENT\tLATEST\tSTATUS")
+		fmt.Fprintln(w, "----\t-------\t------\t------")
+		for _, u := range updates {
+			status := "up to date"
+			switch {
+			case u.Err != nil:
+				status = "check failed"
+			case u.UpdateAvailable:
+				status = "update available"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", u.Name, u.Current, u.Latest, status)
+		}
+		w.Flush()
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking updates: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
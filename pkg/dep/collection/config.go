@@ -1,6 +1,7 @@
 package collection
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"os"
 	"os/exec"
@@ -32,6 +33,11 @@ type Config struct {
 	// Validation settings
 	RequireChecksums bool `json:"require_checksums"`
 	VerifySignatures bool `json:"verify_signatures"`
+
+	// Manifest signing keys, from DEP_COLLECTION_SIGNING_KEY and
+	// DEP_COLLECTION_PUBLIC_KEY; nil leaves manifests unsigned/unverified
+	SigningKey ed25519.PrivateKey `json:"-"`
+	PublicKey  ed25519.PublicKey  `json:"-"`
 }
 
 // getGitOwner attempts to get the git owner from git config or environment
@@ -83,7 +89,7 @@ func getGitOwner() string {
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
-	return &Config{
+	cfg := &Config{
 		CollectionDir:   filepath.Join(config.GetDepPath(), ".collection"),
 		PlatformMatrix: []string{
 			"darwin-amd64",
@@ -104,6 +110,8 @@ func DefaultConfig() *Config {
 		RequireChecksums: true,
 		VerifySignatures: false,
 	}
+	loadKeysFromEnv(cfg)
+	return cfg
 }
 
 // GetCollectionPath returns the collection path for a binary
@@ -116,6 +124,22 @@ func (c *Config) GetManifestPath(name, version string) string {
 	return filepath.Join(c.GetCollectionPath(name, version), "manifest.json")
 }
 
+// GetIndexPath returns the path of the combined manifest written by CollectAll
+func (c *Config) GetIndexPath() string {
+	return filepath.Join(c.CollectionDir, "index.json")
+}
+
+// GetMirrorDir returns the directory laid out as a dep mirror
+// (see util.MirrorURL for the URL scheme it serves).
+func (c *Config) GetMirrorDir() string {
+	return filepath.Join(c.CollectionDir, "mirror")
+}
+
+// GetMirrorAssetPath returns where a raw release asset is kept in the mirror tree
+func (c *Config) GetMirrorAssetPath(repo, version, asset string) string {
+	return filepath.Join(c.GetMirrorDir(), filepath.FromSlash(repo), version, asset)
+}
+
 // GetBinaryPath returns the path for a platform-specific binary
 func (c *Config) GetBinaryPath(name, version, platform string) string {
 	filename := name
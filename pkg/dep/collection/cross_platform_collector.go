@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 type CrossPlatformCollector struct {
 	config   *Config
 	binaries []DepBinary
+	progress func(CollectionProgress)
 }
 
 // NewCrossPlatformCollector creates a new cross-platform collector
@@ -43,8 +45,30 @@ func NewCrossPlatformCollector(config *Config) (*CrossPlatformCollector, error)
 	}, nil
 }
 
+// SetProgress registers a callback invoked as each platform of each binary
+// finishes collecting. It is called from collection goroutines, one call at
+// a time.
+func (c *CrossPlatformCollector) SetProgress(fn func(CollectionProgress)) {
+	c.progress = fn
+}
+
 // CollectBinary downloads a binary for all configured platforms using cross-platform simulation
 func (c *CrossPlatformCollector) CollectBinary(ctx context.Context, name, version string) (*CollectionResult, error) {
+	semaphore := make(chan struct{}, c.config.ConcurrentLimit)
+	var mu sync.Mutex
+	completed, total := 0, len(c.config.PlatformMatrix)
+	return c.collectBinary(ctx, name, version, semaphore, func(binary, version string, platformResult *PlatformResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		completed++
+		c.reportProgress(binary, version, platformResult, completed, total)
+	})
+}
+
+// collectBinary collects one binary for every platform, running platform
+// downloads under the given semaphore so several binaries can share one
+// concurrency budget. onPlatform is called as each platform finishes.
+func (c *CrossPlatformCollector) collectBinary(ctx context.Context, name, version string, semaphore chan struct{}, onPlatform func(binary, version string, result *PlatformResult)) (*CollectionResult, error) {
 	log.Info("Starting cross-platform binary collection", "name", name, "version", version)
 
 	// Find the binary in configuration
@@ -87,7 +111,6 @@ func (c *CrossPlatformCollector) CollectBinary(ctx context.Context, name, versio
 	// Collect for each platform in parallel using cross-platform simulation
 	platformResults := make(chan *PlatformResult, len(c.config.PlatformMatrix))
 	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, c.config.ConcurrentLimit)
 
 	for _, targetPlatform := range c.config.PlatformMatrix {
 		wg.Add(1)
@@ -97,6 +120,7 @@ func (c *CrossPlatformCollector) CollectBinary(ctx context.Context, name, versio
 			defer func() { <-semaphore }() // Release semaphore
 
 			platformResult := c.collectForPlatformCrossPlatform(ctx, binary, targetPlatform)
+			onPlatform(binary.Name, binary.Version, platformResult)
 			platformResults <- platformResult
 		}(targetPlatform)
 	}
@@ -130,6 +154,12 @@ func (c *CrossPlatformCollector) CollectBinary(ctx context.Context, name, versio
 		errors = append(errors, fmt.Sprintf("manifest generation failed: %v", err))
 	} else {
 		result.Manifest = manifest
+
+		if c.config.SigningKey != nil {
+			if err := SignManifest(manifest, c.config.SigningKey); err != nil {
+				log.Warn("Failed to sign manifest", "error", err)
+			}
+		}
 		
 		// Save manifest to disk
 		manifestPath := c.config.GetManifestPath(name, binary.Version)
@@ -148,6 +178,135 @@ func (c *CrossPlatformCollector) CollectBinary(ctx context.Context, name, versio
 	return result, nil
 }
 
+// CollectAll collects several binaries (all configured ones when names is
+// empty) at their configured versions. Platform downloads across all of them
+// share the ConcurrentLimit budget, progress is reported per platform via
+// SetProgress, and a combined index of every collected manifest is written
+// to the collection root.
+func (c *CrossPlatformCollector) CollectAll(ctx context.Context, names []string) (*MultiCollectionResult, error) {
+	if len(names) == 0 {
+		for _, b := range c.binaries {
+			names = append(names, b.Name)
+		}
+	}
+	log.Info("Starting multi-binary collection", "count", len(names), "concurrency", c.config.ConcurrentLimit)
+
+	result := &MultiCollectionResult{
+		BatchCollectionResult: BatchCollectionResult{
+			Results:    make(map[string]*CollectionResult),
+			TotalCount: len(names),
+			StartedAt:  time.Now(),
+		},
+	}
+
+	semaphore := make(chan struct{}, c.config.ConcurrentLimit)
+	var mu sync.Mutex
+	completed, total := 0, len(names)*len(c.config.PlatformMatrix)
+	onPlatform := func(binary, version string, platformResult *PlatformResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		completed++
+		if platformResult.Success {
+			result.PlatformSuccessCount++
+		} else {
+			result.PlatformFailureCount++
+		}
+		c.reportProgress(binary, version, platformResult, completed, total)
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			collectionResult, err := c.collectBinary(ctx, name, "", semaphore, onPlatform)
+			if err != nil {
+				log.Error("Failed to collect binary", "name", name, "error", err)
+				collectionResult = &CollectionResult{
+					Binary:      name,
+					Platforms:   make(map[string]*PlatformResult),
+					CollectedAt: time.Now(),
+					Errors:      []string{err.Error()},
+				}
+				// Count the platforms it never got to so progress still reaches total
+				for _, targetPlatform := range c.config.PlatformMatrix {
+					onPlatform(name, "", &PlatformResult{Platform: targetPlatform, Error: err.Error()})
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Results[name] = collectionResult
+			if collectionResult.Success {
+				result.SuccessCount++
+			} else {
+				result.FailureCount++
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	result.CompletedAt = time.Now()
+	result.Duration = result.CompletedAt.Sub(result.StartedAt)
+
+	index := c.buildCollectionIndex(result)
+	indexPath := c.config.GetIndexPath()
+	if err := c.saveJSON(index, indexPath); err != nil {
+		return result, fmt.Errorf("failed to write collection index: %w", err)
+	}
+	result.IndexPath = indexPath
+
+	log.Info("Multi-binary collection completed",
+		"total", result.TotalCount,
+		"success", result.SuccessCount,
+		"failed", result.FailureCount,
+		"platforms_ok", result.PlatformSuccessCount,
+		"platforms_failed", result.PlatformFailureCount,
+		"duration", result.Duration.Round(time.Second))
+
+	return result, ctx.Err()
+}
+
+func (c *CrossPlatformCollector) reportProgress(binary, version string, platformResult *PlatformResult, completed, total int) {
+	if c.progress == nil {
+		return
+	}
+	c.progress(CollectionProgress{
+		Binary:    binary,
+		Version:   version,
+		Platform:  platformResult.Platform,
+		Success:   platformResult.Success,
+		Error:     platformResult.Error,
+		Completed: completed,
+		Total:     total,
+	})
+}
+
+// buildCollectionIndex lists every binary with a manifest, with manifest
+// paths relative to the collection root so the tree can be mirrored as is.
+func (c *CrossPlatformCollector) buildCollectionIndex(result *MultiCollectionResult) *CollectionIndex {
+	index := &CollectionIndex{
+		GeneratedAt: result.CompletedAt,
+		Binaries:    make(map[string]*CollectionIndexEntry),
+	}
+	for name, r := range result.Results {
+		if r.Manifest == nil || len(r.Manifest.Platforms) == 0 {
+			continue
+		}
+		manifestPath, err := filepath.Rel(c.config.CollectionDir, c.config.GetManifestPath(name, r.Version))
+		if err != nil {
+			manifestPath = c.config.GetManifestPath(name, r.Version)
+		}
+		entry := &CollectionIndexEntry{Version: r.Version, Manifest: filepath.ToSlash(manifestPath)}
+		for platform := range r.Manifest.Platforms {
+			entry.Platforms = append(entry.Platforms, platform)
+		}
+		sort.Strings(entry.Platforms)
+		index.Binaries[name] = entry
+	}
+	return index
+}
+
 // collectForPlatformCrossPlatform collects a binary for a specific platform using simulation
 func (c *CrossPlatformCollector) collectForPlatformCrossPlatform(ctx context.Context, binary *DepBinary, targetPlatform string) *PlatformResult {
 	startTime := time.Now()
@@ -278,10 +437,18 @@ func (c *CrossPlatformCollector) downloadFromGitHubRelease(ctx context.Context,
 
 	// Download the asset
 	tempArchivePath := filepath.Join(tempDir, asset.Name)
-	if err := util.DownloadFile(asset.BrowserDownloadURL, tempArchivePath, true); err != nil {
+	releaseAsset := util.ReleaseAsset{Repo: binary.Repo, Version: binary.Version, Name: asset.Name, URL: asset.BrowserDownloadURL}
+	if err := util.DownloadReleaseAsset(releaseAsset, tempArchivePath, true); err != nil {
 		return "", fmt.Errorf("failed to download asset: %w", err)
 	}
 
+	// Keep the raw asset in the mirror tree so the collection can be served
+	// as a DEP_MIRROR.
+	mirrorPath := c.config.GetMirrorAssetPath(binary.Repo, binary.Version, asset.Name)
+	if err := c.copyFile(tempArchivePath, mirrorPath); err != nil {
+		return "", fmt.Errorf("failed to copy asset to mirror: %w", err)
+	}
+
 	// Extract the archive if needed
 	extractDir := filepath.Join(tempDir, "extracted")
 	if err := os.MkdirAll(extractDir, 0755); err != nil {
@@ -489,6 +656,10 @@ func (c *CrossPlatformCollector) generateManifest(result *CollectionResult) (*Bi
 }
 
 func (c *CrossPlatformCollector) saveManifest(manifest *BinaryManifest, path string) error {
+	return c.saveJSON(manifest, path)
+}
+
+func (c *CrossPlatformCollector) saveJSON(v any, path string) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
@@ -501,7 +672,20 @@ func (c *CrossPlatformCollector) saveManifest(manifest *BinaryManifest, path str
 
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(manifest)
+	return encoder.Encode(v)
+}
+
+// LoadManifest reads a manifest written by the collector
+func LoadManifest(path string) (*BinaryManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest BinaryManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &manifest, nil
 }
 
 // GitHub API types for cross-platform collection
@@ -0,0 +1,91 @@
+package collection
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCollectAllProgressAndIndex(t *testing.T) {
+	cfg := &Config{
+		CollectionDir:   t.TempDir(),
+		PlatformMatrix:  []string{"linux-amd64", "darwin-arm64"},
+		ConcurrentLimit: 2,
+	}
+	// Binaries without a repo fail fast without touching the network
+	c := &CrossPlatformCollector{config: cfg, binaries: []DepBinary{
+		{Name: "alpha", Version: "v1"},
+		{Name: "beta", Version: "v2"},
+	}}
+
+	var mu sync.Mutex
+	var updates []CollectionProgress
+	c.SetProgress(func(p CollectionProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, p)
+	})
+
+	result, err := c.CollectAll(context.Background(), []string{"alpha", "beta", "missing"})
+	if err != nil {
+		t.Fatalf("CollectAll: %v", err)
+	}
+
+	if result.TotalCount != 3 || result.FailureCount != 3 || result.PlatformFailureCount != 6 {
+		t.Errorf("counts = %+v", result)
+	}
+	if len(updates) != 6 {
+		t.Fatalf("got %d progress updates, want one per binary and platform", len(updates))
+	}
+	seen := make(map[int]bool)
+	for _, p := range updates {
+		if p.Total != 6 || p.Success || p.Error == "" {
+			t.Errorf("unexpected progress %+v", p)
+		}
+		seen[p.Completed] = true
+	}
+	for i := 1; i <= 6; i++ {
+		if !seen[i] {
+			t.Errorf("no progress update with Completed=%d", i)
+		}
+	}
+
+	data, err := os.ReadFile(result.IndexPath)
+	if err != nil {
+		t.Fatalf("index not written: %v", err)
+	}
+	var index CollectionIndex
+	if err := json.Unmarshal(data, &index); err != nil || len(index.Binaries) != 0 {
+		t.Errorf("index = %s, %v; want no binaries when nothing was collected", data, err)
+	}
+}
+
+func TestBuildCollectionIndex(t *testing.T) {
+	cfg := &Config{CollectionDir: t.TempDir()}
+	c := &CrossPlatformCollector{config: cfg}
+	result := &MultiCollectionResult{BatchCollectionResult: BatchCollectionResult{
+		CompletedAt: time.Now(),
+		Results: map[string]*CollectionResult{
+			"task": {Binary: "task", Version: "v3", Manifest: &BinaryManifest{Platforms: map[string]*PlatformInfo{
+				"linux-amd64": {}, "darwin-arm64": {},
+			}}},
+			"broken": {Binary: "broken", Version: "v1"},
+		},
+	}}
+
+	index := c.buildCollectionIndex(result)
+	entry := index.Binaries["task"]
+	if len(index.Binaries) != 1 || entry == nil {
+		t.Fatalf("index = %+v, want only task", index.Binaries)
+	}
+	if entry.Manifest != filepath.ToSlash(filepath.Join("binaries", "task", "v3", "manifest.json")) {
+		t.Errorf("manifest path = %q, want it relative to the collection root", entry.Manifest)
+	}
+	if len(entry.Platforms) != 2 || entry.Platforms[0] != "darwin-arm64" {
+		t.Errorf("platforms = %v, want sorted", entry.Platforms)
+	}
+}
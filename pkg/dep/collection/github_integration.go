@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 
 	"github.com/joeblew999/infra/pkg/log"
@@ -59,9 +58,9 @@ func (g *GitHubAPI) SelectAssetForPlatform(release *GitHubRelease, assets []Asse
 
 	// Find matching asset selector
 	var selector *AssetInfo
-	for _, asset := range assets {
-		if asset.OS == targetOS && asset.Arch == targetArch {
-			selector = &asset
+	for i := range assets {
+		if assets[i].OS == targetOS && assets[i].Arch == targetArch {
+			selector = &assets[i]
 			break
 		}
 	}
@@ -72,15 +71,14 @@ func (g *GitHubAPI) SelectAssetForPlatform(release *GitHubRelease, assets []Asse
 
 	log.Debug("Found asset selector", "pattern", selector.Match)
 
+	pattern, err := selector.pattern()
+	if err != nil {
+		return nil, err
+	}
+
 	// Find matching asset in release
 	for _, asset := range release.Assets {
-		matched, err := regexp.MatchString(selector.Match, asset.Name)
-		if err != nil {
-			log.Warn("Invalid regex pattern", "pattern", selector.Match, "error", err)
-			continue
-		}
-		
-		if matched {
+		if pattern.MatchString(asset.Name) {
 			log.Info("Selected asset for platform", 
 				"asset", asset.Name,
 				"platform", fmt.Sprintf("%s-%s", targetOS, targetArch),
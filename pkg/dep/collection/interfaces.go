@@ -82,6 +82,38 @@ type BatchCollectionResult struct {
 	CompletedAt time.Time                  `json:"completed_at"`
 }
 
+// MultiCollectionResult represents results from CrossPlatformCollector.CollectAll
+type MultiCollectionResult struct {
+	BatchCollectionResult
+	PlatformSuccessCount int    `json:"platform_success_count"`
+	PlatformFailureCount int    `json:"platform_failure_count"`
+	IndexPath            string `json:"index_path,omitempty"`
+}
+
+// CollectionProgress reports one finished platform during collection
+type CollectionProgress struct {
+	Binary    string `json:"binary"`
+	Version   string `json:"version"`
+	Platform  string `json:"platform"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+	Completed int    `json:"completed"` // platforms finished so far, across all binaries
+	Total     int    `json:"total"`
+}
+
+// CollectionIndex is the top-level manifest listing every collected binary
+type CollectionIndex struct {
+	GeneratedAt time.Time                        `json:"generated_at"`
+	Binaries    map[string]*CollectionIndexEntry `json:"binaries"`
+}
+
+// CollectionIndexEntry points at one binary's manifest in the collection
+type CollectionIndexEntry struct {
+	Version   string   `json:"version"`
+	Manifest  string   `json:"manifest"` // relative to the collection root
+	Platforms []string `json:"platforms"`
+}
+
 // CollectionStatus represents the current status of a collected binary
 type CollectionStatus struct {
 	Binary        string             `json:"binary"`
@@ -231,6 +263,7 @@ type BinaryManifest struct {
 	Source         *SourceInfo               `json:"source"`
 	Platforms      map[string]*PlatformInfo  `json:"platforms"`
 	ManagedRelease *ManagedReleaseInfo       `json:"managed_release,omitempty"`
+	Signature      string                    `json:"signature,omitempty"` // base64 Ed25519 signature, see SignManifest
 }
 
 // SourceInfo represents information about the original source
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 )
 
 // DepBinary represents a binary dependency (copied from dep package to avoid circular import)
@@ -20,6 +21,22 @@ type AssetInfo struct {
 	OS    string `json:"os"`
 	Arch  string `json:"arch"`
 	Match string `json:"match"`
+
+	re *regexp.Regexp // compiled Match, cached by loadDepConfigFromFile
+}
+
+// pattern returns the compiled Match expression, compiling it if the loader
+// hasn't already.
+func (a *AssetInfo) pattern() (*regexp.Regexp, error) {
+	if a.re != nil {
+		return a.re, nil
+	}
+	re, err := regexp.Compile(a.Match)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset pattern %q for %s/%s: %w", a.Match, a.OS, a.Arch, err)
+	}
+	a.re = re
+	return re, nil
 }
 
 // loadDepConfig loads dependency configuration from dep.json
@@ -52,5 +69,13 @@ func loadDepConfigFromFile(path string) ([]DepBinary, error) {
 		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
 	}
 
+	for i := range binaries {
+		for j := range binaries[i].Assets {
+			if _, err := binaries[i].Assets[j].pattern(); err != nil {
+				return nil, fmt.Errorf("%s: binary %s: %w", path, binaries[i].Name, err)
+			}
+		}
+	}
+
 	return binaries, nil
 }
\ No newline at end of file
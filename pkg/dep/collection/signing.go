@@ -0,0 +1,135 @@
+package collection
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// Environment variables holding base64-encoded Ed25519 keys for manifest
+// signing (collector side) and verification (install side).
+const (
+	EnvSigningKey = "DEP_COLLECTION_SIGNING_KEY"
+	EnvPublicKey  = "DEP_COLLECTION_PUBLIC_KEY"
+)
+
+var (
+	// ErrManifestUnsigned is returned by VerifyManifest for a manifest
+	// without a signature, e.g. one collected before signing existed.
+	ErrManifestUnsigned = errors.New("manifest is not signed")
+	// ErrNoPublicKey is returned by VerifyManifest when there is no key to
+	// check a signed manifest against.
+	ErrNoPublicKey = errors.New("no public key to verify manifest signature")
+	// ErrBadSignature is returned when a manifest doesn't match its signature.
+	ErrBadSignature = errors.New("manifest signature is invalid")
+)
+
+// SignManifest signs the manifest with privKey and stores the signature in
+// manifest.Signature. Sign after the manifest is final: any later change,
+// including to checksums, invalidates it.
+func SignManifest(manifest *BinaryManifest, privKey ed25519.PrivateKey) error {
+	if len(privKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid Ed25519 private key length %d", len(privKey))
+	}
+	payload, err := manifestSigningPayload(manifest)
+	if err != nil {
+		return err
+	}
+	manifest.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privKey, payload))
+	return nil
+}
+
+// VerifyManifest checks manifest.Signature against pubKey.
+func VerifyManifest(manifest *BinaryManifest, pubKey ed25519.PublicKey) error {
+	if manifest.Signature == "" {
+		return ErrManifestUnsigned
+	}
+	if len(pubKey) == 0 {
+		return ErrNoPublicKey
+	}
+	if len(pubKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid Ed25519 public key length %d", len(pubKey))
+	}
+	sig, err := base64.StdEncoding.DecodeString(manifest.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	payload, err := manifestSigningPayload(manifest)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pubKey, payload, sig) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// manifestSigningPayload is the manifest as JSON without its signature.
+// encoding/json sorts map keys, so the bytes are stable across a save/load.
+func manifestSigningPayload(manifest *BinaryManifest) ([]byte, error) {
+	unsigned := *manifest
+	unsigned.Signature = ""
+	payload, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest for signing: %w", err)
+	}
+	return payload, nil
+}
+
+// SigningKeyFromEnv returns the private key in DEP_COLLECTION_SIGNING_KEY,
+// given as a base64 32-byte seed or 64-byte key, or nil if unset.
+func SigningKeyFromEnv() (ed25519.PrivateKey, error) {
+	raw, err := keyFromEnv(EnvSigningKey)
+	if raw == nil || err != nil {
+		return nil, err
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	}
+	return nil, fmt.Errorf("%s: want a %d-byte seed or %d-byte key, got %d bytes", EnvSigningKey, ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+}
+
+// PublicKeyFromEnv returns the public key in DEP_COLLECTION_PUBLIC_KEY, or
+// nil if unset.
+func PublicKeyFromEnv() (ed25519.PublicKey, error) {
+	raw, err := keyFromEnv(EnvPublicKey)
+	if raw == nil || err != nil {
+		return nil, err
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%s: want %d bytes, got %d", EnvPublicKey, ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+func keyFromEnv(name string) ([]byte, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid base64: %w", name, err)
+	}
+	return raw, nil
+}
+
+// loadKeysFromEnv fills in the config's keys from the environment, leaving
+// them nil (signing off) when unset or invalid.
+func loadKeysFromEnv(cfg *Config) {
+	var err error
+	if cfg.SigningKey, err = SigningKeyFromEnv(); err != nil {
+		log.Warn("Ignoring manifest signing key", "error", err)
+	}
+	if cfg.PublicKey, err = PublicKeyFromEnv(); err != nil {
+		log.Warn("Ignoring manifest public key", "error", err)
+	}
+}
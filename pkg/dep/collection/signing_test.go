@@ -0,0 +1,59 @@
+package collection
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerifyManifest(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := &BinaryManifest{
+		Binary:         "task",
+		Version:        "v3.44.1",
+		CollectionDate: time.Now(),
+		Platforms: map[string]*PlatformInfo{
+			"linux-amd64":  {Filename: "task", Size: 10, SHA256: "aa"},
+			"darwin-arm64": {Filename: "task", Size: 12, SHA256: "bb"},
+		},
+	}
+
+	if err := VerifyManifest(manifest, pub); !errors.Is(err, ErrManifestUnsigned) {
+		t.Errorf("unsigned manifest: got %v, want ErrManifestUnsigned", err)
+	}
+	if err := SignManifest(manifest, priv); err != nil {
+		t.Fatal(err)
+	}
+
+	// The signature survives the collector's save and a later load
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	c := &CrossPlatformCollector{}
+	if err := c.saveManifest(manifest, path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyManifest(loaded, pub); err != nil {
+		t.Fatalf("VerifyManifest after reload: %v", err)
+	}
+	if err := VerifyManifest(loaded, nil); !errors.Is(err, ErrNoPublicKey) {
+		t.Errorf("no key: got %v, want ErrNoPublicKey", err)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if err := VerifyManifest(loaded, otherPub); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("wrong key: got %v, want ErrBadSignature", err)
+	}
+
+	loaded.Platforms["linux-amd64"].SHA256 = "cc"
+	if err := VerifyManifest(loaded, pub); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("swapped checksum: got %v, want ErrBadSignature", err)
+	}
+}
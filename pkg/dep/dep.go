@@ -46,6 +46,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/joeblew999/infra/pkg/config"
@@ -113,6 +114,7 @@ type DepBinary struct {
 	Version     string          `json:"version"`
 	ReleaseURL  string          `json:"release_url"` // Full URL to the GitHub release page
 	Assets      []AssetSelector `json:"assets"`
+	PostInstall []string        `json:"post_install,omitempty"` // Shell commands run after install, see runPostInstall
 }
 
 // AssetSelector defines how to select a release asset.
@@ -120,6 +122,54 @@ type AssetSelector struct {
 	OS    string `json:"os"`
 	Arch  string `json:"arch"`
 	Match string `json:"match"` // Regular expression to match the asset filename
+
+	re *regexp.Regexp // compiled Match, cached when the config is loaded
+}
+
+// Pattern returns the compiled Match expression. Selectors loaded from
+// dep.json are compiled once at load time; selectors built in code are
+// compiled on demand.
+func (a AssetSelector) Pattern() (*regexp.Regexp, error) {
+	if a.re != nil {
+		return a.re, nil
+	}
+	re, err := regexp.Compile(a.Match)
+	if err != nil {
+		return nil, fmt.Errorf("invalid asset pattern %q for %s/%s: %w", a.Match, a.OS, a.Arch, err)
+	}
+	return re, nil
+}
+
+// compileAssetPatterns compiles and caches every asset pattern so installs
+// don't recompile them and a bad pattern fails the config load rather than
+// showing up later as "no matching asset".
+func compileAssetPatterns(binaries []DepBinary) error {
+	for i := range binaries {
+		for j := range binaries[i].Assets {
+			asset := &binaries[i].Assets[j]
+			re, err := asset.Pattern()
+			if err != nil {
+				return fmt.Errorf("binary %s: %w", binaries[i].Name, err)
+			}
+			asset.re = re
+		}
+	}
+	return nil
+}
+
+// builderAssets converts selectors for the builders package, carrying the
+// compiled patterns along.
+func builderAssets(selectors []AssetSelector) []builders.AssetSelector {
+	assets := make([]builders.AssetSelector, 0, len(selectors))
+	for _, asset := range selectors {
+		assets = append(assets, builders.AssetSelector{
+			OS:      asset.OS,
+			Arch:    asset.Arch,
+			Match:   asset.Match,
+			Pattern: asset.re,
+		})
+	}
+	return assets
 }
 
 // Installer defines the interface for installing a dependency binary.
@@ -152,9 +202,9 @@ func loadConfig() ([]DepBinary, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to read external config file %s: %w", configPath, err)
 		}
-		var binaries []DepBinary
-		if err := json.Unmarshal(data, &binaries); err != nil {
-			return nil, fmt.Errorf("failed to parse external config file %s: %w", configPath, err)
+		binaries, err := parseConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid external config file %s: %w", configPath, err)
 		}
 		depBinaries = binaries
 		return depBinaries, nil
@@ -166,9 +216,9 @@ func loadConfig() ([]DepBinary, error) {
 		return nil, fmt.Errorf("failed to read embedded config: %w", err)
 	}
 
-	var binaries []DepBinary
-	if err := json.Unmarshal(data, &binaries); err != nil {
-		return nil, fmt.Errorf("failed to parse embedded config: %w", err)
+	binaries, err := parseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedded config: %w", err)
 	}
 
 	depBinaries = binaries
@@ -195,6 +245,39 @@ func EnsureBinaries(names []string, debug bool) error {
 	return nil
 }
 
+// EnsureWithProgress installs all configured binaries like Ensure, reporting
+// each binary's phase and download byte counts to progress so a TUI can draw
+// its own progress bar.
+func EnsureWithProgress(debug bool, progress func(DepProgress)) error {
+	binaries, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load dependency configuration: %w", err)
+	}
+
+	for i, binary := range binaries {
+		report := func(phase DepPhase, downloaded, total int64) {
+			progress(DepProgress{
+				Binary:     binary.Name,
+				Index:      i,
+				Count:      len(binaries),
+				Phase:      phase,
+				Downloaded: downloaded,
+				Total:      total,
+			})
+		}
+
+		report(PhaseInstalling, 0, 0)
+		err := installBinary(binary.Name, debug, false, func(phase string, downloaded, total int64) {
+			report(DepPhase(phase), downloaded, total)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to install %s: %w", binary.Name, err)
+		}
+		report(PhaseDone, 0, 0)
+	}
+	return nil
+}
+
 // EnsureWithCrossPlatform downloads and prepares all binaries with optional cross-platform support
 func EnsureWithCrossPlatform(debug, crossPlatform bool) error {
 	log.Info("Ensuring core binaries...")
@@ -223,6 +306,12 @@ func InstallBinary(name string, debug bool) error {
 
 // InstallBinaryWithCrossPlatform installs a single binary with optional cross-platform support
 func InstallBinaryWithCrossPlatform(name string, debug, crossPlatform bool) error {
+	return installBinary(name, debug, crossPlatform, nil)
+}
+
+// installBinary installs a single binary. progress, when non-nil, receives
+// download and extraction updates from installers that report them.
+func installBinary(name string, debug, crossPlatform bool, progress func(phase string, downloaded, total int64)) error {
 	log.Info("Checking binary", "name", name)
 
 	// Load configuration to find the specific binary
@@ -275,7 +364,7 @@ func InstallBinaryWithCrossPlatform(name string, debug, crossPlatform bool) erro
 	// Handle binary-specific installers first
 	switch targetBinary.Name {
 	case config.BinaryNsc:
-		installer := nscInstaller{}
+		installer := nscInstaller{progress: progress}
 		if err := installer.Install(*targetBinary, debug); err != nil {
 			return err
 		}
@@ -320,32 +409,14 @@ func InstallBinaryWithCrossPlatform(name string, debug, crossPlatform bool) erro
 			}
 		case "github-release":
 			// Use new builders package for github-release
-			builder := builders.GitHubReleaseInstaller{}
-			// Convert AssetSelector types
-			var assets []builders.AssetSelector
-			for _, asset := range targetBinary.Assets {
-				assets = append(assets, builders.AssetSelector{
-					OS:    asset.OS,
-					Arch:  asset.Arch,
-					Match: asset.Match,
-				})
-			}
-			if err := builder.Install(targetBinary.Name, targetBinary.Repo, targetBinary.Version, assets, debug); err != nil {
+			builder := builders.GitHubReleaseInstaller{Progress: progress}
+			if err := builder.Install(targetBinary.Name, targetBinary.Repo, targetBinary.Version, builderAssets(targetBinary.Assets), debug); err != nil {
 				return err
 			}
 		case "macos-app":
 			// Use macOS app installer for DMG-based app installations
 			builder := builders.MacOSAppInstaller{}
-			// Convert AssetSelector types
-			var assets []builders.AssetSelector
-			for _, asset := range targetBinary.Assets {
-				assets = append(assets, builders.AssetSelector{
-					OS:    asset.OS,
-					Arch:  asset.Arch,
-					Match: asset.Match,
-				})
-			}
-			if err := builder.Install(targetBinary.Name, targetBinary.Repo, targetBinary.Version, assets, debug); err != nil {
+			if err := builder.Install(targetBinary.Name, targetBinary.Repo, targetBinary.Version, builderAssets(targetBinary.Assets), debug); err != nil {
 				return err
 			}
 		case "claude-release":
@@ -370,6 +441,13 @@ func InstallBinaryWithCrossPlatform(name string, debug, crossPlatform bool) erro
 		return fmt.Errorf("failed to write metadata for %s: %w", name, err)
 	}
 
+	if err := runPostInstall(targetBinary, installPath, debug); err != nil {
+		// Drop the metadata so the next install retries the hook instead of
+		// treating the binary as up to date.
+		_ = os.Remove(getMetaPath(installPath))
+		return err
+	}
+
 	log.Info("Binary installed successfully", "name", name, "version", targetBinary.Version)
 	return nil
 }
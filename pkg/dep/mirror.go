@@ -0,0 +1,15 @@
+package dep
+
+import "github.com/joeblew999/infra/pkg/dep/util"
+
+// EnvMirror is the environment variable consulted when SetMirror hasn't
+// been called.
+const EnvMirror = util.EnvMirror
+
+// SetMirror makes GitHub release downloads try baseURL first, at
+// <baseURL>/<owner>/<repo>/<version>/<asset>, falling back to GitHub when the
+// mirror answers 404. The collector's <collection>/mirror directory has this
+// layout. An empty baseURL reverts to DEP_MIRROR.
+func SetMirror(baseURL string) {
+	util.SetMirror(baseURL)
+}
@@ -0,0 +1,143 @@
+package dep
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/joeblew999/infra/pkg/dep/collection"
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// ErrNotInCollection is returned when an offline install finds no collected
+// binary for the configured version and current platform.
+var ErrNotInCollection = errors.New("not in collection")
+
+// InstallFromCollection installs every configured binary for the current
+// platform from a directory populated by the cross-platform collector (the
+// layout under .dep/.collection), verifying each file against the SHA256 in
+// its manifest. It never touches the network: a binary missing from the
+// collection is an ErrNotInCollection error. Binaries already at the
+// configured version are left alone; all failures are reported together.
+//
+// With DEP_COLLECTION_PUBLIC_KEY set, each manifest must carry a valid
+// signature before its checksums are trusted; an unsigned manifest is
+// rejected, since stripping the signature would otherwise skip the check.
+// Without a key, unsigned manifests are accepted with a warning so
+// collections made before signing still install.
+func InstallFromCollection(collectionDir string, debug bool) error {
+	binaries, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load dependency configuration: %w", err)
+	}
+	pubKey, err := collection.PublicKeyFromEnv()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range binaries {
+		installPath, err := Get(binaries[i].Name)
+		if err == nil {
+			err = installFromCollection(collectionDir, &binaries[i], installPath, pubKey, debug)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", binaries[i].Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func installFromCollection(collectionDir string, binary *DepBinary, installPath string, pubKey ed25519.PublicKey, debug bool) error {
+	if meta, err := readMeta(installPath); err == nil && meta.Version == binary.Version {
+		log.Info("Binary up to date", "name", binary.Name, "version", binary.Version)
+		return nil
+	}
+
+	layout := &collection.Config{CollectionDir: collectionDir}
+	manifestPath := layout.GetManifestPath(binary.Name, binary.Version)
+	manifest, err := collection.LoadManifest(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: no manifest for version %s at %s", ErrNotInCollection, binary.Version, manifestPath)
+	} else if err != nil {
+		return err
+	}
+
+	switch err := collection.VerifyManifest(manifest, pubKey); {
+	case err == nil:
+	case errors.Is(err, collection.ErrManifestUnsigned) && len(pubKey) > 0:
+		return fmt.Errorf("%w: %s: %w but %s is set", ErrInstallationFailed, manifestPath, err, collection.EnvPublicKey)
+	case errors.Is(err, collection.ErrManifestUnsigned):
+		log.Warn("Collection manifest is unsigned, trusting its checksums", "name", binary.Name, "manifest", manifestPath)
+	case errors.Is(err, collection.ErrNoPublicKey):
+		log.Warn("Collection manifest is signed but no public key is set, signature not checked", "name", binary.Name, "env", collection.EnvPublicKey)
+	default:
+		return fmt.Errorf("%w: %s: %w", ErrInstallationFailed, manifestPath, err)
+	}
+
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	info, ok := manifest.Platforms[platform]
+	if !ok {
+		return fmt.Errorf("%w: version %s has no %s build", ErrNotInCollection, binary.Version, platform)
+	}
+
+	// Resolve against the manifest's directory rather than LocalPath so a
+	// collection copied to a mirror still works.
+	src := filepath.Join(filepath.Dir(manifestPath), platform, info.Filename)
+	if err := copyVerified(src, installPath, info.SHA256); err != nil {
+		return fmt.Errorf("%w: %v", ErrInstallationFailed, err)
+	}
+
+	if err := writeMeta(installPath, &BinaryMeta{Name: binary.Name, Version: binary.Version}); err != nil {
+		return fmt.Errorf("failed to write metadata for %s: %w", binary.Name, err)
+	}
+	if err := runPostInstall(binary, installPath, debug); err != nil {
+		_ = os.Remove(getMetaPath(installPath))
+		return err
+	}
+
+	log.Info("Binary installed from collection", "name", binary.Name, "version", binary.Version, "source", src)
+	return nil
+}
+
+// copyVerified copies src to dst, replacing dst only if the content matches
+// wantSHA256.
+func copyVerified(src, dst, wantSHA256 string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open collected binary: %w", err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), in); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != wantSHA256 {
+		return fmt.Errorf("checksum mismatch for %s: manifest has %s, file is %s", src, wantSHA256, got)
+	}
+
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,108 @@
+package dep
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/joeblew999/infra/pkg/dep/collection"
+)
+
+// writeCollection lays out one collected binary the way the collector does.
+func writeCollection(t *testing.T, dir, name, version string, content []byte, sum string, key ed25519.PrivateKey) {
+	t.Helper()
+	layout := &collection.Config{CollectionDir: dir}
+	platform := runtime.GOOS + "-" + runtime.GOARCH
+	binPath := layout.GetBinaryPath(name, version, platform)
+	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(binPath, content, 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := collection.BinaryManifest{Binary: name, Version: version, Platforms: map[string]*collection.PlatformInfo{
+		platform: {Filename: filepath.Base(binPath), Size: int64(len(content)), SHA256: sum, LocalPath: "/elsewhere/" + name},
+	}}
+	if key != nil {
+		if err := collection.SignManifest(&manifest, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := json.Marshal(manifest)
+	if err := os.WriteFile(layout.GetManifestPath(name, version), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallFromCollection(t *testing.T) {
+	dir := t.TempDir()
+	content := []byte("#!/bin/sh\necho tool\n")
+	sum := sha256.Sum256(content)
+	writeCollection(t, dir, "tool", "v1.0.0", content, hex.EncodeToString(sum[:]), nil)
+
+	installPath := filepath.Join(t.TempDir(), "tool")
+	binary := &DepBinary{Name: "tool", Version: "v1.0.0"}
+	if err := installFromCollection(dir, binary, installPath, nil, false); err != nil {
+		t.Fatalf("installFromCollection: %v", err)
+	}
+	if got, _ := os.ReadFile(installPath); string(got) != string(content) {
+		t.Errorf("installed content = %q", got)
+	}
+	if meta, err := readMeta(installPath); err != nil || meta.Version != "v1.0.0" {
+		t.Errorf("metadata = %+v, %v", meta, err)
+	}
+
+	missing := &DepBinary{Name: "tool", Version: "v2.0.0"}
+	if err := installFromCollection(dir, missing, filepath.Join(t.TempDir(), "tool"), nil, false); !errors.Is(err, ErrNotInCollection) {
+		t.Errorf("uncollected version: got %v, want ErrNotInCollection", err)
+	}
+
+	writeCollection(t, dir, "tampered", "v1.0.0", content, hex.EncodeToString(make([]byte, 32)), nil)
+	tamperedPath := filepath.Join(t.TempDir(), "tampered")
+	err := installFromCollection(dir, &DepBinary{Name: "tampered", Version: "v1.0.0"}, tamperedPath, nil, false)
+	if !errors.Is(err, ErrInstallationFailed) {
+		t.Errorf("checksum mismatch: got %v, want ErrInstallationFailed", err)
+	}
+	if _, err := os.Stat(tamperedPath); !os.IsNotExist(err) {
+		t.Error("binary with a bad checksum should not be installed")
+	}
+}
+
+func TestInstallFromCollectionVerifiesSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	_, otherPriv, _ := ed25519.GenerateKey(nil)
+	content := []byte("binary")
+	sum := sha256.Sum256(content)
+
+	dir := t.TempDir()
+	writeCollection(t, dir, "signed", "v1", content, hex.EncodeToString(sum[:]), priv)
+	writeCollection(t, dir, "forged", "v1", content, hex.EncodeToString(sum[:]), otherPriv)
+
+	if err := installFromCollection(dir, &DepBinary{Name: "signed", Version: "v1"}, filepath.Join(t.TempDir(), "signed"), pub, false); err != nil {
+		t.Errorf("correctly signed manifest: %v", err)
+	}
+	err := installFromCollection(dir, &DepBinary{Name: "forged", Version: "v1"}, filepath.Join(t.TempDir(), "forged"), pub, false)
+	if !errors.Is(err, ErrInstallationFailed) || !errors.Is(err, collection.ErrBadSignature) {
+		t.Errorf("manifest signed by another key: got %v, want ErrInstallationFailed wrapping ErrBadSignature", err)
+	}
+
+	// Stripping the signature must not bypass verification once a key is set.
+	writeCollection(t, dir, "stripped", "v1", content, hex.EncodeToString(sum[:]), nil)
+	strippedPath := filepath.Join(t.TempDir(), "stripped")
+	err = installFromCollection(dir, &DepBinary{Name: "stripped", Version: "v1"}, strippedPath, pub, false)
+	if !errors.Is(err, ErrInstallationFailed) || !errors.Is(err, collection.ErrManifestUnsigned) {
+		t.Errorf("unsigned manifest with a key set: got %v, want ErrInstallationFailed wrapping ErrManifestUnsigned", err)
+	}
+	if _, err := os.Stat(strippedPath); !os.IsNotExist(err) {
+		t.Error("binary from an unsigned manifest should not be installed when a key is set")
+	}
+	if err := installFromCollection(dir, &DepBinary{Name: "stripped", Version: "v1"}, filepath.Join(t.TempDir(), "stripped"), nil, false); err != nil {
+		t.Errorf("unsigned manifest without a key: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package dep
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// EnvInstallPath names the environment variable that carries the installed
+// binary's path to post-install commands.
+const EnvInstallPath = "DEP_INSTALL_PATH"
+
+// runPostInstall runs the binary's post_install commands in order, each
+// through the platform shell with the binary's directory first on PATH and
+// its path in DEP_INSTALL_PATH. The first failing command stops the run.
+func runPostInstall(binary *DepBinary, installPath string, debug bool) error {
+	if len(binary.PostInstall) == 0 {
+		return nil
+	}
+
+	env := append(os.Environ(),
+		"PATH="+filepath.Dir(installPath)+string(os.PathListSeparator)+os.Getenv("PATH"),
+		EnvInstallPath+"="+installPath,
+	)
+
+	for _, command := range binary.PostInstall {
+		log.Info("Running post-install command", "name", binary.Name, "command", command)
+
+		cmd := shellCommand(command)
+		cmd.Env = env
+		var output bytes.Buffer
+		if debug {
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+		} else {
+			cmd.Stdout = &output
+			cmd.Stderr = &output
+		}
+
+		if err := cmd.Run(); err != nil {
+			msg := fmt.Sprintf("%v", err)
+			if out := strings.TrimSpace(output.String()); out != "" {
+				msg += ": " + out
+			}
+			return fmt.Errorf("%w: post-install command %q for %s: %s", ErrInstallationFailed, command, binary.Name, msg)
+		}
+	}
+	return nil
+}
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
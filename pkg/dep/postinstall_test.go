@@ -0,0 +1,46 @@
+package dep
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunPostInstall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("post-install test uses sh scripts")
+	}
+	dir := t.TempDir()
+	installPath := filepath.Join(dir, "mytool")
+	if err := os.WriteFile(installPath, []byte("#!/bin/sh\necho \"ran $1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.txt")
+
+	binary := &DepBinary{Name: "mytool", PostInstall: []string{
+		"mytool init > " + out,
+		"echo \"$" + EnvInstallPath + "\" >> " + out,
+	}}
+	if err := runPostInstall(binary, installPath, false); err != nil {
+		t.Fatalf("runPostInstall: %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ran init\n" + installPath + "\n"; string(got) != want {
+		t.Errorf("post-install output = %q, want %q", got, want)
+	}
+
+	binary.PostInstall = []string{"echo boom >&2; exit 3", "touch " + filepath.Join(dir, "never")}
+	err = runPostInstall(binary, installPath, false)
+	if !errors.Is(err, ErrInstallationFailed) || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected ErrInstallationFailed with command output, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "never")); !os.IsNotExist(err) {
+		t.Error("commands after a failure should not run")
+	}
+}
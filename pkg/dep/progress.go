@@ -5,8 +5,30 @@ import (
 	"io"
 	"strings"
 	"time"
+
+	"github.com/joeblew999/infra/pkg/dep/builders"
+)
+
+// DepPhase is the stage a binary is at in EnsureWithProgress
+type DepPhase string
+
+const (
+	PhaseInstalling  DepPhase = "installing" // started; up-to-date checks and builds report nothing further
+	PhaseDownloading DepPhase = DepPhase(builders.PhaseDownloading)
+	PhaseExtracting  DepPhase = DepPhase(builders.PhaseExtracting)
+	PhaseDone        DepPhase = "done"
 )
 
+// DepProgress is one progress update from EnsureWithProgress
+type DepProgress struct {
+	Binary     string
+	Index      int // position of Binary among the Count binaries being ensured
+	Count      int
+	Phase      DepPhase
+	Downloaded int64 // bytes so far while downloading
+	Total      int64 // Content-Length of the download, -1 if unknown
+}
+
 // ProgressWriter wraps an io.Writer to show download progress
 type ProgressWriter struct {
 	Total       int64
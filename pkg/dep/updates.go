@@ -0,0 +1,116 @@
+package dep
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxUpdateChecks bounds concurrent GitHub API calls in CheckUpdates.
+const maxUpdateChecks = 8
+
+// UpdateInfo compares a pinned github-release binary with the latest
+// upstream release.
+type UpdateInfo struct {
+	Name            string
+	Repo            string
+	Current         string
+	Latest          string
+	UpdateAvailable bool
+	Err             error // set when the latest release could not be fetched
+}
+
+// CheckUpdates looks up the latest GitHub release of every github-release
+// binary in dep.json. It never modifies dep.json. Results keep the config
+// order; binaries whose lookup failed carry Err and are also reported in the
+// returned error.
+func CheckUpdates() ([]UpdateInfo, error) {
+	binaries, err := loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return checkUpdates(binaries)
+}
+
+func checkUpdates(binaries []DepBinary) ([]UpdateInfo, error) {
+	var updates []UpdateInfo
+	for _, b := range binaries {
+		if b.Source == "github-release" {
+			updates = append(updates, UpdateInfo{Name: b.Name, Repo: b.Repo, Current: b.Version})
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxUpdateChecks)
+	for i := range updates {
+		wg.Add(1)
+		go func(u *UpdateInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			owner, repo, _ := strings.Cut(u.Repo, "/")
+			release, err := CheckGitHubRelease(owner, repo)
+			if err != nil {
+				u.Err = fmt.Errorf("%s: %w", u.Name, err)
+				return
+			}
+			u.Latest = release.TagName
+			u.UpdateAvailable = isNewerVersion(u.Current, u.Latest)
+		}(&updates[i])
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, u := range updates {
+		if u.Err != nil {
+			errs = append(errs, u.Err)
+		}
+	}
+	return updates, errors.Join(errs...)
+}
+
+// isNewerVersion reports whether latest is a later release than current.
+// Dotted numeric versions (with or without a leading "v") are compared part
+// by part; anything else counts as newer whenever the tags differ.
+func isNewerVersion(current, latest string) bool {
+	if latest == "" || latest == current {
+		return false
+	}
+	cur, okCur := parseVersion(current)
+	lat, okLat := parseVersion(latest)
+	if !okCur || !okLat {
+		return true
+	}
+	for i := 0; i < max(len(cur), len(lat)); i++ {
+		var c, l int
+		if i < len(cur) {
+			c = cur[i]
+		}
+		if i < len(lat) {
+			l = lat[i]
+		}
+		if c != l {
+			return l > c
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if v == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
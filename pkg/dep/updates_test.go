@@ -0,0 +1,72 @@
+package dep
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.2.3", "v1.10.0", true},
+		{"v1.10.0", "v1.9.9", false},
+		{"1.2", "v1.2.1", true},
+		{"v2.0.0-rc1", "v2.0.0", true},
+		{"v1.0.0", "", false},
+	}
+	for _, tt := range tests {
+		if got := isNewerVersion(tt.current, tt.latest); got != tt.want {
+			t.Errorf("isNewerVersion(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestCheckUpdates(t *testing.T) {
+	latest := map[string]string{"nats-io/natscli": "v0.3.0", "go-task/task": "v3.44.1"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "GITHUB_TOKEN not sent", http.StatusUnauthorized)
+			return
+		}
+		repo := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/repos/"), "/releases/latest")
+		tag, ok := latest[repo]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"tag_name": %q}`, tag)
+	}))
+	defer srv.Close()
+	orig := githubAPIBase
+	githubAPIBase = srv.URL
+	defer func() { githubAPIBase = orig }()
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	updates, err := checkUpdates([]DepBinary{
+		{Name: "nats", Source: "github-release", Repo: "nats-io/natscli", Version: "v0.2.4"},
+		{Name: "garble", Source: "go-build", Repo: "burrowers/garble", Version: "v0.14.2"},
+		{Name: "task", Source: "github-release", Repo: "go-task/task", Version: "v3.44.1"},
+		{Name: "gone", Source: "github-release", Repo: "example/gone", Version: "v1.0.0"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "gone") {
+		t.Errorf("expected the failed lookup to be reported, got %v", err)
+	}
+	if len(updates) != 3 {
+		t.Fatalf("got %d results, want the 3 github-release binaries", len(updates))
+	}
+	if u := updates[0]; u.Name != "nats" || u.Latest != "v0.3.0" || !u.UpdateAvailable {
+		t.Errorf("nats = %+v, want update to v0.3.0", u)
+	}
+	if u := updates[1]; u.Name != "task" || u.UpdateAvailable || u.Err != nil {
+		t.Errorf("task = %+v, want up to date", u)
+	}
+	if u := updates[2]; u.Err == nil {
+		t.Errorf("gone = %+v, want an error", u)
+	}
+}
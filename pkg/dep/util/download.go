@@ -1,50 +1,201 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// ProgressFunc receives the bytes downloaded so far and the total size from
+// Content-Length (-1 when the server doesn't send one).
+type ProgressFunc func(downloaded, total int64)
+
 // DownloadFile downloads a file from URL to destination path with progress tracking
 func DownloadFile(url, destPath string, showProgress bool) error {
+	return download(url, destPath, showProgress, nil)
+}
+
+// DownloadFileWithProgress downloads a file from URL to destination path,
+// reporting byte counts to onProgress instead of drawing a terminal bar.
+func DownloadFileWithProgress(url, destPath string, onProgress ProgressFunc) error {
+	return download(url, destPath, false, onProgress)
+}
+
+// countingReader reports the running byte count of everything read through it.
+type countingReader struct {
+	r          io.Reader
+	total      int64
+	read       int64
+	onProgress ProgressFunc
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if n > 0 {
+		c.read += int64(n)
+		c.onProgress(c.read, c.total)
+	}
+	return n, err
+}
+
+// Downloads go to destPath+partSuffix and are renamed into place only once
+// complete. A partial file left by an interrupted attempt (or an earlier run)
+// is resumed with a Range request; servers that ignore Range get a full
+// re-download.
+const (
+	partSuffix       = ".part"
+	downloadAttempts = 4
+)
+
+// errNotFound marks a 404, which lets mirror downloads fall back to GitHub.
+var errNotFound = errors.New("not found")
+
+// downloadRetryDelay is multiplied by the attempt number between retries.
+var downloadRetryDelay = 2 * time.Second
+
+// retryableError marks failures worth another attempt: dropped connections,
+// short bodies and 5xx responses.
+type retryableError struct{ err error }
+
+func (e retryableError) Error() string { return e.err.Error() }
+func (e retryableError) Unwrap() error { return e.err }
+
+func download(url, destPath string, showProgress bool, onProgress ProgressFunc) error {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(destPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Create destination file
-	out, err := os.Create(destPath)
+	partPath := destPath + partSuffix
+	var err error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(downloadRetryDelay * time.Duration(attempt-1))
+		}
+		err = downloadAttempt(url, partPath, showProgress, onProgress)
+		if err == nil {
+			if err := os.Rename(partPath, destPath); err != nil {
+				return fmt.Errorf("failed to move download into place: %w", err)
+			}
+			return nil
+		}
+		var retry retryableError
+		if !errors.As(err, &retry) {
+			break
+		}
+	}
+
+	// Keep the partial file after transient failures so the next run can
+	// resume; anything else (404, bad range) means it is of no use.
+	var retry retryableError
+	if !errors.As(err, &retry) {
+		os.Remove(partPath)
+	}
+	return err
+}
+
+// downloadAttempt fetches url into partPath, resuming from the bytes already
+// there, and verifies the final size against what the server reported.
+func downloadAttempt(url, partPath string, showProgress bool, onProgress ProgressFunc) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", destPath, err)
+		return fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
-	defer out.Close()
 
 	// Make HTTP request
-	resp, err := http.Get(url)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to download from %s: %w", url, err)
+		return retryableError{fmt.Errorf("failed to download from %s: %w", url, err)}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	// total is the full file size, -1 when unknown.
+	var total int64
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			os.Remove(partPath)
+			return retryableError{fmt.Errorf("unexpected Content-Range %q resuming %s at %d", resp.Header.Get("Content-Range"), url, offset)}
+		}
+		total = size
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusOK:
+		// Either a fresh download or a server that ignored Range.
+		offset = 0
+		total = resp.ContentLength
+		flags |= os.O_TRUNC
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file is already complete (or bogus); only the former is
+		// kept.
+		if _, size, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && size == offset {
+			return nil
+		}
+		os.Remove(partPath)
+		return retryableError{fmt.Errorf("stale partial download of %s discarded", url)}
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("download failed with status %d for %s: %w", resp.StatusCode, url, errNotFound)
+	case resp.StatusCode >= 500:
+		return retryableError{fmt.Errorf("download failed with status %d for %s", resp.StatusCode, url)}
+	default:
 		return fmt.Errorf("download failed with status %d for %s", resp.StatusCode, url)
 	}
 
-	// Download with or without progress
-	fileName := filepath.Base(destPath)
-	contentLength := resp.ContentLength
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", partPath, err)
+	}
+	defer out.Close()
+
+	if err := copyBody(out, resp, offset, total, filepath.Base(strings.TrimSuffix(partPath, partSuffix)), showProgress, onProgress); err != nil {
+		return retryableError{fmt.Errorf("failed to copy downloaded content: %w", err)}
+	}
+
+	if total >= 0 {
+		info, err := out.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", partPath, err)
+		}
+		if info.Size() != total {
+			return retryableError{fmt.Errorf("incomplete download of %s: got %d of %d bytes", url, info.Size(), total)}
+		}
+	}
+	return nil
+}
+
+// copyBody writes the response body to out with or without progress. offset
+// is the number of bytes already on disk and total the full size.
+func copyBody(out io.Writer, resp *http.Response, offset, total int64, fileName string, showProgress bool, onProgress ProgressFunc) error {
+	if onProgress != nil {
+		onProgress(offset, total)
+		_, err := io.Copy(out, &countingReader{r: resp.Body, total: total, read: offset, onProgress: onProgress})
+		return err
+	}
 
 	if !showProgress {
 		// Simple download without progress
-		_, err = io.Copy(out, resp.Body)
+		_, err := io.Copy(out, resp.Body)
 		return err
 	}
 
-	if contentLength > 0 {
+	var err error
+	if contentLength := resp.ContentLength; contentLength > 0 {
 		// Download with full progress bar
 		progressReader := NewProgressReader(resp.Body, contentLength, fileName)
 		_, err = io.Copy(out, progressReader)
@@ -55,12 +206,36 @@ func DownloadFile(url, destPath string, showProgress bool) error {
 		_, err = io.Copy(io.MultiWriter(out, progressWriter), resp.Body)
 		progressWriter.Finish()
 	}
+	return err
+}
 
+// parseContentRange parses "bytes start-end/size" and "bytes */size"; size is
+// -1 when the server sends "*".
+func parseContentRange(v string) (start, size int64, ok bool) {
+	rangePart, sizePart, found := strings.Cut(strings.TrimPrefix(v, "bytes "), "/")
+	if !found || !strings.HasPrefix(v, "bytes ") {
+		return 0, 0, false
+	}
+	size = -1
+	if sizePart != "*" {
+		n, err := strconv.ParseInt(sizePart, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		size = n
+	}
+	if rangePart == "*" {
+		return 0, size, true
+	}
+	first, _, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
 	if err != nil {
-		return fmt.Errorf("failed to copy downloaded content: %w", err)
+		return 0, 0, false
 	}
-
-	return nil
+	return start, size, true
 }
 
 // DownloadToTemp downloads a file to a temporary location with progress
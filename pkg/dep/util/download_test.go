@@ -0,0 +1,120 @@
+package util
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadFileWithProgress(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 256<<10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+		w.Write(payload)
+	}))
+	defer srv.Close()
+
+	var calls int
+	var last, total int64
+	dest := filepath.Join(t.TempDir(), "asset.bin")
+	err := DownloadFileWithProgress(srv.URL, dest, func(downloaded, size int64) {
+		if downloaded < last {
+			t.Errorf("progress went backwards: %d after %d", downloaded, last)
+		}
+		calls++
+		last, total = downloaded, size
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls < 2 {
+		t.Errorf("progress called %d times, want an initial and at least one byte update", calls)
+	}
+	if last != int64(len(payload)) || total != int64(len(payload)) {
+		t.Errorf("final progress = %d/%d, want %d/%d", last, total, len(payload), len(payload))
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Error("downloaded file does not match payload")
+	}
+}
+
+func TestDownloadResumesAfterDroppedConnection(t *testing.T) {
+	downloadRetryDelay = 0
+	t.Cleanup(func() { downloadRetryDelay = 2 * time.Second })
+
+	payload := bytes.Repeat([]byte("0123456789"), 10<<10)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			// Promise the whole file, send a third of it and hang up.
+			w.Header().Set("Content-Length", strconv.Itoa(len(payload)))
+			w.Write(payload[:len(payload)/3])
+			return
+		}
+		http.ServeContent(w, r, "asset.bin", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "asset.bin")
+	if err := DownloadFile(srv.URL, dest, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ranges) != 2 || ranges[0] != "" || ranges[1] != "bytes="+strconv.Itoa(len(payload)/3)+"-" {
+		t.Errorf("Range headers = %q, want none then a resume from %d", ranges, len(payload)/3)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Error("resumed file does not match payload")
+	}
+	if _, err := os.Stat(dest + partSuffix); !os.IsNotExist(err) {
+		t.Error("partial file should be renamed into place")
+	}
+}
+
+func TestDownloadRestartsWhenRangeIgnored(t *testing.T) {
+	payload := []byte("the complete asset")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "asset.bin")
+	if err := os.WriteFile(dest+partSuffix, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := DownloadFile(srv.URL, dest, false); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Errorf("file = %q, want %q", got, payload)
+	}
+}
+
+func TestDownloadDoesNotRetryClientErrors(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "asset.bin")
+	err := DownloadFile(srv.URL, dest, false)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("err = %v, want a 404 failure", err)
+	}
+	if calls != 1 {
+		t.Errorf("server hit %d times, want 1", calls)
+	}
+	if _, err := os.Stat(dest + partSuffix); !os.IsNotExist(err) {
+		t.Error("partial file should be removed after a permanent failure")
+	}
+}
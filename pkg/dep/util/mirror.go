@@ -0,0 +1,81 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnvMirror names an artifact mirror used in place of GitHub when no mirror
+// was set with SetMirror.
+const EnvMirror = "DEP_MIRROR"
+
+// A mirror serves release assets at
+//
+//	<mirror>/<owner>/<repo>/<version>/<asset>
+//
+// where <owner>/<repo> is the GitHub repository and <version> the version
+// string from dep.json (e.g. https://mirror.corp/nats-io/natscli/v0.2.4/nats-0.2.4-linux-amd64.zip).
+// The collector writes exactly this tree under <collection>/mirror, so a
+// plain static file server over that directory (or a bucket synced from it)
+// is a complete mirror.
+var (
+	mirrorMu   sync.RWMutex
+	mirrorBase string
+)
+
+// SetMirror sets the base URL release assets are fetched from before
+// falling back to GitHub. An empty string reverts to DEP_MIRROR.
+func SetMirror(baseURL string) {
+	mirrorMu.Lock()
+	defer mirrorMu.Unlock()
+	mirrorBase = strings.TrimRight(baseURL, "/")
+}
+
+// Mirror returns the active mirror base URL, or "" when none is configured.
+func Mirror() string {
+	mirrorMu.RLock()
+	base := mirrorBase
+	mirrorMu.RUnlock()
+	if base != "" {
+		return base
+	}
+	return strings.TrimRight(os.Getenv(EnvMirror), "/")
+}
+
+// MirrorURL returns where mirror base serves an asset.
+func MirrorURL(base, repo, version, asset string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.Trim(repo, "/") + "/" + version + "/" + asset
+}
+
+// ReleaseAsset identifies a GitHub release asset by its mirror coordinates
+// and its original download URL.
+type ReleaseAsset struct {
+	Repo    string
+	Version string
+	Name    string
+	URL     string
+}
+
+// DownloadReleaseAsset downloads a release asset from the configured mirror,
+// falling back to its GitHub URL when the mirror doesn't have it.
+func DownloadReleaseAsset(asset ReleaseAsset, destPath string, showProgress bool) error {
+	return downloadReleaseAsset(asset, destPath, showProgress, nil)
+}
+
+// DownloadReleaseAssetWithProgress is DownloadReleaseAsset reporting byte
+// counts to onProgress instead of drawing a terminal bar.
+func DownloadReleaseAssetWithProgress(asset ReleaseAsset, destPath string, onProgress ProgressFunc) error {
+	return downloadReleaseAsset(asset, destPath, false, onProgress)
+}
+
+func downloadReleaseAsset(asset ReleaseAsset, destPath string, showProgress bool, onProgress ProgressFunc) error {
+	if base := Mirror(); base != "" {
+		err := download(MirrorURL(base, asset.Repo, asset.Version, asset.Name), destPath, showProgress, onProgress)
+		if !errors.Is(err, errNotFound) {
+			return err
+		}
+	}
+	return download(asset.URL, destPath, showProgress, onProgress)
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMirrorURL(t *testing.T) {
+	tests := []struct {
+		base, repo, version, asset string
+		want                       string
+	}{
+		{"https://mirror.corp", "nats-io/natscli", "v0.2.4", "nats-0.2.4-linux-amd64.zip",
+			"https://mirror.corp/nats-io/natscli/v0.2.4/nats-0.2.4-linux-amd64.zip"},
+		{"https://mirror.corp/dep/", "/owner/repo/", "1.0", "tool.tar.gz",
+			"https://mirror.corp/dep/owner/repo/1.0/tool.tar.gz"},
+	}
+	for _, tt := range tests {
+		if got := MirrorURL(tt.base, tt.repo, tt.version, tt.asset); got != tt.want {
+			t.Errorf("MirrorURL(%q, %q, %q, %q) = %q, want %q", tt.base, tt.repo, tt.version, tt.asset, got, tt.want)
+		}
+	}
+}
+
+func TestDownloadReleaseAssetPrefersMirror(t *testing.T) {
+	var mirrorHits []string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorHits = append(mirrorHits, r.URL.Path)
+		if r.URL.Path == "/owner/repo/v1/cached.zip" {
+			w.Write([]byte("from mirror"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer mirror.Close()
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("from github"))
+	}))
+	defer github.Close()
+
+	t.Setenv(EnvMirror, mirror.URL)
+	dir := t.TempDir()
+
+	tests := []struct {
+		asset string
+		want  string
+	}{
+		{"cached.zip", "from mirror"},
+		{"uncached.zip", "from github"},
+	}
+	for _, tt := range tests {
+		dest := filepath.Join(dir, tt.asset)
+		asset := ReleaseAsset{Repo: "owner/repo", Version: "v1", Name: tt.asset, URL: github.URL + "/" + tt.asset}
+		if err := DownloadReleaseAsset(asset, dest, false); err != nil {
+			t.Fatalf("%s: %v", tt.asset, err)
+		}
+		if got, _ := os.ReadFile(dest); string(got) != tt.want {
+			t.Errorf("%s = %q, want %q", tt.asset, got, tt.want)
+		}
+	}
+	if len(mirrorHits) != 2 || mirrorHits[1] != "/owner/repo/v1/uncached.zip" {
+		t.Errorf("mirror requests = %q", mirrorHits)
+	}
+
+	// SetMirror takes precedence over the environment
+	SetMirror("http://127.0.0.1:1")
+	defer SetMirror("")
+	if got := Mirror(); got != "http://127.0.0.1:1" {
+		t.Errorf("Mirror() = %q after SetMirror", got)
+	}
+}
@@ -0,0 +1,154 @@
+package dep
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ConfigError reports a problem with one dep.json entry.
+type ConfigError struct {
+	Index  int    // position in dep.json
+	Binary string // entry name, empty if missing
+	Field  string // offending field, e.g. "assets[1].match"
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	name := e.Binary
+	if name == "" {
+		name = "unnamed"
+	}
+	if e.Field == "" {
+		return fmt.Sprintf("entry %d (%s): %v", e.Index, name, e.Err)
+	}
+	return fmt.Sprintf("entry %d (%s): %s: %v", e.Index, name, e.Field, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// ValidateConfig checks every entry for the fields its source type needs and
+// returns one error per problem, naming the binary and field. A nil result
+// means the configuration is usable.
+func ValidateConfig(binaries []DepBinary) []error {
+	var errs []error
+	seen := make(map[string]int, len(binaries))
+
+	for i, b := range binaries {
+		fail := func(field, format string, args ...any) {
+			errs = append(errs, &ConfigError{Index: i, Binary: b.Name, Field: field, Err: fmt.Errorf(format, args...)})
+		}
+
+		if strings.TrimSpace(b.Name) == "" {
+			fail("name", "is required")
+		} else if first, dup := seen[b.Name]; dup {
+			fail("name", "duplicates entry %d", first)
+		} else {
+			seen[b.Name] = i
+		}
+		if strings.TrimSpace(b.Version) == "" {
+			fail("version", "is required")
+		}
+
+		for j, command := range b.PostInstall {
+			if strings.TrimSpace(command) == "" {
+				fail(fmt.Sprintf("post_install[%d]", j), "command is empty")
+			}
+		}
+
+		switch b.Source {
+		case "github-release", "macos-app":
+			if !validRepo(b.Repo) {
+				fail("repo", "must be owner/name, got %q", b.Repo)
+			}
+			if len(b.Assets) == 0 {
+				fail("assets", "%s needs at least one asset selector", b.Source)
+			}
+			for j, a := range b.Assets {
+				field := fmt.Sprintf("assets[%d]", j)
+				if a.OS == "" {
+					fail(field+".os", "is required")
+				}
+				if a.Arch == "" {
+					fail(field+".arch", "is required")
+				}
+				if a.Match == "" {
+					fail(field+".match", "is required")
+				} else if _, err := regexp.Compile(a.Match); err != nil {
+					fail(field+".match", "invalid regex %q: %v", a.Match, err)
+				}
+			}
+		case "go-build", "go-install":
+			if !validRepo(b.Repo) {
+				fail("repo", "must be owner/name, got %q", b.Repo)
+			}
+			if b.Package == "" {
+				fail("package", "%s needs a Go package path (use \".\" for the module root)", b.Source)
+			}
+		case "npm-package":
+			if b.Package == "" {
+				fail("package", "is required for %s", b.Source)
+			}
+		case "claude-release":
+		case "":
+			fail("source", "is required")
+		default:
+			fail("source", "unknown source %q", b.Source)
+		}
+	}
+	return errs
+}
+
+func validRepo(repo string) bool {
+	owner, name, ok := strings.Cut(repo, "/")
+	return ok && owner != "" && name != "" && !strings.Contains(name, "/")
+}
+
+// parseConfig decodes and validates a dep.json document. Errors name the
+// offending entry (and line/column for syntax errors) rather than just the
+// file.
+func parseConfig(data []byte) ([]DepBinary, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, describeJSONError(data, err)
+	}
+
+	binaries := make([]DepBinary, len(raw))
+	for i, entry := range raw {
+		dec := json.NewDecoder(bytes.NewReader(entry))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&binaries[i]); err != nil {
+			var peek struct {
+				Name string `json:"name"`
+			}
+			_ = json.Unmarshal(entry, &peek)
+			return nil, &ConfigError{Index: i, Binary: peek.Name, Err: err}
+		}
+	}
+
+	if errs := ValidateConfig(binaries); len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if err := compileAssetPatterns(binaries); err != nil {
+		return nil, err
+	}
+	return binaries, nil
+}
+
+// describeJSONError adds the line and column to JSON syntax errors.
+func describeJSONError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+	offset := int(syntaxErr.Offset)
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	col := offset - bytes.LastIndexByte(data[:offset], '\n')
+	return fmt.Errorf("line %d, column %d: %w", line, col, err)
+}
@@ -0,0 +1,92 @@
+package dep
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig(t *testing.T) {
+	good := []DepBinary{
+		{Name: "bento", Source: "github-release", Repo: "warpstreamlabs/bento", Version: "v1.0.0",
+			Assets: []AssetSelector{{OS: "linux", Arch: "amd64", Match: `linux_amd64\.tar\.gz$`}}},
+		{Name: "garble", Source: "go-build", Repo: "burrowers/garble", Package: ".", Version: "v0.14.2"},
+		{Name: "claude", Source: "claude-release", Repo: "anthropics/claude-code", Version: "latest"},
+	}
+	if errs := ValidateConfig(good); len(errs) != 0 {
+		t.Fatalf("valid config reported errors: %v", errs)
+	}
+
+	tests := []struct {
+		name    string
+		binary  DepBinary
+		wantMsg string
+	}{
+		{"bad regex", DepBinary{Name: "bento", Source: "github-release", Repo: "a/b", Version: "v1",
+			Assets: []AssetSelector{{OS: "linux", Arch: "amd64", Match: `linux_(amd64`}}}, "entry 0 (bento): assets[0].match: invalid regex"},
+		{"no assets", DepBinary{Name: "bento", Source: "github-release", Repo: "a/b", Version: "v1"}, "entry 0 (bento): assets:"},
+		{"go-build without package", DepBinary{Name: "garble", Source: "go-build", Repo: "a/b", Version: "v1"}, "entry 0 (garble): package:"},
+		{"bad repo", DepBinary{Name: "garble", Source: "go-build", Repo: "garble", Package: ".", Version: "v1"}, "entry 0 (garble): repo:"},
+		{"unknown source", DepBinary{Name: "x", Source: "curl", Version: "v1"}, `entry 0 (x): source: unknown source "curl"`},
+		{"missing name", DepBinary{Source: "claude-release", Version: "latest"}, "entry 0 (unnamed): name: is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateConfig([]DepBinary{tt.binary})
+			if len(errs) == 0 {
+				t.Fatal("expected a validation error")
+			}
+			if !strings.Contains(errs[0].Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want it to contain %q", errs[0], tt.wantMsg)
+			}
+		})
+	}
+
+	dup := ValidateConfig([]DepBinary{good[1], good[1]})
+	if len(dup) != 1 || !strings.Contains(dup[0].Error(), "entry 1 (garble): name: duplicates entry 0") {
+		t.Errorf("duplicate names: %v", dup)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	_, err := parseConfig([]byte("[\n  {\"name\": \"a\",\n   \"source\": }\n]"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("syntax error should carry a line number, got %v", err)
+	}
+
+	_, err = parseConfig([]byte(`[{"name": "garble", "source": "go-build", "repo": "burrowers/garble", "version": 1}]`))
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Binary != "garble" {
+		t.Errorf("type error should name the entry, got %v", err)
+	}
+
+	_, err = parseConfig([]byte(`[{"name": "garble", "source": "go-build", "repo": "burrowers/garble", "version": "v1"}]`))
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "package" {
+		t.Errorf("validation error should surface through parseConfig, got %v", err)
+	}
+}
+
+func TestParseConfigCompilesAssetPatterns(t *testing.T) {
+	binaries, err := parseConfig([]byte(`[{"name": "bento", "source": "github-release", "repo": "warpstreamlabs/bento", "version": "v1",
+		"assets": [{"os": "linux", "arch": "amd64", "match": "linux_amd64\\.tar\\.gz$"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binaries[0].Assets[0].re == nil {
+		t.Fatal("asset pattern should be compiled at load time")
+	}
+	if got := builderAssets(binaries[0].Assets)[0].Pattern; got != binaries[0].Assets[0].re {
+		t.Error("builders should receive the cached pattern")
+	}
+
+	_, err = parseConfig([]byte(`[{"name": "bento", "source": "github-release", "repo": "warpstreamlabs/bento", "version": "v1",
+		"assets": [{"os": "linux", "arch": "amd64", "match": "linux_(amd64"}]}]`))
+	if err == nil {
+		t.Fatal("broken pattern should fail the load")
+	}
+	for _, want := range []string{"bento", `"linux_(amd64"`, "missing closing )"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err, want)
+		}
+	}
+}
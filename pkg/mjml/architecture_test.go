@@ -12,17 +12,10 @@ func TestCacheKeyDeterministic(t *testing.T) {
 	data := testData["simple"]
 	
 	// Create cache key multiple times
-	key1, err := renderer.createCacheKey("simple", data)
-	if err != nil {
-		t.Fatalf("Failed to create cache key: %v", err)
-	}
-	
-	key2, err := renderer.createCacheKey("simple", data)
-	if err != nil {
-		t.Fatalf("Failed to create second cache key: %v", err)
-	}
+	key1 := renderer.CacheKey("simple", data)
+	key2 := renderer.CacheKey("simple", data)
 	
-	if key1 != key2 {
+	if key1 == "" || key1 != key2 {
 		t.Errorf("Cache keys are not deterministic: %s != %s", key1, key2)
 	}
 	
@@ -0,0 +1,216 @@
+package mjml
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"sync"
+	"time"
+)
+
+// Recipient is one addressee of a batch send. Data is the template data for
+// this recipient, so each message can be personalised.
+type Recipient struct {
+	Email   string
+	Subject string
+	Data    any
+}
+
+// RecipientResult is the outcome for one recipient; Err is nil when the
+// message was accepted by the SMTP server.
+type RecipientResult struct {
+	Recipient Recipient
+	Err       error
+}
+
+// BatchResult reports every recipient of a batch, in the order given.
+type BatchResult struct {
+	Results []RecipientResult
+}
+
+// Sent returns how many messages were accepted.
+func (b BatchResult) Sent() int {
+	n := 0
+	for _, r := range b.Results {
+		if r.Err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Failures returns the recipients whose message could not be rendered or sent.
+func (b BatchResult) Failures() []RecipientResult {
+	var failed []RecipientResult
+	for _, r := range b.Results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Err joins the per-recipient errors, or returns nil if everything was sent.
+func (b BatchResult) Err() error {
+	var errs []error
+	for _, r := range b.Failures() {
+		errs = append(errs, r.Err)
+	}
+	return errors.Join(errs...)
+}
+
+// batchTemplateName is the name SendBatch loads its template under.
+const batchTemplateName = "batch"
+
+// SendBatch renders the MJML template once per recipient and sends the
+// results with up to concurrency parallel SMTP connections, each reused for
+// many messages. config.MaxPerSecond, when set, caps the send rate across
+// the whole batch. A recipient that fails is recorded in the result and
+// the batch carries on; the error is only for problems that stop the
+// batch from starting, such as a template that doesn't parse.
+func SendBatch(config SMTPConfig, template string, recipients []Recipient, concurrency int) (BatchResult, error) {
+	renderer := NewRenderer(WithFonts(false))
+	if err := renderer.LoadTemplate(batchTemplateName, template); err != nil {
+		return BatchResult{}, err
+	}
+	return sendBatch(config, renderer, batchTemplateName, recipients, concurrency)
+}
+
+func sendBatch(config SMTPConfig, renderer *Renderer, name string, recipients []Recipient, concurrency int) (BatchResult, error) {
+	if config.SMTPHost == "" || config.FromEmail == "" {
+		return BatchResult{}, fmt.Errorf("batch send needs an SMTP host and a from address")
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(recipients) {
+		concurrency = len(recipients)
+	}
+
+	result := BatchResult{Results: make([]RecipientResult, len(recipients))}
+	var throttle <-chan time.Time
+	if config.MaxPerSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(config.MaxPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn := &smtpConn{config: config}
+			defer conn.close()
+			for i := range jobs {
+				rcpt := recipients[i]
+				result.Results[i] = RecipientResult{Recipient: rcpt, Err: sendOne(conn, renderer, name, rcpt, throttle)}
+			}
+		}()
+	}
+	for i := range recipients {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return result, nil
+}
+
+func sendOne(conn *smtpConn, renderer *Renderer, name string, rcpt Recipient, throttle <-chan time.Time) error {
+	html, err := renderer.RenderTemplate(name, rcpt.Data)
+	if err != nil {
+		return fmt.Errorf("failed to render email for %s: %w", rcpt.Email, err)
+	}
+	message, err := buildMessage(conn.config, rcpt.Email, rcpt.Subject, html, nil)
+	if err == nil {
+		message, err = signDKIM(conn.config.DKIM, message)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to build email for %s: %w", rcpt.Email, err)
+	}
+	if throttle != nil {
+		<-throttle
+	}
+	if err := conn.send(rcpt.Email, message); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", rcpt.Email, err)
+	}
+	return nil
+}
+
+// smtpConn is one worker's SMTP session, dialled on first use and kept
+// open between messages.
+type smtpConn struct {
+	config SMTPConfig
+	client *smtp.Client
+}
+
+// dial connects the way smtp.SendMail does: STARTTLS when offered, then
+// PLAIN auth when the server supports it and credentials are set.
+func (c *smtpConn) dial() error {
+	client, err := smtp.Dial(c.config.SMTPHost + ":" + c.config.SMTPPort)
+	if err != nil {
+		return err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.config.SMTPHost}); err != nil {
+			client.Close()
+			return err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok && c.config.Username != "" {
+		auth := smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.SMTPHost)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return err
+		}
+	}
+	c.client = client
+	return nil
+}
+
+// send delivers one message. After a failure the session is reset so the
+// next message can reuse it, or dropped if the reset fails too.
+func (c *smtpConn) send(to string, message []byte) error {
+	if c.client == nil {
+		if err := c.dial(); err != nil {
+			return err
+		}
+	}
+	err := c.transaction(to, message)
+	if err != nil {
+		if resetErr := c.client.Reset(); resetErr != nil {
+			c.close()
+		}
+	}
+	return err
+}
+
+func (c *smtpConn) transaction(to string, message []byte) error {
+	if err := c.client.Mail(c.config.FromEmail); err != nil {
+		return err
+	}
+	if err := c.client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (c *smtpConn) close() {
+	if c.client == nil {
+		return
+	}
+	if err := c.client.Quit(); err != nil {
+		c.client.Close()
+	}
+	c.client = nil
+}
@@ -0,0 +1,145 @@
+package mjml
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeSMTP is a minimal SMTP server that records delivered messages and
+// rejects recipients in reject.
+type fakeSMTP struct {
+	ln     net.Listener
+	reject map[string]bool
+	conns  atomic.Int32
+
+	mu        sync.Mutex
+	delivered map[string]string
+}
+
+func newFakeSMTP(t *testing.T, reject ...string) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTP{ln: ln, reject: map[string]bool{}, delivered: map[string]string{}}
+	for _, r := range reject {
+		s.reject[r] = true
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns.Add(1)
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *fakeSMTP) config() SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	return SMTPConfig{SMTPHost: host, SMTPPort: port, FromEmail: "news@example.com", FromName: "News"}
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(format string, args ...any) { fmt.Fprintf(conn, format+"\r\n", args...) }
+	reply("220 fake ESMTP")
+	var rcpt string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(cmd, "MAIL"), cmd == "RSET", cmd == "NOOP":
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "RCPT"):
+			rcpt = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+			if s.reject[rcpt] {
+				reply("550 no such user")
+				continue
+			}
+			reply("250 ok")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var body strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				body.WriteString(l)
+			}
+			s.mu.Lock()
+			s.delivered[rcpt] = body.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSendBatch(t *testing.T) {
+	srv := newFakeSMTP(t, "bounce@example.com")
+	template := `<mjml><mj-body><mj-section><mj-column><mj-text>Hi {{.Name}}</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+	var recipients []Recipient
+	for i := 0; i < 6; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		if i == 3 {
+			email = "bounce@example.com"
+		}
+		recipients = append(recipients, Recipient{Email: email, Subject: "News", Data: map[string]string{"Name": fmt.Sprintf("User%d", i)}})
+	}
+
+	result, err := SendBatch(srv.config(), template, recipients, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sent() != 5 || len(result.Failures()) != 1 {
+		t.Fatalf("sent %d, failed %v", result.Sent(), result.Failures())
+	}
+	if failed := result.Failures()[0]; failed.Recipient.Email != "bounce@example.com" || !strings.Contains(failed.Err.Error(), "550") {
+		t.Errorf("unexpected failure %+v", failed)
+	}
+	for i, r := range result.Results {
+		if r.Recipient.Email != recipients[i].Email {
+			t.Errorf("result %d is for %s, want %s", i, r.Recipient.Email, recipients[i].Email)
+		}
+	}
+	if n := srv.conns.Load(); n != 2 {
+		t.Errorf("expected one connection per worker, got %d", n)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if body := srv.delivered["user4@example.com"]; !strings.Contains(body, "User4") {
+		t.Errorf("message not personalised:\n%s", body)
+	}
+}
+
+func TestSendBatchRejectsBadTemplate(t *testing.T) {
+	if _, err := SendBatch(SMTPConfig{SMTPHost: "localhost", FromEmail: "a@b.c"}, "{{.Broken", nil, 1); err == nil {
+		t.Fatal("expected a template error")
+	}
+}
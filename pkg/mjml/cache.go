@@ -0,0 +1,124 @@
+package mjml
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// Distributed cache defaults for NewCacheBucket.
+const (
+	DefaultCacheBucket = "mjml_cache"
+	DefaultCacheTTL    = 24 * time.Hour
+)
+
+// WithDistributedCache shares rendered HTML across instances through a NATS
+// KV bucket (see NewCacheBucket). The in-memory cache stays in front of it,
+// so this also enables WithCache. Entries expire with the bucket TTL.
+func WithDistributedCache(kv nats.KeyValue) RendererOption {
+	return func(opts *RenderOptions) {
+		opts.EnableCache = true
+		opts.DistributedCache = kv
+	}
+}
+
+// NewCacheBucket opens the KV bucket used by WithDistributedCache, creating
+// it with the given TTL (DefaultCacheTTL when zero) if it doesn't exist. An
+// existing bucket has its TTL brought in line.
+func NewCacheBucket(nc *nats.Conn, bucket string, ttl time.Duration) (nats.KeyValue, error) {
+	if bucket == "" {
+		bucket = DefaultCacheBucket
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	kv, err := js.CreateKeyValue(&nats.KeyValueConfig{
+		Bucket:      bucket,
+		Description: "Rendered MJML email HTML",
+		Replicas:    1,
+		TTL:         ttl,
+	})
+	if errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		kv, err = updateCacheBucketTTL(js, bucket, ttl)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mjml cache bucket: %w", err)
+	}
+	return kv, nil
+}
+
+// updateCacheBucketTTL changes the max age of an existing cache bucket.
+func updateCacheBucketTTL(js nats.JetStreamContext, bucket string, ttl time.Duration) (nats.KeyValue, error) {
+	info, err := js.StreamInfo("KV_" + bucket)
+	if err != nil {
+		return nil, err
+	}
+	streamConfig := info.Config
+	streamConfig.MaxAge = ttl
+	if _, err := js.UpdateStream(&streamConfig); err != nil {
+		return nil, err
+	}
+	return js.KeyValue(bucket)
+}
+
+// templateVersion identifies the source a template was parsed from,
+// partials included. It is part of every distributed cache key, so
+// reloading a changed template stops other instances' entries for the old
+// version from being served; those age out with the bucket TTL.
+func templateVersion(source, partialsVersion string) string {
+	sum := sha256.Sum256([]byte(partialsVersion + "\x00" + source))
+	return fmt.Sprintf("%x", sum[:6])
+}
+
+// distributedKey maps an in-memory cache key to a KV key. KV keys only allow
+// letters, digits and -/_=. so anything else in the template name becomes _.
+func distributedKey(name, version, cacheKey string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '=':
+			return r
+		}
+		return '_'
+	}, name)
+	hash := cacheKey[strings.LastIndex(cacheKey, "_")+1:]
+	return safe + "." + version + "." + hash
+}
+
+// getDistributed looks a rendered template up in the KV bucket. Errors
+// count as misses: the cache must never fail a render.
+func (r *Renderer) getDistributed(key string) (string, bool) {
+	kv := r.options.DistributedCache
+	if kv == nil {
+		return "", false
+	}
+	entry, err := kv.Get(key)
+	if err != nil {
+		if !errors.Is(err, nats.ErrKeyNotFound) {
+			log.Warn("MJML distributed cache read failed", "key", key, "error", err)
+		}
+		return "", false
+	}
+	return string(entry.Value()), true
+}
+
+// putDistributed stores rendered HTML in the KV bucket, best effort.
+func (r *Renderer) putDistributed(key, html string) {
+	kv := r.options.DistributedCache
+	if kv == nil {
+		return
+	}
+	if _, err := kv.Put(key, []byte(html)); err != nil {
+		log.Warn("MJML distributed cache write failed", "key", key, "error", err)
+	}
+}
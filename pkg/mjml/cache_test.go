@@ -0,0 +1,69 @@
+package mjml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+const cacheTestTemplate = `<mjml><mj-body><mj-section><mj-column><mj-text>Hello {{.Name}}</mj-text></mj-column></mj-section></mj-body></mjml>`
+
+func TestDistributedCacheSharedAcrossRenderers(t *testing.T) {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, JetStream: true, StoreDir: t.TempDir(), NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	defer ns.Shutdown()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nc.Close()
+
+	kv, err := NewCacheBucket(nc, "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, err := kv.Status(); err != nil || status.TTL() != time.Hour {
+		t.Fatalf("bucket status %v, %v", status, err)
+	}
+
+	first := NewRenderer(WithDistributedCache(kv), WithFonts(false))
+	second := NewRenderer(WithDistributedCache(kv), WithFonts(false))
+	for _, r := range []*Renderer{first, second} {
+		if err := r.LoadTemplate("welcome", cacheTestTemplate); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data := map[string]string{"Name": "Ada"}
+	if _, err := first.RenderTemplate("welcome", data); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := kv.Keys()
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("expected one shared entry, got %v, %v", keys, err)
+	}
+
+	// The second renderer serves whatever the bucket holds for the key.
+	if _, err := kv.Put(keys[0], []byte("from-kv")); err != nil {
+		t.Fatal(err)
+	}
+	if html, err := second.RenderTemplate("welcome", data); err != nil || html != "from-kv" {
+		t.Fatalf("expected a distributed cache hit, got %q, %v", html, err)
+	}
+
+	// Reloading a changed template moves it to new keys.
+	if err := second.LoadTemplate("welcome", cacheTestTemplate+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if html, err := second.RenderTemplate("welcome", data); err != nil || html == "from-kv" {
+		t.Fatalf("reloaded template served a stale entry: %q, %v", html, err)
+	}
+}
@@ -0,0 +1,110 @@
+package mjml
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-msgauth/dkim"
+)
+
+// verifyDKIM checks the DKIM-Signature on raw with go-msgauth, serving the
+// public key record from a fake DNS lookup, and returns the signature tags.
+func verifyDKIM(t *testing.T, raw []byte, record string) map[string]string {
+	t.Helper()
+	verifications, err := dkim.VerifyWithOptions(bytes.NewReader(raw), &dkim.VerifyOptions{
+		LookupTXT: func(domain string) ([]string, error) {
+			if domain != "mail._domainkey.example.com" {
+				return nil, fmt.Errorf("unexpected lookup %q", domain)
+			}
+			return []string{record}, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verifications) != 1 {
+		t.Fatalf("got %d signatures, want 1", len(verifications))
+	}
+	if v := verifications[0]; v.Err != nil || v.Domain != "example.com" {
+		t.Fatalf("verification: domain %q, err %v", v.Domain, v.Err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse signed message: %v", err)
+	}
+	tags := map[string]string{}
+	for _, tag := range strings.Split(msg.Header.Get("DKIM-Signature"), ";") {
+		k, v, _ := strings.Cut(tag, "=")
+		tags[strings.TrimSpace(k)] = strings.Join(strings.Fields(v), "")
+	}
+	return tags
+}
+
+func TestSignDKIM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPub, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER})
+
+	config := SMTPConfig{FromEmail: "news@example.com", FromName: "News"}
+	raw, err := buildMessage(config, "alice@example.com", "Hello  there", "<p>Hi</p>\n<p>Bye</p>\n\n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if unsigned, _ := signDKIM(nil, raw); !bytes.Equal(unsigned, raw) {
+		t.Fatal("messages should be untouched without a DKIM config")
+	}
+
+	for name, tc := range map[string]struct {
+		pem    []byte
+		record string
+		algo   string
+	}{
+		"rsa":     {rsaPEM, "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(rsaPub), "rsa-sha256"},
+		"ed25519": {edPEM, "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(edPub), "ed25519-sha256"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signed, err := signDKIM(&DKIMConfig{Domain: "example.com", Selector: "mail", PrivateKeyPEM: string(tc.pem)}, raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tags := verifyDKIM(t, signed, tc.record)
+			if tags["a"] != tc.algo || tags["d"] != "example.com" || tags["s"] != "mail" || tags["c"] != "relaxed/relaxed" {
+				t.Errorf("tags %v", tags)
+			}
+			if tags["h"] != "From:To:Subject:MIME-Version:Content-Type" {
+				t.Errorf("signed headers %s", tags["h"])
+			}
+		})
+	}
+
+	if _, err := signDKIM(&DKIMConfig{Domain: "example.com", Selector: "mail", PrivateKeyPEM: "nope"}, raw); err == nil {
+		t.Error("expected an error for a bad key")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -10,6 +11,9 @@ import (
 )
 
 func main() {
+	serve := flag.String("serve", "", "serve a live template preview on this address (e.g. :8025) instead of writing HTML files")
+	flag.Parse()
+
 	// Create renderer with caching enabled
 	renderer := mjml.NewRenderer(
 		mjml.WithCache(true),
@@ -25,6 +29,11 @@ func main() {
 
 	fmt.Printf("Loaded templates: %v\n\n", renderer.ListTemplates())
 
+	if *serve != "" {
+		fmt.Printf("Previewing templates on http://localhost%s\n", *serve)
+		log.Fatal(mjml.PreviewServer(*serve, renderer))
+	}
+
 	// Example 1: Simple email
 	fmt.Println("=== Simple Email Example ===")
 	simpleData := mjml.EmailData{
@@ -0,0 +1,186 @@
+package mjml
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// EnvPDFBrowser overrides the headless Chrome/Chromium used by RenderToPDF.
+const EnvPDFBrowser = "MJML_PDF_BROWSER"
+
+// ErrPDFRendererUnavailable is returned when no headless browser can be found.
+var ErrPDFRendererUnavailable = errors.New("no headless Chrome/Chromium found for PDF rendering (install one or set " + EnvPDFBrowser + ")")
+
+const (
+	pdfRenderTimeout = 60 * time.Second
+	pdfImageTimeout  = 10 * time.Second
+	pdfImageMaxBytes = 10 << 20
+)
+
+// pdfBrowserNames are looked up on PATH when MJML_PDF_BROWSER isn't set.
+var pdfBrowserNames = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome", "headless_shell"}
+
+// remoteImageRef matches src/background attributes and CSS url() values that
+// point at http(s) URLs; the URL is the last non-empty submatch.
+var remoteImageRef = regexp.MustCompile(`(?i)(?:\b(?:src|background)\s*=\s*["'](https?://[^"']+)["']|url\(\s*["']?(https?://[^"')]+)["']?\s*\))`)
+
+// RenderToPDF converts rendered email HTML, e.g. the output of
+// RenderTemplate, to a PDF for archiving. Remote images are fetched and
+// embedded as data URIs first so the PDF doesn't depend on them staying
+// online. Rendering uses a headless Chrome/Chromium.
+func RenderToPDF(html string) ([]byte, error) {
+	browser, err := findPDFBrowser()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pdfRenderTimeout)
+	defer cancel()
+
+	html = inlineRemoteImages(ctx, &http.Client{Timeout: pdfImageTimeout}, html)
+
+	dir, err := os.MkdirTemp("", "mjml-pdf-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	htmlPath := filepath.Join(dir, "email.html")
+	pdfPath := filepath.Join(dir, "email.pdf")
+	if err := os.WriteFile(htmlPath, []byte(html), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write email HTML: %w", err)
+	}
+
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		"--no-pdf-header-footer",
+		"--user-data-dir=" + filepath.Join(dir, "profile"),
+		"--print-to-pdf=" + pdfPath,
+	}
+	if runtime.GOOS == "linux" && os.Geteuid() == 0 {
+		// Chromium refuses to start its sandbox as root (containers, CI).
+		args = append(args, "--no-sandbox")
+	}
+	args = append(args, "file://"+filepath.ToSlash(htmlPath))
+
+	cmd := exec.CommandContext(ctx, browser, args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed to print PDF: %w: %s", browser, err, strings.TrimSpace(output.String()))
+	}
+
+	pdf, err := os.ReadFile(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s produced no PDF: %w", browser, err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		return nil, fmt.Errorf("%s produced an invalid PDF", browser)
+	}
+	return pdf, nil
+}
+
+// findPDFBrowser resolves the headless browser: MJML_PDF_BROWSER, then
+// well-known names on PATH, then the default macOS app bundles.
+func findPDFBrowser() (string, error) {
+	if path := os.Getenv(EnvPDFBrowser); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%w: %s=%s: %v", ErrPDFRendererUnavailable, EnvPDFBrowser, path, err)
+		}
+		return path, nil
+	}
+	for _, name := range pdfBrowserNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	if runtime.GOOS == "darwin" {
+		for _, path := range []string{
+			"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+			"/Applications/Chromium.app/Contents/MacOS/Chromium",
+		} {
+			if fileExists(path) {
+				return path, nil
+			}
+		}
+	}
+	return "", ErrPDFRendererUnavailable
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// inlineRemoteImages replaces http(s) image references with data URIs. An
+// image that can't be fetched is left as is and logged; a missing logo
+// shouldn't stop the email from being archived.
+func inlineRemoteImages(ctx context.Context, client *http.Client, html string) string {
+	inlined := make(map[string]string)
+	return remoteImageRef.ReplaceAllStringFunc(html, func(ref string) string {
+		m := remoteImageRef.FindStringSubmatch(ref)
+		url := m[1]
+		if url == "" {
+			url = m[2]
+		}
+
+		dataURI, ok := inlined[url]
+		if !ok {
+			var err error
+			if dataURI, err = fetchDataURI(ctx, client, url); err != nil {
+				log.Warn("Could not embed remote image in PDF", "url", url, "error", err)
+			}
+			inlined[url] = dataURI
+		}
+		if dataURI == "" {
+			return ref
+		}
+		return strings.Replace(ref, url, dataURI, 1)
+	})
+}
+
+func fetchDataURI(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, pdfImageMaxBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > pdfImageMaxBytes {
+		return "", fmt.Errorf("image larger than %d bytes", pdfImageMaxBytes)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = http.DetectContentType(data)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,69 @@
+package mjml
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestInlineRemoteImages(t *testing.T) {
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/logo.png" {
+			http.NotFound(w, r)
+			return
+		}
+		fetches++
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png-bytes"))
+	}))
+	defer srv.Close()
+
+	html := `<img src="` + srv.URL + `/logo.png"><td background='` + srv.URL + `/logo.png'>` +
+		`<div style="background-image: url(` + srv.URL + `/logo.png)"></div><img src="` + srv.URL + `/missing.png">`
+	got := inlineRemoteImages(context.Background(), srv.Client(), html)
+
+	if n := strings.Count(got, "data:image/png;base64,cG5nLWJ5dGVz"); n != 3 {
+		t.Errorf("expected 3 embedded images, got %d in %s", n, got)
+	}
+	if fetches != 1 {
+		t.Errorf("logo fetched %d times, want 1", fetches)
+	}
+	if !strings.Contains(got, srv.URL+"/missing.png") {
+		t.Error("unfetchable image should be left untouched")
+	}
+}
+
+func TestRenderToPDFWithoutBrowser(t *testing.T) {
+	t.Setenv(EnvPDFBrowser, filepath.Join(t.TempDir(), "no-such-chrome"))
+	if _, err := RenderToPDF("<html></html>"); !errors.Is(err, ErrPDFRendererUnavailable) {
+		t.Fatalf("expected ErrPDFRendererUnavailable, got %v", err)
+	}
+}
+
+func TestRenderToPDF(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake browser is a shell script")
+	}
+	// A stand-in browser that writes a PDF wherever --print-to-pdf points.
+	browser := filepath.Join(t.TempDir(), "chrome")
+	script := "#!/bin/sh\nfor a in \"$@\"; do case $a in --print-to-pdf=*) printf '%%PDF-1.4 fake' > \"${a#--print-to-pdf=}\";; esac; done\n"
+	if err := os.WriteFile(browser, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvPDFBrowser, browser)
+
+	pdf, err := RenderToPDF("<html><body>Invoice</body></html>")
+	if err != nil {
+		t.Fatalf("RenderToPDF: %v", err)
+	}
+	if string(pdf) != "%PDF-1.4 fake" {
+		t.Errorf("pdf = %q", pdf)
+	}
+}
@@ -0,0 +1,118 @@
+package mjml
+
+import (
+	"html/template"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+var (
+	sampleDataMu sync.RWMutex
+	sampleData   = make(map[string]any)
+)
+
+// RegisterSampleData sets the data the preview server renders a template
+// with. Templates without registered data fall back to TestData.
+func RegisterSampleData(name string, data any) {
+	sampleDataMu.Lock()
+	defer sampleDataMu.Unlock()
+	sampleData[name] = data
+}
+
+// sampleDataFor returns the preview data for a template
+func sampleDataFor(name string) any {
+	sampleDataMu.RLock()
+	data, ok := sampleData[name]
+	sampleDataMu.RUnlock()
+	if ok {
+		return data
+	}
+
+	defaults := TestData()
+	if data, ok := defaults[name]; ok {
+		return data
+	}
+	return defaults["simple"]
+}
+
+// PreviewServer serves a live preview of the renderer's templates on addr:
+// an index of all templates and each one rendered with its sample data.
+// Templates are re-read from the renderer's template directory on every
+// request, so edits show up on refresh. It blocks like http.ListenAndServe.
+func PreviewServer(addr string, renderer *Renderer) error {
+	log.Info("MJML preview server listening", "addr", addr, "template_dir", renderer.options.TemplateDir)
+	return http.ListenAndServe(addr, previewHandler(renderer))
+}
+
+var previewIndex = template.Must(template.New("index").Parse(`<!doctype html>
+<html><head><title>MJML templates</title></head>
+<body style="font-family: sans-serif">
+<h1>MJML templates</h1>
+{{if .Error}}<pre style="color: #c0392b">{{.Error}}</pre>{{end}}
+<ul>
+{{range .Templates}}<li><a href="/preview/{{.}}">{{.}}</a></li>
+{{else}}<li>No templates loaded from {{.Dir}}</li>
+{{end}}</ul>
+</body></html>
+`))
+
+func previewHandler(renderer *Renderer) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		var loadErr string
+		if err := reloadPreviewTemplates(renderer); err != nil {
+			loadErr = err.Error()
+		}
+		names := renderer.ListTemplates()
+		sort.Strings(names)
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := previewIndex.Execute(w, map[string]any{
+			"Templates": names,
+			"Dir":       renderer.options.TemplateDir,
+			"Error":     loadErr,
+		}); err != nil {
+			log.Error("Failed to render preview index", "error", err)
+		}
+	})
+
+	mux.HandleFunc("GET /preview/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if err := reloadPreviewTemplates(renderer); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		name := r.PathValue("name")
+		if !renderer.HasTemplate(name) {
+			http.Error(w, "template "+name+" not found", http.StatusNotFound)
+			return
+		}
+		html, err := renderer.RenderTemplate(name, sampleDataFor(name))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(html))
+	})
+
+	return mux
+}
+
+// reloadPreviewTemplates re-reads the template directory, if there is one,
+// and drops cached output so the next render reflects the files on disk.
+func reloadPreviewTemplates(renderer *Renderer) error {
+	dir := renderer.options.TemplateDir
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil
+	}
+	if err := renderer.LoadTemplatesFromDir(dir); err != nil {
+		return err
+	}
+	renderer.ClearCache()
+	return nil
+}
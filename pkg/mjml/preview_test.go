@@ -0,0 +1,57 @@
+package mjml
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPreviewHandler(t *testing.T) {
+	dir := t.TempDir()
+	tmplPath := filepath.Join(dir, "greeting.mjml")
+	write := func(text string) {
+		t.Helper()
+		content := `<mjml><mj-body><mj-section><mj-column><mj-text>` + text + `</mj-text></mj-column></mj-section></mj-body></mjml>`
+		if err := os.WriteFile(tmplPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("Hello {{.Name}}")
+	RegisterSampleData("greeting", EmailData{Name: "Preview Person"})
+
+	renderer := NewRenderer(WithTemplateDir(dir), WithCache(true), WithFonts(false))
+	srv := httptest.NewServer(previewHandler(renderer))
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/"); code != http.StatusOK || !strings.Contains(body, `href="/preview/greeting"`) {
+		t.Fatalf("index = %d %s", code, body)
+	}
+	if _, body := get("/preview/greeting"); !strings.Contains(body, "Hello Preview Person") {
+		t.Errorf("preview did not use registered sample data: %s", body)
+	}
+
+	// Edits on disk show up on the next request
+	write("Goodbye {{.Name}}")
+	if _, body := get("/preview/greeting"); !strings.Contains(body, "Goodbye Preview Person") {
+		t.Errorf("preview did not pick up the edited template")
+	}
+
+	if code, _ := get("/preview/missing"); code != http.StatusNotFound {
+		t.Errorf("unknown template = %d, want 404", code)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/nats-io/nats.go"
 	"github.com/preslavrachev/gomjml/mjml"
 	"github.com/joeblew999/infra/pkg/config"
 	"github.com/joeblew999/infra/pkg/font"
@@ -24,20 +25,25 @@ import (
 
 // Renderer handles MJML template loading, caching, and rendering
 type Renderer struct {
-	templates   map[string]*template.Template
-	cache       map[string]string // Cache for rendered HTML
-	mu          sync.RWMutex
-	options     *RenderOptions
-	fontManager *font.Manager
+	templates       map[string]*template.Template
+	sources         map[string]string  // Raw template sources, kept to re-parse when partials change
+	partials        *template.Template // Shared layouts and partials every template can include
+	versions        map[string]string  // Source version of each template, see templateVersion
+	partialsVersion string             // Running hash of every loaded partial
+	cache           map[string]string  // Cache for rendered HTML
+	mu              sync.RWMutex
+	options         *RenderOptions
+	fontManager     *font.Manager
 }
 
 // RenderOptions configures the MJML renderer behavior
 type RenderOptions struct {
-	EnableCache      bool   // Cache rendered HTML for performance
-	EnableDebug      bool   // Add debug attributes to HTML
-	EnableValidation bool   // Validate MJML before rendering
-	TemplateDir      string // Default directory for templates
-	EnableFonts      bool   // Enable Google Fonts integration
+	EnableCache      bool          // Cache rendered HTML for performance
+	EnableDebug      bool          // Add debug attributes to HTML
+	EnableValidation bool          // Validate MJML before rendering
+	TemplateDir      string        // Default directory for templates
+	EnableFonts      bool          // Enable Google Fonts integration
+	DistributedCache nats.KeyValue // Shared second-level HTML cache, see WithDistributedCache
 }
 
 // RendererOption configures the renderer
@@ -94,6 +100,9 @@ func NewRenderer(opts ...RendererOption) *Renderer {
 
 	renderer := &Renderer{
 		templates: make(map[string]*template.Template),
+		sources:   make(map[string]string),
+		versions:  make(map[string]string),
+		partials:  template.New(""),
 		cache:     make(map[string]string),
 		options:   options,
 	}
@@ -106,26 +115,70 @@ func NewRenderer(opts ...RendererOption) *Renderer {
 	return renderer
 }
 
-// LoadTemplate loads a single MJML template with the given name
+// LoadTemplate loads a single MJML template with the given name. The
+// template can include any loaded partial with {{template "name" .}}.
 func (r *Renderer) LoadTemplate(name, content string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	tmpl, err := template.New(name).Parse(content)
+	tmpl, err := r.parseTemplate(name, content)
 	if err != nil {
-		return fmt.Errorf("failed to parse template %s: %w", name, err)
+		return err
 	}
 
 	r.templates[name] = tmpl
+	r.sources[name] = content
+	r.versions[name] = templateVersion(content, r.partialsVersion)
 	
 	// Clear cache for this template
+	r.dropCached(name)
+	
+	return nil
+}
+
+// LoadPartial registers a layout or partial shared by all templates. The
+// content is available as {{template "name" .}} along with any blocks it
+// defines. A layout can call a block such as {{template "content" .}} that
+// each template fills in with {{define "content"}}...{{end}}.
+func (r *Renderer) LoadPartial(name, content string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.partials.New(name).Parse(content); err != nil {
+		return fmt.Errorf("failed to parse partial %s: %w", name, err)
+	}
+
+	r.partialsVersion = templateVersion(name+"\x00"+content, r.partialsVersion)
+
+	// Re-parse loaded templates so they see the new partial
+	for tmplName, source := range r.sources {
+		tmpl, err := r.parseTemplate(tmplName, source)
+		if err != nil {
+			return err
+		}
+		r.templates[tmplName] = tmpl
+		r.versions[tmplName] = templateVersion(source, r.partialsVersion)
+	}
 	if r.options.EnableCache {
-		delete(r.cache, name)
+		r.cache = make(map[string]string)
 	}
-	
 	return nil
 }
 
+// parseTemplate parses content as name on top of a copy of the partials, so
+// blocks defined by one template don't leak into another.
+func (r *Renderer) parseTemplate(name, content string) (*template.Template, error) {
+	set, err := r.partials.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone partials for template %s: %w", name, err)
+	}
+	tmpl, err := set.New(name).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
 // LoadTemplateFromFile loads a single MJML template from a file
 func (r *Renderer) LoadTemplateFromFile(name, filePath string) error {
 	content, err := os.ReadFile(filePath)
@@ -136,13 +189,41 @@ func (r *Renderer) LoadTemplateFromFile(name, filePath string) error {
 	return r.LoadTemplate(name, string(content))
 }
 
-// LoadTemplatesFromDir loads all .mjml files from a directory
+// partialsDirName is the template subdirectory holding shared partials
+const partialsDirName = "partials"
+
+// LoadTemplatesFromDir loads all .mjml files from a directory. Files under
+// its partials/ subdirectory are registered as partials (named after the
+// file) before the templates are loaded.
 func (r *Renderer) LoadTemplatesFromDir(dir string) error {
+	partialsDir := filepath.Join(dir, partialsDirName)
+	if info, err := os.Stat(partialsDir); err == nil && info.IsDir() {
+		err := filepath.WalkDir(partialsDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".mjml") {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read partial file %s: %w", path, err)
+			}
+			return r.LoadPartial(strings.TrimSuffix(filepath.Base(path), ".mjml"), string(content))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 		
+		if d.IsDir() && path == partialsDir {
+			return filepath.SkipDir
+		}
 		if d.IsDir() || !strings.HasSuffix(path, ".mjml") {
 			return nil
 		}
@@ -157,6 +238,7 @@ func (r *Renderer) LoadTemplatesFromDir(dir string) error {
 func (r *Renderer) RenderTemplate(name string, data any) (string, error) {
 	r.mu.RLock()
 	tmpl, exists := r.templates[name]
+	version := r.versions[name]
 	r.mu.RUnlock()
 	
 	if !exists {
@@ -164,19 +246,25 @@ func (r *Renderer) RenderTemplate(name string, data any) (string, error) {
 	}
 
 	// Create deterministic cache key based on template name and data content
-	cacheKey, err := r.createCacheKey(name, data)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cache key for template %s: %w", name, err)
-	}
+	cacheKey := r.CacheKey(name, data)
+	caching := r.options.EnableCache && cacheKey != ""
 	
 	// Check cache if enabled
-	if r.options.EnableCache {
+	if caching {
 		r.mu.RLock()
 		if cached, found := r.cache[cacheKey]; found {
 			r.mu.RUnlock()
 			return cached, nil
 		}
 		r.mu.RUnlock()
+
+		// Another instance may already have rendered it
+		if cached, found := r.getDistributed(distributedKey(name, version, cacheKey)); found {
+			r.mu.Lock()
+			r.cache[cacheKey] = cached
+			r.mu.Unlock()
+			return cached, nil
+		}
 	}
 
 	// Execute template to get MJML
@@ -194,10 +282,11 @@ func (r *Renderer) RenderTemplate(name string, data any) (string, error) {
 	}
 
 	// Cache result if enabled
-	if r.options.EnableCache {
+	if caching {
 		r.mu.Lock()
 		r.cache[cacheKey] = html
 		r.mu.Unlock()
+		r.putDistributed(distributedKey(name, version, cacheKey), html)
 	}
 
 	return html, nil
@@ -208,6 +297,10 @@ func (r *Renderer) RenderString(mjmlContent string) (string, error) {
 	return r.renderMJML(mjmlContent)
 }
 
+// mjmlRenderMu serialises gomjml renders: gomjml keeps the current
+// document's mj-attributes in package globals while it renders.
+var mjmlRenderMu sync.Mutex
+
 // renderMJML converts MJML content to HTML using gomjml
 func (r *Renderer) renderMJML(mjmlContent string) (string, error) {
 	var mjmlOpts []mjml.RenderOption
@@ -220,7 +313,9 @@ func (r *Renderer) renderMJML(mjmlContent string) (string, error) {
 		mjmlOpts = append(mjmlOpts, mjml.WithCache())
 	}
 
+	mjmlRenderMu.Lock()
 	html, err := mjml.Render(mjmlContent, mjmlOpts...)
+	mjmlRenderMu.Unlock()
 	if err != nil {
 		return "", fmt.Errorf("gomjml render failed: %w", err)
 	}
@@ -255,18 +350,29 @@ func (r *Renderer) RemoveTemplate(name string) {
 	defer r.mu.Unlock()
 	
 	delete(r.templates, name)
+	delete(r.sources, name)
+	delete(r.versions, name)
 	
 	// Clear cache entries for this template
-	if r.options.EnableCache {
-		for key := range r.cache {
-			if strings.HasPrefix(key, name+"_") {
-				delete(r.cache, key)
-			}
+	r.dropCached(name)
+}
+
+// dropCached removes a template's entries from the in-memory cache. Entries
+// in the distributed cache are keyed by template version, so they stop
+// matching on their own. The caller must hold r.mu.
+func (r *Renderer) dropCached(name string) {
+	if !r.options.EnableCache {
+		return
+	}
+	for key := range r.cache {
+		if strings.HasPrefix(key, name+"_") {
+			delete(r.cache, key)
 		}
 	}
 }
 
-// ClearCache clears all cached rendered HTML
+// ClearCache clears all cached rendered HTML in this instance. The
+// distributed cache, if any, is shared and left alone.
 func (r *Renderer) ClearCache() {
 	if !r.options.EnableCache {
 		return
@@ -290,21 +396,45 @@ func (r *Renderer) GetCacheSize() int {
 	return len(r.cache)
 }
 
-// createCacheKey creates a deterministic cache key based on template name and data content
-func (r *Renderer) createCacheKey(name string, data any) (string, error) {
-	// Serialize data to JSON for consistent hashing
-	dataBytes, err := json.Marshal(data)
+// CacheKey returns the render cache key for a template and its data:
+// the template name plus a hash of the data's Go type and its canonical
+// JSON. The type is part of the key because templates see Go values, not
+// JSON: a struct and a map with the same JSON can render differently (field
+// names vs json tags, methods). Maps with the same entries get the same key
+// whatever their insertion order. Data that can't be encoded as JSON
+// (channels, functions) returns "" and is rendered without caching.
+func (r *Renderer) CacheKey(name string, data any) string {
+	canonical, err := canonicalJSON(data)
 	if err != nil {
-		return "", fmt.Errorf("failed to serialize data for caching: %w", err)
+		return ""
 	}
-	
-	// Create hash of template name + data content
+
 	hasher := sha256.New()
 	hasher.Write([]byte(name))
-	hasher.Write(dataBytes)
+	hasher.Write([]byte{0})
+	fmt.Fprintf(hasher, "%T", data)
+	hasher.Write([]byte{0})
+	hasher.Write(canonical)
 	hash := fmt.Sprintf("%x", hasher.Sum(nil))
-	
-	return fmt.Sprintf("%s_%s", name, hash[:16]), nil // Use first 16 chars of hash
+
+	return fmt.Sprintf("%s_%s", name, hash[:16]) // Use first 16 chars of hash
+}
+
+// canonicalJSON encodes data, then decodes and re-encodes it generically so
+// the result depends only on the values: encoding/json sorts map keys, so
+// map iteration order drops out, and UseNumber keeps large integers exact.
+func canonicalJSON(data any) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
 }
 
 // LoadFont downloads and caches a Google Font for use in email templates
@@ -2,6 +2,7 @@ package mjml
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -233,4 +234,103 @@ func TestCanonicalTestDataStructures(t *testing.T) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+func TestLoadTemplatesFromDirWithPartials(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"partials/layout.mjml": `<mjml>
+		<mj-head><mj-title>{{.Subject}}</mj-title></mj-head>
+		<mj-body>
+			{{template "header" .}}
+			<mj-section><mj-column>{{template "content" .}}</mj-column></mj-section>
+			{{template "footer" .}}
+		</mj-body>
+	</mjml>`,
+		"partials/header.mjml": `<mj-section><mj-column><mj-text>{{.CompanyName}} header</mj-text></mj-column></mj-section>`,
+		"partials/footer.mjml": `<mj-section><mj-column><mj-text>Sent by {{.CompanyName}}</mj-text></mj-column></mj-section>`,
+		"welcome.mjml":         `{{define "content"}}<mj-text>Welcome {{.Name}}</mj-text>{{end}}{{template "layout" .}}`,
+		"reset.mjml":           `{{define "content"}}<mj-text>Reset for {{.Name}}</mj-text>{{end}}{{template "layout" .}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	renderer := NewRenderer(WithFonts(false))
+	if err := renderer.LoadTemplatesFromDir(dir); err != nil {
+		t.Fatalf("LoadTemplatesFromDir failed: %v", err)
+	}
+	if got := len(renderer.ListTemplates()); got != 2 {
+		t.Fatalf("loaded %d templates %v, want welcome and reset only", got, renderer.ListTemplates())
+	}
+
+	data := EmailData{Name: "Ada", Subject: "Hi", CompanyName: "Acme"}
+	for name, want := range map[string]string{"welcome": "Welcome Ada", "reset": "Reset for Ada"} {
+		html, err := renderer.RenderTemplate(name, data)
+		if err != nil {
+			t.Fatalf("RenderTemplate(%s) failed: %v", name, err)
+		}
+		for _, s := range []string{want, "Acme header", "Sent by Acme"} {
+			if !strings.Contains(html, s) {
+				t.Errorf("%s: rendered HTML missing %q", name, s)
+			}
+		}
+	}
+
+	// A partial loaded later is picked up by already loaded templates
+	if err := renderer.LoadPartial("footer", `<mj-section><mj-column><mj-text>New footer</mj-text></mj-column></mj-section>`); err != nil {
+		t.Fatal(err)
+	}
+	html, err := renderer.RenderTemplate("welcome", data)
+	if err != nil || !strings.Contains(html, "New footer") {
+		t.Errorf("updated partial not used: %v", err)
+	}
+}
+
+func TestCacheKeyNormalizesData(t *testing.T) {
+	r := NewRenderer(WithCache(true), WithFonts(false))
+
+	type message struct {
+		Subject string `json:"subject"`
+		Count   int    `json:"count"`
+	}
+	asStruct := r.CacheKey("note", message{Subject: "Hi", Count: 2})
+	if again := r.CacheKey("note", message{Subject: "Hi", Count: 2}); asStruct == "" || asStruct != again {
+		t.Errorf("equal data should share a key: %s vs %s", asStruct, again)
+	}
+	asMap := r.CacheKey("note", map[string]any{"count": 2, "subject": "Hi"})
+	if asMap == asStruct {
+		t.Error("a struct and a map render differently, so they need different keys")
+	}
+	reordered := map[string]any{}
+	reordered["subject"] = "Hi"
+	reordered["count"] = 2
+	if key := r.CacheKey("note", reordered); key != asMap {
+		t.Errorf("map insertion order should not change the key: %s vs %s", key, asMap)
+	}
+	if other := r.CacheKey("note", message{Subject: "Hi", Count: 3}); other == asStruct {
+		t.Error("different data should get a different key")
+	}
+	if other := r.CacheKey("other", message{Subject: "Hi", Count: 2}); other == asStruct {
+		t.Error("different templates should get a different key")
+	}
+	if key := r.CacheKey("note", map[string]any{"f": func() {}}); key != "" {
+		t.Errorf("unencodable data should not be cacheable, got %s", key)
+	}
+
+	// Unencodable data still renders, just uncached.
+	if err := r.LoadTemplate("note", `<mjml><mj-body><mj-section><mj-column><mj-text>{{.msg}}</mj-text></mj-column></mj-section></mj-body></mjml>`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.RenderTemplate("note", map[string]any{"msg": "hi", "f": func() {}}); err != nil {
+		t.Fatal(err)
+	}
+	if r.GetCacheSize() != 0 {
+		t.Errorf("expected nothing cached, got %d", r.GetCacheSize())
+	}
+}
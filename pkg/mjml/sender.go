@@ -0,0 +1,129 @@
+package mjml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+)
+
+// SMTPConfig holds the SMTP server and sender used to deliver emails
+type SMTPConfig struct {
+	SMTPHost  string
+	SMTPPort  string
+	Username  string
+	Password  string
+	FromEmail string
+	FromName  string
+
+	// MaxPerSecond caps how many messages SendBatch sends per second, to
+	// stay under provider throttling; zero means no limit.
+	MaxPerSecond int
+
+	// DKIM signs every message when set; see DKIMConfig.
+	DKIM *DKIMConfig
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Filename    string
+	ContentType string // defaults to application/octet-stream
+	Data        []byte
+}
+
+// SendEmail sends an HTML email with optional attachments. Without
+// attachments the message is a plain text/html body; with them it is
+// multipart/mixed with the HTML first. The message is DKIM-signed when
+// config.DKIM is set.
+func SendEmail(config SMTPConfig, to, subject, html string, attachments []Attachment) error {
+	message, err := buildMessage(config, to, subject, html, attachments)
+	if err != nil {
+		return err
+	}
+	if message, err = signDKIM(config.DKIM, message); err != nil {
+		return err
+	}
+
+	auth := smtp.PlainAuth("", config.Username, config.Password, config.SMTPHost)
+	if err := smtp.SendMail(config.SMTPHost+":"+config.SMTPPort, auth, config.FromEmail, []string{to}, message); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to, err)
+	}
+	return nil
+}
+
+// buildMessage assembles the RFC 5322 message sent by SendEmail
+func buildMessage(config SMTPConfig, to, subject, html string, attachments []Attachment) ([]byte, error) {
+	var buf bytes.Buffer
+	from := mail.Address{Name: config.FromName, Address: config.FromEmail}
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	if len(attachments) == 0 {
+		buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+		buf.WriteString(html)
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+
+	body, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(body)
+	if _, err := qp.Write([]byte(html)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": a.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, a.Data); err != nil {
+			return nil, fmt.Errorf("failed to encode attachment %s: %w", a.Filename, err)
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines writes data as base64 wrapped at 76 characters, the
+// line length limit for MIME bodies.
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:76]); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := fmt.Fprintf(w, "%s\r\n", encoded)
+	return err
+}
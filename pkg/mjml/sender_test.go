@@ -0,0 +1,81 @@
+package mjml
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuildMessageWithAttachment(t *testing.T) {
+	config := SMTPConfig{FromEmail: "billing@example.com", FromName: "Billing"}
+	invoice := bytes.Repeat([]byte("%PDF-1.4 invoice "), 20)
+
+	raw, err := buildMessage(config, "alice@example.com", "Your invoice", "<p>Thanks!</p>",
+		[]Attachment{{Filename: "invoice.pdf", ContentType: "application/pdf", Data: invoice}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := mr.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, _ := io.ReadAll(body) // multipart decodes quoted-printable itself
+	if !strings.HasPrefix(body.Header.Get("Content-Type"), "text/html") || string(html) != "<p>Thanks!</p>" {
+		t.Errorf("first part = %q (%s), want the HTML body", html, body.Header.Get("Content-Type"))
+	}
+
+	att, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("attachment part missing: %v", err)
+	}
+	if att.FileName() != "invoice.pdf" || att.Header.Get("Content-Type") != "application/pdf; name=invoice.pdf" {
+		t.Errorf("attachment headers = %v", att.Header)
+	}
+	if enc := att.Header.Get("Content-Transfer-Encoding"); enc != "base64" {
+		t.Errorf("Content-Transfer-Encoding = %q, want base64", enc)
+	}
+	encoded, _ := io.ReadAll(att)
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("base64 line longer than 76 chars: %d", len(line))
+		}
+	}
+	decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(encoded)))
+	if err != nil || !bytes.Equal(decoded, invoice) {
+		t.Errorf("attachment did not round-trip: %v", err)
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly two parts, got %v", err)
+	}
+}
+
+func TestBuildMessageWithoutAttachments(t *testing.T) {
+	raw, err := buildMessage(SMTPConfig{FromEmail: "a@example.com"}, "b@example.com", "Hi", "<p>hi</p>", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := msg.Header.Get("Content-Type"); ct != "text/html; charset=UTF-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
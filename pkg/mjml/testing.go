@@ -2,51 +2,16 @@ package mjml
 
 import (
 	"fmt"
-	"net/smtp"
 	"os"
 	"strings"
 )
 
 // EmailTestConfig holds configuration for sending test emails
-type EmailTestConfig struct {
-	SMTPHost     string
-	SMTPPort     string
-	Username     string
-	Password     string
-	FromEmail    string
-	FromName     string
-}
+type EmailTestConfig = SMTPConfig
 
 // SendTestEmail sends an HTML email for testing in real email clients
 func SendTestEmail(config EmailTestConfig, toEmail, subject, htmlBody string) error {
-	// Create message
-	message := fmt.Sprintf(
-		"From: %s <%s>\r\n"+
-			"To: %s\r\n"+
-			"Subject: %s\r\n"+
-			"MIME-Version: 1.0\r\n"+
-			"Content-Type: text/html; charset=UTF-8\r\n"+
-			"\r\n"+
-			"%s",
-		config.FromName, config.FromEmail,
-		toEmail,
-		subject,
-		htmlBody,
-	)
-
-	// Setup authentication
-	auth := smtp.PlainAuth("", config.Username, config.Password, config.SMTPHost)
-	
-	// Send email
-	err := smtp.SendMail(
-		config.SMTPHost+":"+config.SMTPPort,
-		auth,
-		config.FromEmail,
-		[]string{toEmail},
-		[]byte(message),
-	)
-	
-	return err
+	return SendEmail(config, toEmail, subject, htmlBody, nil)
 }
 
 // SendAllTestEmails sends all generated email templates for testing
@@ -68,6 +68,17 @@ func Ensure(ctx context.Context) (*Artifacts, error) {
 	return loadArtifacts(paths, storeDir)
 }
 
+// Load returns the authentication artifacts already on disk without installing
+// nsc or generating anything. The error wraps os.ErrNotExist when Ensure has
+// not generated them yet.
+func Load() (*Artifacts, error) {
+	paths := requiredPaths()
+	if !artifactsReady(paths) {
+		return nil, fmt.Errorf("nats auth artifacts not generated: %w", os.ErrNotExist)
+	}
+	return loadArtifacts(paths, config.GetNATSAuthStorePath())
+}
+
 func requiredPaths() map[string]string {
 	return map[string]string{
 		"operator":           config.GetNATSOperatorJWTPath(),
@@ -2,6 +2,7 @@ package auth_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -46,3 +47,18 @@ func TestEnsureArtifacts(t *testing.T) {
 	require.Equal(t, artifacts.SystemAccountID, artifactsAgain.SystemAccountID)
 	require.Equal(t, artifacts.ApplicationAccountID, artifactsAgain.ApplicationAccountID)
 }
+
+func TestLoadWithoutArtifacts(t *testing.T) {
+	t.Setenv("ENVIRONMENT", "test")
+
+	base := filepath.Dir(config.GetNATSAuthStorePath())
+	require.NoError(t, os.RemoveAll(base))
+	t.Cleanup(func() {
+		_ = os.RemoveAll(base)
+	})
+
+	_, err := auth.Load()
+	require.True(t, errors.Is(err, os.ErrNotExist), "Load() error = %v", err)
+	_, err = os.Stat(base)
+	require.True(t, os.IsNotExist(err), "Load must not create %s", base)
+}
@@ -33,6 +33,10 @@ type ClusterNode struct {
 	LeafPort    int    `json:"leaf_port"`
 	IsLocal     bool   `json:"is_local"`
 	Status      string `json:"status"`
+
+	// LeafRemotes are outbound leaf connections for this node only, added
+	// after the cluster-wide ClusterConfig.LeafRemotes.
+	LeafRemotes []LeafRemote `json:"leaf_remotes,omitempty"`
 }
 
 // ClusterConfig represents the configuration for a NATS cluster
@@ -42,6 +46,7 @@ type ClusterConfig struct {
 	Environment     string        `json:"environment"`
 	EnableWebGUI    bool          `json:"enable_web_gui"`
 	EnableJetStream bool          `json:"enable_jetstream"`
+	LeafRemotes     []LeafRemote  `json:"leaf_remotes,omitempty"` // Outbound leaf connections for every node
 }
 
 // Using config functions instead of hardcoded values
@@ -199,6 +204,48 @@ func ensureClusterNode(clusterConfig ClusterConfig, node ClusterNode, clusterDat
 }
 
 func writeNodeConfig(clusterConfig ClusterConfig, node ClusterNode, configPath, dataDir string, authArtifacts *auth.Artifacts) error {
+	natsConfig, err := renderNodeConfig(clusterConfig, node, dataDir, authArtifacts)
+	if err != nil {
+		return err
+	}
+
+	jetstreamDir := filepath.Join(dataDir, "jetstream")
+	if err := os.MkdirAll(jetstreamDir, 0755); err != nil {
+		return fmt.Errorf("failed to create JetStream directory: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte(natsConfig), 0644); err != nil {
+		return fmt.Errorf("failed to write config for node %s: %w", node.Name, err)
+	}
+
+	return nil
+}
+
+// RenderClusterConfigs returns the nats.conf that EnsureCluster would write
+// for each node, keyed by node name, without touching the filesystem.
+func RenderClusterConfigs(cfg ClusterConfig, authArtifacts *auth.Artifacts) (map[string]string, error) {
+	if authArtifacts == nil {
+		return nil, fmt.Errorf("auth artifacts are required")
+	}
+	clusterDataPath := config.GetNATSClusterDataPath()
+	configs := make(map[string]string, len(cfg.Nodes))
+	for _, node := range cfg.Nodes {
+		text, err := renderNodeConfig(cfg, node, filepath.Join(clusterDataPath, node.Name), authArtifacts)
+		if err != nil {
+			return nil, err
+		}
+		configs[node.Name] = text
+	}
+	return configs, nil
+}
+
+// renderNodeConfig builds the nats.conf contents for a node whose data lives in dataDir.
+func renderNodeConfig(clusterConfig ClusterConfig, node ClusterNode, dataDir string, authArtifacts *auth.Artifacts) (string, error) {
+	leafRemotes := leafRemotesForNode(clusterConfig, node)
+	if err := validateLeafRemotes(leafRemotes); err != nil {
+		return "", fmt.Errorf("invalid leaf remotes for node %s: %w", node.Name, err)
+	}
+
 	routes := make([]string, 0, len(clusterConfig.Nodes)-1)
 	for _, other := range clusterConfig.Nodes {
 		if other.Name == node.Name {
@@ -213,9 +260,6 @@ func writeNodeConfig(clusterConfig ClusterConfig, node ClusterNode, configPath,
 
 	routesStr := strings.Join(routes, ",")
 	jetstreamDir := filepath.Join(dataDir, "jetstream")
-	if err := os.MkdirAll(jetstreamDir, 0755); err != nil {
-		return fmt.Errorf("failed to create JetStream directory: %w", err)
-	}
 
 	natsConfig := fmt.Sprintf(`
 # NATS Server Configuration for %s
@@ -246,9 +290,7 @@ cluster {
 	    max_file_store: 2GB
 	}
 
-leaf {
-	listen: %d
-}
+%s
 
 debug: false
 trace: false
@@ -269,38 +311,21 @@ logtime: true
 		node.ClusterPort,
 		routesStr,
 		jetstreamDir,
-		node.LeafPort,
+		renderLeafBlock(node.LeafPort, leafRemotes),
 	)
 
-	if err := os.WriteFile(configPath, []byte(natsConfig), 0644); err != nil {
-		return fmt.Errorf("failed to write config for node %s: %w", node.Name, err)
-	}
-
-	return nil
+	return natsConfig, nil
 }
 
 func clusterProcessName(node ClusterNode) string {
 	return node.Name
 }
 
-// StopLocalCluster stops the goreman-supervised local NATS cluster processes
+// StopLocalCluster stops the goreman-supervised local NATS cluster processes,
+// stepping down the JetStream meta leader and stopping it last. It falls back
+// to stopping every node at once when the leader cannot be determined.
 func StopLocalCluster() error {
-	log.Info("Stopping local NATS cluster")
-	if err := goreman.StopGroup("nats-cluster"); err != nil {
-		log.Debug("goreman group stop", "error", err)
-	}
-	clusterConfig := GetLocalClusterConfig()
-	var stopErr error
-	for _, node := range clusterConfig.Nodes {
-		configPath := filepath.Join(config.GetNATSClusterDataPath(), node.Name, "nats.conf")
-		if err := stopNodeProcessByConfig(configPath); err != nil {
-			log.Warn("Failed to stop NATS node", "node", node.Name, "error", err)
-			if stopErr == nil {
-				stopErr = err
-			}
-		}
-	}
-	return stopErr
+	return StopLocalClusterWithOptions(context.Background(), StopOptions{})
 }
 
 // EnsureSingleFlyNode starts a single NATS node for the specified Fly app under goreman supervision
@@ -352,16 +377,19 @@ func EnsureSingleFlyNode(ctx context.Context, flyAppName string) error {
 	return nil
 }
 
-// checkNodeHTTPHealth performs HTTP health check on a NATS node's monitoring endpoint
-func checkNodeHTTPHealth(node ClusterNode, isLocal bool) bool {
-	var url string
+// nodeMonitorURL returns the base URL of a node's HTTP monitoring endpoint.
+func nodeMonitorURL(node ClusterNode, isLocal bool) string {
 	if isLocal {
 		// For local nodes, use localhost with the specific HTTP port
-		url = fmt.Sprintf("http://127.0.0.1:%d/", node.HTTPPort)
-	} else {
-		// For Fly nodes, use the fly.dev hostname with standard port 8222
-		url = fmt.Sprintf("http://%s.fly.dev:8222/", node.Name)
+		return fmt.Sprintf("http://127.0.0.1:%d/", node.HTTPPort)
 	}
+	// For Fly nodes, use the fly.dev hostname with standard port 8222
+	return fmt.Sprintf("http://%s.fly.dev:8222/", node.Name)
+}
+
+// checkNodeHTTPHealth performs HTTP health check on a NATS node's monitoring endpoint
+func checkNodeHTTPHealth(node ClusterNode, isLocal bool) bool {
+	url := nodeMonitorURL(node, isLocal)
 
 	client := &http.Client{
 		Timeout: 5 * time.Second,
@@ -436,15 +464,39 @@ func getFlyClusterNodeStatus(appName string) (string, error) {
 }
 
 func stopNodeProcessByConfig(configPath string) error {
+	pids, err := findNodePIDs(configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, pid := range pids {
+		proc, err := os.FindProcess(pid)
+		if err != nil {
+			log.Warn("Failed to find process", "pid", pid, "error", err)
+			continue
+		}
+		if err := proc.Signal(os.Interrupt); err != nil {
+			if killErr := proc.Kill(); killErr != nil {
+				log.Warn("Failed to terminate process", "pid", pid, "error", killErr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findNodePIDs returns the PIDs of nats-server processes started with configPath.
+func findNodePIDs(configPath string) ([]int, error) {
 	cmd := exec.Command("pgrep", "-f", configPath)
 	output, err := cmd.Output()
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			return nil // nothing to stop
+			return nil, nil // no matching process
 		}
-		return fmt.Errorf("pgrep failed for %s: %w", configPath, err)
+		return nil, fmt.Errorf("pgrep failed for %s: %w", configPath, err)
 	}
 
+	var pids []int
 	scanner := bufio.NewScanner(bytes.NewReader(output))
 	for scanner.Scan() {
 		pidStr := strings.TrimSpace(scanner.Text())
@@ -456,23 +508,14 @@ func stopNodeProcessByConfig(configPath string) error {
 			log.Warn("Invalid PID from pgrep", "pid", pidStr, "error", err)
 			continue
 		}
-		proc, err := os.FindProcess(pid)
-		if err != nil {
-			log.Warn("Failed to find process", "pid", pid, "error", err)
-			continue
-		}
-		if err := proc.Signal(os.Interrupt); err != nil {
-			if killErr := proc.Kill(); killErr != nil {
-				log.Warn("Failed to terminate process", "pid", pid, "error", killErr)
-			}
-		}
+		pids = append(pids, pid)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("scan pgrep output: %w", err)
+		return nil, fmt.Errorf("scan pgrep output: %w", err)
 	}
 
-	return nil
+	return pids, nil
 }
 
 // DeployFlyCluster deploys NATS cluster to Fly.io across multiple regions
@@ -500,15 +543,27 @@ func deployFlyClusterNode(ctx context.Context, node ClusterNode, clusterConfig C
 
 // UpgradeCluster performs rolling upgrade of NATS cluster using lame duck mode
 func UpgradeCluster(ctx context.Context, isLocal bool) error {
-	if isLocal {
-		return fmt.Errorf("rolling upgrade not supported for local goreman-managed clusters yet")
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
 	log.Info("Starting rolling cluster upgrade with lame duck mode...")
 
 	var clusterConfig ClusterConfig
+	var authArtifacts *auth.Artifacts
 	if isLocal {
 		clusterConfig = GetLocalClusterConfig()
+
+		// Install the target binary and auth material once, up front, so
+		// every node restarts onto the same version and config.
+		if err := dep.InstallBinary(config.BinaryNatsServer, false); err != nil {
+			return fmt.Errorf("failed to ensure nats binary: %w", err)
+		}
+		artifacts, err := auth.Ensure(ctx)
+		if err != nil {
+			return fmt.Errorf("ensure auth materials: %w", err)
+		}
+		authArtifacts = artifacts
 	} else {
 		clusterConfig = GetFlyClusterConfig()
 	}
@@ -517,13 +572,13 @@ func UpgradeCluster(ctx context.Context, isLocal bool) error {
 	for i, node := range clusterConfig.Nodes {
 		log.Info("Upgrading cluster node", "node", node.Name, "step", fmt.Sprintf("%d/%d", i+1, len(clusterConfig.Nodes)))
 
-		if err := upgradeClusterNode(ctx, node, isLocal); err != nil {
+		if err := upgradeClusterNode(ctx, clusterConfig, node, isLocal, authArtifacts); err != nil {
 			log.Error("Failed to upgrade cluster node", "node", node.Name, "error", err)
 			return fmt.Errorf("failed to upgrade node %s: %w", node.Name, err)
 		}
 
 		// Wait for node to rejoin cluster before proceeding
-		if err := waitForNodeReady(ctx, node, isLocal); err != nil {
+		if err := waitForNodeReady(ctx, clusterConfig, node, isLocal, config.GetNATSClusterReadinessTimeout()); err != nil {
 			log.Error("Node failed to rejoin cluster", "node", node.Name, "error", err)
 			return fmt.Errorf("node %s failed to rejoin cluster: %w", node.Name, err)
 		}
@@ -536,9 +591,9 @@ func UpgradeCluster(ctx context.Context, isLocal bool) error {
 }
 
 // upgradeClusterNode upgrades a single node using lame duck mode
-func upgradeClusterNode(ctx context.Context, node ClusterNode, isLocal bool) error {
+func upgradeClusterNode(ctx context.Context, clusterConfig ClusterConfig, node ClusterNode, isLocal bool, authArtifacts *auth.Artifacts) error {
 	if isLocal {
-		return fmt.Errorf("local cluster upgrade is not implemented for goreman-managed clusters")
+		return upgradeLocalClusterNode(ctx, clusterConfig, node, authArtifacts)
 	}
 
 	// For Fly.io: use Fly's deployment command
@@ -549,25 +604,30 @@ func upgradeClusterNode(ctx context.Context, node ClusterNode, isLocal bool) err
 	return cmd.Run()
 }
 
-// waitForNodeReady waits for a node to rejoin the cluster and be ready
-func waitForNodeReady(ctx context.Context, node ClusterNode, isLocal bool) error {
-	if isLocal {
-		return fmt.Errorf("waitForNodeReady not supported for local goreman-managed cluster")
-	}
-
-	timeout := 30 * time.Second
-	ticker := time.NewTicker(2 * time.Second)
+// waitForNodeReady waits for a node to rejoin the cluster: its /routez must
+// show a route to every other node and, when JetStream is enabled, /healthz
+// must report it current. On timeout the error names what is still missing.
+func waitForNodeReady(ctx context.Context, clusterConfig ClusterConfig, node ClusterNode, isLocal bool, timeout time.Duration) error {
+	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	client := &http.Client{Timeout: 5 * time.Second}
+	base := nodeMonitorURL(node, isLocal)
+
 	for {
+		problems := checkNodeReadiness(client, base, clusterConfig, node)
+		if len(problems) == 0 {
+			log.Info("Node rejoined cluster", "node", node.Name)
+			return nil
+		}
+
 		select {
 		case <-timeoutCtx.Done():
-			return fmt.Errorf("timeout waiting for node to be ready")
+			return fmt.Errorf("node %s not ready after %s: %s", node.Name, timeout, strings.Join(problems, "; "))
 		case <-ticker.C:
-			return nil // Placeholder until Fly.io health integration is added
 		}
 	}
 }
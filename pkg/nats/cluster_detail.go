@@ -0,0 +1,141 @@
+package nats
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// NodeDetail extends a ClusterNode with data from its /varz and /jsz
+// monitoring endpoints. The monitoring fields are empty when the node is not
+// running or could not be queried; Error records why.
+type NodeDetail struct {
+	ClusterNode
+	ServerName  string           `json:"server_name,omitempty"`
+	Version     string           `json:"version,omitempty"`
+	Connections int              `json:"connections"`
+	Uptime      string           `json:"uptime,omitempty"`
+	JetStream   *JetStreamDetail `json:"jetstream,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// JetStreamDetail is the JetStream meta cluster view reported by a node.
+type JetStreamDetail struct {
+	MetaLeader   string             `json:"meta_leader,omitempty"`
+	IsMetaLeader bool               `json:"is_meta_leader"`
+	ClusterSize  int                `json:"cluster_size"`
+	Replicas     []JetStreamReplica `json:"replicas,omitempty"`
+	Streams      int                `json:"streams"`
+	Consumers    int                `json:"consumers"`
+}
+
+// JetStreamReplica describes a meta cluster peer as seen by the reporting node.
+type JetStreamReplica struct {
+	Name    string        `json:"name"`
+	Current bool          `json:"current"`
+	Offline bool          `json:"offline,omitempty"`
+	Active  time.Duration `json:"active"`
+	Lag     uint64        `json:"lag,omitempty"`
+}
+
+// varzResponse holds the /varz fields we report.
+type varzResponse struct {
+	ServerName  string `json:"server_name"`
+	Version     string `json:"version"`
+	Connections int    `json:"connections"`
+	Uptime      string `json:"uptime"`
+}
+
+// jszResponse holds the /jsz fields we report.
+type jszResponse struct {
+	Disabled    bool `json:"disabled"`
+	Streams     int  `json:"streams"`
+	Consumers   int  `json:"consumers"`
+	MetaCluster *struct {
+		Leader      string             `json:"leader"`
+		ClusterSize int                `json:"cluster_size"`
+		Replicas    []JetStreamReplica `json:"replicas"`
+	} `json:"meta_cluster"`
+}
+
+// GetClusterStatusDetailed returns the cluster status along with monitoring
+// detail for each node. Running and unhealthy nodes are queried concurrently;
+// a failed query is recorded on that node rather than failing the call.
+func GetClusterStatusDetailed(isLocal bool) (ClusterConfig, []NodeDetail, error) {
+	clusterConfig, err := GetClusterStatus(isLocal)
+	if err != nil {
+		return clusterConfig, nil, err
+	}
+
+	details := make([]NodeDetail, len(clusterConfig.Nodes))
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	var wg sync.WaitGroup
+	for i, node := range clusterConfig.Nodes {
+		details[i] = NodeDetail{ClusterNode: node}
+		if node.Status != "running" && node.Status != "unhealthy" {
+			continue
+		}
+		wg.Add(1)
+		go func(detail *NodeDetail) {
+			defer wg.Done()
+			fetchNodeDetail(client, detail, isLocal)
+		}(&details[i])
+	}
+	wg.Wait()
+
+	return clusterConfig, details, nil
+}
+
+// fetchNodeDetail fills detail from the node's monitoring endpoints.
+func fetchNodeDetail(client *http.Client, detail *NodeDetail, isLocal bool) {
+	base := nodeMonitorURL(detail.ClusterNode, isLocal)
+
+	var varz varzResponse
+	if err := getMonitorJSON(client, base+"varz", &varz); err != nil {
+		log.Debug("Failed to fetch NATS varz", "node", detail.Name, "error", err)
+		detail.Error = err.Error()
+		return
+	}
+	detail.ServerName = varz.ServerName
+	detail.Version = varz.Version
+	detail.Connections = varz.Connections
+	detail.Uptime = varz.Uptime
+
+	var jsz jszResponse
+	if err := getMonitorJSON(client, base+"jsz", &jsz); err != nil {
+		log.Debug("Failed to fetch NATS jsz", "node", detail.Name, "error", err)
+		detail.Error = err.Error()
+		return
+	}
+	if jsz.Disabled {
+		return
+	}
+	js := &JetStreamDetail{Streams: jsz.Streams, Consumers: jsz.Consumers}
+	if meta := jsz.MetaCluster; meta != nil {
+		js.MetaLeader = meta.Leader
+		js.IsMetaLeader = meta.Leader != "" && meta.Leader == varz.ServerName
+		js.ClusterSize = meta.ClusterSize
+		js.Replicas = meta.Replicas
+	}
+	detail.JetStream = js
+}
+
+func getMonitorJSON(client *http.Client, url string, out any) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("get %s: unexpected status %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s: %w", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package nats
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFetchNodeDetail(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/varz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"server_name":"nats-1","version":"2.10.0","connections":4,"uptime":"1h2m3s"}`))
+	})
+	mux.HandleFunc("/jsz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"streams":2,"consumers":3,"meta_cluster":{"leader":"nats-1","cluster_size":3,
+			"replicas":[{"name":"nats-2","current":true,"active":1000000},{"name":"nats-3","current":false,"offline":true}]}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	detail := NodeDetail{ClusterNode: ClusterNode{Name: "nats-1", HTTPPort: port, Status: "running"}}
+	fetchNodeDetail(srv.Client(), &detail, true)
+
+	if detail.Error != "" {
+		t.Fatalf("unexpected error: %s", detail.Error)
+	}
+	if detail.Connections != 4 || detail.Uptime != "1h2m3s" || detail.Version != "2.10.0" {
+		t.Fatalf("varz not applied: %+v", detail)
+	}
+	js := detail.JetStream
+	if js == nil || !js.IsMetaLeader || js.MetaLeader != "nats-1" || js.ClusterSize != 3 || js.Streams != 2 {
+		t.Fatalf("unexpected jetstream detail: %+v", js)
+	}
+	if len(js.Replicas) != 2 || js.Replicas[0].Active != time.Millisecond || !js.Replicas[1].Offline {
+		t.Fatalf("unexpected replicas: %+v", js.Replicas)
+	}
+}
+
+func TestFetchNodeDetailRecordsError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	detail := NodeDetail{ClusterNode: ClusterNode{Name: "nats-1", HTTPPort: port}}
+	fetchNodeDetail(srv.Client(), &detail, true)
+	if detail.Error == "" || detail.JetStream != nil {
+		t.Fatalf("expected error to be recorded, got %+v", detail)
+	}
+}
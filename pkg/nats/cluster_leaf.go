@@ -0,0 +1,99 @@
+package nats
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LeafRemote describes an outbound leaf node connection, e.g. from a local
+// development cluster to a Fly-hosted hub. URLs can come straight from
+// GetClusterLeafRemotes(false).
+type LeafRemote struct {
+	URLs        []string `json:"urls"`
+	Credentials string   `json:"credentials,omitempty"` // Path to a .creds file
+	Account     string   `json:"account,omitempty"`     // Local account to bind the remote to
+	TLS         *LeafTLS `json:"tls,omitempty"`
+}
+
+// LeafTLS configures TLS for a leaf remote. Empty fields are omitted.
+type LeafTLS struct {
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	CAFile   string `json:"ca_file,omitempty"`
+}
+
+// leafRemotesForNode returns the cluster-wide remotes followed by the
+// node's own remotes.
+func leafRemotesForNode(clusterConfig ClusterConfig, node ClusterNode) []LeafRemote {
+	remotes := make([]LeafRemote, 0, len(clusterConfig.LeafRemotes)+len(node.LeafRemotes))
+	remotes = append(remotes, clusterConfig.LeafRemotes...)
+	remotes = append(remotes, node.LeafRemotes...)
+	return remotes
+}
+
+// validateLeafRemotes checks that each remote has a URL and that every
+// referenced credentials and TLS file exists.
+func validateLeafRemotes(remotes []LeafRemote) error {
+	for i, remote := range remotes {
+		if len(remote.URLs) == 0 {
+			return fmt.Errorf("leaf remote %d: at least one url is required", i)
+		}
+		files := map[string]string{"credentials": remote.Credentials}
+		if remote.TLS != nil {
+			files["tls cert_file"] = remote.TLS.CertFile
+			files["tls key_file"] = remote.TLS.KeyFile
+			files["tls ca_file"] = remote.TLS.CAFile
+		}
+		for field, path := range files {
+			if path == "" {
+				continue
+			}
+			if _, err := os.Stat(path); err != nil {
+				return fmt.Errorf("leaf remote %s: %s %s: %w", strings.Join(remote.URLs, ","), field, path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderLeafBlock renders the leaf {} section of a node config.
+func renderLeafBlock(listenPort int, remotes []LeafRemote) string {
+	var b strings.Builder
+	b.WriteString("leaf {\n")
+	fmt.Fprintf(&b, "\tlisten: %d\n", listenPort)
+	if len(remotes) > 0 {
+		b.WriteString("\tremotes [\n")
+		for _, remote := range remotes {
+			urls := make([]string, len(remote.URLs))
+			for i, u := range remote.URLs {
+				urls[i] = fmt.Sprintf("%q", u)
+			}
+			b.WriteString("\t\t{\n")
+			fmt.Fprintf(&b, "\t\t\turls: [%s]\n", strings.Join(urls, ", "))
+			if remote.Credentials != "" {
+				fmt.Fprintf(&b, "\t\t\tcredentials: %q\n", remote.Credentials)
+			}
+			if remote.Account != "" {
+				fmt.Fprintf(&b, "\t\t\taccount: %q\n", remote.Account)
+			}
+			if tls := remote.TLS; tls != nil {
+				b.WriteString("\t\t\ttls {\n")
+				if tls.CertFile != "" {
+					fmt.Fprintf(&b, "\t\t\t\tcert_file: %q\n", tls.CertFile)
+				}
+				if tls.KeyFile != "" {
+					fmt.Fprintf(&b, "\t\t\t\tkey_file: %q\n", tls.KeyFile)
+				}
+				if tls.CAFile != "" {
+					fmt.Fprintf(&b, "\t\t\t\tca_file: %q\n", tls.CAFile)
+				}
+				b.WriteString("\t\t\t}\n")
+			}
+			b.WriteString("\t\t}\n")
+		}
+		b.WriteString("\t]\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
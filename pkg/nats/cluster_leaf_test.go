@@ -0,0 +1,79 @@
+package nats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats-server/v2/conf"
+
+	"github.com/joeblew999/infra/pkg/nats/auth"
+)
+
+func TestWriteNodeConfigLeafRemotes(t *testing.T) {
+	dir := t.TempDir()
+	credsPath := filepath.Join(dir, "hub.creds")
+	if err := os.WriteFile(credsPath, []byte("creds"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	caPath := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caPath, []byte("ca"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	node := ClusterNode{Name: "nats-1", Host: "127.0.0.1", Port: 4222, ClusterPort: 6222, HTTPPort: 8222, LeafPort: 4522}
+	cfg := ClusterConfig{
+		ClusterName: "test",
+		Nodes:       []ClusterNode{node},
+		LeafRemotes: []LeafRemote{{
+			URLs:        []string{"tls://nats-iad.fly.dev:4322", "tls://nats-lhr.fly.dev:4323"},
+			Credentials: credsPath,
+			TLS:         &LeafTLS{CAFile: caPath},
+		}},
+	}
+	artifacts := &auth.Artifacts{OperatorJWT: "op", SystemAccountID: "SYS", SystemAccountJWT: "sys", ApplicationAccountID: "APP", ApplicationAccountJWT: "app"}
+
+	configPath := filepath.Join(dir, "nats.conf")
+	if err := writeNodeConfig(cfg, node, configPath, dir, artifacts); err != nil {
+		t.Fatalf("writeNodeConfig: %v", err)
+	}
+
+	parsed, err := conf.ParseFile(configPath)
+	if err != nil {
+		t.Fatalf("generated config does not parse: %v", err)
+	}
+	leaf, _ := parsed["leaf"].(map[string]any)
+	remotes, _ := leaf["remotes"].([]any)
+	if len(remotes) != 1 {
+		t.Fatalf("expected one leaf remote, got %#v", leaf)
+	}
+	remote := remotes[0].(map[string]any)
+	if remote["credentials"] != credsPath {
+		t.Errorf("credentials = %v, want %s", remote["credentials"], credsPath)
+	}
+	if urls, _ := remote["urls"].([]any); len(urls) != 2 {
+		t.Errorf("urls = %v", remote["urls"])
+	}
+	if tls, _ := remote["tls"].(map[string]any); tls["ca_file"] != caPath {
+		t.Errorf("tls = %v", remote["tls"])
+	}
+}
+
+func TestWriteNodeConfigRejectsMissingCredentials(t *testing.T) {
+	dir := t.TempDir()
+	node := ClusterNode{Name: "nats-1", LeafPort: 4522, LeafRemotes: []LeafRemote{{
+		URLs:        []string{"tls://hub:7422"},
+		Credentials: filepath.Join(dir, "missing.creds"),
+	}}}
+	cfg := ClusterConfig{Nodes: []ClusterNode{node}}
+
+	configPath := filepath.Join(dir, "nats.conf")
+	err := writeNodeConfig(cfg, node, configPath, dir, &auth.Artifacts{})
+	if err == nil || !strings.Contains(err.Error(), "missing.creds") {
+		t.Fatalf("expected missing credentials error, got %v", err)
+	}
+	if _, statErr := os.Stat(configPath); !os.IsNotExist(statErr) {
+		t.Fatal("config should not be written when validation fails")
+	}
+}
@@ -0,0 +1,221 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	gonats "github.com/nats-io/nats.go"
+
+	"github.com/joeblew999/infra/pkg/config"
+	"github.com/joeblew999/infra/pkg/goreman"
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// metaLeaderStepDownSubject is the system account API that asks the current
+// JetStream meta leader to hand leadership to another peer.
+const metaLeaderStepDownSubject = "$JS.API.META.LEADER.STEPDOWN"
+
+// StopOptions controls how StopLocalClusterWithOptions shuts nodes down.
+type StopOptions struct {
+	// LeaderTimeout bounds the search for the JetStream meta leader (default: 10s).
+	LeaderTimeout time.Duration
+	// RequireLeader fails the stop when no leader is found, instead of falling
+	// back to stopping every node at once.
+	RequireLeader bool
+}
+
+// StopLocalClusterWithOptions stops the local cluster in a JetStream-friendly
+// order: the meta leader is asked to step down, followers are stopped and
+// waited on, and the former leader is stopped last.
+func StopLocalClusterWithOptions(ctx context.Context, opts StopOptions) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.LeaderTimeout <= 0 {
+		opts.LeaderTimeout = 10 * time.Second
+	}
+
+	log.Info("Stopping local NATS cluster")
+	clusterConfig := GetLocalClusterConfig()
+
+	running, err := runningClusterNodes(clusterConfig.Nodes)
+	if err != nil {
+		return err
+	}
+	if len(running) == 0 {
+		log.Info("No local NATS cluster nodes are running")
+		return nil
+	}
+	clusterConfig.Nodes = running
+
+	leader, err := findMetaLeader(ctx, clusterConfig.Nodes, opts.LeaderTimeout)
+	if err != nil {
+		if opts.RequireLeader {
+			return fmt.Errorf("determine JetStream meta leader: %w", err)
+		}
+		log.Warn("Could not determine JetStream meta leader, stopping nodes without ordering", "error", err)
+		return stopLocalClusterUnordered(clusterConfig)
+	}
+
+	if err := stepDownMetaLeader(ctx, leader); err != nil {
+		log.Warn("JetStream meta leader step-down failed, continuing shutdown", "leader", leader.Name, "error", err)
+	} else {
+		log.Info("JetStream meta leader stepped down", "leader", leader.Name)
+	}
+
+	followers, last := orderNodesForStop(clusterConfig.Nodes, leader.Name)
+	if err := stopLocalClusterNodes(ctx, followers); err != nil {
+		return err
+	}
+	return stopLocalClusterNodes(ctx, last)
+}
+
+// runningClusterNodes returns the nodes that have a nats-server process.
+func runningClusterNodes(nodes []ClusterNode) ([]ClusterNode, error) {
+	var running []ClusterNode
+	for _, node := range nodes {
+		configPath := filepath.Join(config.GetNATSClusterDataPath(), node.Name, "nats.conf")
+		pids, err := findNodePIDs(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("find node %s: %w", node.Name, err)
+		}
+		if len(pids) > 0 {
+			running = append(running, node)
+		}
+	}
+	return running, nil
+}
+
+// stopLocalClusterUnordered signals every node at once.
+func stopLocalClusterUnordered(clusterConfig ClusterConfig) error {
+	if err := goreman.StopGroup("nats-cluster"); err != nil {
+		log.Debug("goreman group stop", "error", err)
+	}
+	var stopErr error
+	for _, node := range clusterConfig.Nodes {
+		configPath := filepath.Join(config.GetNATSClusterDataPath(), node.Name, "nats.conf")
+		if err := stopNodeProcessByConfig(configPath); err != nil {
+			log.Warn("Failed to stop NATS node", "node", node.Name, "error", err)
+			if stopErr == nil {
+				stopErr = err
+			}
+		}
+	}
+	return stopErr
+}
+
+// stopLocalClusterNodes signals the nodes and waits for all of them to exit.
+func stopLocalClusterNodes(ctx context.Context, nodes []ClusterNode) error {
+	for _, node := range nodes {
+		if err := goreman.Stop(clusterProcessName(node)); err != nil {
+			log.Debug("goreman stop", "process", clusterProcessName(node), "error", err)
+		}
+		configPath := filepath.Join(config.GetNATSClusterDataPath(), node.Name, "nats.conf")
+		if err := stopNodeProcessByConfig(configPath); err != nil {
+			return fmt.Errorf("stop node %s: %w", node.Name, err)
+		}
+	}
+	for _, node := range nodes {
+		configPath := filepath.Join(config.GetNATSClusterDataPath(), node.Name, "nats.conf")
+		if err := waitForNodeExit(ctx, configPath); err != nil {
+			return fmt.Errorf("stop node %s: %w", node.Name, err)
+		}
+		log.Info("NATS node stopped", "node", node.Name)
+	}
+	return nil
+}
+
+// orderNodesForStop splits nodes into followers and the leader.
+func orderNodesForStop(nodes []ClusterNode, leaderName string) (followers, leader []ClusterNode) {
+	for _, node := range nodes {
+		if node.Name == leaderName {
+			leader = append(leader, node)
+		} else {
+			followers = append(followers, node)
+		}
+	}
+	return followers, leader
+}
+
+// findMetaLeader polls the nodes' /jsz endpoints until one reports the
+// JetStream meta leader, then returns that node. It gives up straight away
+// when no node's monitoring endpoint answers at all.
+func findMetaLeader(ctx context.Context, nodes []ClusterNode, timeout time.Duration) (ClusterNode, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	for {
+		answered := false
+		for _, node := range nodes {
+			var jsz jszResponse
+			if err := getMonitorJSON(client, nodeMonitorURL(node, true)+"jsz", &jsz); err != nil {
+				continue
+			}
+			answered = true
+			if jsz.MetaCluster == nil || jsz.MetaCluster.Leader == "" {
+				continue
+			}
+			for _, candidate := range nodes {
+				if candidate.Name == jsz.MetaCluster.Leader {
+					return candidate, nil
+				}
+			}
+			return ClusterNode{}, fmt.Errorf("meta leader %q is not a known cluster node", jsz.MetaCluster.Leader)
+		}
+		if !answered {
+			return ClusterNode{}, fmt.Errorf("no node answered on its monitoring endpoint")
+		}
+
+		select {
+		case <-timeoutCtx.Done():
+			return ClusterNode{}, fmt.Errorf("no node reported a meta leader within %s", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// stepDownMetaLeader asks the meta leader to step down using the system
+// account credentials.
+func stepDownMetaLeader(ctx context.Context, leader ClusterNode) error {
+	url := fmt.Sprintf("nats://127.0.0.1:%d", leader.Port)
+	nc, err := gonats.Connect(url,
+		gonats.UserCredentials(config.GetNATSSystemCredsPath()),
+		gonats.Name("infra-cluster-stop"),
+		gonats.Timeout(5*time.Second),
+	)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", url, err)
+	}
+	defer nc.Close()
+
+	reqCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	msg, err := nc.RequestWithContext(reqCtx, metaLeaderStepDownSubject, nil)
+	if err != nil {
+		return fmt.Errorf("request step-down: %w", err)
+	}
+
+	var resp struct {
+		Success bool `json:"success"`
+		Error   *struct {
+			Description string `json:"description"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(msg.Data, &resp); err != nil {
+		return fmt.Errorf("decode step-down response: %w", err)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("step-down rejected: %s", resp.Error.Description)
+	}
+	if !resp.Success {
+		return fmt.Errorf("step-down not acknowledged")
+	}
+	return nil
+}
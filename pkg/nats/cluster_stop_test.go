@@ -0,0 +1,81 @@
+package nats
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func jszTestNode(t *testing.T, name, leader string) ClusterNode {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"meta_cluster":{"leader":"` + leader + `","cluster_size":3}}`))
+	}))
+	t.Cleanup(srv.Close)
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return ClusterNode{Name: name, HTTPPort: port}
+}
+
+func TestFindMetaLeaderAndStopOrder(t *testing.T) {
+	nodes := []ClusterNode{
+		jszTestNode(t, "nats-1", "nats-2"),
+		jszTestNode(t, "nats-2", "nats-2"),
+		jszTestNode(t, "nats-3", "nats-2"),
+	}
+
+	leader, err := findMetaLeader(context.Background(), nodes, time.Second)
+	if err != nil {
+		t.Fatalf("findMetaLeader: %v", err)
+	}
+	if leader.Name != "nats-2" {
+		t.Fatalf("leader = %s, want nats-2", leader.Name)
+	}
+
+	followers, last := orderNodesForStop(nodes, leader.Name)
+	if len(followers) != 2 || followers[0].Name != "nats-1" || followers[1].Name != "nats-3" {
+		t.Fatalf("unexpected followers: %+v", followers)
+	}
+	if len(last) != 1 || last[0].Name != "nats-2" {
+		t.Fatalf("leader should be stopped last, got %+v", last)
+	}
+}
+
+func TestFindMetaLeaderTimesOut(t *testing.T) {
+	nodes := []ClusterNode{jszTestNode(t, "nats-1", "")}
+	if _, err := findMetaLeader(context.Background(), nodes, 100*time.Millisecond); err == nil {
+		t.Fatal("expected timeout when no leader is reported")
+	}
+}
+
+func TestFindMetaLeaderNoAnswer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	start := time.Now()
+	_, err = findMetaLeader(context.Background(), []ClusterNode{{Name: "nats-1", HTTPPort: port}}, 10*time.Second)
+	if err == nil {
+		t.Fatal("expected an error when no node answers")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("findMetaLeader waited %s with nothing answering", elapsed)
+	}
+}
+
+func TestStopLocalClusterNothingRunning(t *testing.T) {
+	start := time.Now()
+	if err := StopLocalClusterWithOptions(context.Background(), StopOptions{LeaderTimeout: 10 * time.Second, RequireLeader: true}); err != nil {
+		t.Fatalf("stop with nothing running: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("stop took %s with nothing running", elapsed)
+	}
+}
@@ -0,0 +1,55 @@
+package nats
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/nats-server/v2/conf"
+
+	"github.com/joeblew999/infra/pkg/config"
+	"github.com/joeblew999/infra/pkg/nats/auth"
+)
+
+func TestRenderClusterConfigs(t *testing.T) {
+	cfg := ClusterConfig{
+		ClusterName: "preview",
+		Nodes: []ClusterNode{
+			{Name: "nats-1", Host: "127.0.0.1", Port: 4222, ClusterPort: 6222, HTTPPort: 8222, LeafPort: 4522},
+			{Name: "nats-2", Host: "127.0.0.1", Port: 4223, ClusterPort: 6223, HTTPPort: 8223, LeafPort: 4523},
+			{Name: "nats-3", Host: "127.0.0.1", Port: 4224, ClusterPort: 6224, HTTPPort: 8224, LeafPort: 4524},
+		},
+	}
+	artifacts := &auth.Artifacts{OperatorJWT: "op", SystemAccountID: "SYS", SystemAccountJWT: "sys", ApplicationAccountID: "APP", ApplicationAccountJWT: "app"}
+
+	configs, err := RenderClusterConfigs(cfg, artifacts)
+	if err != nil {
+		t.Fatalf("RenderClusterConfigs: %v", err)
+	}
+	if len(configs) != 3 {
+		t.Fatalf("expected 3 configs, got %d", len(configs))
+	}
+
+	parsed, err := conf.Parse(configs["nats-2"])
+	if err != nil {
+		t.Fatalf("nats-2 config does not parse: %v", err)
+	}
+	cluster := parsed["cluster"].(map[string]any)
+	routes := cluster["routes"].([]any)
+	if len(routes) != 2 || routes[0] != "nats://127.0.0.1:6222" || routes[1] != "nats://127.0.0.1:6224" {
+		t.Fatalf("nats-2 routes = %v", routes)
+	}
+	jetstream := parsed["jetstream"].(map[string]any)
+	wantStore := filepath.Join(config.GetNATSClusterDataPath(), "nats-2", "jetstream")
+	if jetstream["store_dir"] != wantStore {
+		t.Fatalf("store_dir = %v, want %s", jetstream["store_dir"], wantStore)
+	}
+	if !strings.Contains(configs["nats-1"], "server_name: nats-1") {
+		t.Fatalf("nats-1 config missing server_name:\n%s", configs["nats-1"])
+	}
+
+	if _, err := os.Stat(wantStore); !os.IsNotExist(err) {
+		t.Fatalf("preview should not create %s", wantStore)
+	}
+}
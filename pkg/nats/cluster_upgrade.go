@@ -0,0 +1,208 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joeblew999/infra/pkg/config"
+	"github.com/joeblew999/infra/pkg/goreman"
+	"github.com/joeblew999/infra/pkg/log"
+	"github.com/joeblew999/infra/pkg/nats/auth"
+	"github.com/joeblew999/infra/pkg/service"
+)
+
+// lameDuckDrainTimeout bounds how long we wait for a node in lame duck mode to
+// shed its clients. It covers the server's default lame_duck_duration (2m).
+const lameDuckDrainTimeout = 150 * time.Second
+
+// upgradeLocalClusterNode restarts a goreman-managed node onto the current
+// binary and config. The node is put into lame duck mode first so clients
+// migrate to its peers before it stops.
+func upgradeLocalClusterNode(ctx context.Context, clusterConfig ClusterConfig, node ClusterNode, authArtifacts *auth.Artifacts) error {
+	nodeDataPath := filepath.Join(config.GetNATSClusterDataPath(), node.Name)
+	configPath := filepath.Join(nodeDataPath, "nats.conf")
+	processName := clusterProcessName(node)
+
+	pids, err := findNodePIDs(configPath)
+	if err != nil {
+		return err
+	}
+	if pid, ok := goreman.GetProcessPID(processName); ok && len(pids) == 0 {
+		pids = []int{pid}
+	}
+
+	if len(pids) == 0 {
+		log.Warn("NATS node not running, starting it without lame duck", "node", node.Name)
+	} else {
+		for _, pid := range pids {
+			if err := enterLameDuck(ctx, pid); err != nil {
+				return fmt.Errorf("enter lame duck mode: %w", err)
+			}
+		}
+		log.Info("NATS node entered lame duck mode", "node", node.Name, "pids", pids)
+
+		if err := waitForNodeDrain(ctx, node, configPath); err != nil {
+			log.Warn("NATS node did not drain in time, stopping anyway", "node", node.Name, "error", err)
+		}
+		if err := goreman.Stop(processName); err != nil {
+			log.Debug("goreman stop", "process", processName, "error", err)
+		}
+		if err := stopNodeProcessByConfig(configPath); err != nil {
+			return err
+		}
+		if err := waitForNodeExit(ctx, configPath); err != nil {
+			return err
+		}
+	}
+
+	if err := writeNodeConfig(clusterConfig, node, configPath, nodeDataPath, authArtifacts); err != nil {
+		return err
+	}
+	processCfg := service.NewConfig(config.Get(config.BinaryNatsServer), []string{"--config", configPath})
+	if err := service.Start(processName, processCfg); err != nil {
+		return fmt.Errorf("failed to restart cluster node %s: %w", node.Name, err)
+	}
+	return nil
+}
+
+// enterLameDuck asks the nats-server with the given PID to enter lame duck mode.
+func enterLameDuck(ctx context.Context, pid int) error {
+	cmd := exec.CommandContext(ctx, config.Get(config.BinaryNatsServer), "--signal", fmt.Sprintf("ldm=%d", pid))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("signal ldm=%d: %w: %s", pid, err, msg)
+		}
+		return fmt.Errorf("signal ldm=%d: %w", pid, err)
+	}
+	return nil
+}
+
+// waitForNodeDrain waits until a lame duck node has no client connections or
+// has exited on its own.
+func waitForNodeDrain(ctx context.Context, node ClusterNode, configPath string) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, lameDuckDrainTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	client := &http.Client{Timeout: 2 * time.Second}
+	base := nodeMonitorURL(node, true)
+	for {
+		pids, err := findNodePIDs(configPath)
+		if err == nil && len(pids) == 0 {
+			return nil
+		}
+		var varz varzResponse
+		if err := getMonitorJSON(client, base+"varz", &varz); err == nil && varz.Connections == 0 {
+			return nil
+		}
+
+		select {
+		case <-timeoutCtx.Done():
+			return fmt.Errorf("timeout waiting for connections to drain")
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForNodeExit waits for every process started with configPath to exit.
+func waitForNodeExit(ctx context.Context, configPath string) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		pids, err := findNodePIDs(configPath)
+		if err != nil {
+			return err
+		}
+		if len(pids) == 0 {
+			return nil
+		}
+
+		select {
+		case <-timeoutCtx.Done():
+			return fmt.Errorf("timeout waiting for node processes %v to exit", pids)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkNodeReadiness returns what is keeping a node from being ready, or nil
+// when it has routes to all peers and (with JetStream) reports healthy.
+func checkNodeReadiness(client *http.Client, base string, clusterConfig ClusterConfig, node ClusterNode) []string {
+	var problems []string
+
+	connected, err := fetchRoutePeers(client, base)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var missing []string
+	for _, peer := range clusterConfig.Nodes {
+		if peer.Name == node.Name {
+			continue
+		}
+		if _, ok := connected[peer.Name]; !ok {
+			missing = append(missing, peer.Name)
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing routes to %s", strings.Join(missing, ", ")))
+	}
+
+	if clusterConfig.EnableJetStream {
+		if err := checkJetStreamCurrent(client, base); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems
+}
+
+// fetchRoutePeers returns the server names the node has cluster routes to,
+// per /routez. Route pooling can open several routes to the same peer.
+func fetchRoutePeers(client *http.Client, base string) (map[string]struct{}, error) {
+	var routez struct {
+		Routes []struct {
+			RemoteName string `json:"remote_name"`
+		} `json:"routes"`
+	}
+	if err := getMonitorJSON(client, base+"routez", &routez); err != nil {
+		return nil, err
+	}
+	peers := make(map[string]struct{}, len(routez.Routes))
+	for _, route := range routez.Routes {
+		if route.RemoteName != "" {
+			peers[route.RemoteName] = struct{}{}
+		}
+	}
+	return peers, nil
+}
+
+// checkJetStreamCurrent uses /healthz, which fails until the node's JetStream
+// meta group and assets are current with the cluster.
+func checkJetStreamCurrent(client *http.Client, base string) error {
+	resp, err := client.Get(base + "healthz")
+	if err != nil {
+		return fmt.Errorf("jetstream health: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var health struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&health)
+	if health.Error != "" {
+		return fmt.Errorf("jetstream not current: %s", health.Error)
+	}
+	return fmt.Errorf("jetstream not current: healthz returned %s", resp.Status)
+}
@@ -0,0 +1,79 @@
+package nats
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func readinessTestServer(t *testing.T, routez func() string, healthy func() bool) (ClusterConfig, ClusterNode) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/routez", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(routez()))
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if healthy() {
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"status":"unavailable","error":"JetStream is not current with the meta leader"}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	node := ClusterNode{Name: "nats-1", HTTPPort: port}
+	cfg := ClusterConfig{
+		EnableJetStream: true,
+		Nodes:           []ClusterNode{node, {Name: "nats-2"}, {Name: "nats-3"}},
+	}
+	return cfg, node
+}
+
+func TestWaitForNodeReadyReportsMissingRoutes(t *testing.T) {
+	// Pooled routes: several connections to nats-2, none to nats-3.
+	cfg, node := readinessTestServer(t,
+		func() string {
+			return `{"num_routes":3,"routes":[{"remote_name":"nats-2"},{"remote_name":"nats-2"},{"remote_name":"nats-2"}]}`
+		},
+		func() bool { return false },
+	)
+
+	err := waitForNodeReady(context.Background(), cfg, node, true, 100*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected timeout while routes are missing")
+	}
+	// Only the unconnected peer is reported, alongside the JetStream problem.
+	for _, want := range []string{"missing routes to nats-3;", "JetStream is not current"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestWaitForNodeReadyOnceRoutesConnect(t *testing.T) {
+	var polls atomic.Int32
+	cfg, node := readinessTestServer(t,
+		func() string {
+			if polls.Add(1) < 2 {
+				return `{"routes":[]}`
+			}
+			return `{"routes":[{"remote_name":"nats-2"},{"remote_name":"nats-3"}]}`
+		},
+		func() bool { return true },
+	)
+
+	if err := waitForNodeReady(context.Background(), cfg, node, true, 5*time.Second); err != nil {
+		t.Fatalf("expected node ready: %v", err)
+	}
+}
@@ -2,11 +2,16 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/joeblew999/infra/pkg/config"
 	"github.com/joeblew999/infra/pkg/nats"
+	"github.com/joeblew999/infra/pkg/nats/auth"
 	"github.com/spf13/cobra"
 )
 
@@ -38,11 +43,21 @@ func NewClusterCmd() *cobra.Command {
 	var localStopCmd = &cobra.Command{
 		Use:   "stop",
 		Short: "Stop local NATS cluster",
-		Long:  `Stop all local NATS cluster processes supervised by goreman`,
+		Long: `Stop all local NATS cluster processes supervised by goreman.
+The JetStream meta leader is asked to step down and is stopped after the
+follower nodes. If the leader cannot be found within --leader-timeout, all
+nodes are stopped at once unless --require-leader is set.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return nats.StopLocalCluster()
+			leaderTimeout, _ := cmd.Flags().GetDuration("leader-timeout")
+			requireLeader, _ := cmd.Flags().GetBool("require-leader")
+			return nats.StopLocalClusterWithOptions(context.Background(), nats.StopOptions{
+				LeaderTimeout: leaderTimeout,
+				RequireLeader: requireLeader,
+			})
 		},
 	}
+	localStopCmd.Flags().Duration("leader-timeout", 10*time.Second, "How long to look for the JetStream meta leader")
+	localStopCmd.Flags().Bool("require-leader", false, "Fail instead of stopping unordered when no leader is found")
 
 	var localStatusCmd = &cobra.Command{
 		Use:   "status",
@@ -56,7 +71,7 @@ func NewClusterCmd() *cobra.Command {
 	var localUpgradeCmd = &cobra.Command{
 		Use:   "upgrade",
 		Short: "Upgrade local NATS cluster",
-		Long:  `Placeholder for rolling upgrades of the goreman-managed local NATS cluster (not yet implemented)`,
+		Long:  `Rolling restart of the goreman-managed local NATS cluster: each node enters lame duck mode, drains, restarts on the current binary and config, and must rejoin the cluster before the next node is upgraded`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			return nats.UpgradeCluster(ctx, true)
@@ -104,6 +119,23 @@ func NewClusterCmd() *cobra.Command {
 		},
 	}
 
+	var configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Show NATS cluster node configs",
+		Long: `Print the nats.conf of each cluster node. By default the files currently
+on disk are shown; with --preview the configs EnsureCluster would write are
+rendered instead, without installing nsc, generating credentials, writing
+files or starting servers. Auth material that has not been generated yet is
+shown as placeholders.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			preview, _ := cmd.Flags().GetBool("preview")
+			prod, _ := cmd.Flags().GetBool("prod")
+			return showClusterConfigs(!prod, preview)
+		},
+	}
+	configCmd.Flags().Bool("preview", false, "Render the configs that would be written instead of reading them from disk")
+	configCmd.Flags().Bool("prod", false, "Use the Fly.io production cluster layout")
+
 	// Bootstrap command for initial setup
 	var bootstrapCmd = &cobra.Command{
 		Use:   "bootstrap",
@@ -138,6 +170,7 @@ This command is idempotent and safe to run multiple times.`,
 	clusterCmd.AddCommand(prodStatusCmd)
 	clusterCmd.AddCommand(prodUpgradeCmd)
 	clusterCmd.AddCommand(statusCmd)
+	clusterCmd.AddCommand(configCmd)
 	clusterCmd.AddCommand(bootstrapCmd)
 
 	return clusterCmd
@@ -145,7 +178,7 @@ This command is idempotent and safe to run multiple times.`,
 
 // showClusterStatus displays cluster status in a user-friendly format
 func showClusterStatus(isLocal bool) error {
-	clusterConfig, err := nats.GetClusterStatus(isLocal)
+	clusterConfig, details, err := nats.GetClusterStatusDetailed(isLocal)
 	if err != nil {
 		return fmt.Errorf("failed to get cluster status: %w", err)
 	}
@@ -161,14 +194,16 @@ func showClusterStatus(isLocal bool) error {
 	fmt.Printf("Environment: %s\n", clusterConfig.Environment)
 	fmt.Printf("Nodes: %d\n", len(clusterConfig.Nodes))
 	fmt.Printf("JetStream: %v\n", clusterConfig.EnableJetStream)
-	fmt.Printf("Web GUI: %v\n\n", clusterConfig.EnableWebGUI)
+	fmt.Printf("Web GUI: %v\n", clusterConfig.EnableWebGUI)
+	fmt.Printf("Meta Leader: %s\n\n", metaLeader(details))
 
 	// Display node details
 	fmt.Println("Node Details:")
-	fmt.Printf("%-12s %-8s %-12s %-14s %-12s %-10s\n", "NAME", "REGION", "CLIENT_PORT", "CLUSTER_PORT", "HTTP_PORT", "STATUS")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-12s %-8s %-12s %-14s %-12s %-6s %-12s %-10s\n", "NAME", "REGION", "CLIENT_PORT", "CLUSTER_PORT", "HTTP_PORT", "CONNS", "UPTIME", "STATUS")
+	fmt.Println(strings.Repeat("-", 100))
 
-	for _, node := range clusterConfig.Nodes {
+	for _, detail := range details {
+		node := detail.ClusterNode
 		status := node.Status
 		statusEmoji := "❓"
 		switch status {
@@ -179,9 +214,16 @@ func showClusterStatus(isLocal bool) error {
 		case "error":
 			statusEmoji = "❌"
 		}
+		if detail.JetStream != nil && detail.JetStream.IsMetaLeader {
+			status += " (leader)"
+		}
+		uptime := detail.Uptime
+		if uptime == "" {
+			uptime = "-"
+		}
 
-		fmt.Printf("%-12s %-8s %-12d %-14d %-12d %s %s\n",
-			node.Name, node.Region, node.Port, node.ClusterPort, node.HTTPPort, statusEmoji, status)
+		fmt.Printf("%-12s %-8s %-12d %-14d %-12d %-6d %-12s %s %s\n",
+			node.Name, node.Region, node.Port, node.ClusterPort, node.HTTPPort, detail.Connections, uptime, statusEmoji, status)
 	}
 
 	// Display web GUI URLs for local cluster
@@ -199,3 +241,66 @@ func showClusterStatus(isLocal bool) error {
 
 	return nil
 }
+
+// metaLeader returns the JetStream meta leader reported by any node.
+func metaLeader(details []nats.NodeDetail) string {
+	for _, detail := range details {
+		if detail.JetStream != nil && detail.JetStream.MetaLeader != "" {
+			return detail.JetStream.MetaLeader
+		}
+	}
+	return "unknown"
+}
+
+// showClusterConfigs prints each node's nats.conf, either rendered from the
+// cluster layout (preview) or read from the node's data directory.
+func showClusterConfigs(isLocal, preview bool) error {
+	clusterConfig := nats.GetFlyClusterConfig()
+	if isLocal {
+		clusterConfig = nats.GetLocalClusterConfig()
+	}
+
+	var configs map[string]string
+	if preview {
+		// Previewing must not install nsc or write credentials, so render
+		// from whatever auth material exists and fall back to placeholders.
+		authArtifacts, err := auth.Load()
+		if errors.Is(err, os.ErrNotExist) {
+			authArtifacts = placeholderAuthArtifacts()
+		} else if err != nil {
+			return fmt.Errorf("load auth materials: %w", err)
+		}
+		configs, err = nats.RenderClusterConfigs(clusterConfig, authArtifacts)
+		if err != nil {
+			return fmt.Errorf("render cluster configs: %w", err)
+		}
+	} else {
+		configs = make(map[string]string, len(clusterConfig.Nodes))
+		for _, node := range clusterConfig.Nodes {
+			path := filepath.Join(config.GetNATSClusterDataPath(), node.Name, "nats.conf")
+			data, err := os.ReadFile(path)
+			if err != nil {
+				configs[node.Name] = fmt.Sprintf("# %s: %v\n", path, err)
+				continue
+			}
+			configs[node.Name] = string(data)
+		}
+	}
+
+	for _, node := range clusterConfig.Nodes {
+		fmt.Printf("==> %s <==\n%s\n", node.Name, strings.TrimSpace(configs[node.Name]))
+	}
+	return nil
+}
+
+// placeholderAuthArtifacts stands in for auth material that has not been
+// generated yet, so a preview shows where it will go.
+func placeholderAuthArtifacts() *auth.Artifacts {
+	return &auth.Artifacts{
+		OperatorJWT:           "<operator-jwt>",
+		SystemAccountID:       "<system-account-id>",
+		SystemAccountJWT:      "<system-account-jwt>",
+		ApplicationAccountID:  "<application-account-id>",
+		ApplicationAccountJWT: "<application-account-jwt>",
+	}
+}
@@ -2,10 +2,15 @@ package pocketbase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"sync"
 
 	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
 
 	"github.com/joeblew999/infra/pkg/config"
 	"github.com/joeblew999/infra/pkg/dep"
@@ -15,6 +20,7 @@ import (
 
 // Server represents a PocketBase server instance
 type Server struct {
+	mu      sync.Mutex
 	app     *pocketbase.PocketBase
 	port    string
 	env     string
@@ -35,7 +41,14 @@ func (s *Server) SetDataDir(dataDir string) {
 	s.dataDir = dataDir
 }
 
-// Start starts the PocketBase server
+// SetPort sets the HTTP port the server listens on
+func (s *Server) SetPort(port string) {
+	s.port = port
+}
+
+// Start runs the PocketBase server until ctx is cancelled or Stop is
+// called, then shuts the HTTP server down gracefully and closes the
+// databases before returning.
 func (s *Server) Start(ctx context.Context) error {
 	log.Info("Starting PocketBase server", "port", s.port, "env", s.env, "data_dir", s.dataDir)
 
@@ -49,33 +62,60 @@ func (s *Server) Start(ctx context.Context) error {
 	app := pocketbase.NewWithConfig(pocketbase.Config{
 		DefaultDataDir: s.dataDir,
 	})
+	if err := app.Bootstrap(); err != nil {
+		return fmt.Errorf("failed to bootstrap PocketBase: %w", err)
+	}
 
+	s.mu.Lock()
 	s.app = app
-
-	// Start the PocketBase server
-	log.Info("Starting PocketBase server...", "port", s.port, "data_dir", s.dataDir)
-
-	// Use the app's built-in serve functionality with proper context handling
-	app.RootCmd.SetArgs([]string{
-		"serve",
-		"--dir", s.dataDir,
-		"--http", ":" + s.port,
+	s.mu.Unlock()
+
+	// Serve directly rather than through app.Execute, which only shuts down
+	// on process signals and so can't be stopped by ctx. Serve registers its
+	// graceful-shutdown OnTerminate hook before OnServe fires.
+	serving := make(chan struct{})
+	app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		close(serving)
+		return e.Next()
 	})
-
-	// Execute synchronously - this will block, which is expected for a server
-	// This should work for the embedded version
-	if err := app.Execute(); err != nil {
+	served := make(chan error, 1)
+	go func() {
+		served <- apis.Serve(app, apis.ServeConfig{HttpAddr: ":" + s.port})
+	}()
+
+	var err error
+	select {
+	case err = <-served:
+	case <-ctx.Done():
+		log.Info("Stopping PocketBase server", "port", s.port)
+		select {
+		case <-serving:
+			s.Stop()
+			err = <-served
+		case err = <-served:
+		}
+	}
+	s.Stop()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Error("PocketBase server error", "error", err)
 		return err
 	}
-
 	return nil
 }
 
-// Stop gracefully stops the PocketBase server
+// Stop gracefully stops the PocketBase server: OnTerminate shuts the HTTP
+// server down and the bootstrap state (database connections) is reset.
 func (s *Server) Stop() error {
-	// PocketBase stops via context cancellation, no explicit Stop method needed
-	return nil
+	s.mu.Lock()
+	app := s.app
+	s.app = nil
+	s.mu.Unlock()
+	if app == nil {
+		return nil
+	}
+	return app.OnTerminate().Trigger(&core.TerminateEvent{App: app}, func(e *core.TerminateEvent) error {
+		return e.App.ResetBootstrapState()
+	})
 }
 
 // GetDataDir returns the PocketBase data directory
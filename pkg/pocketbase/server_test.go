@@ -0,0 +1,58 @@
+// PocketBase v0.30's Collection.UnmarshalJSON recurses forever when
+// encoding/json is backed by json/v2, so this test needs the v1 implementation.
+
+//go:build !goexperiment.jsonv2
+
+package pocketbase
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestServerStopsOnContextCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	server := NewServer("test")
+	server.SetDataDir(t.TempDir())
+	server.SetPort(strconv.Itoa(port))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Start(ctx) }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("PocketBase never listened on %d: %v", port, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	begin := time.Now()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Start returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("PocketBase did not stop after its context was cancelled")
+	}
+	if elapsed := time.Since(begin); elapsed > 3*time.Second {
+		t.Errorf("stop took %s, want well inside the 10s stop timeout", elapsed)
+	}
+}
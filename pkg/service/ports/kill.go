@@ -0,0 +1,109 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ErrNoProcess is returned when nothing is listening on a port.
+var ErrNoProcess = errors.New("no process listening on port")
+
+// KillError reports a listener that was found but could not be stopped.
+type KillError struct {
+	Port int
+	PID  int32
+	Err  error
+}
+
+func (e *KillError) Error() string {
+	return fmt.Sprintf("kill pid %d on port %d: %v", e.PID, e.Port, e.Err)
+}
+
+func (e *KillError) Unwrap() error { return e.Err }
+
+// KillProcessByPort kills the process listening on the given port. It returns
+// an error wrapping ErrNoProcess when the port is free and a *KillError when
+// the listener survives.
+func KillProcessByPort(port int) error {
+	pid, err := listenerPID(port)
+	if err != nil {
+		return err
+	}
+	if err := killPID(pid); err != nil {
+		return &KillError{Port: port, PID: pid, Err: err}
+	}
+	return nil
+}
+
+// KillProcessByPortGraceful asks the listener on port to shut down (SIGTERM,
+// or a plain taskkill on Windows) and waits up to grace for the port to free
+// before killing it outright. This gives services such as JetStream or
+// PocketBase the chance to flush their data dirs. A grace of zero behaves
+// like KillProcessByPort.
+func KillProcessByPortGraceful(port int, grace time.Duration) error {
+	pid, err := listenerPID(port)
+	if err != nil {
+		return err
+	}
+	if grace > 0 {
+		if err := platformTerminate(pid); err == nil && WaitAvailable(port, grace) {
+			return nil
+		}
+	}
+	if err := killPID(pid); err != nil {
+		if IsAvailable(port) {
+			// It exited on its own between the grace period and the kill.
+			return nil
+		}
+		return &KillError{Port: port, PID: pid, Err: err}
+	}
+	return nil
+}
+
+// KillProcess terminates a process by PID.
+func KillProcess(pid string) error {
+	if pid == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(pid), 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid pid %q: %w", pid, err)
+	}
+	return killPID(int32(n))
+}
+
+// listenerPID finds the PID listening on port. gopsutil covers every platform
+// without shelling out; the platform tools are only consulted when it cannot
+// see the socket (e.g. one owned by another user on macOS).
+func listenerPID(port int) (int32, error) {
+	if conns, err := psnet.Connections("tcp"); err == nil {
+		for _, c := range conns {
+			if c.Status == "LISTEN" && c.Laddr.Port == uint32(port) && c.Pid > 0 {
+				return c.Pid, nil
+			}
+		}
+	}
+	if pid := platformListenerPID(port); pid > 0 {
+		return pid, nil
+	}
+	return 0, fmt.Errorf("port %d: %w", port, ErrNoProcess)
+}
+
+func killPID(pid int32) error {
+	p, err := process.NewProcess(pid)
+	if err == nil {
+		if err = p.Kill(); err == nil {
+			return nil
+		}
+	}
+	if platformErr := platformKill(pid); platformErr != nil {
+		return errors.Join(err, platformErr)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+
+package ports
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func platformListenerPID(port int) int32 {
+	output, err := exec.Command("lsof", "-ti", ":"+strconv.Itoa(port), "-sTCP:LISTEN").Output()
+	if err != nil {
+		return 0
+	}
+	return parseLsofPID(string(output))
+}
+
+// parseLsofPID returns the first PID printed by `lsof -t`.
+func parseLsofPID(output string) int32 {
+	for _, line := range strings.Split(output, "\n") {
+		if pid, err := strconv.ParseInt(strings.TrimSpace(line), 10, 32); err == nil && pid > 0 {
+			return int32(pid)
+		}
+	}
+	return 0
+}
+
+func platformKill(pid int32) error {
+	return exec.Command("kill", "-9", strconv.Itoa(int(pid))).Run()
+}
+
+func platformTerminate(pid int32) error {
+	return syscall.Kill(int(pid), syscall.SIGTERM)
+}
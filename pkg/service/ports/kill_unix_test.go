@@ -0,0 +1,98 @@
+//go:build !windows
+
+package ports
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestHelperListener is not a real test: TestKillProcessByPort re-runs the
+// test binary with PORTS_HELPER_LISTEN set so it has a separate process
+// holding a port.
+func TestHelperListener(t *testing.T) {
+	if os.Getenv("PORTS_HELPER_LISTEN") == "" {
+		t.Skip("helper process")
+	}
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Println(l.Addr().(*net.TCPAddr).Port)
+	select {}
+}
+
+// startListenerHelper runs TestHelperListener in a child process and returns
+// it with the port it holds.
+func startListenerHelper(t *testing.T) (*exec.Cmd, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperListener$")
+	cmd.Env = append(os.Environ(), "PORTS_HELPER_LISTEN=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("read helper port: %v", err)
+	}
+	port, err := strconv.Atoi(line[:len(line)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmd, port
+}
+
+func TestKillProcessByPort(t *testing.T) {
+	cmd, port := startListenerHelper(t)
+
+	if got := GetProcessByPort(port); got != strconv.Itoa(cmd.Process.Pid) {
+		t.Fatalf("GetProcessByPort = %q, want %d", got, cmd.Process.Pid)
+	}
+	if err := KillProcessByPort(port); err != nil {
+		t.Fatalf("KillProcessByPort: %v", err)
+	}
+	_ = cmd.Wait()
+	if !WaitAvailable(port, 2*time.Second) {
+		t.Fatalf("port %d still busy after kill", port)
+	}
+
+	if err := KillProcessByPort(port); !errors.Is(err, ErrNoProcess) {
+		t.Fatalf("second kill = %v, want ErrNoProcess", err)
+	}
+}
+
+func TestKillProcessByPortGraceful(t *testing.T) {
+	cmd, port := startListenerHelper(t)
+
+	if err := KillProcessByPortGraceful(port, 5*time.Second); err != nil {
+		t.Fatalf("KillProcessByPortGraceful: %v", err)
+	}
+	_ = cmd.Wait()
+	status := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !status.Signaled() || status.Signal() != syscall.SIGTERM {
+		t.Fatalf("helper exit = %v, want SIGTERM", cmd.ProcessState)
+	}
+}
+
+func TestParseLsofPID(t *testing.T) {
+	if got := parseLsofPID("4242\n4243\n"); got != 4242 {
+		t.Errorf("parseLsofPID = %d, want 4242", got)
+	}
+	if got := parseLsofPID(""); got != 0 {
+		t.Errorf("parseLsofPID(empty) = %d, want 0", got)
+	}
+}
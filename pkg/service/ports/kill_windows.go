@@ -0,0 +1,43 @@
+//go:build windows
+
+package ports
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+func platformListenerPID(port int) int32 {
+	output, err := exec.Command("netstat", "-ano", "-p", "TCP").Output()
+	if err != nil {
+		return 0
+	}
+	return parseNetstatPID(string(output), port)
+}
+
+// parseNetstatPID finds the LISTENING row for port in `netstat -ano` output.
+// The local address is matched on its exact port so :80 does not match :8080.
+func parseNetstatPID(output string, port int) int32 {
+	suffix := ":" + strconv.Itoa(port)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[3] != "LISTENING" || !strings.HasSuffix(fields[1], suffix) {
+			continue
+		}
+		if pid, err := strconv.ParseInt(fields[4], 10, 32); err == nil && pid > 0 {
+			return int32(pid)
+		}
+	}
+	return 0
+}
+
+func platformKill(pid int32) error {
+	return exec.Command("taskkill", "/F", "/PID", strconv.Itoa(int(pid))).Run()
+}
+
+// platformTerminate asks the process to close without /F, the closest Windows
+// has to SIGTERM.
+func platformTerminate(pid int32) error {
+	return exec.Command("taskkill", "/PID", strconv.Itoa(int(pid))).Run()
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package ports
+
+import "testing"
+
+func TestParseNetstatPID(t *testing.T) {
+	output := `
+Active Connections
+
+  Proto  Local Address          Foreign Address        State           PID
+  TCP    0.0.0.0:8080           0.0.0.0:0              LISTENING       1111
+  TCP    0.0.0.0:80             0.0.0.0:0              LISTENING       2222
+  TCP    127.0.0.1:80           127.0.0.1:51000        ESTABLISHED     3333
+  TCP    [::]:443               [::]:0                 LISTENING       4444
+`
+	tests := []struct {
+		port int
+		want int32
+	}{
+		{80, 2222},
+		{8080, 1111},
+		{443, 4444},
+		{9999, 0},
+	}
+	for _, tt := range tests {
+		if got := parseNetstatPID(output, tt.port); got != tt.want {
+			t.Errorf("parseNetstatPID(%d) = %d, want %d", tt.port, got, tt.want)
+		}
+	}
+}
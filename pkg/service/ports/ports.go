@@ -63,6 +63,33 @@ func WaitAvailable(port int, timeout time.Duration) bool {
 	return false
 }
 
+// FindFree asks the kernel for an unused TCP port. The probe listener is
+// closed before returning so the caller can bind the port itself.
+func FindFree() (int, error) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, fmt.Errorf("find free port: %w", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	if err := listener.Close(); err != nil {
+		return 0, fmt.Errorf("release probe port %d: %w", port, err)
+	}
+	return port, nil
+}
+
+// FindFreeInRange returns the first available port in [low, high].
+func FindFreeInRange(low, high int) (int, error) {
+	if low < 1 || high > 65535 || low > high {
+		return 0, fmt.Errorf("invalid port range %d-%d", low, high)
+	}
+	for port := low; port <= high; port++ {
+		if IsAvailable(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port in range %d-%d", low, high)
+}
+
 // Inspect inspects the port and classifies ownership against the expected PID (if any).
 func Inspect(port int, expectedPID string) (*Probe, error) {
 	probe := &Probe{Port: port}
@@ -117,34 +144,11 @@ func FormatConflictMessage(service string, probe *Probe) string {
 
 // GetProcessByPort returns the PID of the process listening on the given port.
 func GetProcessByPort(port int) string {
-	portStr := strconv.Itoa(port)
-
-	if runtime.GOOS == "windows" {
-		cmd := exec.Command("cmd", "/c", fmt.Sprintf(`netstat -ano | findstr :%s | findstr LISTENING`, portStr))
-		output, err := cmd.Output()
-		if err != nil {
-			return ""
-		}
-
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "LISTENING") {
-				parts := strings.Fields(line)
-				if len(parts) > 0 {
-					return parts[len(parts)-1]
-				}
-			}
-		}
-		return ""
-	}
-
-	cmd := exec.Command("sh", "-c", fmt.Sprintf(`lsof -ti :%s -sTCP:LISTEN 2>/dev/null | head -1`, portStr))
-	output, err := cmd.Output()
+	pid, err := listenerPID(port)
 	if err != nil {
 		return ""
 	}
-
-	return strings.TrimSpace(string(output))
+	return strconv.Itoa(int(pid))
 }
 
 // GetCommandForPID returns the command line for a given PID, if available.
@@ -247,30 +251,6 @@ func shortenPath(path string) string {
 	return clean
 }
 
-// KillProcessByPort kills any process listening on the given port.
-func KillProcessByPort(port int) error {
-	pid := GetProcessByPort(port)
-	if pid == "" {
-		return nil
-	}
-	return KillProcess(pid)
-}
-
-// KillProcess terminates a process by PID.
-func KillProcess(pid string) error {
-	if pid == "" {
-		return nil
-	}
-
-	if runtime.GOOS == "windows" {
-		cmd := exec.Command("taskkill", "/F", "/PID", pid)
-		return cmd.Run()
-	}
-
-	cmd := exec.Command("kill", "-9", pid)
-	return cmd.Run()
-}
-
 // KillProcessByName kills processes by name pattern.
 func KillProcessByName(name string) error {
 	if runtime.GOOS == "windows" {
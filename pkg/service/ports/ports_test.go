@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestFindFreeCanRebind(t *testing.T) {
+	port, err := FindFree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Fatalf("rebind port %d: %v", port, err)
+	}
+	l.Close()
+}
+
+func TestFindFreeInRange(t *testing.T) {
+	held, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer held.Close()
+	busy := held.Addr().(*net.TCPAddr).Port
+
+	if _, err := FindFreeInRange(busy, busy); err == nil {
+		t.Errorf("expected error when the only port in range is taken")
+	}
+	if _, err := FindFreeInRange(10, 5); err == nil {
+		t.Error("expected error for inverted range")
+	}
+	if _, err := FindFreeInRange(0, 70000); err == nil {
+		t.Error("expected error for out-of-bounds range")
+	}
+
+	free, err := FindFree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := FindFreeInRange(free, free)
+	if err != nil || got != free {
+		t.Fatalf("FindFreeInRange(%d, %d) = %d, %v", free, free, got, err)
+	}
+}
@@ -2,9 +2,9 @@ package runtime
 
 import (
 	"fmt"
-	"sort"
 	"strconv"
 
+	"github.com/joeblew999/infra/pkg/caddy"
 	"github.com/joeblew999/infra/pkg/config"
 )
 
@@ -14,51 +14,48 @@ type RouteSpec struct {
 	Target string
 }
 
-// caddyTemplate captures the aggregated routing information needed to generate a Caddyfile.
-type caddyTemplate struct {
-	ListenPort int
-	RootTarget string
-	Routes     []RouteSpec
-}
-
-func buildCaddyTemplate(specs []ServiceSpec) (caddyTemplate, error) {
-	var tpl caddyTemplate
-
-	listenPort, err := strconv.Atoi(config.GetCaddyPort())
-	if err != nil {
-		return tpl, fmt.Errorf("invalid caddy port: %w", err)
-	}
-	tpl.ListenPort = listenPort
-
-	routeSet := make(map[string]RouteSpec)
-
+// caddyServiceRoutes lists what Caddy should front for specs: the web server
+// at the root plus every declared route.
+func caddyServiceRoutes(specs []ServiceSpec) []caddy.ServiceRoute {
+	var routes []caddy.ServiceRoute
 	for _, spec := range specs {
 		if spec.ID == ServiceWeb && spec.Port != "" {
-			tpl.RootTarget = config.FormatLocalHostPort(spec.Port)
+			routes = append(routes, caddy.ServiceRoute{Name: spec.DisplayName, Port: spec.Port})
 		}
 		for _, route := range spec.Routes {
 			if route.Path == "" || route.Target == "" {
 				continue
 			}
-			if _, ok := routeSet[route.Path]; ok {
-				continue
-			}
-			routeSet[route.Path] = route
+			routes = append(routes, caddy.ServiceRoute{Name: spec.DisplayName, Target: route.Target, PathPrefix: route.Path})
 		}
 	}
+	return routes
+}
 
-	if tpl.RootTarget == "" {
-		tpl.RootTarget = config.FormatLocalHostPort(config.GetWebServerPort())
-	}
-
-	tpl.Routes = make([]RouteSpec, 0, len(routeSet))
-	for _, route := range routeSet {
-		tpl.Routes = append(tpl.Routes, route)
+func generateCaddyConfig(specs []ServiceSpec) (caddy.CaddyConfig, error) {
+	listenPort, err := strconv.Atoi(config.GetCaddyPort())
+	if err != nil {
+		return caddy.CaddyConfig{}, fmt.Errorf("invalid caddy port: %w", err)
 	}
+	cfg := caddy.ConfigFromServices(caddyServiceRoutes(specs)).WithPort(listenPort)
+	cfg.AccessLog = caddy.DefaultAccessLogPath()
+	return cfg, nil
+}
 
-	sort.Slice(tpl.Routes, func(i, j int) bool {
-		return tpl.Routes[i].Path < tpl.Routes[j].Path
-	})
+// CaddyConfigForRunningServices builds the Caddy config for the services the
+// runtime has actually started, so the proxy only routes to what came up.
+func CaddyConfigForRunningServices() (caddy.CaddyConfig, error) {
+	return generateCaddyConfig(runningServiceSpecs())
+}
 
-	return tpl, nil
+// caddySpecs picks the services Caddy should route to: the running ones once
+// startup has begun, otherwise everything selected for this run.
+func caddySpecs() []ServiceSpec {
+	if running := runningServiceSpecs(); len(running) > 0 {
+		return running
+	}
+	if len(activeServiceSpecs) > 0 {
+		return activeServiceSpecs
+	}
+	return buildServiceSpecs(activeOptions)
 }
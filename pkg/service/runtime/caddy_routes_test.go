@@ -4,7 +4,7 @@ import (
 	"testing"
 )
 
-func TestBuildCaddyTemplate(t *testing.T) {
+func TestGenerateCaddyConfig(t *testing.T) {
 	specs := []ServiceSpec{
 		{
 			ID:          ServiceWeb,
@@ -29,20 +29,36 @@ func TestBuildCaddyTemplate(t *testing.T) {
 		},
 	}
 
-	tpl, err := buildCaddyTemplate(specs)
+	cfg, err := generateCaddyConfig(specs)
 	if err != nil {
-		t.Fatalf("buildCaddyTemplate returned error: %v", err)
+		t.Fatalf("generateCaddyConfig returned error: %v", err)
 	}
 
-	if tpl.ListenPort == 0 {
+	if cfg.Port == 0 {
 		t.Fatalf("expected listen port to be set")
 	}
 
-	if tpl.RootTarget != "localhost:1337" {
-		t.Fatalf("expected root target localhost:1337, got %s", tpl.RootTarget)
+	if cfg.Target != "localhost:1337" {
+		t.Fatalf("expected root target localhost:1337, got %s", cfg.Target)
 	}
 
-	if len(tpl.Routes) != 2 {
-		t.Fatalf("expected two routes, got %d", len(tpl.Routes))
+	if len(cfg.Routes) != 2 {
+		t.Fatalf("expected two routes, got %d", len(cfg.Routes))
+	}
+}
+
+func TestCaddyConfigForRunningServices(t *testing.T) {
+	markServiceRunning(ServiceSpec{ID: ServiceWeb, Port: "1337"})
+	markServiceRunning(ServiceSpec{ID: ServiceBento, Routes: []RouteSpec{{Path: "/bento/*", Target: "localhost:4195"}}})
+	defer markServiceStopped(ServiceWeb)
+	defer markServiceStopped(ServiceBento)
+
+	cfg, err := CaddyConfigForRunningServices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Hugo is declared but not running, so /docs/* must not be routed.
+	if cfg.Target != "localhost:1337" || len(cfg.Routes) != 1 || cfg.Routes[0].Path != "/bento/*" {
+		t.Fatalf("unexpected config: %+v", cfg)
 	}
 }
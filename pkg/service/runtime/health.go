@@ -0,0 +1,158 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/joeblew999/infra/pkg/log"
+	svcports "github.com/joeblew999/infra/pkg/service/ports"
+)
+
+// AggregateHealthPath is where the web server exposes AggregateHealthHandler.
+const AggregateHealthPath = "/healthz/aggregate"
+
+// healthProbeTimeout bounds each per-service probe.
+const healthProbeTimeout = 2 * time.Second
+
+const (
+	healthUp       = "up"
+	healthDown     = "down"
+	healthDegraded = "degraded"
+)
+
+// HealthReport is the aggregated health of the services Start brought up.
+type HealthReport struct {
+	// Status is "up", "degraded" (an optional service is down) or "down" (a
+	// required service is down).
+	Status    string          `json:"status"`
+	CheckedAt time.Time       `json:"checked_at"`
+	Services  []ServiceHealth `json:"services"`
+}
+
+// ServiceHealth is the probe result for one service.
+type ServiceHealth struct {
+	ID        ServiceID `json:"id"`
+	Name      string    `json:"name"`
+	Required  bool      `json:"required"`
+	Status    string    `json:"status"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// runningServices tracks the services Start brought up, in start order, so
+// health checks only probe what is actually supposed to be running.
+var runningServices struct {
+	sync.Mutex
+	specs []ServiceSpec
+}
+
+func markServiceRunning(spec ServiceSpec) {
+	runningServices.Lock()
+	defer runningServices.Unlock()
+	runningServices.specs = append(runningServices.specs, spec)
+}
+
+func markServiceStopped(id ServiceID) {
+	runningServices.Lock()
+	defer runningServices.Unlock()
+	for i, spec := range runningServices.specs {
+		if spec.ID == id {
+			runningServices.specs = append(runningServices.specs[:i], runningServices.specs[i+1:]...)
+			return
+		}
+	}
+}
+
+func runningServiceSpecs() []ServiceSpec {
+	runningServices.Lock()
+	defer runningServices.Unlock()
+	return append([]ServiceSpec(nil), runningServices.specs...)
+}
+
+// AggregateHealth probes every running service concurrently.
+func AggregateHealth(ctx context.Context) HealthReport {
+	return aggregateHealth(ctx, runningServiceSpecs())
+}
+
+func aggregateHealth(ctx context.Context, specs []ServiceSpec) HealthReport {
+	report := HealthReport{
+		Status:    healthUp,
+		CheckedAt: time.Now(),
+		Services:  make([]ServiceHealth, len(specs)),
+	}
+
+	var wg sync.WaitGroup
+	for i, spec := range specs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Services[i] = probeService(ctx, spec)
+		}()
+	}
+	wg.Wait()
+
+	for _, svc := range report.Services {
+		if svc.Status == healthUp {
+			continue
+		}
+		if svc.Required {
+			report.Status = healthDown
+		} else if report.Status == healthUp {
+			report.Status = healthDegraded
+		}
+	}
+	return report
+}
+
+// probeService runs the spec's Ready probe, or a single connection attempt
+// to its port. Services with neither are reported up.
+func probeService(ctx context.Context, spec ServiceSpec) ServiceHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	begin := time.Now()
+	var err error
+	if spec.Ready != nil {
+		err = spec.Ready(ctx)
+	} else if port := svcports.ParsePort(spec.Port); port != 0 {
+		err = dialPort(ctx, port)
+	}
+
+	health := ServiceHealth{
+		ID:        spec.ID,
+		Name:      spec.DisplayName,
+		Required:  spec.Required,
+		Status:    healthUp,
+		LatencyMS: float64(time.Since(begin).Microseconds()) / 1000,
+	}
+	if err != nil {
+		health.Status = healthDown
+		health.Error = err.Error()
+	}
+	return health
+}
+
+// AggregateHealthHandler serves AggregateHealth as JSON, answering 503 when a
+// required service is down.
+func AggregateHealthHandler() http.Handler {
+	return aggregateHealthHandler(AggregateHealth)
+}
+
+func aggregateHealthHandler(check func(context.Context) HealthReport) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := check(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == healthDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(report); err != nil && !errors.Is(err, context.Canceled) {
+			log.Error("Error encoding aggregate health", "error", err)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAggregateHealth(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	failing := func(context.Context) error { return errors.New("no leader") }
+	specs := []ServiceSpec{
+		{ID: ServiceWeb, Required: true, Port: port},
+		{ID: ServiceBento, Ready: failing},
+	}
+
+	report := aggregateHealth(context.Background(), specs)
+	if report.Status != healthDegraded {
+		t.Fatalf("optional service down should degrade, got %s", report.Status)
+	}
+	if report.Services[0].Status != healthUp || report.Services[1].Error != "no leader" {
+		t.Fatalf("unexpected per-service results: %+v", report.Services)
+	}
+
+	specs[1].Required = true
+	if got := aggregateHealth(context.Background(), specs).Status; got != healthDown {
+		t.Fatalf("required service down should be down, got %s", got)
+	}
+}
+
+func TestAggregateHealthHandler(t *testing.T) {
+	for _, tt := range []struct {
+		status string
+		code   int
+	}{
+		{healthUp, http.StatusOK},
+		{healthDegraded, http.StatusOK},
+		{healthDown, http.StatusServiceUnavailable},
+	} {
+		h := aggregateHealthHandler(func(context.Context) HealthReport {
+			return HealthReport{Status: tt.status, Services: []ServiceHealth{{ID: ServiceNATS, Status: tt.status}}}
+		})
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, AggregateHealthPath, nil))
+		if rec.Code != tt.code {
+			t.Errorf("%s: code = %d, want %d", tt.status, rec.Code, tt.code)
+		}
+		var got HealthReport
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil || got.Status != tt.status || len(got.Services) != 1 {
+			t.Errorf("%s: body = %+v, %v", tt.status, got, err)
+		}
+	}
+}
+
+func TestRunningServices(t *testing.T) {
+	markServiceRunning(ServiceSpec{ID: ServiceNATS})
+	markServiceRunning(ServiceSpec{ID: ServiceWeb})
+	markServiceStopped(ServiceNATS)
+	defer markServiceStopped(ServiceWeb)
+
+	got := runningServiceSpecs()
+	if len(got) != 1 || got[0].ID != ServiceWeb {
+		t.Fatalf("running = %v", got)
+	}
+}
@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joeblew999/infra/pkg/goreman"
+	"github.com/joeblew999/infra/pkg/log"
+)
+
+// defaultStopTimeout bounds each service's stop step when its spec does not
+// set StopTimeout.
+const defaultStopTimeout = 10 * time.Second
+
+// startedService is a service Start brought up, with the cleanup its start
+// hook returned.
+type startedService struct {
+	spec    ServiceSpec
+	cleanup func()
+}
+
+// stopServices stops services in reverse start order so nothing is torn down
+// while a dependent (e.g. Caddy proxying to PocketBase) is still running.
+func stopServices(started []startedService) {
+	for i := len(started) - 1; i >= 0; i-- {
+		svc := started[i]
+		markServiceStopped(svc.spec.ID)
+		log.Info("🛑 Stopping service", "service", svc.spec.DisplayName)
+		begin := time.Now()
+		if err := stopService(context.Background(), svc); err != nil {
+			log.Warn("⚠️ Service did not stop cleanly", "service", svc.spec.DisplayName, "error", err)
+			continue
+		}
+		log.Info("✅ Service stopped", "service", svc.spec.DisplayName, "duration", time.Since(begin).Round(time.Millisecond))
+	}
+}
+
+// stopService runs the spec's Stop hook, stops its goreman processes and then
+// its start cleanup, giving up once StopTimeout has passed.
+func stopService(ctx context.Context, svc startedService) error {
+	timeout := svc.spec.StopTimeout
+	if timeout <= 0 {
+		timeout = defaultStopTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		var errs []error
+		if svc.spec.Stop != nil {
+			errs = append(errs, svc.spec.Stop(ctx))
+		}
+		for _, name := range svc.spec.GoremanProcesses {
+			if goreman.IsRunning(name) {
+				errs = append(errs, goreman.Stop(name))
+			}
+		}
+		if svc.cleanup != nil {
+			svc.cleanup()
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("stop timed out after %s", timeout)
+	}
+}
+
+// runInBackground runs fn in a goroutine with its own context and returns a
+// cleanup that cancels that context and waits for fn to return, so in-process
+// servers finish draining before the next service is stopped.
+func runInBackground(ctx context.Context, fn func(context.Context) error, onErr func(error)) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := fn(ctx); err != nil && onErr != nil {
+			onErr(err)
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
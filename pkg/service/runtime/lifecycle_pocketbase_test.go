@@ -0,0 +1,65 @@
+// PocketBase v0.30's Collection.UnmarshalJSON recurses forever when
+// encoding/json is backed by json/v2, so this test needs the v1 implementation.
+
+//go:build !goexperiment.jsonv2
+
+package runtime
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/joeblew999/infra/pkg/pocketbase"
+)
+
+func TestStopServicePocketBaseExitsWithinTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	_, port, _ := net.SplitHostPort(addr)
+
+	server := pocketbase.NewServer("test")
+	server.SetDataDir(t.TempDir())
+	server.SetPort(port)
+
+	var startErr error
+	cleanup := runPocketBase(context.Background(), server, func(err error) { startErr = err })
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			cleanup()
+			t.Fatalf("PocketBase never listened on %s: %v", port, err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	svc := startedService{
+		spec:    ServiceSpec{ID: ServicePocketBase, Stop: stopPocketBase, StopTimeout: 5 * time.Second},
+		cleanup: cleanup,
+	}
+	begin := time.Now()
+	if err := stopService(context.Background(), svc); err != nil {
+		t.Fatalf("stopService: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 3*time.Second {
+		t.Errorf("stop took %s, want well inside the %s stop timeout", elapsed, svc.spec.StopTimeout)
+	}
+	if startErr != nil {
+		t.Errorf("PocketBase reported %v", startErr)
+	}
+	if conn, err := net.Dial("tcp", addr); err == nil {
+		conn.Close()
+		t.Errorf("PocketBase still listening on %s after stop", port)
+	}
+}
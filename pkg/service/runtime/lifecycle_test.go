@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopServicesReverseOrder(t *testing.T) {
+	var stopped []string
+	record := func(id string) func() { return func() { stopped = append(stopped, id) } }
+	hook := func(id string) func(context.Context) error {
+		return func(context.Context) error {
+			stopped = append(stopped, id+":stop")
+			return nil
+		}
+	}
+
+	stopServices([]startedService{
+		{spec: ServiceSpec{ID: ServiceNATS}, cleanup: record("nats")},
+		{spec: ServiceSpec{ID: ServiceWeb, Stop: hook("web")}, cleanup: record("web")},
+		{spec: ServiceSpec{ID: ServiceCaddy, Stop: hook("caddy")}},
+	})
+
+	if got := strings.Join(stopped, ","); got != "caddy:stop,web:stop,web,nats" {
+		t.Fatalf("stop order = %s", got)
+	}
+}
+
+func TestStopServiceTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	svc := startedService{spec: ServiceSpec{
+		ID:          ServicePocketBase,
+		StopTimeout: 50 * time.Millisecond,
+		Stop: func(context.Context) error {
+			<-block
+			return nil
+		},
+	}}
+	if err := stopService(context.Background(), svc); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+}
+
+func TestRunInBackgroundWaitsForExit(t *testing.T) {
+	exited := false
+	cleanup := runInBackground(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		exited = true
+		return nil
+	}, nil)
+	cleanup()
+	if !exited {
+		t.Fatal("cleanup returned before the background function exited")
+	}
+}
+
+func TestServiceSpecsHaveStopHooks(t *testing.T) {
+	for _, spec := range AllServiceSpecs() {
+		if (spec.ID == ServicePocketBase || spec.ID == ServiceCaddy) && spec.Stop == nil {
+			t.Errorf("%s has no Stop hook", spec.ID)
+		}
+	}
+}
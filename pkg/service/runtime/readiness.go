@@ -0,0 +1,125 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	svcports "github.com/joeblew999/infra/pkg/service/ports"
+)
+
+// defaultReadyTimeout bounds how long Start waits for a service to become
+// ready when its spec does not set ReadyTimeout.
+const defaultReadyTimeout = 30 * time.Second
+
+// orderServiceSpecs sorts specs so every service comes after the services it
+// depends on, keeping the declared order otherwise. Dependencies on services
+// that are not in specs (filtered out via OnlyServices/SkipServices) are
+// ignored.
+func orderServiceSpecs(specs []ServiceSpec) ([]ServiceSpec, error) {
+	index := make(map[ServiceID]int, len(specs))
+	for i, spec := range specs {
+		index[spec.ID] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(specs))
+	ordered := make([]ServiceSpec, 0, len(specs))
+
+	var visit func(i int, path []ServiceID) error
+	visit = func(i int, path []ServiceID) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			cycle := append(path, specs[i].ID)
+			parts := make([]string, len(cycle))
+			for j, id := range cycle {
+				parts[j] = string(id)
+			}
+			return fmt.Errorf("service dependency cycle: %s", strings.Join(parts, " -> "))
+		}
+		state[i] = visiting
+		for _, dep := range specs[i].DependsOn {
+			if j, ok := index[dep]; ok {
+				if err := visit(j, append(path, specs[i].ID)); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = done
+		ordered = append(ordered, specs[i])
+		return nil
+	}
+
+	for i := range specs {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// failedDependency returns the first dependency of spec that failed to start.
+func failedDependency(spec ServiceSpec, failed map[ServiceID]error) (ServiceID, error) {
+	for _, dep := range spec.DependsOn {
+		if err, ok := failed[dep]; ok {
+			return dep, err
+		}
+	}
+	return "", nil
+}
+
+// waitServiceReady blocks until spec is ready: its Ready probe succeeds or,
+// without one, its port accepts connections. Services without either are
+// ready as soon as Start returns.
+func waitServiceReady(ctx context.Context, spec ServiceSpec) error {
+	timeout := spec.ReadyTimeout
+	if timeout <= 0 {
+		timeout = defaultReadyTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if spec.Ready != nil {
+		return spec.Ready(ctx)
+	}
+	port := svcports.ParsePort(spec.Port)
+	if port == 0 {
+		return nil
+	}
+	return waitForPort(ctx, port)
+}
+
+// waitForPort polls until something accepts TCP connections on the local
+// port or ctx ends.
+func waitForPort(ctx context.Context, port int) error {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if dialPort(ctx, port) == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("port %d not accepting connections: %w", port, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// dialPort makes a single TCP connection attempt to the local port.
+func dialPort(ctx context.Context, port int) error {
+	dialer := net.Dialer{Timeout: 250 * time.Millisecond}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", fmt.Sprint(port)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,80 @@
+package runtime
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func specIDs(specs []ServiceSpec) string {
+	ids := make([]string, len(specs))
+	for i, spec := range specs {
+		ids[i] = string(spec.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestOrderServiceSpecs(t *testing.T) {
+	specs := []ServiceSpec{
+		{ID: ServiceWeb, DependsOn: []ServiceID{ServiceNATS}},
+		{ID: ServiceNATS},
+		{ID: ServicePocketBase},
+		{ID: ServiceCaddy, DependsOn: []ServiceID{ServiceWeb, ServiceHugo}}, // hugo filtered out
+	}
+	ordered, err := orderServiceSpecs(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := specIDs(ordered); got != "nats,web,pocketbase,caddy" {
+		t.Fatalf("order = %s", got)
+	}
+
+	_, err = orderServiceSpecs([]ServiceSpec{
+		{ID: ServiceWeb, DependsOn: []ServiceID{ServiceCaddy}},
+		{ID: ServiceCaddy, DependsOn: []ServiceID{ServiceWeb}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "web -> caddy -> web") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestBaseServiceDependenciesAreOrdered(t *testing.T) {
+	ordered, err := orderServiceSpecs(AllServiceSpecs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[ServiceID]bool{}
+	for _, spec := range ordered {
+		for _, dep := range spec.DependsOn {
+			if !seen[dep] {
+				t.Errorf("%s starts before its dependency %s", spec.ID, dep)
+			}
+		}
+		seen[spec.ID] = true
+	}
+}
+
+func TestWaitServiceReady(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	if err := waitServiceReady(context.Background(), ServiceSpec{Port: port}); err != nil {
+		t.Fatalf("listening port should be ready: %v", err)
+	}
+
+	l.Close()
+	err = waitServiceReady(context.Background(), ServiceSpec{Port: port, ReadyTimeout: 300 * time.Millisecond})
+	if err == nil {
+		t.Fatal("closed port should time out")
+	}
+
+	if err := waitServiceReady(context.Background(), ServiceSpec{}); err != nil {
+		t.Fatalf("service without a port is ready immediately: %v", err)
+	}
+}
@@ -1,21 +1,19 @@
 package runtime
 
 import (
+	"context"
 	"fmt"
 	"os"
 
+	"github.com/nats-io/nats.go"
+
 	"github.com/joeblew999/infra/pkg/caddy"
 	"github.com/joeblew999/infra/pkg/config"
 	"github.com/joeblew999/infra/pkg/log"
 )
 
-func startCaddy() (func(), error) {
-	specs := activeServiceSpecs
-	if len(specs) == 0 {
-		specs = buildServiceSpecs(activeOptions)
-	}
-
-	cfg, err := generateCaddyConfig(specs)
+func startCaddy(ctx context.Context) (func(), error) {
+	cfg, err := generateCaddyConfig(caddySpecs())
 	if err != nil {
 		return nil, fmt.Errorf("build caddy config: %w", err)
 	}
@@ -25,7 +23,37 @@ func startCaddy() (func(), error) {
 		return nil, err
 	}
 	log.Info("✅ Caddy reverse proxy started supervised")
-	return nil, nil
+	return exportCaddyAccessLog(ctx, cfg.AccessLog), nil
+}
+
+// stopCaddy stops the supervised Caddy process through goreman, which
+// signals it and waits for it to exit.
+func stopCaddy(context.Context) error {
+	return caddy.StopSupervised()
+}
+
+// exportCaddyAccessLog republishes Caddy's access log on NATS so HTTP
+// traffic can be correlated with process events. Without NATS the log is
+// still written to disk.
+func exportCaddyAccessLog(ctx context.Context, path string) func() {
+	if path == "" {
+		return nil
+	}
+	nc, err := nats.Connect(config.GetNATSURL(), nats.Name("caddy-access-log"))
+	if err != nil {
+		log.Warn("⚠️ Caddy access log not exported to NATS", "path", path, "error", err)
+		return nil
+	}
+	log.Info("📜 Exporting Caddy access log", "path", path, "subject", caddy.AccessLogSubject)
+	stop := runInBackground(ctx, func(ctx context.Context) error {
+		return caddy.TailAccessLog(ctx, path, caddy.PublishAccessLog(nc, caddy.AccessLogSubject))
+	}, func(err error) {
+		log.Warn("⚠️ Caddy access log export stopped", "error", err)
+	})
+	return func() {
+		stop()
+		nc.Close()
+	}
 }
 
 func ensureCaddyDirectories() error {
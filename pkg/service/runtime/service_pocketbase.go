@@ -4,12 +4,20 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/joeblew999/infra/pkg/config"
 	"github.com/joeblew999/infra/pkg/log"
 	"github.com/joeblew999/infra/pkg/pocketbase"
 )
 
+// pocketBaseServer is the embedded PocketBase while it runs, so its spec's
+// Stop hook can shut it down.
+var (
+	pocketBaseMu     sync.Mutex
+	pocketBaseServer *pocketbase.Server
+)
+
 func startPocketBase(ctx context.Context, mode string, record func(error)) (func(), error) {
 	log.Info("🚀 Starting embedded PocketBase server...")
 	pbEnv := "production"
@@ -17,18 +25,44 @@ func startPocketBase(ctx context.Context, mode string, record func(error)) (func
 		pbEnv = "development"
 	}
 
-	pbServer := pocketbase.NewServer(pbEnv)
-	go func() {
-		if err := pbServer.Start(ctx); err != nil {
-			log.Warn("PocketBase failed to start", "error", err)
-			if record != nil {
-				record(fmt.Errorf("pocketbase failed to start: %w", err))
-			}
+	cleanup := runPocketBase(ctx, pocketbase.NewServer(pbEnv), record)
+	NotifyCaddyRoutesChanged()
+	return cleanup, nil
+}
+
+// runPocketBase serves server in the background. Cancelling ctx or calling
+// stopPocketBase shuts it down; the cleanup waits until its databases are
+// closed.
+func runPocketBase(ctx context.Context, server *pocketbase.Server, record func(error)) func() {
+	pocketBaseMu.Lock()
+	pocketBaseServer = server
+	pocketBaseMu.Unlock()
+
+	stop := runInBackground(ctx, server.Start, func(err error) {
+		log.Warn("PocketBase failed to start", "error", err)
+		if record != nil {
+			record(fmt.Errorf("pocketbase failed to start: %w", err))
 		}
-	}()
+	})
+	return func() {
+		stop()
+		pocketBaseMu.Lock()
+		if pocketBaseServer == server {
+			pocketBaseServer = nil
+		}
+		pocketBaseMu.Unlock()
+	}
+}
 
-	NotifyCaddyRoutesChanged()
-	return nil, nil
+// stopPocketBase gracefully stops the running embedded PocketBase, if any.
+func stopPocketBase(context.Context) error {
+	pocketBaseMu.Lock()
+	server := pocketBaseServer
+	pocketBaseMu.Unlock()
+	if server == nil {
+		return nil
+	}
+	return server.Stop()
 }
 
 func ensurePocketBaseDirectories() error {
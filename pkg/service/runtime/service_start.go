@@ -9,28 +9,8 @@ import (
 
 var caddyReload = caddy.ReloadWithConfig
 
-func generateCaddyConfig(specs []ServiceSpec) (caddy.CaddyConfig, error) {
-	tpl, err := buildCaddyTemplate(specs)
-	if err != nil {
-		return caddy.CaddyConfig{}, err
-	}
-
-	cfg := caddy.CaddyConfig{
-		Port:   tpl.ListenPort,
-		Target: tpl.RootTarget,
-	}
-	for _, route := range tpl.Routes {
-		cfg.Routes = append(cfg.Routes, caddy.ProxyRoute{Path: route.Path, Target: route.Target})
-	}
-	return cfg, nil
-}
-
 func reloadCaddyConfig() error {
-	specs := activeServiceSpecs
-	if len(specs) == 0 {
-		specs = buildServiceSpecs(activeOptions)
-	}
-
+	specs := caddySpecs()
 	if !hasService(specs, ServiceCaddy) {
 		return nil
 	}
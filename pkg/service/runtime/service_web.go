@@ -3,7 +3,6 @@ package runtime
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/joeblew999/infra/pkg/config"
 	"github.com/joeblew999/infra/pkg/log"
@@ -14,22 +13,21 @@ func startWebServer(ctx context.Context, opts Options, record func(error)) (func
 	webPort := config.GetWebServerPort()
 	log.Info("🌐 Starting web server", "address", "http://0.0.0.0:"+webPort)
 
-	go func() {
-		svc := webapp.NewService(
-			webapp.WithPort(webPort),
-			webapp.WithNATSURL(config.GetNATSURL()),
-		)
+	svc := webapp.NewService(
+		webapp.WithPort(webPort),
+		webapp.WithNATSURL(config.GetNATSURL()),
+		webapp.WithHandler(AggregateHealthPath, AggregateHealthHandler()),
+	)
 
-		if err := svc.Start(ctx); err != nil {
-			log.Error("❌ Failed to start web server", "error", err)
-			if record != nil {
-				record(fmt.Errorf("web server failed to start: %w", err))
-			}
+	// webapp.Service.Start drains in-flight requests with http.Server.Shutdown
+	// once its context is cancelled; the cleanup waits for that to finish.
+	cleanup := runInBackground(ctx, svc.Start, func(err error) {
+		log.Error("❌ Failed to start web server", "error", err)
+		if record != nil {
+			record(fmt.Errorf("web server failed to start: %w", err))
 		}
-	}()
-
-	time.Sleep(500 * time.Millisecond)
-	return nil, nil
+	})
+	return cleanup, nil
 }
 
 func ensureWebDirectories() error {
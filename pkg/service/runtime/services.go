@@ -38,6 +38,19 @@ type ServiceSpec struct {
 	GoremanProcesses []string
 	Enabled          func(opts Options) bool
 	Routes           []RouteSpec
+	// DependsOn lists services that must be ready before this one starts.
+	// If one of them fails, this service is not started.
+	DependsOn []ServiceID
+	// Ready reports when the service can take traffic. Without it the
+	// service is ready once Port accepts connections.
+	Ready func(ctx context.Context) error
+	// ReadyTimeout bounds the readiness wait (default 30s).
+	ReadyTimeout time.Duration
+	// Stop runs during shutdown, before the service's goreman processes are
+	// stopped and its start cleanup runs.
+	Stop func(ctx context.Context) error
+	// StopTimeout bounds the whole stop step (default 10s).
+	StopTimeout time.Duration
 }
 
 func (s ServiceSpec) String() string {
@@ -64,7 +77,8 @@ var baseServiceSpecs = []ServiceSpec{
 		Ensure: func(ctx context.Context, opts Options) error {
 			return ensureWebDirectories()
 		},
-		Enabled: func(opts Options) bool { return true },
+		Enabled:   func(opts Options) bool { return true },
+		DependsOn: []ServiceID{ServiceNATS},
 	},
 }
 
@@ -73,6 +87,13 @@ func init() {
 }
 
 func buildServiceSpecs(opts Options) []ServiceSpec {
+	specs, _ := partitionServiceSpecs(opts)
+	return specs
+}
+
+// partitionServiceSpecs returns the specs selected by opts.OnlyServices and
+// opts.SkipServices along with the IDs of the services they filtered out.
+func partitionServiceSpecs(opts Options) ([]ServiceSpec, []ServiceID) {
 	include := make(map[ServiceID]struct{})
 	if len(opts.OnlyServices) > 0 {
 		for _, id := range opts.OnlyServices {
@@ -98,18 +119,18 @@ func buildServiceSpecs(opts Options) []ServiceSpec {
 	}
 
 	services := make([]ServiceSpec, 0, len(baseServiceSpecs))
-	for _, spec := range baseServiceSpecs {
+	var filtered []ServiceID
+	appendService := func(spec ServiceSpec) {
 		if shouldInclude(spec.ID) {
 			services = append(services, spec)
 			publishServiceSpec(spec)
+		} else {
+			filtered = append(filtered, spec.ID)
 		}
 	}
 
-	appendService := func(spec ServiceSpec) {
-		if shouldInclude(spec.ID) {
-			services = append(services, spec)
-			publishServiceSpec(spec)
-		}
+	for _, spec := range baseServiceSpecs {
+		appendService(spec)
 	}
 
 	appendService(ServiceSpec{
@@ -143,6 +164,7 @@ func buildServiceSpecs(opts Options) []ServiceSpec {
 		Ensure: func(ctx context.Context, opts Options) error {
 			return ensurePocketBaseDirectories()
 		},
+		Stop:    stopPocketBase,
 		Enabled: func(opts Options) bool { return true },
 		Routes: []RouteSpec{
 			{Path: "/pocketbase/*", Target: config.FormatLocalHostPort(config.GetPocketBasePort())},
@@ -157,13 +179,16 @@ func buildServiceSpecs(opts Options) []ServiceSpec {
 		Required:    true,
 		Port:        config.GetCaddyPort(),
 		Start: func(ctx context.Context, opts Options, record func(error)) (func(), error) {
-			return startCaddy()
+			return startCaddy(ctx)
 		},
 		Ensure: func(ctx context.Context, opts Options) error {
 			return ensureCaddyDirectories()
 		},
+		Stop:             stopCaddy,
 		Enabled:          func(opts Options) bool { return true },
 		GoremanProcesses: []string{"caddy"},
+		// Caddy proxies / to the web server.
+		DependsOn: []ServiceID{ServiceWeb},
 	})
 
 	appendService(ServiceSpec{
@@ -260,7 +285,7 @@ func buildServiceSpecs(opts Options) []ServiceSpec {
 		},
 	})
 
-	return services
+	return services, filtered
 }
 
 func collectServicePorts(opts Options) []ServicePort {
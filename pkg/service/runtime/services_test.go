@@ -0,0 +1,24 @@
+package runtime
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPartitionServiceSpecs(t *testing.T) {
+	specs, filtered := partitionServiceSpecs(Options{
+		OnlyServices: []ServiceID{ServiceNATS, ServicePocketBase, ServiceCaddy},
+		SkipServices: []ServiceID{ServiceCaddy},
+	})
+	if got := specIDs(specs); got != "nats,pocketbase" {
+		t.Fatalf("selected = %s, want nats,pocketbase", got)
+	}
+	for _, id := range []ServiceID{ServiceWeb, ServiceCaddy, ServiceBento} {
+		if !slices.Contains(filtered, id) {
+			t.Errorf("%s should be reported as filtered, got %v", id, filtered)
+		}
+	}
+	if len(specs)+len(filtered) != len(AllServiceSpecs()) {
+		t.Errorf("every service should be either selected or filtered")
+	}
+}
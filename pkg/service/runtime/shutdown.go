@@ -7,6 +7,10 @@ import (
 	svcports "github.com/joeblew999/infra/pkg/service/ports"
 )
 
+// portShutdownGrace is how long a service gets to exit on SIGTERM before its
+// port is force-killed.
+const portShutdownGrace = 5 * time.Second
+
 // Shutdown stops running infrastructure services by signalling processes, ports, and goreman groups.
 func Shutdown() {
 	log.Info("🛑 Shutting down all infrastructure services...")
@@ -40,7 +44,7 @@ func Shutdown() {
 		if port == 0 {
 			continue
 		}
-		if err := svcports.KillProcessByPort(port); err == nil {
+		if err := svcports.KillProcessByPortGraceful(port, portShutdownGrace); err == nil {
 			log.Info("✅ Stopped service port", "service", spec.Service, "port", spec.Port)
 			portsKilled++
 		}
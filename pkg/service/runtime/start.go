@@ -54,19 +54,27 @@ func Start(opts Options) error {
 
 	log.Info("Running in Service mode with goreman supervision...")
 
-	specs := buildServiceSpecs(opts)
+	selected, filtered := partitionServiceSpecs(opts)
+	specs, err := orderServiceSpecs(selected)
+	if err != nil {
+		return err
+	}
+	summary := &startupSummary{}
+	for _, id := range filtered {
+		summary.skip(id, "filtered by --only/--skip")
+	}
 	activeServiceSpecs = specs
 
 	errCh := make(chan error, 1)
 
-	var cleanupStack []func()
+	// Services run under their own context so a shutdown signal does not tear
+	// them all down at once; stopServices stops them in reverse order first.
+	serviceCtx, cancelServices := context.WithCancel(context.WithoutCancel(ctx))
+	var started []startedService
 
 	defer func() {
-		for i := len(cleanupStack) - 1; i >= 0; i-- {
-			if cleanupStack[i] != nil {
-				cleanupStack[i]()
-			}
-		}
+		stopServices(started)
+		cancelServices()
 		goreman.StopAll()
 	}()
 
@@ -97,8 +105,13 @@ func Start(opts Options) error {
 
 	log.Info("🚀 Starting all infrastructure services...")
 
+	// failed records services that did not come up so their dependents are
+	// skipped instead of racing against a missing prerequisite.
+	failed := make(map[ServiceID]error)
+
 	for idx, svc := range specs {
 		if svc.Enabled != nil && !svc.Enabled(opts) {
+			summary.skip(svc.ID, "disabled")
 			continue
 		}
 
@@ -107,11 +120,23 @@ func Start(opts Options) error {
 		startNotes := make([]string, 0, 2)
 		port := svcports.ParsePort(svc.Port)
 
+		if dep, depErr := failedDependency(svc, failed); dep != "" {
+			msg := fmt.Sprintf("Not started: dependency %s failed: %v", dep, depErr)
+			log.Error("❌ Skipping service, dependency failed", "service", svc.DisplayName, "dependency", dep, "error", depErr)
+			publishAction(svc, actionStartupBlocked, msg)
+			publishStatus(svc, serviceStateBlocked, false, 0, port, "unknown", msg)
+			failed[svc.ID] = fmt.Errorf("dependency %s failed", dep)
+			summary.skip(svc.ID, fmt.Sprintf("dependency %s failed", dep))
+			continue
+		}
+
 		if svc.Ensure != nil {
 			if err := svc.Ensure(ctx, opts); err != nil {
 				msg := fmt.Sprintf("Ensure failed: %v", err)
 				log.Error("Failed to prepare service", "service", svc.DisplayName, "error", err)
 				recordErr(fmt.Errorf("%s ensure failed: %w", svc.DisplayName, err))
+				failed[svc.ID] = err
+				summary.fail(svc.ID)
 				publishAction(svc, actionEnsureFailed, msg)
 				publishStatus(svc, serviceStateError, false, 0, port, "unknown", msg)
 				continue
@@ -168,19 +193,34 @@ func Start(opts Options) error {
 			publishStatus(svc, serviceStatePending, false, 0, port, "unknown", "")
 		}
 
-		cleanup, err := svc.Start(ctx, opts, recordErr)
-		if cleanup != nil {
-			cleanupStack = append(cleanupStack, cleanup)
+		cleanup, err := svc.Start(serviceCtx, opts, recordErr)
+		if err == nil || cleanup != nil {
+			started = append(started, startedService{spec: svc, cleanup: cleanup})
 		}
 		if err != nil {
 			msg := fmt.Sprintf("Start failed: %v", err)
 			log.Warn("Service failed to start", "service", svc.DisplayName, "error", err)
 			recordErr(fmt.Errorf("%s failed to start: %w", svc.DisplayName, err))
+			failed[svc.ID] = err
+			summary.fail(svc.ID)
 			publishAction(svc, actionStartFailed, msg)
 			publishStatus(svc, serviceStateError, false, 0, port, "unknown", msg)
 			continue
 		}
 
+		if err := waitServiceReady(ctx, svc); err != nil {
+			msg := fmt.Sprintf("Not ready: %v", err)
+			log.Error("❌ Service did not become ready", "service", svc.DisplayName, "error", err)
+			recordErr(fmt.Errorf("%s did not become ready: %w", svc.DisplayName, err))
+			failed[svc.ID] = err
+			summary.fail(svc.ID)
+			publishAction(svc, actionStartFailed, msg)
+			publishStatus(svc, serviceStateError, false, 0, port, "unknown", msg)
+			continue
+		}
+		summary.start(svc.ID)
+		markServiceRunning(svc)
+
 		pid := 0
 		if candidatePID, ok := goreman.GetProcessPID(string(svc.ID)); ok {
 			pid = candidatePID
@@ -208,7 +248,10 @@ func Start(opts Options) error {
 		log.Info("External process status", "name", name, "status", stat)
 	}
 
-	log.Info("🎉 All infrastructure services started successfully!")
+	summary.log()
+	if len(summary.failed) == 0 {
+		log.Info("🎉 All infrastructure services started successfully!")
+	}
 	log.Info("💡 Web server accessible at http://0.0.0.0:" + config.GetWebServerPort())
 
 	<-ctx.Done()
@@ -267,3 +310,26 @@ func ownershipString(ownership svcports.Ownership) string {
 		return "unknown"
 	}
 }
+
+// startupSummary records what happened to each service during Start so a
+// focused run (e.g. --only nats,pocketbase) shows what was left out.
+type startupSummary struct {
+	started []string
+	skipped []string
+	failed  []string
+}
+
+func (s *startupSummary) start(id ServiceID) { s.started = append(s.started, string(id)) }
+
+func (s *startupSummary) fail(id ServiceID) { s.failed = append(s.failed, string(id)) }
+
+func (s *startupSummary) skip(id ServiceID, reason string) {
+	s.skipped = append(s.skipped, fmt.Sprintf("%s (%s)", id, reason))
+}
+
+func (s *startupSummary) log() {
+	log.Info("📋 Startup summary",
+		"started", strings.Join(s.started, ", "),
+		"skipped", strings.Join(s.skipped, ", "),
+		"failed", strings.Join(s.failed, ", "))
+}
@@ -52,15 +52,29 @@ func WithDocsDevMode(enabled bool) Option {
 	}
 }
 
+// WithHandler mounts an extra handler at pattern, for routes owned by
+// packages that cannot be imported here (e.g. the runtime that starts us).
+func WithHandler(pattern string, h http.Handler) Option {
+	return func(s *Service) {
+		s.handlers = append(s.handlers, mountedHandler{pattern: pattern, handler: h})
+	}
+}
+
 // Service runs the web UI and supporting routes.
 type Service struct {
 	port        string
 	natsURL     string
 	docsDevMode bool
+	handlers    []mountedHandler
 
 	router *chi.Mux
 }
 
+type mountedHandler struct {
+	pattern string
+	handler http.Handler
+}
+
 // NewService constructs a Service with repository defaults.
 func NewService(opts ...Option) *Service {
 	s := &Service{
@@ -148,6 +162,9 @@ func (s *Service) ensureRouter(ctx context.Context, nc *nats.Conn) error {
 		docsDev:  s.docsDevMode,
 	}
 	app.setupRoutes(ctx)
+	for _, m := range s.handlers {
+		router.Handle(m.pattern, m.handler)
+	}
 
 	s.router = router
 	return nil
@@ -227,7 +244,7 @@ func (a *appState) setupRoutes(ctx context.Context) {
 		RPOrigins:     []string{config.FormatLocalHTTP(config.GetWebServerPort())},
 	}
 	userStore := auth.NewInMemoryUserStore()
-	sessionStore := auth.NewInMemorySessionStore()
+	sessionStore := auth.NewInMemorySessionStore(auth.SessionStoreConfig{})
 	authService, _ := auth.NewAuthService(authConfig, userStore, sessionStore, "pkg/auth/web")
 	a.router.Route("/auth", func(r chi.Router) {
 		authService.RegisterRoutes(r)